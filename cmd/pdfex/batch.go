@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/yourusername/pdfex/internal/metrics"
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// singleFileFlags are the legacy options that write one output file, so they can't be used
+// when several PDFs are processed
+var singleFileFlags = []string{"html", "markdown", "export-json", "alto", "csv", "stats"}
+
+// runBatch parses several PDFs in parallel, writing the chunks of each next to it, and prints a
+// line per file and a summary. With jsonOutput, the summary is also saved as JSON, and an error
+// is returned if that fails.
+func runBatch(files []string, options *pdfex.BatchOptions, chunkOptions *pdfex.ChunkOptions, jsonOutput string, metricsCSV *metrics.MetricsCollection) (*pdfex.BatchSummary, error) {
+	summary := pdfex.ProcessBatch(files, options, func(filename string, doc *pdfex.PDFDocument) error {
+		if metricsCSV != nil {
+			metricsCSV.Add(doc.Metrics())
+		}
+		return writeChunks(doc, chunksFileName(filename), chunkOptions)
+	})
+
+	for _, result := range summary.Results {
+		if result.Err != nil {
+			fmt.Printf("%s: error (%s): %v\n", result.File, result.ErrorKind, result.Err)
+			continue
+		}
+		recovered := ""
+		if result.Recovered {
+			recovered = " (recovered)"
+		}
+		fmt.Printf("%s: %d pages in %v%s\n", result.File, result.Pages, result.Duration.Round(time.Millisecond), recovered)
+	}
+	fmt.Printf("\nProcessed %d files: %d succeeded, %d failed, %d pages in %v\n",
+		summary.Files, summary.Succeeded, summary.Failed, summary.Pages, summary.Duration.Round(time.Millisecond))
+
+	if jsonOutput != "" {
+		content, err := json.MarshalIndent(summary, "", "  ")
+		if err == nil {
+			err = os.WriteFile(jsonOutput, content, 0644)
+		}
+		if err != nil {
+			return summary, fmt.Errorf("error writing batch summary to %s: %v", jsonOutput, err)
+		}
+		fmt.Printf("Batch summary saved to %s\n", jsonOutput)
+	}
+
+	return summary, nil
+}
+
+// openMetricsCSV opens the CSV file that a row of metrics is streamed to for each document
+// processed. Rows are appended to an existing file if requested, with the header written only
+// if it is empty. The returned function flushes and closes the file.
+func openMetricsCSV(filename string, appendRows bool) (*metrics.MetricsCollection, func() error, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendRows {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	file, err := os.OpenFile(filename, flags, 0644)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	bw := bufio.NewWriter(file)
+	collection := metrics.NewMetricsCollection()
+	if info.Size() > 0 {
+		err = collection.AppendCSVTo(bw)
+	} else {
+		err = collection.ExportCSVTo(bw)
+	}
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	closeCSV := func() error {
+		err := collection.CSVErr()
+		if flushErr := bw.Flush(); err == nil {
+			err = flushErr
+		}
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+	return collection, closeCSV, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// runChunks implements "pdfex chunks [-size n] [-overlap n] [-by mode] [options] <pdf_file>",
+// which writes the text chunks of a document as JSON Lines or Parquet
+func runChunks(args []string) int {
+	fs := flag.NewFlagSet("chunks", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	size := fs.Int("size", 1000, "Maximum length of text chunks in characters")
+	overlap := fs.Int("overlap", 0, "Characters repeated from the end of each chunk at the start of the next")
+	by := fs.String("by", "paragraph", "Where text chunks end: paragraph, sentence, page or heading")
+	metadata := fs.Bool("metadata", false, "Add the file name, title and author to each chunk")
+	format := fs.String("format", "jsonl", "Output format: jsonl or parquet")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex chunks [-size n] [-overlap n] [-by mode] [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	if *format != "jsonl" && *format != "parquet" {
+		fmt.Fprintf(os.Stderr, "Error: unknown chunk format: %s\n", *format)
+		return 2
+	}
+
+	split, err := pdfex.ParseChunkSplit(*by)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	options := &pdfex.ChunkOptions{MaxSize: *size, Overlap: *overlap, SplitOn: split, IncludeMetadata: *metadata}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	err = common.writeOutput(func(w io.Writer) error {
+		if *format == "parquet" {
+			return doc.WriteChunksParquet(w, options)
+		}
+		return doc.WriteChunksJSONL(w, options)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing chunks: %v\n", err)
+		return commandStatus(err)
+	}
+	return 0
+}
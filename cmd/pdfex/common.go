@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/utils"
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// commonFlags are the flags shared by the subcommands that process one document
+type commonFlags struct {
+	output     *string
+	pages      *string
+	verifyXRef *bool
+	verbose    *bool
+	debug      *bool
+}
+
+// addCommonFlags registers the shared flags on a subcommand's flag set
+func addCommonFlags(fs *flag.FlagSet) *commonFlags {
+	c := addInputFlags(fs)
+	c.output = fs.String("o", "", "Write the output to this file instead of stdout")
+	return c
+}
+
+// addInputFlags registers the shared flags other than -o, for commands whose outputs are
+// files of their own; their output stream is stdout
+func addInputFlags(fs *flag.FlagSet) *commonFlags {
+	return &commonFlags{
+		output:     new(string),
+		pages:      fs.String("pages", "", "Only process these pages, e.g. 1-5,12 or 10-"),
+		verifyXRef: fs.Bool("verify-xref", false, "Compare the xref table with a full scan of the file"),
+		verbose:    fs.Bool("v", false, "Log informational messages to stderr"),
+		debug:      fs.Bool("debug", false, "Log debug messages to stderr"),
+	}
+}
+
+// parseOptions returns the parse options selected by the shared flags. Only errors are logged
+// unless -v or -debug is given, so that the output stream holds nothing but the result.
+func (c *commonFlags) parseOptions() *pdfex.ParseOptions {
+	options := pdfex.DefaultParseOptions()
+	options.LogLevel = utils.LogError
+	if *c.debug {
+		options.LogLevel = utils.LogDebug
+	} else if *c.verbose {
+		options.LogLevel = utils.LogInfo
+	}
+	options.PageRange = *c.pages
+	options.VerifyXRef = *c.verifyXRef
+	return options
+}
+
+// open parses a document with the options selected by the shared flags. Log messages go to
+// stderr so that they never mix with output written to stdout.
+func (c *commonFlags) open(filename string) (*pdfex.PDFDocument, error) {
+	return c.openWith(filename, c.parseOptions())
+}
+
+// openWith parses a document like open, with options a subcommand has adjusted
+func (c *commonFlags) openWith(filename string, options *pdfex.ParseOptions) (*pdfex.PDFDocument, error) {
+	utils.SetLogWriter(os.Stderr)
+	doc, err := parseInput(filename, options)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing PDF: %w", err)
+	}
+	return doc, nil
+}
+
+// stdinName names a document read from standard input in output file names and provenance
+const stdinName = "stdin"
+
+// parseInput parses the named PDF file, or standard input when the name is "-"
+func parseInput(filename string, options *pdfex.ParseOptions) (*pdfex.PDFDocument, error) {
+	if filename == "-" {
+		return pdfex.ParsePDFFromReaderWithOptions(os.Stdin, stdinName, options)
+	}
+	return pdfex.ParsePDFWithOptions(filename, options)
+}
+
+// inputBase returns the name of an input file without directory and extension, for naming
+// the files derived from it
+func inputBase(filename string) string {
+	if filename == "-" {
+		return stdinName
+	}
+	return strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+}
+
+// chunksFileName returns the name of the chunks file written next to an input file
+func chunksFileName(filename string) string {
+	if filename == "-" {
+		return stdinName + "_chunks.jsonl"
+	}
+	return strings.TrimSuffix(filename, ".pdf") + "_chunks.jsonl"
+}
+
+// nopCloser keeps stdout open when an output stream is closed
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// createOutput returns the named file, or stdout when the name is empty or "-"
+func createOutput(name string) (io.WriteCloser, error) {
+	if name == "" || name == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	file, err := os.Create(name)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s: %w", name, err)
+	}
+	return file, nil
+}
+
+// outputWriter returns the stream selected by -o
+func (c *commonFlags) outputWriter() (io.WriteCloser, error) {
+	return createOutput(*c.output)
+}
+
+// writeOutput writes to the stream selected by -o, closing it afterwards
+func (c *commonFlags) writeOutput(write func(w io.Writer) error) error {
+	w, err := c.outputWriter()
+	if err != nil {
+		return err
+	}
+	if err := write(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// printOutput writes a report to the stream selected by -o. print needn't check its writes:
+// they are buffered, and the first that failed is reported when the buffer is flushed.
+func (c *commonFlags) printOutput(print func(w io.Writer)) error {
+	return c.writeOutput(func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		print(bw)
+		return bw.Flush()
+	})
+}
+
+// writeJSON writes v to w as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
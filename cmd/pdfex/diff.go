@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// diffPrefixes marks the lines of a page diff like diff -u does
+var diffPrefixes = map[pdfex.DiffOp]string{
+	pdfex.DiffEqual:  " ",
+	pdfex.DiffDelete: "-",
+	pdfex.DiffInsert: "+",
+}
+
+// runDiff implements "pdfex diff [options] <old.pdf> <new.pdf>", which prints a page-aligned
+// text diff of two documents and a summary of the added, removed and changed pages. Like
+// diff, the exit status is 0 if the texts are the same, 1 if they differ and 2, 3, 4 or 6 on error.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	jsonOutput := fs.Bool("json", false, "Print the differences as JSON")
+	summaryOnly := fs.Bool("summary", false, "Only print the status of each page and the summary")
+	context := fs.Int("context", 3, "Unchanged `lines` shown around each change, -1 for all")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex diff [options] <old.pdf> <new.pdf>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	oldDoc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", fs.Arg(0), err)
+		return commandStatus(err)
+	}
+	newDoc, err := common.open(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", fs.Arg(1), err)
+		return commandStatus(err)
+	}
+
+	diff := pdfex.Diff(oldDoc, newDoc)
+	err = common.writeOutput(func(w io.Writer) error {
+		if *jsonOutput {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(diff)
+		}
+		return writeDiff(w, fs.Arg(0), fs.Arg(1), diff, *context, *summaryOnly)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing diff: %v\n", err)
+		return commandStatus(err)
+	}
+
+	if !diff.Identical() {
+		return 1
+	}
+	return 0
+}
+
+// writeDiff prints a header per added, removed or changed page followed by its lines, then
+// a summary
+func writeDiff(w io.Writer, oldName, newName string, diff *pdfex.DocumentDiff, context int, summaryOnly bool) error {
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", oldName, newName); err != nil {
+		return err
+	}
+
+	for _, page := range diff.Pages {
+		if page.Status == pdfex.PageUnchanged {
+			continue
+		}
+
+		var header string
+		switch page.Status {
+		case pdfex.PageAdded:
+			header = fmt.Sprintf("@@ page %d added @@", page.NewPage)
+		case pdfex.PageRemoved:
+			header = fmt.Sprintf("@@ page %d removed @@", page.OldPage)
+		default:
+			header = fmt.Sprintf("@@ page %d -> page %d changed @@", page.OldPage, page.NewPage)
+		}
+		if _, err := fmt.Fprintln(w, header); err != nil {
+			return err
+		}
+		if summaryOnly {
+			continue
+		}
+
+		if err := writeDiffLines(w, page.Lines, context); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "Pages: %d added, %d removed, %d changed, %d unchanged\n",
+		diff.Added, diff.Removed, diff.Changed, diff.Unchanged)
+	return err
+}
+
+// writeDiffLines prints the changed lines of a page with up to context unchanged lines
+// around them, separating groups that are further apart with "..."
+func writeDiffLines(w io.Writer, lines []pdfex.DiffLine, context int) error {
+	show := make([]bool, len(lines))
+	for i, line := range lines {
+		if context < 0 {
+			show[i] = true
+			continue
+		}
+		if line.Op == pdfex.DiffEqual {
+			continue
+		}
+		for j := i - context; j <= i+context; j++ {
+			if j >= 0 && j < len(lines) {
+				show[j] = true
+			}
+		}
+	}
+
+	lastShown := -1
+	for i, line := range lines {
+		if !show[i] {
+			continue
+		}
+		if lastShown >= 0 && i > lastShown+1 {
+			if _, err := fmt.Fprintln(w, "..."); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s%s\n", diffPrefixes[line.Op], line.Text); err != nil {
+			return err
+		}
+		lastShown = i
+	}
+	return nil
+}
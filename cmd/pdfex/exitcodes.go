@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// Exit statuses of pdfex, so that scripts can tell failures apart. Subcommands return 0 on
+// success, 1 for findings (differences, matches not found, problems reported) and 2 for an
+// invalid command line or another failure, but use exitNotPDF, exitEncrypted and exitIO like
+// pdfex without a command when a document can't be read or an output can't be written.
+const (
+	exitOK        = 0
+	exitError     = 1 // The document is damaged beyond recovery, or several kinds of failure in a batch
+	exitUsage     = 2 // Invalid command line
+	exitNotPDF    = 3 // The file has no %PDF- header
+	exitEncrypted = 4 // The document is encrypted; nothing was extracted
+	exitRecovered = 5 // Outputs were written, but the document was damaged and needed recovery
+	exitIO        = 6 // A file couldn't be read or an output couldn't be written
+)
+
+// exitStatus returns the exit status for the outcome of one file
+func exitStatus(result *pdfex.BatchResult) int {
+	if result.Err == nil {
+		if result.Recovered {
+			return exitRecovered
+		}
+		return exitOK
+	}
+	return errorStatus(result.Err, exitError)
+}
+
+// errorStatus returns the exit status for an error parsing a document or writing an output,
+// or other for an error of no kind with a status of its own
+func errorStatus(err error, other int) int {
+	switch pdfex.KindOf(err) {
+	case pdfex.ErrorKindNotPDF:
+		return exitNotPDF
+	case pdfex.ErrorKindEncrypted:
+		return exitEncrypted
+	case pdfex.ErrorKindIO:
+		return exitIO
+	}
+	return other
+}
+
+// commandStatus returns the exit status of a subcommand that failed with err
+func commandStatus(err error) int {
+	return errorStatus(err, exitUsage)
+}
+
+// batchExitStatus returns the exit status of a batch: that of its failures if they are all of
+// one kind, exitError if they are of several, or else exitRecovered if any file needed
+// recovery
+func batchExitStatus(summary *pdfex.BatchSummary) int {
+	status := exitOK
+	for i := range summary.Results {
+		code := exitStatus(&summary.Results[i])
+		switch {
+		case code == exitOK || code == status:
+		case code == exitRecovered:
+			if status == exitOK {
+				status = exitRecovered
+			}
+		case status == exitOK || status == exitRecovered:
+			status = code
+		default:
+			status = exitError
+		}
+	}
+	return status
+}
+
+// writeReport writes the per-file results of a run as the JSON report selected by -report
+func writeReport(w io.Writer, summary *pdfex.BatchSummary) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(summary)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// runForms implements "pdfex forms [-values] [-o fields.json] <pdf_file>", which exports the
+// fields of an interactive form as JSON
+func runForms(args []string) int {
+	fs := flag.NewFlagSet("forms", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	valuesOnly := fs.Bool("values", false, "Export only the filled-in values, keyed by field name")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex forms [-values] [-o fields.json] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	err = common.writeOutput(func(w io.Writer) error {
+		if *valuesOnly {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(doc.FormValues())
+		}
+		return doc.WriteFormFields(w)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing form fields: %v\n", err)
+		return commandStatus(err)
+	}
+	return 0
+}
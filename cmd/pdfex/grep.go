@@ -0,0 +1,441 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/pdfex/internal/utils"
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// ANSI colour sequences used for grep output, matching GNU grep's defaults
+const (
+	colorFile  = "\033[35m"
+	colorPage  = "\033[32m"
+	colorLine  = "\033[32m"
+	colorMatch = "\033[1;31m"
+	colorSep   = "\033[36m"
+	colorReset = "\033[0m"
+)
+
+// grepResult holds the formatted output for one file
+type grepResult struct {
+	lines   []string
+	matches []grepMatch // Matches with their context, for JSON output
+	matched bool
+	err     error
+}
+
+// grepMatch is a matching line in the JSON output
+type grepMatch struct {
+	File   string   `json:"file"`
+	Page   int      `json:"page"`
+	Line   int      `json:"line"` // 1-based line number on the page
+	Text   string   `json:"text"`
+	Before []string `json:"before,omitempty"` // Context lines before the match
+	After  []string `json:"after,omitempty"`  // Context lines after the match
+}
+
+// grepPrinter formats matching lines with optional colour
+type grepPrinter struct {
+	regex   *regexp.Regexp
+	color   bool
+	context int
+	json    bool
+	common  *commonFlags     // Parse options shared with the other commands
+	cache   *pdfex.TextCache // Text of the files searched before, nil to parse every file
+	overlay string           // Overlay format to write for files with matches, if any
+}
+
+// runGrep implements "pdfex grep [options] <pattern> <file_or_dir>...", or with -e pattern. Each
+// matching line is printed as file:page:line:text, like grep -n. The text of each file is kept in
+// the text cache, so searching the same files again only parses the ones that changed. The exit
+// status is 0 if a line matched, 1 if none did and 2, 3, 4 or 6 on error.
+func runGrep(args []string) int {
+	flags := flag.NewFlagSet("grep", flag.ExitOnError)
+	common := addCommonFlags(flags)
+	pattern := flags.String("e", "", "Regular expression to search for, instead of the first argument")
+	recursive := flags.Bool("r", false, "Search directories recursively")
+	ignoreCase := flags.Bool("i", false, "Ignore case distinctions")
+	fixed := flags.Bool("F", false, "Search for the pattern as a fixed string, not a regular expression")
+	contextLines := flags.Int("C", 0, "Print `N` lines of context around each match")
+	jsonOutput := flags.Bool("json", false, "Print the matches with their context as JSON")
+	colorMode := flags.String("color", "auto", "Highlight matches: auto, always or never")
+	workers := flags.Int("j", runtime.NumCPU(), "Number of files to search in parallel, 0 for one per CPU")
+	cacheDir := flags.String("cache", "", "Keep the text of searched files in this directory (default: pdfex/text in the user cache directory)")
+	noCache := flags.Bool("no-cache", false, "Parse every file instead of using the text cache")
+	overlay := flags.String("overlay", "", "Also write highlight rectangles of the matches next to each file: xfdf or json")
+
+	flags.Usage = func() {
+		fmt.Println("Usage: pdfex grep [options] <pattern> <file_or_dir>...")
+		fmt.Println("       pdfex grep [options] -e pattern <file_or_dir>...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	paths := flags.Args()
+	if *pattern == "" && len(paths) > 0 {
+		*pattern, paths = paths[0], paths[1:]
+	}
+	if *pattern == "" || len(paths) < 1 {
+		flags.Usage()
+		return 2
+	}
+
+	expr := *pattern
+	if *fixed {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if *ignoreCase {
+		expr = "(?i)" + expr
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid pattern: %v\n", err)
+		return 2
+	}
+
+	var color bool
+	switch *colorMode {
+	case "always":
+		color = true
+	case "never":
+		color = false
+	case "auto":
+		color = (*common.output == "" || *common.output == "-") && isTerminal(os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown colour mode %q\n", *colorMode)
+		return 2
+	}
+
+	if *overlay != "" && *overlay != "xfdf" && *overlay != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unknown overlay format %q\n", *overlay)
+		return 2
+	}
+
+	files, err := collectPDFFiles(paths, *recursive)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	// JSON is never coloured
+	printer := &grepPrinter{regex: regex, color: color && !*jsonOutput, context: *contextLines, json: *jsonOutput, common: common, overlay: *overlay}
+	if !*noCache {
+		printer.cache = openGrepCache(*cacheDir)
+	}
+	results := make([]grepResult, len(files))
+
+	if *workers < 1 {
+		*workers = runtime.NumCPU()
+	}
+	if *workers > len(files) {
+		*workers = len(files)
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < *workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = printer.searchFile(files[i])
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Print in input order so output is deterministic
+	status := 1
+	matches := []grepMatch{}
+	for i, result := range results {
+		matches = append(matches, result.matches...)
+		if result.matched && status == 1 {
+			status = 0
+		}
+		if result.err != nil {
+			fmt.Fprintf(os.Stderr, "pdfex grep: %s: %v\n", files[i], result.err)
+			status = commandStatus(result.err)
+		}
+	}
+
+	if *jsonOutput {
+		err = common.writeOutput(func(w io.Writer) error {
+			return writeJSON(w, matches)
+		})
+	} else {
+		err = common.printOutput(func(w io.Writer) {
+			for _, result := range results {
+				for _, line := range result.lines {
+					fmt.Fprintln(w, line)
+				}
+			}
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing matches: %v\n", err)
+		return commandStatus(err)
+	}
+
+	return status
+}
+
+// collectPDFFiles expands the command line arguments into a list of PDF files
+func collectPDFFiles(args []string, recursive bool) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		// "-" is standard input
+		if arg == "-" {
+			files = append(files, arg)
+			continue
+		}
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+
+		if !recursive {
+			return nil, fmt.Errorf("%s is a directory (use -r to search it)", arg)
+		}
+
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".pdf") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// openGrepCache opens the text cache in dir, or in the default directory if dir is empty. Without
+// a usable cache the files are parsed on every search, so a failure is only a warning.
+func openGrepCache(dir string) *pdfex.TextCache {
+	if dir == "" {
+		var err error
+		if dir, err = pdfex.DefaultTextCacheDir(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: no text cache: %v\n", err)
+			return nil
+		}
+	}
+	cache, err := pdfex.OpenTextCache(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: no text cache: %v\n", err)
+		return nil
+	}
+	return cache
+}
+
+// searchFile searches the text of a PDF and returns the formatted matching lines of each page
+func (g *grepPrinter) searchFile(filename string) grepResult {
+	pages, err := g.searchText(filename)
+	if err != nil {
+		return grepResult{err: err}
+	}
+	// The positional search finds the lines of each page the matches start on
+	matches, err := pdfex.FindTextIn(pages, g.regex.String())
+	if err != nil {
+		return grepResult{err: err}
+	}
+	matchedLines := make(map[int]map[int]bool)
+	for _, match := range matches {
+		if matchedLines[match.Page] == nil {
+			matchedLines[match.Page] = make(map[int]bool)
+		}
+		matchedLines[match.Page][match.Line] = true
+	}
+
+	var result grepResult
+	for _, page := range pages {
+		if len(matchedLines[page.Page]) > 0 {
+			g.searchPage(&result, filename, page, matchedLines[page.Page])
+		}
+	}
+
+	if result.matched && g.overlay != "" {
+		if err := g.writeOverlay(filename); err != nil {
+			result.err = err
+		}
+	}
+
+	return result
+}
+
+// searchText returns the page text of a PDF, from the text cache if the file is in it. Standard
+// input is always parsed, as it can't be read again to look it up.
+func (g *grepPrinter) searchText(filename string) ([]pdfex.PageText, error) {
+	if g.cache == nil || filename == "-" {
+		doc, err := g.common.open(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer doc.Close()
+		return doc.SearchText(), nil
+	}
+
+	utils.SetLogWriter(os.Stderr)
+	pages, err := g.cache.Text(filename, g.common.parseOptions())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing PDF: %w", err)
+	}
+	return pages, nil
+}
+
+// writeOverlay writes the highlight rectangles of the matches in a file to an overlay file
+// next to it, e.g. report.pdf.xfdf. The rectangles need the text positions, which the cache
+// doesn't keep, so the file is parsed again.
+func (g *grepPrinter) writeOverlay(filename string) error {
+	if filename == "-" {
+		return fmt.Errorf("no overlay can be written next to standard input")
+	}
+	doc, err := g.common.open(filename)
+	if err != nil {
+		return err
+	}
+	defer doc.Close()
+	highlights, err := doc.HighlightMatches(g.regex.String())
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(filename + "." + g.overlay)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if g.overlay == "xfdf" {
+		return pdfex.WriteXFDF(out, filename, highlights)
+	}
+	return pdfex.WriteHighlightsJSON(out, filename, highlights)
+}
+
+// searchPage appends the matching lines of a page, with context, to the result. matched holds
+// the 1-based numbers of the lines that matches start on.
+func (g *grepPrinter) searchPage(result *grepResult, filename string, page pdfex.PageText, matched map[int]bool) {
+	lines := strings.Split(page.Text, "\n")
+	pageNum := page.Page
+	lastPrinted := -1
+
+	for n := range lines {
+		if !matched[n+1] {
+			continue
+		}
+		result.matched = true
+
+		if g.json {
+			result.matches = append(result.matches, g.newMatch(filename, pageNum, lines, n))
+			continue
+		}
+
+		start := n - g.context
+		if start <= lastPrinted {
+			start = lastPrinted + 1
+		}
+		if start < 0 {
+			start = 0
+		}
+		// Separate non-adjacent context groups, including groups on different pages
+		if g.context > 0 && len(result.lines) > 0 && (lastPrinted < 0 || start > lastPrinted+1) {
+			result.lines = append(result.lines, g.paint(colorSep, "--"))
+		}
+
+		end := n + g.context
+		if end >= len(lines) {
+			end = len(lines) - 1
+		}
+		for c := start; c <= end; c++ {
+			if c > n && matched[c+1] {
+				// Later matches print their own context
+				end = c - 1
+				break
+			}
+			result.lines = append(result.lines, g.formatLine(filename, pageNum, c+1, lines[c], c == n))
+		}
+		lastPrinted = end
+	}
+}
+
+// newMatch returns the JSON form of the match on line n of a page, with its context lines
+func (g *grepPrinter) newMatch(filename string, pageNum int, lines []string, n int) grepMatch {
+	if filename == "-" {
+		filename = "(standard input)"
+	}
+	match := grepMatch{File: filename, Page: pageNum, Line: n + 1, Text: lines[n]}
+
+	start := n - g.context
+	if start < 0 {
+		start = 0
+	}
+	end := n + g.context + 1
+	if end > len(lines) {
+		end = len(lines)
+	}
+	if start < n {
+		match.Before = lines[start:n]
+	}
+	if n+1 < end {
+		match.After = lines[n+1 : end]
+	}
+	return match
+}
+
+// formatLine formats a line as file:page:line:text, or file-page-line-text for context lines
+func (g *grepPrinter) formatLine(filename string, pageNum, lineNum int, line string, match bool) string {
+	if filename == "-" {
+		filename = "(standard input)"
+	}
+	sep := "-"
+	if match {
+		sep = ":"
+		if g.color {
+			line = g.regex.ReplaceAllStringFunc(line, func(s string) string {
+				return g.paint(colorMatch, s)
+			})
+		}
+	}
+
+	return g.paint(colorFile, filename) + g.paint(colorSep, sep) +
+		g.paint(colorPage, fmt.Sprintf("p%d", pageNum)) + g.paint(colorSep, sep) +
+		g.paint(colorLine, fmt.Sprintf("l%d", lineNum)) + g.paint(colorSep, sep) + line
+}
+
+// paint wraps text in a colour sequence when colour output is enabled
+func (g *grepPrinter) paint(color, s string) string {
+	if !g.color || s == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// isTerminal reports whether the file is an interactive terminal
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// runImages implements "pdfex images [-json] [-extract dir] [-thumbnails dir [-preview]] [options]
+// <pdf_file>", which lists the images drawn on each page and optionally saves them and the
+// page thumbnails
+func runImages(args []string) int {
+	fs := flag.NewFlagSet("images", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	jsonOutput := fs.Bool("json", false, "List the images as JSON")
+	extract := fs.String("extract", "", "Save the images to this directory as JPEG or PNG files")
+	thumbnails := fs.String("thumbnails", "", "Save the page thumbnails to this directory as PNG files")
+	preview := fs.Bool("preview", false, "With -thumbnails, save a preview built from the page images for pages without a thumbnail")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex images [-json] [-extract dir] [-thumbnails dir [-preview]] [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+	images := doc.GetImages()
+
+	err = common.writeOutput(func(w io.Writer) error {
+		if *jsonOutput {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(images)
+		}
+		for _, image := range images {
+			line := fmt.Sprintf("page %d\t%s\tobject %d\t%dx%d\t%s\t%s\t[%.2f %.2f %.2f %.2f]\n",
+				image.Page, image.Name, image.Object, image.Width, image.Height, image.ColorSpace,
+				image.Filter, image.Bounds.X1, image.Bounds.Y1, image.Bounds.X2, image.Bounds.Y2)
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing image list: %v\n", err)
+		return commandStatus(err)
+	}
+
+	status := 0
+	base := inputBase(fs.Arg(0))
+	if *thumbnails != "" {
+		if status = saveThumbnails(doc, *thumbnails, base, *preview); status == 2 {
+			return status
+		}
+	}
+
+	if *extract == "" {
+		return status
+	}
+	if err := os.MkdirAll(*extract, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", *extract, err)
+		return commandStatus(err)
+	}
+	for _, image := range images {
+		mime, data, err := doc.ImageData(image)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping image: %v\n", err)
+			status = 1
+			continue
+		}
+		ext := ".png"
+		if mime == "image/jpeg" {
+			ext = ".jpg"
+		}
+		name := fmt.Sprintf("%s-p%d-%s%s", base, image.Page, strings.TrimPrefix(image.Name, "/"), ext)
+		if err := os.WriteFile(filepath.Join(*extract, name), data, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", name, err)
+			return commandStatus(err)
+		}
+	}
+	return status
+}
+
+// saveThumbnails saves the thumbnail of each selected page to dir as a PNG file, or with preview
+// a preview built from the page images for pages without one. It returns the exit status.
+func saveThumbnails(doc *pdfex.PDFDocument, dir, base string, preview bool) int {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", dir, err)
+		return commandStatus(err)
+	}
+	status := 0
+	for pageNum := 1; pageNum <= doc.PageCount(); pageNum++ {
+		if !doc.PageSelected(pageNum) {
+			continue
+		}
+		var img image.Image
+		var err error
+		if preview {
+			img, err = doc.GetPagePreview(pageNum, 0)
+		} else {
+			img, err = doc.GetPageThumbnail(pageNum)
+		}
+		if errors.Is(err, pdfex.ErrNoThumbnail) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping thumbnail: %v\n", err)
+			status = 1
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping thumbnail of page %d: %v\n", pageNum, err)
+			status = 1
+			continue
+		}
+		name := fmt.Sprintf("%s-p%d-thumb.png", base, pageNum)
+		if err := os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", name, err)
+			return commandStatus(err)
+		}
+	}
+	return status
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/metrics"
+	"github.com/yourusername/pdfex/internal/utils"
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// runInfo implements "pdfex info [-fast] [-ocr] [--compare old.json] <pdf_file>". When comparing, the
+// exit status is 0 if the structure is unchanged, 1 if it changed and 2, 3, 4 or 6 on error, like diff.
+func runInfo(args []string) int {
+	fs := flag.NewFlagSet("info", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	compare := fs.String("compare", "", "Compare with metrics previously saved by -json and report what changed")
+	jsonOutput := fs.Bool("json", false, "Print the comparison as JSON")
+	fast := fs.Bool("fast", false, "Only read the version, page count, encryption and producer, without parsing the document")
+	ocr := fs.Bool("ocr", false, "Classify the pages and list the scanned ones without a text layer, which need OCR")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex info [-fast] [-ocr] [--compare old.json] [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	if *fast {
+		return printFastInfo(common, fs.Arg(0))
+	}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	if *compare == "" {
+		err = common.printOutput(func(w io.Writer) {
+			printBasicInfo(w, doc)
+			if *ocr {
+				printOCRPages(w, doc)
+			}
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing info: %v\n", err)
+			return commandStatus(err)
+		}
+		return 0
+	}
+
+	data, err := os.ReadFile(*compare)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", *compare, err)
+		return commandStatus(err)
+	}
+	old, err := metrics.ParseJSON(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", *compare, err)
+		return 2
+	}
+
+	delta := doc.Metrics().Compare(old)
+	err = common.writeOutput(func(w io.Writer) error {
+		if *jsonOutput {
+			return writeJSON(w, delta)
+		}
+		_, err := io.WriteString(w, delta.HumanReadableFormat())
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing comparison: %v\n", err)
+		return commandStatus(err)
+	}
+
+	if delta.Changed() {
+		return 1
+	}
+	return 0
+}
+
+// printFastInfo prints what GetPDFInfo reads without parsing the document, reading standard
+// input when the name is "-"
+func printFastInfo(common *commonFlags, filename string) int {
+	utils.SetLogWriter(os.Stderr)
+	utils.SetLogLevel(common.parseOptions().LogLevel)
+	var info *pdfex.PDFInfo
+	var err error
+	if filename == "-" {
+		info, err = pdfex.GetPDFInfoFromReader(os.Stdin, stdinName)
+	} else {
+		info, err = pdfex.GetPDFInfo(filename)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading PDF: %v\n", err)
+		return commandStatus(err)
+	}
+
+	err = common.printOutput(func(w io.Writer) {
+		fmt.Fprintf(w, "PDF Version: %s\n", info.Version)
+		if info.PageCount >= 0 {
+			fmt.Fprintf(w, "Number of pages: %d\n", info.PageCount)
+		} else {
+			fmt.Fprintln(w, "Number of pages: unknown")
+		}
+		fmt.Fprintf(w, "Encrypted: %v\n", info.Encrypted)
+		if info.Producer != "" {
+			fmt.Fprintf(w, "Producer: %s\n", info.Producer)
+		}
+		fmt.Fprintf(w, "File size: %d bytes\n", info.FileSize)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing info: %v\n", err)
+		return commandStatus(err)
+	}
+	return 0
+}
+
+// printOCRPages prints the pages that are scanned images without a text layer
+func printOCRPages(w io.Writer, doc *pdfex.PDFDocument) {
+	var pages []string
+	for _, class := range doc.ClassifyPages() {
+		if class.ImageOnly {
+			pages = append(pages, fmt.Sprintf("%d (%.0f%% image)", class.Page, class.ImageCoverage*100))
+		}
+	}
+	if len(pages) == 0 {
+		fmt.Fprintln(w, "Pages needing OCR: none")
+		return
+	}
+	fmt.Fprintf(w, "Pages needing OCR: %s\n", strings.Join(pages, ", "))
+}
@@ -3,68 +3,327 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"runtime"
 	"strings"
+	"time"
 
+	"github.com/yourusername/pdfex/internal/metrics"
 	"github.com/yourusername/pdfex/internal/utils"
 	"github.com/yourusername/pdfex/pkg/pdfex"
 )
 
+// subcommands lists the commands dispatched by main
+const subcommands = "text, info, images, meta, chunks, validate, objects, dump, watch, split, grep, sanitize, security, redaction-check, reorder-check, manifest, forms, diff, revisions"
+
 func main() {
-	// Define command line flags
-	verbose := flag.Bool("v", false, "Enable verbose output (sets log level to INFO)")
-	debug := flag.Bool("debug", false, "Enable debug output (sets log level to DEBUG)")
+	// Dispatch subcommands before parsing the global flags
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "text":
+			os.Exit(runText(os.Args[2:]))
+		case "images":
+			os.Exit(runImages(os.Args[2:]))
+		case "meta":
+			os.Exit(runMeta(os.Args[2:]))
+		case "chunks":
+			os.Exit(runChunks(os.Args[2:]))
+		case "validate":
+			os.Exit(runValidate(os.Args[2:]))
+		case "objects":
+			os.Exit(runObjects(os.Args[2:]))
+		case "dump":
+			os.Exit(runDump(os.Args[2:]))
+		case "watch":
+			os.Exit(runWatch(os.Args[2:]))
+		case "split":
+			os.Exit(runSplit(os.Args[2:]))
+		case "grep":
+			os.Exit(runGrep(os.Args[2:]))
+		case "info":
+			os.Exit(runInfo(os.Args[2:]))
+		case "sanitize":
+			os.Exit(runSanitize(os.Args[2:]))
+		case "security":
+			os.Exit(runSecurity(os.Args[2:]))
+		case "redaction-check":
+			os.Exit(runRedactionCheck(os.Args[2:]))
+		case "reorder-check":
+			os.Exit(runReorderCheck(os.Args[2:]))
+		case "manifest":
+			os.Exit(runManifest(os.Args[2:]))
+		case "forms":
+			os.Exit(runForms(os.Args[2:]))
+		case "diff":
+			os.Exit(runDiff(os.Args[2:]))
+		case "revisions":
+			os.Exit(runRevisions(os.Args[2:]))
+		}
+	}
+
+	// Define command line flags; the outputs are selected by flags of their own, so -o isn't one
+	common := addInputFlags(flag.CommandLine)
 	statsOutput := flag.String("stats", "", "Output statistics in human-readable format to the specified file")
-	jsonOutput := flag.String("json", "", "Output statistics in JSON format to the specified file")
+	jsonOutput := flag.String("json", "", "Output statistics in JSON format to the specified file (with several files, the batch summary)")
+	chunkSize := flag.Int("chunk-size", 1000, "Maximum length of text chunks in characters")
+	chunkOverlap := flag.Int("chunk-overlap", 0, "Characters repeated from the end of each chunk at the start of the next")
+	chunkBy := flag.String("chunk-by", "paragraph", "Where text chunks end: paragraph, sentence, page or heading")
+	htmlOutput := flag.String("html", "", "Export the document as HTML to the specified file")
+	htmlPositioned := flag.Bool("html-positioned", false, "Place HTML text and images as on the page instead of flowing them")
+	markdownOutput := flag.String("markdown", "", "Export the document as Markdown to the specified file")
+	exportOutput := flag.String("export-json", "", "Export the document structure as JSON to the specified file")
+	altoOutput := flag.String("alto", "", "Export the document text as ALTO XML to the specified file")
+	csvOutput := flag.String("csv", "", "Export one row per word to the specified CSV file (tab-separated if it ends in .tsv)")
+	csvSpans := flag.Bool("csv-spans", false, "Write one CSV row per text span instead of per word")
+	recursive := flag.Bool("r", false, "Process the PDFs in directories recursively")
+	jobs := flag.Int("jobs", runtime.NumCPU(), "Number of files to process in parallel when given several")
+	metricsCSVOutput := flag.String("metrics-csv", "", "Write a CSV row of metrics for each file processed to the specified file")
+	appendMetrics := flag.Bool("append", false, "Append the -metrics-csv rows to the file instead of replacing it")
+	anomalies := flag.Float64("anomalies", 0, "With several files, report those whose parse time, object density or filter usage is this many standard deviations from the mean")
+	report := flag.String("report", "", "Print a per-file success/failure report in this format (json) on stdout; other output goes to stderr")
 
 	// Parse command line flags
 	flag.Parse()
 
-	// Set log level based on flags
-	if *debug {
+	// Set log level based on flags. Log messages go to stderr, as those of the commands do.
+	utils.SetLogWriter(os.Stderr)
+	if *common.debug {
 		utils.SetLogLevel(utils.LogDebug)
-	} else if *verbose {
+	} else if *common.verbose {
 		utils.SetLogLevel(utils.LogInfo)
 	}
 
 	// Check if a PDF file was specified
 	if flag.NArg() < 1 {
-		fmt.Println("Usage: pdfex [options] <pdf_file>")
+		fmt.Println("Usage: pdfex <command> [options] <pdf_file>")
+		fmt.Println("       pdfex [options] <pdf_file_or_directory>...")
+		fmt.Println()
+		fmt.Println("Commands: " + subcommands)
+		fmt.Println("Run pdfex <command> -h for the options of a command. Without a command, pdfex")
+		fmt.Println("prints a summary and writes the outputs selected by these options:")
 		flag.PrintDefaults()
-		os.Exit(1)
+		os.Exit(exitUsage)
+	}
+
+	if *report != "" && *report != "json" {
+		fmt.Printf("Error: unknown report format %q\n", *report)
+		os.Exit(exitUsage)
+	}
+	// The report is the only thing written to stdout, so it can be piped to a monitor
+	reportOut := os.Stdout
+	if *report != "" {
+		os.Stdout = os.Stderr
 	}
 
 	filename := flag.Arg(0)
 
-	// Parse the PDF file
-	doc, err := pdfex.ParsePDF(filename)
+	options := pdfex.DefaultParseOptions()
+	options.LogLevel = utils.GetLogLevel()
+	options.VerifyXRef = *common.verifyXRef
+	options.PageRange = *common.pages
+
+	chunkSplit, err := pdfex.ParseChunkSplit(*chunkBy)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	chunkOptions := &pdfex.ChunkOptions{MaxSize: *chunkSize, Overlap: *chunkOverlap, SplitOn: chunkSplit}
+
+	// Metrics rows are streamed to the CSV file as each document is parsed, and collected for
+	// the anomaly report
+	var metricsCSV *metrics.MetricsCollection
+	closeMetricsCSV := func() error { return nil }
+	if *appendMetrics && *metricsCSVOutput == "" {
+		fmt.Println("Error: -append needs -metrics-csv")
+		os.Exit(exitUsage)
+	}
+	if *metricsCSVOutput != "" {
+		metricsCSV, closeMetricsCSV, err = openMetricsCSV(*metricsCSVOutput, *appendMetrics)
+		if err != nil {
+			fmt.Printf("Error opening %s: %v\n", *metricsCSVOutput, err)
+			os.Exit(exitIO)
+		}
+	}
+
+	// Several files, or a directory, are processed in parallel
+	if info, err := os.Stat(filename); flag.NArg() > 1 || (err == nil && info.IsDir()) {
+		for _, name := range singleFileFlags {
+			if flagSet(name) {
+				fmt.Printf("Error: -%s takes a single PDF file\n", name)
+				os.Exit(exitUsage)
+			}
+		}
+		files, err := collectPDFFiles(flag.Args(), *recursive)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		if *anomalies > 0 && metricsCSV == nil {
+			metricsCSV = metrics.NewMetricsCollection()
+		}
+		summary, summaryErr := runBatch(files, &pdfex.BatchOptions{Jobs: *jobs, ParseOptions: options}, chunkOptions, *jsonOutput, metricsCSV)
+		if err := closeMetricsCSV(); err != nil && summaryErr == nil {
+			summaryErr = fmt.Errorf("error writing metrics to %s: %v", *metricsCSVOutput, err)
+		}
+		if *anomalies > 0 {
+			fmt.Println()
+			fmt.Print(metricsCSV.FindAnomalies(*anomalies).HumanReadableFormat())
+		}
+		if *report != "" {
+			if err := writeReport(reportOut, summary); err != nil {
+				fmt.Printf("Error writing report: %v\n", err)
+				os.Exit(exitIO)
+			}
+		}
+		if summaryErr != nil {
+			fmt.Printf("Error: %v\n", summaryErr)
+			os.Exit(exitIO)
+		}
+		os.Exit(batchExitStatus(summary))
+	}
+
+	if *anomalies > 0 {
+		fmt.Println("Error: -anomalies compares several files")
+		os.Exit(exitUsage)
+	}
+
+	// The outcome of the file, for the exit status and the report
+	startTime := time.Now()
+	result := &pdfex.BatchResult{File: filename}
+	finish := func() {
+		result.Duration = time.Since(startTime)
+		if err := closeMetricsCSV(); err != nil {
+			fmt.Printf("Error writing metrics to %s: %v\n", *metricsCSVOutput, err)
+			if result.Err == nil {
+				result.SetErr(err)
+			}
+		}
+		if *report != "" {
+			summary := &pdfex.BatchSummary{Files: 1, Duration: result.Duration, Results: []pdfex.BatchResult{*result}}
+			if result.Err == nil {
+				summary.Succeeded, summary.Pages = 1, result.Pages
+				if result.Recovered {
+					summary.Recovered = 1
+				}
+			} else {
+				summary.Failed = 1
+			}
+			if err := writeReport(reportOut, summary); err != nil {
+				fmt.Printf("Error writing report: %v\n", err)
+				os.Exit(exitIO)
+			}
+		}
+		os.Exit(exitStatus(result))
+	}
+	// Outputs that fail don't stop the others, but the first failure is reported
+	outputFailed := func(err error) {
+		if result.Err == nil {
+			result.SetErr(err)
+		}
+	}
+
+	// Parse the PDF file, or standard input for "-"
+	doc, err := parseInput(filename, options)
 	if err != nil {
 		fmt.Printf("Error parsing PDF: %v\n", err)
-		os.Exit(1)
+		result.SetErr(err)
+		finish()
 	}
+	result.Pages = doc.PageCount()
+	result.Recovered = doc.DegradationReport().Degraded()
+	if metricsCSV != nil {
+		metricsCSV.Add(doc.Metrics())
+	}
+
+	printBasicInfo(os.Stdout, doc)
 
-	// Print basic info
-	fmt.Printf("PDF Version: %s\n", doc.Version())
-	fmt.Printf("Number of objects: %d\n", doc.ObjectCount())
-	fmt.Printf("Number of pages: %d\n", doc.PageCount())
-	fmt.Printf("Number of fonts: %d\n", doc.FontCount())
-	fmt.Printf("Number of text chunks: %d\n", doc.TextChunkCount())
+	// The text of encrypted documents can't be decoded, so nothing is written
+	if doc.Encrypted() {
+		fmt.Println("Error: the document is encrypted; nothing was extracted")
+		result.SetErr(pdfex.ErrEncrypted)
+		finish()
+	}
 
 	// Output chunks to a file
-	chunksFile := strings.TrimSuffix(filename, ".pdf") + "_chunks.txt"
-	err = doc.SaveChunksToFile(chunksFile)
+	chunksFile := chunksFileName(filename)
+	err = writeChunks(doc, chunksFile, chunkOptions)
 	if err != nil {
 		fmt.Printf("Error saving chunks: %v\n", err)
+		outputFailed(err)
 	} else {
 		fmt.Printf("Chunks saved to %s\n", chunksFile)
 	}
 
+	// Export HTML if requested
+	if *htmlOutput != "" {
+		err = writeHTML(doc, *htmlOutput, &pdfex.HTMLOptions{Positioned: *htmlPositioned})
+		if err != nil {
+			fmt.Printf("Error writing HTML to %s: %v\n", *htmlOutput, err)
+			outputFailed(err)
+		} else {
+			fmt.Printf("HTML saved to %s\n", *htmlOutput)
+		}
+	}
+
+	// Export Markdown if requested
+	if *markdownOutput != "" {
+		markdown, err := doc.ExportMarkdown()
+		if err == nil {
+			err = os.WriteFile(*markdownOutput, []byte(markdown), 0644)
+		}
+		if err != nil {
+			fmt.Printf("Error writing Markdown to %s: %v\n", *markdownOutput, err)
+			outputFailed(err)
+		} else {
+			fmt.Printf("Markdown saved to %s\n", *markdownOutput)
+		}
+	}
+
+	// Export the document structure if requested
+	if *exportOutput != "" {
+		err = writeStructuredExport(doc, *exportOutput)
+		if err != nil {
+			fmt.Printf("Error writing document structure to %s: %v\n", *exportOutput, err)
+			outputFailed(err)
+		} else {
+			fmt.Printf("Document structure saved to %s\n", *exportOutput)
+		}
+	}
+
+	// Export ALTO XML if requested
+	if *altoOutput != "" {
+		err = writeALTO(doc, *altoOutput)
+		if err != nil {
+			fmt.Printf("Error writing ALTO XML to %s: %v\n", *altoOutput, err)
+			outputFailed(err)
+		} else {
+			fmt.Printf("ALTO XML saved to %s\n", *altoOutput)
+		}
+	}
+
+	// Export words or spans as CSV if requested
+	if *csvOutput != "" {
+		csvOptions := &pdfex.CSVOptions{Spans: *csvSpans}
+		if strings.HasSuffix(strings.ToLower(*csvOutput), ".tsv") {
+			csvOptions.Delimiter = '\t'
+		}
+		err = writeCSV(doc, *csvOutput, csvOptions)
+		if err != nil {
+			fmt.Printf("Error writing CSV to %s: %v\n", *csvOutput, err)
+			outputFailed(err)
+		} else {
+			fmt.Printf("CSV saved to %s\n", *csvOutput)
+		}
+	}
+
 	// Output statistics if requested
 	if *statsOutput != "" {
 		statsContent := doc.Metrics().HumanReadableFormat()
 		err = os.WriteFile(*statsOutput, []byte(statsContent), 0644)
 		if err != nil {
 			fmt.Printf("Error writing statistics to %s: %v\n", *statsOutput, err)
+			outputFailed(err)
 		} else {
 			fmt.Printf("Statistics saved to %s\n", *statsOutput)
 		}
@@ -74,10 +333,12 @@ func main() {
 		jsonContent, err := doc.Metrics().JSONFormat()
 		if err != nil {
 			fmt.Printf("Error generating JSON: %v\n", err)
+			outputFailed(err)
 		} else {
 			err = os.WriteFile(*jsonOutput, jsonContent, 0644)
 			if err != nil {
 				fmt.Printf("Error writing JSON to %s: %v\n", *jsonOutput, err)
+				outputFailed(err)
 			} else {
 				fmt.Printf("JSON statistics saved to %s\n", *jsonOutput)
 			}
@@ -104,4 +365,104 @@ func main() {
 			fmt.Println(doc.Metrics().HumanReadableFormat())
 		}
 	}
+
+	finish()
+}
+
+// flagSet reports whether a global flag was given on the command line
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// printBasicInfo prints a summary of the document structure
+func printBasicInfo(w io.Writer, doc *pdfex.PDFDocument) {
+	fmt.Fprintf(w, "PDF Version: %s\n", doc.Version())
+	fmt.Fprintf(w, "Number of objects: %d\n", doc.ObjectCount())
+	fmt.Fprintf(w, "Number of pages: %d\n", doc.PageCount())
+	fmt.Fprintf(w, "Number of fonts: %d\n", doc.FontCount())
+	fmt.Fprintf(w, "Number of text chunks: %d\n", doc.TextChunkCount())
+
+	// Report how the document was recovered, if it was
+	report := doc.DegradationReport()
+	if report.Degraded() || report.Verified {
+		fmt.Fprintf(w, "Recovery: %s\n", report.Summary())
+	}
+	recovery := doc.RecoveryReport()
+	for _, step := range recovery.Steps {
+		fmt.Fprintf(w, "  %s: %s\n", step.Heuristic, step.Detail)
+	}
+	if recovery.Repaired() {
+		fmt.Fprintf(w, "  recovered objects: %d, lost objects: %d\n", len(recovery.Recovered), len(recovery.Lost))
+	}
+}
+
+// writeChunks writes the text chunks of a document to a JSON Lines file
+func writeChunks(doc *pdfex.PDFDocument, filename string, options *pdfex.ChunkOptions) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := doc.WriteChunksJSONL(file, options); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeHTML exports a document as HTML to a file
+func writeHTML(doc *pdfex.PDFDocument, filename string, options *pdfex.HTMLOptions) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := doc.ExportHTML(file, options); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeStructuredExport exports the structure of a document as JSON to a file
+func writeStructuredExport(doc *pdfex.PDFDocument, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := doc.ExportJSON(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeALTO exports the text of a document as ALTO XML to a file
+func writeALTO(doc *pdfex.PDFDocument, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := doc.ExportALTO(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeCSV exports the words or spans of a document as delimited rows to a file
+func writeCSV(doc *pdfex.PDFDocument, filename string, options *pdfex.CSVOptions) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := doc.ExportCSV(file, options); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
 }
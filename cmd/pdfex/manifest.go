@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// runManifest implements "pdfex manifest [-o manifest.json] [-columns n] [-pages range]
+// <pdf_file>", which writes the checksum manifest of a document for archival pipelines
+func runManifest(args []string) int {
+	fs := flag.NewFlagSet("manifest", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	columns := fs.Int("columns", pdfex.ColumnsOff, "Column layout used for the text hashes: 0 (off), -1 (auto) or a column count")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex manifest [-o manifest.json] [-columns n] [-pages range] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	options := common.parseOptions()
+	options.Columns = *columns
+	doc, err := common.openWith(fs.Arg(0), options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	err = common.writeOutput(func(w io.Writer) error {
+		return doc.WriteManifest(w)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing manifest: %v\n", err)
+		return commandStatus(err)
+	}
+	return 0
+}
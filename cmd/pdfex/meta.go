@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// runMeta implements "pdfex meta [-xmp|-info] [options] <pdf_file>", which writes the document
+// metadata as JSON, the typed document information as JSON, or the raw XMP packet
+func runMeta(args []string) int {
+	fs := flag.NewFlagSet("meta", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	xmp := fs.Bool("xmp", false, "Write the raw XMP packet instead of JSON")
+	info := fs.Bool("info", false, "Write the document information with parsed dates instead")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex meta [-xmp|-info] [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+	meta := doc.Metadata()
+	if *xmp && meta.XMP == nil {
+		fmt.Fprintln(os.Stderr, "Error: the document has no XMP metadata")
+		return 1
+	}
+
+	err = common.writeOutput(func(w io.Writer) error {
+		if *xmp {
+			_, err := w.Write(meta.XMP)
+			return err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if *info {
+			return encoder.Encode(doc.DocumentInfo())
+		}
+		return encoder.Encode(meta)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing metadata: %v\n", err)
+		return commandStatus(err)
+	}
+	return 0
+}
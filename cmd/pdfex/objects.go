@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// runObjects implements "pdfex objects [-json] [options] <pdf_file>", which lists every object
+// with its generation, offset, type, dictionary keys and stream filters
+func runObjects(args []string) int {
+	fs := flag.NewFlagSet("objects", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	jsonOutput := fs.Bool("json", false, "List the objects as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex objects [-json] [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+	objects := doc.Objects()
+
+	err = common.writeOutput(func(w io.Writer) error {
+		if *jsonOutput {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(objects)
+		}
+		for _, obj := range objects {
+			kind := obj.Type
+			if obj.Subtype != "" {
+				kind += "/" + obj.Subtype
+			}
+			if kind == "" {
+				kind = "-"
+			}
+			offset := "-"
+			if obj.Offset >= 0 {
+				offset = fmt.Sprint(obj.Offset)
+			}
+			line := fmt.Sprintf("%d %d\toffset %s\t%s\t[%s]", obj.Number, obj.Generation, offset, kind, strings.Join(obj.Keys, " "))
+			if obj.Stream {
+				line += fmt.Sprintf("\tstream %d bytes", obj.Length)
+				if len(obj.Filters) > 0 {
+					line += " " + strings.Join(obj.Filters, ",")
+				}
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing object list: %v\n", err)
+		return commandStatus(err)
+	}
+	return 0
+}
+
+// runDump implements "pdfex dump -obj n [-decode] [options] <pdf_file>", which writes the stream
+// data of one object, as stored or decoded, or the content of an object without a stream
+func runDump(args []string) int {
+	fs := flag.NewFlagSet("dump", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	num := fs.Int("obj", 0, "Number of the object to dump")
+	decode := fs.Bool("decode", false, "Decode the stream through its filters")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex dump -obj n [-decode] [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || *num <= 0 {
+		fs.Usage()
+		return 2
+	}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+	data, err := doc.ObjectData(*num, *decode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	err = common.writeOutput(func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing object %d: %v\n", *num, err)
+		return commandStatus(err)
+	}
+	return 0
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// runRedactionCheck implements "pdfex redaction-check [-json] <pdf_file>", which lists the text
+// a reader can't see, such as text left under redaction boxes, with its page and position. The
+// exit status is 0 if none was found, 1 if some was and 2, 3, 4 or 6 on error.
+func runRedactionCheck(args []string) int {
+	fs := flag.NewFlagSet("redaction-check", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	jsonOutput := fs.Bool("json", false, "Print the hidden text as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex redaction-check [-json] [-pages range] [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	found := doc.FindHiddenText()
+	if *jsonOutput {
+		err = common.writeOutput(func(w io.Writer) error {
+			return writeJSON(w, found)
+		})
+	} else {
+		err = common.printOutput(func(w io.Writer) {
+			printHiddenText(w, found)
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return commandStatus(err)
+	}
+
+	if len(found) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// printHiddenText prints the hidden text of a document, one run per line
+func printHiddenText(w io.Writer, found []pdfex.HiddenText) {
+	if len(found) == 0 {
+		fmt.Fprintln(w, "No hidden text found")
+		return
+	}
+	for _, run := range found {
+		fmt.Fprintf(w, "Page %d at (%.1f, %.1f)-(%.1f, %.1f): %s: %q\n", run.Page,
+			run.Bounds.X1, run.Bounds.Y1, run.Bounds.X2, run.Bounds.Y2, run.Reason, run.Text)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// reorderReport is the JSON output for one file
+type reorderReport struct {
+	File   string               `json:"file"`
+	Issues []pdfex.ReorderIssue `json:"issues"`
+}
+
+// runReorderCheck implements "pdfex reorder-check [options] <file_or_dir>...", which lists the
+// lines whose extracted text needs bidi or combining-mark reordering. The exit status is 0 if
+// no line needs reordering, 1 if some do and 2, 3, 4 or 6 on error.
+func runReorderCheck(args []string) int {
+	flags := flag.NewFlagSet("reorder-check", flag.ExitOnError)
+	common := addCommonFlags(flags)
+	recursive := flags.Bool("r", false, "Check directories recursively")
+	jsonOutput := flags.Bool("json", false, "Print the report as JSON")
+
+	flags.Usage = func() {
+		fmt.Println("Usage: pdfex reorder-check [options] <file_or_dir>...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return 2
+	}
+
+	files, err := collectPDFFiles(flags.Args(), *recursive)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	status := 0
+	reports := []reorderReport{}
+	for _, file := range files {
+		doc, err := common.open(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", file, err)
+			status = commandStatus(err)
+			continue
+		}
+
+		issues := doc.CheckReordering()
+		if len(issues) > 0 && status == 0 {
+			status = 1
+		}
+		if issues == nil {
+			issues = []pdfex.ReorderIssue{}
+		}
+		reports = append(reports, reorderReport{File: file, Issues: issues})
+	}
+
+	if *jsonOutput {
+		err = common.writeOutput(func(w io.Writer) error {
+			return writeJSON(w, reports)
+		})
+	} else {
+		err = common.printOutput(func(w io.Writer) {
+			printReorderReports(w, reports)
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return commandStatus(err)
+	}
+
+	return status
+}
+
+// printReorderReports prints the lines needing reordering with their text before and after
+func printReorderReports(w io.Writer, reports []reorderReport) {
+	for _, report := range reports {
+		for _, issue := range report.Issues {
+			fmt.Fprintf(w, "%s:p%d:l%d: %s\n", report.File, issue.Page, issue.Line, strings.Join(issue.Issues, ", "))
+			fmt.Fprintf(w, "  before: %s\n", issue.Before)
+			fmt.Fprintf(w, "  after:  %s\n", issue.After)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// runRevisions implements "pdfex revisions [-json] [-extract n] [options] <pdf_file>", which
+// lists the revisions of an incrementally updated file, or writes the file as it was at one
+func runRevisions(args []string) int {
+	fs := flag.NewFlagSet("revisions", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	jsonOutput := fs.Bool("json", false, "List the revisions as JSON")
+	extract := fs.Int("extract", 0, "Write the file as it was at this revision, numbered from 1")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex revisions [-json] [-extract n] [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || *extract < 0 {
+		fs.Usage()
+		return 2
+	}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	if *extract > 0 {
+		err = common.writeOutput(func(w io.Writer) error {
+			return doc.WriteRevision(w, *extract)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing revision %d: %v\n", *extract, err)
+			return commandStatus(err)
+		}
+		return 0
+	}
+
+	revisions, err := doc.GetRevisions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	err = common.writeOutput(func(w io.Writer) error {
+		if *jsonOutput {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(revisions)
+		}
+		for _, r := range revisions {
+			xref := "-"
+			if r.XRefOffset >= 0 {
+				xref = fmt.Sprint(r.XRefOffset)
+			}
+			line := fmt.Sprintf("%d\tbytes %d-%d\txref %s\t%d objects", r.Number, r.Start, r.End, xref, len(r.Objects))
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing revision list: %v\n", err)
+		return commandStatus(err)
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// runSanitize implements "pdfex sanitize [options] <pdf_file>", which reports the JavaScript,
+// embedded files, external actions and unreferenced objects a sanitized copy would drop. The exit
+// status is 0 if there is nothing to remove, 1 if there is and 2, 3, 4 or 6 on error.
+func runSanitize(args []string) int {
+	fs := flag.NewFlagSet("sanitize", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	jsonOutput := fs.Bool("json", false, "Print the report as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex sanitize [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	report := doc.SanitizeReport()
+	if *jsonOutput {
+		err = common.writeOutput(func(w io.Writer) error {
+			return writeJSON(w, report)
+		})
+	} else {
+		err = common.printOutput(func(w io.Writer) {
+			printSanitizeReport(w, report)
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return commandStatus(err)
+	}
+
+	if report.Clean() {
+		return 0
+	}
+	return 1
+}
+
+// printSanitizeReport prints what sanitizing would remove
+func printSanitizeReport(w io.Writer, report *pdfex.SanitizeReport) {
+	if report.Clean() {
+		fmt.Fprintln(w, "Nothing to remove")
+		return
+	}
+
+	for _, item := range report.Unsafe {
+		if item.Detail != "" {
+			fmt.Fprintf(w, "Object %d: %s (%s)\n", item.ObjectNumber, item.Kind, item.Detail)
+		} else {
+			fmt.Fprintf(w, "Object %d: %s\n", item.ObjectNumber, item.Kind)
+		}
+	}
+	if len(report.Unreferenced) > 0 {
+		fmt.Fprintf(w, "Unreferenced objects: %v\n", report.Unreferenced)
+	}
+}
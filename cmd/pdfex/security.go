@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// runSecurity implements "pdfex security [-json] [-scripts] <pdf_file>", which flags the risky
+// constructs of a document with the objects holding them, or with -scripts prints its JavaScript.
+// The exit status is 0 if nothing was found, 1 if something was and 2, 3, 4 or 6 on error.
+func runSecurity(args []string) int {
+	fs := flag.NewFlagSet("security", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	jsonOutput := fs.Bool("json", false, "Print the report as JSON")
+	scriptsOnly := fs.Bool("scripts", false, "Print the JavaScript of the document instead of the indicators")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex security [-json] [-scripts] [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	// found reports whether the output lists anything
+	var found bool
+	switch {
+	case *scriptsOnly:
+		scripts := doc.JavaScripts()
+		found = len(scripts) > 0
+		if *jsonOutput {
+			err = common.writeOutput(func(w io.Writer) error {
+				return writeJSON(w, scripts)
+			})
+		} else {
+			err = common.printOutput(func(w io.Writer) {
+				printScripts(w, scripts)
+			})
+		}
+	default:
+		report := doc.SecurityReport()
+		found = !report.Clean()
+		if *jsonOutput {
+			err = common.writeOutput(func(w io.Writer) error {
+				return writeJSON(w, report)
+			})
+		} else {
+			err = common.printOutput(func(w io.Writer) {
+				printSecurityReport(w, report)
+			})
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return commandStatus(err)
+	}
+
+	if found {
+		return 1
+	}
+	return 0
+}
+
+// printSecurityReport prints the indicators of a security report, one per line
+func printSecurityReport(w io.Writer, report *pdfex.SecurityReport) {
+	if report.Clean() {
+		fmt.Fprintln(w, "No risky constructs found")
+		return
+	}
+
+	for _, indicator := range report.Indicators {
+		if indicator.Detail != "" {
+			fmt.Fprintf(w, "Object %d: %s risk: %s (%s)\n", indicator.ObjectNumber, indicator.Risk, indicator.Kind, indicator.Detail)
+		} else {
+			fmt.Fprintf(w, "Object %d: %s risk: %s\n", indicator.ObjectNumber, indicator.Risk, indicator.Kind)
+		}
+	}
+}
+
+// printScripts prints the JavaScript of a document, each script under a header naming it and
+// the object holding it
+func printScripts(w io.Writer, scripts []pdfex.Script) {
+	if len(scripts) == 0 {
+		fmt.Fprintln(w, "No JavaScript found")
+		return
+	}
+	for _, script := range scripts {
+		fmt.Fprintf(w, "=== %s (object %d) ===\n%s\n", script.Name, script.ObjectNumber, script.Source)
+	}
+}
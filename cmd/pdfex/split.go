@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// runSplit implements "pdfex split --by-outline level=N [options] <pdf_file>"
+func runSplit(args []string) int {
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	common := addInputFlags(fs)
+	byOutline := fs.String("by-outline", "level=1", "Split at outline (bookmark) entries, e.g. level=1 for chapters")
+	format := fs.String("format", "text", "Output format for each section: text or json")
+	outputDir := fs.String("o", "", "Output directory or s3://bucket/prefix (default: directory of the input file)")
+	skipExisting := fs.Bool("skip-existing", false, "Don't overwrite sections that are already in the output location")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex split --by-outline level=N [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 1
+	}
+
+	level, err := parseOutlineLevel(*byOutline)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	var ext string
+	switch *format {
+	case "text":
+		ext = ".txt"
+	case "json":
+		ext = ".json"
+	default:
+		fmt.Printf("Error: unknown output format %q\n", *format)
+		return 1
+	}
+
+	filename := fs.Arg(0)
+	doc, err := common.open(filename)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	sections, err := doc.SplitByOutline(level)
+	if err != nil {
+		fmt.Printf("Error splitting PDF: %v\n", err)
+		return 1
+	}
+
+	dir := *outputDir
+	if dir == "" {
+		dir = filepath.Dir(filename)
+	}
+	store, err := pdfex.OpenOutputStore(dir)
+	if err != nil {
+		fmt.Printf("Error opening output location: %v\n", err)
+		return commandStatus(err)
+	}
+
+	base := inputBase(filename)
+	for i, section := range sections {
+		var data []byte
+		if *format == "json" {
+			data, err = json.MarshalIndent(section, "", "  ")
+			if err != nil {
+				fmt.Printf("Error generating JSON: %v\n", err)
+				return 1
+			}
+		} else {
+			data = []byte(section.Text)
+		}
+
+		name := fmt.Sprintf("%s_%02d_%s%s", base, i+1, slugify(section.Title), ext)
+		written := true
+		if *skipExisting {
+			written, err = pdfex.PutIfAbsent(store, name, bytes.NewReader(data))
+		} else {
+			err = store.Put(name, bytes.NewReader(data))
+		}
+		if err != nil {
+			fmt.Printf("Error writing %s: %v\n", store.Location(name), err)
+			return commandStatus(err)
+		}
+
+		if written {
+			fmt.Printf("Pages %d-%d saved to %s\n", section.StartPage, section.EndPage, store.Location(name))
+		} else {
+			fmt.Printf("Pages %d-%d skipped, %s already exists\n", section.StartPage, section.EndPage, store.Location(name))
+		}
+	}
+
+	return 0
+}
+
+// parseOutlineLevel parses a "level=N" outline split specification
+func parseOutlineLevel(spec string) (int, error) {
+	value := strings.TrimPrefix(spec, "level=")
+	level, err := strconv.Atoi(value)
+	if err != nil || level < 1 {
+		return 0, fmt.Errorf("invalid outline split %q, expected level=N", spec)
+	}
+	return level, nil
+}
+
+// slugify turns a section title into a file name component
+func slugify(title string) string {
+	var sb strings.Builder
+	lastDash := true
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+			lastDash = false
+		} else if !lastDash {
+			sb.WriteRune('-')
+			lastDash = true
+		}
+		if sb.Len() >= 48 {
+			break
+		}
+	}
+
+	slug := strings.Trim(sb.String(), "-")
+	if slug == "" {
+		return "untitled"
+	}
+	return slug
+}
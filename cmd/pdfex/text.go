@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// textFormats are the output formats of the text subcommand
+const textFormats = "plain, layout, markdown, html, alto, json, csv or tsv"
+
+// runText implements "pdfex text [-format f] [options] <pdf_file>", which writes the text of a
+// document in one format to stdout or a file
+func runText(args []string) int {
+	fs := flag.NewFlagSet("text", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	format := fs.String("format", "plain", "Output format: "+textFormats)
+	positioned := fs.Bool("positioned", false, "With -format html, place text and images as on the page")
+	spans := fs.Bool("spans", false, "With -format csv or tsv, write one row per text span instead of per word")
+	columns := fs.Int("columns", pdfex.ColumnsOff, "Column layout: 0 (off), -1 (auto) or a column count")
+	newlines := fs.String("newlines", "preserve", "Line breaks: preserve, paragraphs (one per line) or spaces")
+	collapseSpaces := fs.Bool("collapse-spaces", false, "Collapse runs of spaces and tabs and trim the ends of lines")
+	stripControl := fs.Bool("strip-control", false, "Remove control characters other than tabs and line breaks")
+	repairMojibake := fs.Bool("repair-mojibake", false, "Re-decode garbled pages when a Windows-1252 or Latin-1 round trip makes them more plausible")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex text [-format f] [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	var write func(doc *pdfex.PDFDocument, w io.Writer) error
+	switch *format {
+	case "plain":
+		write = writePlainText
+	case "layout":
+		write = func(doc *pdfex.PDFDocument, w io.Writer) error {
+			layout, err := doc.ExtractTextLayout()
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(w, layout)
+			return err
+		}
+	case "markdown":
+		write = func(doc *pdfex.PDFDocument, w io.Writer) error {
+			markdown, err := doc.ExportMarkdown()
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(w, markdown)
+			return err
+		}
+	case "html":
+		write = func(doc *pdfex.PDFDocument, w io.Writer) error {
+			return doc.ExportHTML(w, &pdfex.HTMLOptions{Positioned: *positioned})
+		}
+	case "alto":
+		write = func(doc *pdfex.PDFDocument, w io.Writer) error {
+			return doc.ExportALTO(w)
+		}
+	case "json":
+		write = func(doc *pdfex.PDFDocument, w io.Writer) error {
+			return doc.ExportJSON(w)
+		}
+	case "csv", "tsv":
+		options := &pdfex.CSVOptions{Spans: *spans}
+		if *format == "tsv" {
+			options.Delimiter = '\t'
+		}
+		write = func(doc *pdfex.PDFDocument, w io.Writer) error {
+			return doc.ExportCSV(w, options)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q; use %s\n", *format, textFormats)
+		return 2
+	}
+
+	options := common.parseOptions()
+	switch *newlines {
+	case "preserve":
+		options.Text.Newlines = pdfex.NewlinesPreserve
+	case "paragraphs":
+		options.Text.Newlines = pdfex.NewlinesParagraphs
+	case "spaces":
+		options.Text.Newlines = pdfex.NewlinesSpaces
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown newline policy %q; use preserve, paragraphs or spaces\n", *newlines)
+		return 2
+	}
+	options.Text.CollapseSpaces = *collapseSpaces
+	options.Text.StripControlChars = *stripControl
+	options.Columns = *columns
+	options.RepairMojibake = *repairMojibake
+	doc, err := common.openWith(fs.Arg(0), options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	err = common.writeOutput(func(w io.Writer) error {
+		return write(doc, w)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing text: %v\n", err)
+		return commandStatus(err)
+	}
+	return 0
+}
+
+// writePlainText writes the extracted text of the selected pages, separated by form feeds, as
+// each page is extracted
+func writePlainText(doc *pdfex.PDFDocument, w io.Writer) error {
+	first := true
+	return doc.EachPageText(func(pageNum int, text string) error {
+		if !first {
+			if _, err := io.WriteString(w, "\f"); err != nil {
+				return err
+			}
+		}
+		first = false
+		_, err := io.WriteString(w, strings.TrimRight(text, "\n")+"\n")
+		return err
+	})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// validationResult is the JSON form of the validate report
+type validationResult struct {
+	Summary  string          `json:"summary"` // How the object table was obtained
+	Findings []pdfex.Finding `json:"findings"`
+}
+
+// runValidate implements "pdfex validate [-json] [-pdfa] [-xref] [options] <pdf_file>", which
+// checks the structure of a document, with -pdfa key PDF/A requirements and with -xref the
+// consistency of its cross-reference sections, and lists typed findings. The exit status is 0
+// if nothing was found, 1 if there are findings and 2, 3, 4 or 6 on error.
+func runValidate(args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	common := addCommonFlags(fs)
+	jsonOutput := fs.Bool("json", false, "Print the report as JSON")
+	pdfa := fs.Bool("pdfa", false, "Also screen the document against key PDF/A requirements")
+	xref := fs.Bool("xref", false, "Also audit the cross-reference sections for orphaned, unreachable and shadowed objects and free-list errors")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: pdfex validate [-json] [-pdfa] [-xref] [options] <pdf_file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	doc, err := common.open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return commandStatus(err)
+	}
+
+	findings, err := doc.Validate()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if *pdfa {
+		findings = append(findings, doc.CheckPDFA()...)
+	}
+	if *xref {
+		audit, err := doc.AuditXRef()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		findings = append(findings, audit...)
+	}
+	report := doc.DegradationReport()
+	result := validationResult{Summary: report.Summary(), Findings: findings}
+
+	err = common.writeOutput(func(w io.Writer) error {
+		if *jsonOutput {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(result)
+		}
+		if _, err := fmt.Fprintln(w, result.Summary); err != nil {
+			return err
+		}
+		for _, finding := range result.Findings {
+			if _, err := fmt.Fprintf(w, "  %s\n", finding); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return commandStatus(err)
+	}
+
+	if len(findings) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/yourusername/pdfex/internal/utils"
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+// watchedFile is the last seen state of a PDF in a watched directory
+type watchedFile struct {
+	size    int64
+	modTime time.Time
+	pending bool // Changed since it was last processed
+}
+
+// watcher polls a directory for new and modified PDFs and extracts them to an output store
+type watcher struct {
+	dir          string
+	recursive    bool
+	layout       string
+	text         bool
+	chunks       bool
+	chunkOptions *pdfex.ChunkOptions
+	batch        *pdfex.BatchOptions
+	store        pdfex.OutputStore
+	files        map[string]*watchedFile
+}
+
+// runWatch implements "pdfex watch [options] <dir>", which extracts the text and chunks of PDFs
+// dropped into a directory. The directory is polled rather than subscribed to, so it works the
+// same on network file systems; a file is processed once its size and modification time are
+// unchanged between two polls, so files still being copied are left alone.
+func runWatch(args []string) int {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	common := addInputFlags(flags)
+	output := flags.String("o", "", "Output directory or s3://bucket/prefix (default: the watched directory)")
+	layout := flags.String("layout", "{dir}/{name}_{kind}.{ext}",
+		"Output names: {dir} is the subdirectory of the PDF, {name} its name without extension, {kind} text or chunks and {ext} txt or jsonl")
+	interval := flags.Duration("interval", 2*time.Second, "How often to look for new and modified PDFs")
+	recursive := flags.Bool("r", false, "Also watch subdirectories")
+	initial := flags.Bool("initial", true, "Also process the PDFs already in the directory when watching starts")
+	text := flags.Bool("text", true, "Write the extracted text")
+	chunks := flags.Bool("chunks", true, "Write the text chunks as JSON Lines")
+	chunkSize := flags.Int("chunk-size", 1000, "Maximum length of text chunks in characters")
+	chunkBy := flags.String("chunk-by", "paragraph", "Where text chunks end: paragraph, sentence, page or heading")
+	jobs := flags.Int("jobs", runtime.NumCPU(), "Number of files to process in parallel")
+
+	flags.Usage = func() {
+		fmt.Println("Usage: pdfex watch [options] <dir>")
+		fmt.Println("The directory is polled every -interval. A PDF is processed once its size and")
+		fmt.Println("modification time are unchanged between two polls, so within two intervals of")
+		fmt.Println("being written; a shorter interval reacts sooner but lists the directory more often.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return 2
+	}
+	dir := flags.Arg(0)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "Error: %s is not a directory\n", dir)
+		return 2
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid interval: %v\n", *interval)
+		return 2
+	}
+
+	split, err := pdfex.ParseChunkSplit(*chunkBy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	location := *output
+	if location == "" {
+		location = dir
+	}
+	store, err := pdfex.OpenOutputStore(location)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening output location: %v\n", err)
+		return commandStatus(err)
+	}
+
+	utils.SetLogWriter(os.Stderr)
+	options := common.parseOptions()
+
+	w := &watcher{
+		dir:          dir,
+		recursive:    *recursive,
+		layout:       *layout,
+		text:         *text,
+		chunks:       *chunks,
+		chunkOptions: &pdfex.ChunkOptions{MaxSize: *chunkSize, SplitOn: split},
+		batch:        &pdfex.BatchOptions{Jobs: *jobs, ParseOptions: options},
+		store:        store,
+		files:        make(map[string]*watchedFile),
+	}
+
+	// Files already present count as changed only if they should be processed
+	if err := w.scan(*initial); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	fmt.Printf("Watching %s for PDFs, writing to %s\n", dir, location)
+
+	for range time.Tick(*interval) {
+		ready := w.ready()
+		if err := w.scan(true); err != nil {
+			utils.Logf(utils.LogError, "Scanning %s: %v\n", dir, err)
+			continue
+		}
+		w.process(ready)
+	}
+	return 0
+}
+
+// scan records the current state of the PDFs in the directory, marking new and modified ones
+// as pending when markChanged is set
+func (w *watcher) scan(markChanged bool) error {
+	seen := make(map[string]bool)
+	err := filepath.WalkDir(w.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != w.dir && !w.recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(p), ".pdf") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			// Removed between listing and stat
+			return nil
+		}
+
+		seen[p] = true
+		file, ok := w.files[p]
+		if ok && file.size == info.Size() && file.modTime.Equal(info.ModTime()) {
+			return nil
+		}
+		w.files[p] = &watchedFile{size: info.Size(), modTime: info.ModTime(), pending: markChanged}
+		return nil
+	})
+
+	for p := range w.files {
+		if !seen[p] {
+			delete(w.files, p)
+		}
+	}
+	return err
+}
+
+// ready returns the pending files with the state they had at the last scan, to be processed if
+// the next scan finds them unchanged
+func (w *watcher) ready() map[string]watchedFile {
+	ready := make(map[string]watchedFile)
+	for p, file := range w.files {
+		if file.pending {
+			ready[p] = *file
+		}
+	}
+	return ready
+}
+
+// process extracts the files that were pending before the last scan and haven't changed since
+func (w *watcher) process(ready map[string]watchedFile) {
+	var files []string
+	for p, before := range ready {
+		file, ok := w.files[p]
+		if ok && file.size == before.size && file.modTime.Equal(before.modTime) {
+			files = append(files, p)
+			file.pending = false
+		}
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	summary := pdfex.ProcessBatch(files, w.batch, w.extract)
+	for _, result := range summary.Results {
+		if result.Err != nil {
+			utils.Logf(utils.LogError, "%s: %v\n", result.File, result.Err)
+			continue
+		}
+		fmt.Printf("%s: %d pages extracted in %v\n", result.File, result.Pages, result.Duration.Round(time.Millisecond))
+	}
+}
+
+// extract writes the text and chunks of a document to the output store
+func (w *watcher) extract(filename string, doc *pdfex.PDFDocument) error {
+	if w.text {
+		texts := doc.ExtractPageTexts()
+		if err := w.put(filename, "text", "txt", []byte(strings.Join(texts, "\f"))); err != nil {
+			return err
+		}
+	}
+	if w.chunks {
+		var buf bytes.Buffer
+		if err := doc.WriteChunksJSONL(&buf, w.chunkOptions); err != nil {
+			return err
+		}
+		if err := w.put(filename, "chunks", "jsonl", buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// put stores an artifact of a PDF under the name given by the output layout
+func (w *watcher) put(filename, kind, ext string, data []byte) error {
+	name := w.artifactName(filename, kind, ext)
+	if err := w.store.Put(name, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("error writing %s: %v", w.store.Location(name), err)
+	}
+	return nil
+}
+
+// artifactName expands the output layout for an artifact of a PDF in the watched directory
+func (w *watcher) artifactName(filename, kind, ext string) string {
+	rel, err := filepath.Rel(w.dir, filename)
+	if err != nil {
+		rel = filepath.Base(filename)
+	}
+	rel = filepath.ToSlash(rel)
+	name := strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+
+	expanded := strings.NewReplacer("{dir}", path.Dir(rel), "{name}", name, "{kind}", kind, "{ext}", ext).Replace(w.layout)
+	return strings.TrimPrefix(path.Clean(expanded), "./")
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/yourusername/pdfex/grpc/pdfexpb"
+	"github.com/yourusername/pdfex/grpc/promcollector"
+	"github.com/yourusername/pdfex/grpc/server"
+	"github.com/yourusername/pdfex/internal/utils"
+	"github.com/yourusername/pdfex/pkg/pdfex"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	addr := flag.String("addr", ":50051", "Address to listen on")
+	maxSize := flag.Int("max-size", 256, "Largest accepted document in MiB")
+	verbose := flag.Bool("v", false, "Log informational messages")
+	metricsAddr := flag.String("metrics-addr", "", "Address to serve Prometheus metrics on at /metrics; none if empty")
+	flag.Parse()
+
+	utils.SetLogWriter(os.Stderr)
+	options := pdfex.DefaultParseOptions()
+	options.LogLevel = utils.LogError
+	if *verbose {
+		options.LogLevel = utils.LogInfo
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *metricsAddr != "" {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(promcollector.New())
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		go func() {
+			if err := http.ListenAndServe(*metricsAddr, mux); err != nil {
+				fmt.Fprintf(os.Stderr, "Error serving metrics: %v\n", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	// Requests carry the whole document, so the message limit is the document size limit
+	s := grpc.NewServer(grpc.MaxRecvMsgSize(*maxSize << 20))
+	pdfexpb.RegisterExtractorServer(s, &server.Server{Options: options})
+
+	fmt.Fprintf(os.Stderr, "Serving pdfex on %s\n", listener.Addr())
+	if err := s.Serve(listener); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,737 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: pdfex/v1/pdfex.proto
+
+package pdfexpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExtractRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Data              []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`                                                       // The PDF file
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                                       // File name, for messages and provenance
+	PageRange         string                 `protobuf:"bytes,3,opt,name=page_range,json=pageRange,proto3" json:"page_range,omitempty"`                            // Pages to extract, e.g. "1-5,12"; all if empty
+	StripRunningLines bool                   `protobuf:"varint,4,opt,name=strip_running_lines,json=stripRunningLines,proto3" json:"strip_running_lines,omitempty"` // Drop running headers, footers and page numbers (delays the first page)
+	IncludeSpans      bool                   `protobuf:"varint,5,opt,name=include_spans,json=includeSpans,proto3" json:"include_spans,omitempty"`                  // Send the positioned text spans of each page
+	IncludeBlocks     bool                   `protobuf:"varint,6,opt,name=include_blocks,json=includeBlocks,proto3" json:"include_blocks,omitempty"`               // Send the text blocks and paragraphs of each page
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ExtractRequest) Reset() {
+	*x = ExtractRequest{}
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractRequest) ProtoMessage() {}
+
+func (x *ExtractRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractRequest.ProtoReflect.Descriptor instead.
+func (*ExtractRequest) Descriptor() ([]byte, []int) {
+	return file_pdfex_v1_pdfex_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExtractRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExtractRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExtractRequest) GetPageRange() string {
+	if x != nil {
+		return x.PageRange
+	}
+	return ""
+}
+
+func (x *ExtractRequest) GetStripRunningLines() bool {
+	if x != nil {
+		return x.StripRunningLines
+	}
+	return false
+}
+
+func (x *ExtractRequest) GetIncludeSpans() bool {
+	if x != nil {
+		return x.IncludeSpans
+	}
+	return false
+}
+
+func (x *ExtractRequest) GetIncludeBlocks() bool {
+	if x != nil {
+		return x.IncludeBlocks
+	}
+	return false
+}
+
+type ExtractResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*ExtractResponse_Info
+	//	*ExtractResponse_Page
+	Result        isExtractResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtractResponse) Reset() {
+	*x = ExtractResponse{}
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractResponse) ProtoMessage() {}
+
+func (x *ExtractResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractResponse.ProtoReflect.Descriptor instead.
+func (*ExtractResponse) Descriptor() ([]byte, []int) {
+	return file_pdfex_v1_pdfex_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExtractResponse) GetResult() isExtractResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ExtractResponse) GetInfo() *DocumentInfo {
+	if x != nil {
+		if x, ok := x.Result.(*ExtractResponse_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *ExtractResponse) GetPage() *PageResult {
+	if x != nil {
+		if x, ok := x.Result.(*ExtractResponse_Page); ok {
+			return x.Page
+		}
+	}
+	return nil
+}
+
+type isExtractResponse_Result interface {
+	isExtractResponse_Result()
+}
+
+type ExtractResponse_Info struct {
+	Info *DocumentInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"` // Always the first message
+}
+
+type ExtractResponse_Page struct {
+	Page *PageResult `protobuf:"bytes,2,opt,name=page,proto3,oneof"`
+}
+
+func (*ExtractResponse_Info) isExtractResponse_Result() {}
+
+func (*ExtractResponse_Page) isExtractResponse_Result() {}
+
+type DocumentInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PdfVersion    string                 `protobuf:"bytes,2,opt,name=pdf_version,json=pdfVersion,proto3" json:"pdf_version,omitempty"`
+	PageCount     int32                  `protobuf:"varint,3,opt,name=page_count,json=pageCount,proto3" json:"page_count,omitempty"`
+	SourceSha256  string                 `protobuf:"bytes,4,opt,name=source_sha256,json=sourceSha256,proto3" json:"source_sha256,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DocumentInfo) Reset() {
+	*x = DocumentInfo{}
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocumentInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentInfo) ProtoMessage() {}
+
+func (x *DocumentInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentInfo.ProtoReflect.Descriptor instead.
+func (*DocumentInfo) Descriptor() ([]byte, []int) {
+	return file_pdfex_v1_pdfex_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DocumentInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DocumentInfo) GetPdfVersion() string {
+	if x != nil {
+		return x.PdfVersion
+	}
+	return ""
+}
+
+func (x *DocumentInfo) GetPageCount() int32 {
+	if x != nil {
+		return x.PageCount
+	}
+	return 0
+}
+
+func (x *DocumentInfo) GetSourceSha256() string {
+	if x != nil {
+		return x.SourceSha256
+	}
+	return ""
+}
+
+type PageResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"` // 1-based
+	Width         float64                `protobuf:"fixed64,2,opt,name=width,proto3" json:"width,omitempty"`  // In points
+	Height        float64                `protobuf:"fixed64,3,opt,name=height,proto3" json:"height,omitempty"`
+	Rotation      int32                  `protobuf:"varint,4,opt,name=rotation,proto3" json:"rotation,omitempty"`
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	Spans         []*TextSpan            `protobuf:"bytes,6,rep,name=spans,proto3" json:"spans,omitempty"`
+	Blocks        []*Block               `protobuf:"bytes,7,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageResult) Reset() {
+	*x = PageResult{}
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageResult) ProtoMessage() {}
+
+func (x *PageResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageResult.ProtoReflect.Descriptor instead.
+func (*PageResult) Descriptor() ([]byte, []int) {
+	return file_pdfex_v1_pdfex_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PageResult) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *PageResult) GetWidth() float64 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *PageResult) GetHeight() float64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *PageResult) GetRotation() int32 {
+	if x != nil {
+		return x.Rotation
+	}
+	return 0
+}
+
+func (x *PageResult) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *PageResult) GetSpans() []*TextSpan {
+	if x != nil {
+		return x.Spans
+	}
+	return nil
+}
+
+func (x *PageResult) GetBlocks() []*Block {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+type TextSpan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	X             float64                `protobuf:"fixed64,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y             float64                `protobuf:"fixed64,3,opt,name=y,proto3" json:"y,omitempty"`
+	Width         float64                `protobuf:"fixed64,4,opt,name=width,proto3" json:"width,omitempty"`
+	FontName      string                 `protobuf:"bytes,5,opt,name=font_name,json=fontName,proto3" json:"font_name,omitempty"`
+	FontSize      float64                `protobuf:"fixed64,6,opt,name=font_size,json=fontSize,proto3" json:"font_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TextSpan) Reset() {
+	*x = TextSpan{}
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TextSpan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextSpan) ProtoMessage() {}
+
+func (x *TextSpan) ProtoReflect() protoreflect.Message {
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextSpan.ProtoReflect.Descriptor instead.
+func (*TextSpan) Descriptor() ([]byte, []int) {
+	return file_pdfex_v1_pdfex_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TextSpan) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *TextSpan) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *TextSpan) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *TextSpan) GetWidth() float64 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *TextSpan) GetFontName() string {
+	if x != nil {
+		return x.FontName
+	}
+	return ""
+}
+
+func (x *TextSpan) GetFontSize() float64 {
+	if x != nil {
+		return x.FontSize
+	}
+	return 0
+}
+
+type Rect struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X1            float64                `protobuf:"fixed64,1,opt,name=x1,proto3" json:"x1,omitempty"` // Lower-left corner
+	Y1            float64                `protobuf:"fixed64,2,opt,name=y1,proto3" json:"y1,omitempty"`
+	X2            float64                `protobuf:"fixed64,3,opt,name=x2,proto3" json:"x2,omitempty"` // Upper-right corner
+	Y2            float64                `protobuf:"fixed64,4,opt,name=y2,proto3" json:"y2,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rect) Reset() {
+	*x = Rect{}
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rect) ProtoMessage() {}
+
+func (x *Rect) ProtoReflect() protoreflect.Message {
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rect.ProtoReflect.Descriptor instead.
+func (*Rect) Descriptor() ([]byte, []int) {
+	return file_pdfex_v1_pdfex_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Rect) GetX1() float64 {
+	if x != nil {
+		return x.X1
+	}
+	return 0
+}
+
+func (x *Rect) GetY1() float64 {
+	if x != nil {
+		return x.Y1
+	}
+	return 0
+}
+
+func (x *Rect) GetX2() float64 {
+	if x != nil {
+		return x.X2
+	}
+	return 0
+}
+
+func (x *Rect) GetY2() float64 {
+	if x != nil {
+		return x.Y2
+	}
+	return 0
+}
+
+type Block struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Bounds        *Rect                  `protobuf:"bytes,2,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	Paragraphs    []*Paragraph           `protobuf:"bytes,3,rep,name=paragraphs,proto3" json:"paragraphs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Block) Reset() {
+	*x = Block{}
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Block) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Block) ProtoMessage() {}
+
+func (x *Block) ProtoReflect() protoreflect.Message {
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Block.ProtoReflect.Descriptor instead.
+func (*Block) Descriptor() ([]byte, []int) {
+	return file_pdfex_v1_pdfex_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Block) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Block) GetBounds() *Rect {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *Block) GetParagraphs() []*Paragraph {
+	if x != nil {
+		return x.Paragraphs
+	}
+	return nil
+}
+
+type Paragraph struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Bounds        *Rect                  `protobuf:"bytes,2,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Paragraph) Reset() {
+	*x = Paragraph{}
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Paragraph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Paragraph) ProtoMessage() {}
+
+func (x *Paragraph) ProtoReflect() protoreflect.Message {
+	mi := &file_pdfex_v1_pdfex_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Paragraph.ProtoReflect.Descriptor instead.
+func (*Paragraph) Descriptor() ([]byte, []int) {
+	return file_pdfex_v1_pdfex_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Paragraph) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Paragraph) GetBounds() *Rect {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+var File_pdfex_v1_pdfex_proto protoreflect.FileDescriptor
+
+const file_pdfex_v1_pdfex_proto_rawDesc = "" +
+	"\n" +
+	"\x14pdfex/v1/pdfex.proto\x12\bpdfex.v1\"\xd3\x01\n" +
+	"\x0eExtractRequest\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"page_range\x18\x03 \x01(\tR\tpageRange\x12.\n" +
+	"\x13strip_running_lines\x18\x04 \x01(\bR\x11stripRunningLines\x12#\n" +
+	"\rinclude_spans\x18\x05 \x01(\bR\fincludeSpans\x12%\n" +
+	"\x0einclude_blocks\x18\x06 \x01(\bR\rincludeBlocks\"u\n" +
+	"\x0fExtractResponse\x12,\n" +
+	"\x04info\x18\x01 \x01(\v2\x16.pdfex.v1.DocumentInfoH\x00R\x04info\x12*\n" +
+	"\x04page\x18\x02 \x01(\v2\x14.pdfex.v1.PageResultH\x00R\x04pageB\b\n" +
+	"\x06result\"\x87\x01\n" +
+	"\fDocumentInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
+	"\vpdf_version\x18\x02 \x01(\tR\n" +
+	"pdfVersion\x12\x1d\n" +
+	"\n" +
+	"page_count\x18\x03 \x01(\x05R\tpageCount\x12#\n" +
+	"\rsource_sha256\x18\x04 \x01(\tR\fsourceSha256\"\xd5\x01\n" +
+	"\n" +
+	"PageResult\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x05R\x06number\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\x01R\x05width\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x01R\x06height\x12\x1a\n" +
+	"\brotation\x18\x04 \x01(\x05R\brotation\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\x12(\n" +
+	"\x05spans\x18\x06 \x03(\v2\x12.pdfex.v1.TextSpanR\x05spans\x12'\n" +
+	"\x06blocks\x18\a \x03(\v2\x0f.pdfex.v1.BlockR\x06blocks\"\x8a\x01\n" +
+	"\bTextSpan\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\f\n" +
+	"\x01x\x18\x02 \x01(\x01R\x01x\x12\f\n" +
+	"\x01y\x18\x03 \x01(\x01R\x01y\x12\x14\n" +
+	"\x05width\x18\x04 \x01(\x01R\x05width\x12\x1b\n" +
+	"\tfont_name\x18\x05 \x01(\tR\bfontName\x12\x1b\n" +
+	"\tfont_size\x18\x06 \x01(\x01R\bfontSize\"F\n" +
+	"\x04Rect\x12\x0e\n" +
+	"\x02x1\x18\x01 \x01(\x01R\x02x1\x12\x0e\n" +
+	"\x02y1\x18\x02 \x01(\x01R\x02y1\x12\x0e\n" +
+	"\x02x2\x18\x03 \x01(\x01R\x02x2\x12\x0e\n" +
+	"\x02y2\x18\x04 \x01(\x01R\x02y2\"x\n" +
+	"\x05Block\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12&\n" +
+	"\x06bounds\x18\x02 \x01(\v2\x0e.pdfex.v1.RectR\x06bounds\x123\n" +
+	"\n" +
+	"paragraphs\x18\x03 \x03(\v2\x13.pdfex.v1.ParagraphR\n" +
+	"paragraphs\"G\n" +
+	"\tParagraph\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12&\n" +
+	"\x06bounds\x18\x02 \x01(\v2\x0e.pdfex.v1.RectR\x06bounds2R\n" +
+	"\tExtractor\x12E\n" +
+	"\fExtractPages\x12\x18.pdfex.v1.ExtractRequest\x1a\x19.pdfex.v1.ExtractResponse0\x01B,Z*github.com/yourusername/pdfex/grpc/pdfexpbb\x06proto3"
+
+var (
+	file_pdfex_v1_pdfex_proto_rawDescOnce sync.Once
+	file_pdfex_v1_pdfex_proto_rawDescData []byte
+)
+
+func file_pdfex_v1_pdfex_proto_rawDescGZIP() []byte {
+	file_pdfex_v1_pdfex_proto_rawDescOnce.Do(func() {
+		file_pdfex_v1_pdfex_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pdfex_v1_pdfex_proto_rawDesc), len(file_pdfex_v1_pdfex_proto_rawDesc)))
+	})
+	return file_pdfex_v1_pdfex_proto_rawDescData
+}
+
+var file_pdfex_v1_pdfex_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_pdfex_v1_pdfex_proto_goTypes = []any{
+	(*ExtractRequest)(nil),  // 0: pdfex.v1.ExtractRequest
+	(*ExtractResponse)(nil), // 1: pdfex.v1.ExtractResponse
+	(*DocumentInfo)(nil),    // 2: pdfex.v1.DocumentInfo
+	(*PageResult)(nil),      // 3: pdfex.v1.PageResult
+	(*TextSpan)(nil),        // 4: pdfex.v1.TextSpan
+	(*Rect)(nil),            // 5: pdfex.v1.Rect
+	(*Block)(nil),           // 6: pdfex.v1.Block
+	(*Paragraph)(nil),       // 7: pdfex.v1.Paragraph
+}
+var file_pdfex_v1_pdfex_proto_depIdxs = []int32{
+	2, // 0: pdfex.v1.ExtractResponse.info:type_name -> pdfex.v1.DocumentInfo
+	3, // 1: pdfex.v1.ExtractResponse.page:type_name -> pdfex.v1.PageResult
+	4, // 2: pdfex.v1.PageResult.spans:type_name -> pdfex.v1.TextSpan
+	6, // 3: pdfex.v1.PageResult.blocks:type_name -> pdfex.v1.Block
+	5, // 4: pdfex.v1.Block.bounds:type_name -> pdfex.v1.Rect
+	7, // 5: pdfex.v1.Block.paragraphs:type_name -> pdfex.v1.Paragraph
+	5, // 6: pdfex.v1.Paragraph.bounds:type_name -> pdfex.v1.Rect
+	0, // 7: pdfex.v1.Extractor.ExtractPages:input_type -> pdfex.v1.ExtractRequest
+	1, // 8: pdfex.v1.Extractor.ExtractPages:output_type -> pdfex.v1.ExtractResponse
+	8, // [8:9] is the sub-list for method output_type
+	7, // [7:8] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_pdfex_v1_pdfex_proto_init() }
+func file_pdfex_v1_pdfex_proto_init() {
+	if File_pdfex_v1_pdfex_proto != nil {
+		return
+	}
+	file_pdfex_v1_pdfex_proto_msgTypes[1].OneofWrappers = []any{
+		(*ExtractResponse_Info)(nil),
+		(*ExtractResponse_Page)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pdfex_v1_pdfex_proto_rawDesc), len(file_pdfex_v1_pdfex_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pdfex_v1_pdfex_proto_goTypes,
+		DependencyIndexes: file_pdfex_v1_pdfex_proto_depIdxs,
+		MessageInfos:      file_pdfex_v1_pdfex_proto_msgTypes,
+	}.Build()
+	File_pdfex_v1_pdfex_proto = out.File
+	file_pdfex_v1_pdfex_proto_goTypes = nil
+	file_pdfex_v1_pdfex_proto_depIdxs = nil
+}
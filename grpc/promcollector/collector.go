@@ -0,0 +1,61 @@
+// Package promcollector exports the pdfex service metrics through the Prometheus client, so
+// that an application can register them in its own registry next to its other metrics:
+//
+//	prometheus.MustRegister(promcollector.New())
+package promcollector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yourusername/pdfex/pkg/pdfex"
+)
+
+var (
+	parsedDesc = prometheus.NewDesc(
+		"pdfex_documents_parsed_total", "Documents parsed, by result.", []string{"result"}, nil)
+	durationDesc = prometheus.NewDesc(
+		"pdfex_parse_duration_seconds", "Time taken to parse a document.", nil, nil)
+	pagesDesc = prometheus.NewDesc(
+		"pdfex_pages_processed_total", "Pages of the documents parsed successfully.", nil, nil)
+	filterFailuresDesc = prometheus.NewDesc(
+		"pdfex_filter_failures_total", "Streams a filter failed to decode, by filter.", []string{"filter"}, nil)
+	decompressedDesc = prometheus.NewDesc(
+		"pdfex_decompressed_bytes_total", "Bytes produced by decoding streams.", nil, nil)
+	objectCacheDesc = prometheus.NewDesc(
+		"pdfex_object_cache_lookups_total", "Lookups of objects read on demand, by result.", []string{"result"}, nil)
+)
+
+// Collector is a prometheus.Collector of the metrics pdfex accumulates over the life of the
+// process, across every document parsed. The metrics are process-wide, so register one
+// Collector per registry.
+type Collector struct{}
+
+// New returns a Collector of the pdfex service metrics
+func New() *Collector {
+	return &Collector{}
+}
+
+// Describe sends the descriptors of the metrics to ch
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- parsedDesc
+	ch <- durationDesc
+	ch <- pagesDesc
+	ch <- filterFailuresDesc
+	ch <- decompressedDesc
+	ch <- objectCacheDesc
+}
+
+// Collect sends the current values of the metrics to ch, all read at one moment
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	s := pdfex.ReadServiceMetrics()
+
+	ch <- prometheus.MustNewConstMetric(parsedDesc, prometheus.CounterValue, float64(s.Parsed), "success")
+	ch <- prometheus.MustNewConstMetric(parsedDesc, prometheus.CounterValue, float64(s.ParseFailures), "failure")
+	ch <- prometheus.MustNewConstHistogram(durationDesc, s.Parsed+s.ParseFailures, s.DurationSum, s.DurationBuckets)
+	ch <- prometheus.MustNewConstMetric(pagesDesc, prometheus.CounterValue, float64(s.PagesProcessed))
+	for filter, count := range s.FilterFailures {
+		ch <- prometheus.MustNewConstMetric(filterFailuresDesc, prometheus.CounterValue, float64(count), filter)
+	}
+	ch <- prometheus.MustNewConstMetric(decompressedDesc, prometheus.CounterValue, float64(s.BytesDecompressed))
+	ch <- prometheus.MustNewConstMetric(objectCacheDesc, prometheus.CounterValue, float64(s.ObjectCacheHits), "hit")
+	ch <- prometheus.MustNewConstMetric(objectCacheDesc, prometheus.CounterValue, float64(s.ObjectCacheMisses), "miss")
+}
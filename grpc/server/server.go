@@ -0,0 +1,85 @@
+// Package server implements the pdfex gRPC Extractor service on top of the pdfex library
+package server
+
+import (
+	"github.com/yourusername/pdfex/grpc/pdfexpb"
+	"github.com/yourusername/pdfex/pkg/pdfex"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the Extractor service
+type Server struct {
+	pdfexpb.UnimplementedExtractorServer
+
+	// Options are the base parse options of every request; nil uses DefaultParseOptions
+	Options *pdfex.ParseOptions
+}
+
+// ExtractPages parses the document in the request and streams its pages as they are extracted
+func (s *Server) ExtractPages(req *pdfexpb.ExtractRequest, stream pdfexpb.Extractor_ExtractPagesServer) error {
+	if len(req.GetData()) == 0 {
+		return status.Error(codes.InvalidArgument, "no document data")
+	}
+
+	options := pdfex.DefaultParseOptions()
+	if s.Options != nil {
+		copied := *s.Options
+		options = &copied
+	}
+	options.PageRange = req.GetPageRange()
+	options.StripRunningLines = req.GetStripRunningLines()
+
+	doc, err := pdfex.ParsePDFFromBytesWithOptions(req.GetData(), req.GetName(), options)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	defer doc.Close()
+
+	info := &pdfexpb.DocumentInfo{
+		Name:         req.GetName(),
+		PdfVersion:   doc.Version(),
+		PageCount:    int32(doc.PageCount()),
+		SourceSha256: doc.SourceSHA256(),
+	}
+	if err := stream.Send(&pdfexpb.ExtractResponse{Result: &pdfexpb.ExtractResponse_Info{Info: info}}); err != nil {
+		return err
+	}
+
+	return doc.StreamPages(stream.Context(), func(page pdfex.PageResult) error {
+		result := &pdfexpb.PageResult{
+			Number:   int32(page.Number),
+			Width:    page.Width,
+			Height:   page.Height,
+			Rotation: int32(page.Rotation),
+			Text:     page.Text,
+		}
+		if req.GetIncludeSpans() {
+			for _, span := range page.Spans {
+				result.Spans = append(result.Spans, &pdfexpb.TextSpan{
+					Text:     span.Text,
+					X:        span.X,
+					Y:        span.Y,
+					Width:    span.Width,
+					FontName: span.FontName,
+					FontSize: span.FontSize,
+				})
+			}
+		}
+		if req.GetIncludeBlocks() {
+			for _, block := range page.Blocks {
+				entry := &pdfexpb.Block{Text: block.Text, Bounds: rect(block.Bounds)}
+				for _, paragraph := range block.Paragraphs {
+					entry.Paragraphs = append(entry.Paragraphs, &pdfexpb.Paragraph{Text: paragraph.Text, Bounds: rect(paragraph.Bounds)})
+				}
+				result.Blocks = append(result.Blocks, entry)
+			}
+		}
+		return stream.Send(&pdfexpb.ExtractResponse{Result: &pdfexpb.ExtractResponse_Page{Page: result}})
+	})
+}
+
+// rect converts a rectangle into its message
+func rect(r pdfex.Rect) *pdfexpb.Rect {
+	return &pdfexpb.Rect{X1: r.X1, Y1: r.Y1, X2: r.X2, Y2: r.Y2}
+}
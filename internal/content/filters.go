@@ -6,47 +6,52 @@ import (
 	"encoding/ascii85"
 	"fmt"
 	"io"
-	"strconv"
 	"strings"
 
+	"github.com/yourusername/pdfex/internal/metrics"
 	"github.com/yourusername/pdfex/internal/utils"
 )
 
 // DecompressStream decompresses a PDF stream based on its filter type
 func DecompressStream(stream []byte, filterSpec string, decodeParms map[string]interface{}) ([]byte, error) {
+	return DecompressStreamLimited(stream, filterSpec, decodeParms, 0)
+}
+
+// DecompressStreamLimited decompresses a stream like DecompressStream, stopping with a
+// *LimitError as soon as the output of a filter would exceed maxSize bytes; 0 means no limit
+func DecompressStreamLimited(stream []byte, filterSpec string, decodeParms map[string]interface{}, maxSize int64) ([]byte, error) {
+	result, err := decompressStream(stream, filterSpec, decodeParms, maxSize)
+	if err == nil {
+		metrics.Service.RecordDecompressed(len(result))
+	}
+	return result, err
+}
+
+// decompressStream decodes a stream through each of its filters
+func decompressStream(stream []byte, filterSpec string, decodeParms map[string]interface{}, maxSize int64) ([]byte, error) {
 	// Handle filter arrays like [/FlateDecode /ASCII85Decode]
 	if strings.HasPrefix(filterSpec, "[") && strings.HasSuffix(filterSpec, "]") {
 		filterArray := utils.ParseArray(filterSpec)
 		result := stream
 		var err error
 
-		// Apply filters in reverse order (last filter first)
-		for i := len(filterArray) - 1; i >= 0; i-- {
+		// Filters are listed in the order they are applied when decoding
+		for i := range filterArray {
 			// Get decode parameters for this filter if available
 			var filterParms map[string]interface{}
 			if decodeParms != nil {
-				if parmsArray, ok := decodeParms["Array"]; ok {
-					// If DecodeParms is an array, get the corresponding parameters
-					parmsArrayStr := parmsArray.(string)
-					if strings.HasPrefix(parmsArrayStr, "[") {
-						parmsItems := utils.ParseArray(parmsArrayStr)
-						if i < len(parmsItems) {
-							filterParms = make(map[string]interface{})
-							if strings.HasPrefix(parmsItems[i], "<<") {
-								parmBytes := []byte(parmsItems[i])[2 : len(parmsItems[i])-2]
-								err := utils.ParseDictionary(parmBytes, filterParms)
-								if err != nil {
-									return nil, fmt.Errorf("error parsing filter params: %v", err)
-								}
-							}
-						}
+				// If DecodeParms is an array, get the corresponding parameters
+				if parmsArray, ok := utils.DictFrom(decodeParms).Array("Array"); ok && i < len(parmsArray) {
+					filterParms = make(map[string]interface{})
+					if parms, ok := parmsArray[i].(utils.Dict); ok {
+						filterParms = parms.Map()
 					}
 				}
 			}
 
-			result, err = applySingleFilter(result, filterArray[i], filterParms)
+			result, err = applySingleFilter(result, filterArray[i], filterParms, maxSize)
 			if err != nil {
-				return nil, fmt.Errorf("filter %s error: %v", filterArray[i], err)
+				return nil, fmt.Errorf("filter %s error: %w", filterArray[i], err)
 			}
 		}
 
@@ -54,13 +59,26 @@ func DecompressStream(stream []byte, filterSpec string, decodeParms map[string]i
 	}
 
 	// Single filter
-	return applySingleFilter(stream, filterSpec, decodeParms)
+	return applySingleFilter(stream, filterSpec, decodeParms, maxSize)
 }
 
-// applySingleFilter applies a single filter to a stream
-func applySingleFilter(stream []byte, filterType string, decodeParms map[string]interface{}) ([]byte, error) {
+// applySingleFilter applies a single filter to a stream, recording failures in the service metrics
+func applySingleFilter(stream []byte, filterType string, decodeParms map[string]interface{}, maxSize int64) ([]byte, error) {
+	result, err := decodeFilter(stream, filterType, decodeParms, maxSize)
+	if err == nil && maxSize > 0 && int64(len(result)) > maxSize {
+		err = &LimitError{Limit: "MaxDecompressedStreamSize", Max: maxSize}
+	}
+	if err != nil {
+		metrics.Service.RecordFilterFailure(filterType)
+	}
+	return result, err
+}
+
+// decodeFilter decodes a stream with one filter. Flate streams stop inflating once they exceed
+// maxSize, so that a small stream can't expand to exhaust memory.
+func decodeFilter(stream []byte, filterType string, decodeParms map[string]interface{}, maxSize int64) ([]byte, error) {
 	switch filterType {
-	case "/FlatDecode":
+	case "/FlateDecode":
 		// Standard library zlib
 		reader := bytes.NewReader(stream)
 		zlibReader, err := zlib.NewReader(reader)
@@ -69,27 +87,35 @@ func applySingleFilter(stream []byte, filterType string, decodeParms map[string]
 		}
 		defer zlibReader.Close()
 
-		decompressed, err := io.ReadAll(zlibReader)
+		var inflated io.Reader = zlibReader
+		if maxSize > 0 {
+			inflated = io.LimitReader(zlibReader, maxSize+1)
+		}
+		decompressed, err := io.ReadAll(inflated)
 		if err != nil {
 			return nil, fmt.Errorf("zlib decompression failed: %v", err)
 		}
+		if maxSize > 0 && int64(len(decompressed)) > maxSize {
+			return nil, &LimitError{Limit: "MaxDecompressedStreamSize", Max: maxSize}
+		}
 
 		// Handle predictor if specified
-		if decodeParms != nil {
-			if predictorStr, ok := decodeParms["Predictor"]; ok {
-				predictor, _ := strconv.Atoi(predictorStr.(string))
-				if predictor > 1 {
-					// Apply predictor post-processing
-					return applyPredictor(decompressed, predictor, decodeParms)
-				}
-			}
+		if predictor := utils.GetInteger(decodeParms["Predictor"], 1); predictor > 1 {
+			// Apply predictor post-processing
+			return applyPredictor(decompressed, predictor, decodeParms)
 		}
 
 		return decompressed, nil
 
 	case "/ASCII85Decode":
 		// Standard library ascii85
-		decoder := ascii85.NewDecoder(bytes.NewReader(stream))
+		// The data ends at the ~> marker and may be wrapped in <~ ~> and whitespace
+		data := bytes.TrimSpace(stream)
+		data = bytes.TrimPrefix(data, []byte("<~"))
+		if end := bytes.Index(data, []byte("~>")); end >= 0 {
+			data = data[:end]
+		}
+		decoder := ascii85.NewDecoder(bytes.NewReader(data))
 		decoded, err := io.ReadAll(decoder)
 		if err != nil {
 			return nil, fmt.Errorf("ascii85 decoding failed: %v", err)
@@ -103,7 +129,7 @@ func applySingleFilter(stream []byte, filterType string, decodeParms map[string]
 
 	case "/RunLengthDecode":
 		// Custom implementation (simple algorithm)
-		return decodeRunLength(stream)
+		return decodeRunLength(stream, maxSize)
 
 	case "/DCTDecode":
 		// DCT (JPEG) - just return the stream as is since it's a JPEG image
@@ -128,12 +154,16 @@ func applySingleFilter(stream []byte, filterType string, decodeParms map[string]
 	}
 }
 
-// decodeRunLength decodes a run-length encoded stream
-func decodeRunLength(input []byte) ([]byte, error) {
+// decodeRunLength decodes a run-length encoded stream, stopping once the output exceeds
+// maxSize bytes unless it is 0
+func decodeRunLength(input []byte, maxSize int64) ([]byte, error) {
 	var output bytes.Buffer
 	i := 0
 
 	for i < len(input) {
+		if maxSize > 0 && int64(output.Len()) > maxSize {
+			return nil, &LimitError{Limit: "MaxDecompressedStreamSize", Max: maxSize}
+		}
 		length := int(input[i])
 
 		if length == 128 {
@@ -169,7 +199,7 @@ func decodeRunLength(input []byte) ([]byte, error) {
 // IsSupported returns whether a filter type is supported
 func IsSupported(filterType string) bool {
 	switch filterType {
-	case "/FlatDecode", "/ASCII85Decode", "/RunLengthDecode", "/DCTDecode", "/JPXDecode":
+	case "/FlateDecode", "/ASCII85Decode", "/RunLengthDecode", "/DCTDecode", "/JPXDecode":
 		return true
 	default:
 		return false
@@ -179,7 +209,7 @@ func IsSupported(filterType string) bool {
 // GetSupportedFilters returns a list of supported filter types
 func GetSupportedFilters() []string {
 	return []string{
-		"/FlatDecode",
+		"/FlateDecode",
 		"/ASCII85Decode",
 		"/RunLengthDecode",
 		"/DCTDecode",
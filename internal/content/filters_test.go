@@ -0,0 +1,71 @@
+package content
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"testing"
+)
+
+// encodeASCII85 encodes data with the ASCII85Decode filter's encoding, without the ~> marker
+func encodeASCII85(data []byte) []byte {
+	encoded := make([]byte, ascii85.MaxEncodedLen(len(data)))
+	return encoded[:ascii85.Encode(encoded, data)]
+}
+
+// deflate compresses data with zlib, as the FlateDecode filter expects
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestDecompressStream(t *testing.T) {
+	content := []byte("BT /F1 12 Tf 72 720 Td (Hello) Tj ET")
+	a85 := encodeASCII85(content)
+	tests := []struct {
+		name   string
+		filter string
+		stream []byte
+	}{
+		{"flate", "/FlateDecode", deflate(content)},
+		{"ascii85", "/ASCII85Decode", append(a85, "~>"...)},
+		{"ascii85 without ~>", "/ASCII85Decode", a85},
+		{"ascii85 wrapped", "/ASCII85Decode", append(append([]byte("<~"), a85...), "~>\n"...)},
+		{"ascii85 then flate", "[/ASCII85Decode /FlateDecode]", append(encodeASCII85(deflate(content)), "~>"...)},
+		{"ascii85 without ~> then flate", "[/ASCII85Decode /FlateDecode]", encodeASCII85(deflate(content))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecompressStream(tt.stream, tt.filter, nil)
+			if err != nil {
+				t.Fatalf("DecompressStream: %v", err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("got %q, want %q", got, content)
+			}
+		})
+	}
+}
+
+func TestDecompressStreamPredictor(t *testing.T) {
+	// Two rows of three bytes, the second encoded with the PNG Up filter
+	stream := deflate([]byte{0, 1, 2, 3, 2, 3, 4, 5})
+	got, err := DecompressStream(stream, "/FlateDecode", map[string]interface{}{"Predictor": 12, "Columns": 3})
+	if err != nil {
+		t.Fatalf("DecompressStream: %v", err)
+	}
+	if want := []byte{1, 2, 3, 4, 6, 8}; !bytes.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDecompressStreamFilterOrder(t *testing.T) {
+	// The filters are listed in the order they decode, so the reverse order fails
+	stream := append(encodeASCII85(deflate([]byte("Hello"))), "~>"...)
+	if _, err := DecompressStream(stream, "[/FlateDecode /ASCII85Decode]", nil); err == nil {
+		t.Error("decoding ASCII85 data with FlateDecode first succeeded")
+	}
+}
@@ -0,0 +1,15 @@
+package content
+
+import "fmt"
+
+// LimitError is returned when a document exceeds one of the resource limits of a parse, such
+// as the decompressed size of a stream. Limit is the name of the option, e.g.
+// "MaxDecompressedStreamSize".
+type LimitError struct {
+	Limit string
+	Max   int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("resource limit exceeded: %s of %d", e.Limit, e.Max)
+}
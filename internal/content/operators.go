@@ -0,0 +1,223 @@
+package content
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Operation represents a single content stream operator together with its operands.
+// Operands are kept in their raw PDF syntax, e.g. "12", "/F1", "(Hello)", "<48656C6C6F>"
+// or "[(A) -120 (B)]", so callers can interpret them in the context of the operator.
+type Operation struct {
+	Operator string
+	Operands []string
+}
+
+// ParseOperations splits a content stream into its sequence of operations
+func ParseOperations(data []byte) []Operation {
+	var ops []Operation
+	var operands []string
+
+	pos := 0
+	for {
+		token, next, ok := nextToken(data, pos)
+		if !ok {
+			break
+		}
+		pos = next
+
+		if isOperandToken(token) {
+			operands = append(operands, token)
+			continue
+		}
+
+		ops = append(ops, Operation{Operator: token, Operands: operands})
+		operands = nil
+
+		// Inline image data is binary and must be skipped up to the EI marker
+		if token == "ID" {
+			pos = skipInlineImageData(data, pos)
+		}
+	}
+
+	return ops
+}
+
+// ParseArrayOperand splits an array operand such as "[(A) -120 (B)]" into its elements
+func ParseArrayOperand(operand string) []string {
+	operand = strings.TrimSpace(operand)
+	if !strings.HasPrefix(operand, "[") || !strings.HasSuffix(operand, "]") {
+		return nil
+	}
+
+	inner := []byte(operand[1 : len(operand)-1])
+	var items []string
+
+	pos := 0
+	for {
+		token, next, ok := nextToken(inner, pos)
+		if !ok {
+			break
+		}
+		items = append(items, token)
+		pos = next
+	}
+
+	return items
+}
+
+// nextToken returns the next token starting at pos, skipping whitespace and comments
+func nextToken(data []byte, pos int) (string, int, bool) {
+	// Skip whitespace and comments
+	for pos < len(data) {
+		c := data[pos]
+		if isWhitespace(c) {
+			pos++
+		} else if c == '%' {
+			for pos < len(data) && data[pos] != '\n' && data[pos] != '\r' {
+				pos++
+			}
+		} else {
+			break
+		}
+	}
+
+	if pos >= len(data) {
+		return "", pos, false
+	}
+
+	start := pos
+	switch c := data[pos]; {
+	case c == '(':
+		end := scanLiteralString(data, pos)
+		return string(data[start:end]), end, true
+
+	case c == '<' && pos+1 < len(data) && data[pos+1] == '<':
+		end := scanBalanced(data, pos, "<<", ">>")
+		return string(data[start:end]), end, true
+
+	case c == '<':
+		end := bytes.IndexByte(data[pos:], '>')
+		if end == -1 {
+			return string(data[start:]), len(data), true
+		}
+		return string(data[start : pos+end+1]), pos + end + 1, true
+
+	case c == '[':
+		end := scanBalanced(data, pos, "[", "]")
+		return string(data[start:end]), end, true
+
+	case c == '{' || c == '}' || c == ']' || c == ')' || c == '>':
+		// Stray delimiters are returned as single-character tokens
+		return string(c), pos + 1, true
+
+	case c == '/':
+		pos++
+		for pos < len(data) && !isWhitespace(data[pos]) && !isDelimiter(data[pos]) {
+			pos++
+		}
+		return string(data[start:pos]), pos, true
+
+	default:
+		for pos < len(data) && !isWhitespace(data[pos]) && !isDelimiter(data[pos]) {
+			pos++
+		}
+		return string(data[start:pos]), pos, true
+	}
+}
+
+// scanLiteralString returns the position just past the literal string starting at pos,
+// honouring nested parentheses and backslash escapes
+func scanLiteralString(data []byte, pos int) int {
+	depth := 0
+	for pos < len(data) {
+		switch data[pos] {
+		case '\\':
+			pos++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return pos + 1
+			}
+		}
+		pos++
+	}
+	return len(data)
+}
+
+// scanBalanced returns the position just past a balanced open/close region starting at pos.
+// Literal strings inside the region are skipped so delimiters within them are ignored.
+func scanBalanced(data []byte, pos int, open, close string) int {
+	depth := 0
+	for pos < len(data) {
+		if data[pos] == '(' {
+			pos = scanLiteralString(data, pos)
+			continue
+		}
+		if bytes.HasPrefix(data[pos:], []byte(open)) {
+			depth++
+			pos += len(open)
+			continue
+		}
+		if bytes.HasPrefix(data[pos:], []byte(close)) {
+			depth--
+			pos += len(close)
+			if depth == 0 {
+				return pos
+			}
+			continue
+		}
+		pos++
+	}
+	return len(data)
+}
+
+// skipInlineImageData skips past the binary data of an inline image to the end of its EI marker
+func skipInlineImageData(data []byte, pos int) int {
+	// A single whitespace byte separates ID from the image data
+	if pos < len(data) && isWhitespace(data[pos]) {
+		pos++
+	}
+
+	for i := pos; i+1 < len(data); i++ {
+		if data[i] == 'E' && data[i+1] == 'I' &&
+			(i == 0 || isWhitespace(data[i-1])) &&
+			(i+2 >= len(data) || isWhitespace(data[i+2]) || isDelimiter(data[i+2])) {
+			return i + 2
+		}
+	}
+
+	return len(data)
+}
+
+// isOperandToken reports whether a token is an operand rather than an operator
+func isOperandToken(token string) bool {
+	if token == "" {
+		return false
+	}
+
+	switch c := token[0]; {
+	case c == '/' || c == '(' || c == '<' || c == '[':
+		return true
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return true
+	}
+
+	return token == "true" || token == "false" || token == "null"
+}
+
+// isWhitespace reports whether a byte is PDF whitespace
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+// isDelimiter reports whether a byte is a PDF delimiter character
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
@@ -2,37 +2,19 @@ package content
 
 import (
 	"fmt"
-	"strconv"
+
+	"github.com/yourusername/pdfex/internal/utils"
 )
 
-// applyPredictor applies predictor algorithms for FlatDecode and LZWDecode
+// applyPredictor applies predictor algorithms for FlateDecode and LZWDecode
 func applyPredictor(data []byte, predictor int, decodeParms map[string]interface{}) ([]byte, error) {
 	// Get parameters
-	columns := 1
-	if columnsStr, ok := decodeParms["Columns"]; ok {
-		var err error
-		columns, err = strconv.Atoi(columnsStr.(string))
-		if err != nil {
-			return nil, fmt.Errorf("invalid Columns value: %v", err)
-		}
-	}
-
-	colors := 1
-	if colorsStr, ok := decodeParms["Colors"]; ok {
-		var err error
-		colors, err = strconv.Atoi(colorsStr.(string))
-		if err != nil {
-			return nil, fmt.Errorf("invalid Colors value: %v", err)
-		}
-	}
-
-	bitsPerComponent := 8
-	if bpcStr, ok := decodeParms["BitsPerComponent"]; ok {
-		var err error
-		bitsPerComponent, err = strconv.Atoi(bpcStr.(string))
-		if err != nil {
-			return nil, fmt.Errorf("invalid BitsPerComponent value: %v", err)
-		}
+	columns := utils.GetInteger(decodeParms["Columns"], 1)
+	colors := utils.GetInteger(decodeParms["Colors"], 1)
+	bitsPerComponent := utils.GetInteger(decodeParms["BitsPerComponent"], 8)
+	if columns < 1 || colors < 1 || bitsPerComponent < 1 {
+		return nil, fmt.Errorf("invalid predictor parameters: Columns %d, Colors %d, BitsPerComponent %d",
+			columns, colors, bitsPerComponent)
 	}
 
 	// Calculate bytes per pixel and row stride
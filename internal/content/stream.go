@@ -11,6 +11,15 @@ import (
 	"github.com/yourusername/pdfex/internal/utils"
 )
 
+// Regular expressions for text and inline image operators
+var (
+	textObjectRegex     = regexp.MustCompile(`BT(.*?)ET`)
+	showTextRegex       = regexp.MustCompile(`\((.*?)\)\s+Tj`)
+	showTextArrayRegex  = regexp.MustCompile(`\[(.*?)\]\s+TJ`)
+	stringOperandRegex  = regexp.MustCompile(`\((.*?)\)`)
+	inlineImageKeyRegex = regexp.MustCompile(`/([A-Za-z0-9]+)\s+([^/\s]+)`)
+)
+
 // StreamProcessor handles PDF stream processing
 type StreamProcessor struct {
 	ObjectNumber int
@@ -32,7 +41,7 @@ func NewStreamProcessor(objNum int, stream []byte, dict map[string]interface{})
 // Process processes the stream with all filters
 func (sp *StreamProcessor) Process() error {
 	// Check if stream has a filter
-	if filter, ok := sp.Dictionary["Filter"]; ok {
+	if filter := utils.DictFrom(sp.Dictionary).Get("Filter"); filter != nil {
 		// Get decode parameters if any
 		var decodeParms map[string]interface{}
 		if parms, ok := sp.Dictionary["DecodeParms"]; ok {
@@ -52,7 +61,7 @@ func (sp *StreamProcessor) Process() error {
 		}
 
 		// Decompress the stream based on filter type
-		decompressed, err := DecompressStream(sp.Stream, filter.(string), decodeParms)
+		decompressed, err := DecompressStream(sp.Stream, filter.String(), decodeParms)
 		if err != nil {
 			return fmt.Errorf("failed to decompress stream: %v", err)
 		}
@@ -71,9 +80,8 @@ func (sp *StreamProcessor) GetLength() (int, error) {
 		switch l := lengthObj.(type) {
 		case string:
 			if strings.Contains(l, " R") {
-				// Length is an indirect reference
-				// Here we would normally look up the object, but for simplicity
-				// we'll just return the actual length
+				// Length is an indirect reference, resolved by the document when it delimited
+				// the stream data, so the data holds exactly that many bytes
 				return len(sp.Stream), nil
 			}
 			// Length is a direct value
@@ -94,13 +102,14 @@ func (sp *StreamProcessor) GetLength() (int, error) {
 // GetStreamType returns the stream type based on the dictionary
 func (sp *StreamProcessor) GetStreamType() string {
 	// Check for Type key
-	if typeObj, ok := sp.Dictionary["Type"]; ok {
-		return typeObj.(string)
+	dict := utils.DictFrom(sp.Dictionary)
+	if typeName, ok := dict.Get("Type").(utils.Name); ok {
+		return typeName.String()
 	}
 
 	// Check for Subtype key
-	if subtypeObj, ok := sp.Dictionary["Subtype"]; ok {
-		return subtypeObj.(string)
+	if subtype, ok := dict.Get("Subtype").(utils.Name); ok {
+		return subtype.String()
 	}
 
 	// Check content type based on other keys
@@ -143,15 +152,13 @@ func (sp *StreamProcessor) ExtractText() (string, error) {
 	}
 
 	// Find all text objects
-	textRegex := regexp.MustCompile(`BT(.*?)ET`)
-	textMatches := textRegex.FindAll(sp.Stream, -1)
+	textMatches := textObjectRegex.FindAll(sp.Stream, -1)
 
 	var textBuilder strings.Builder
 
 	for _, textBlock := range textMatches {
 		// Extract text showing operators
-		tjRegex := regexp.MustCompile(`\((.*?)\)\s+Tj`)
-		tjMatches := tjRegex.FindAllSubmatch(textBlock, -1)
+		tjMatches := showTextRegex.FindAllSubmatch(textBlock, -1)
 
 		for _, match := range tjMatches {
 			textBytes := match[1]
@@ -161,15 +168,13 @@ func (sp *StreamProcessor) ExtractText() (string, error) {
 		}
 
 		// Handle TJ operator
-		tjArrayRegex := regexp.MustCompile(`\[(.*?)\]\s+TJ`)
-		tjArrayMatches := tjArrayRegex.FindAllSubmatch(textBlock, -1)
+		tjArrayMatches := showTextArrayRegex.FindAllSubmatch(textBlock, -1)
 
 		for _, tjArrayMatch := range tjArrayMatches {
 			tjArray := tjArrayMatch[1]
 
 			// Extract string parts from the TJ array
-			stringRegex := regexp.MustCompile(`\((.*?)\)`)
-			stringMatches := stringRegex.FindAllSubmatch(tjArray, -1)
+			stringMatches := stringOperandRegex.FindAllSubmatch(tjArray, -1)
 
 			for _, match := range stringMatches {
 				textBytes := match[1]
@@ -203,8 +208,7 @@ func ProcessInlineImage(content []byte) (map[string]interface{}, []byte, error)
 	dict := make(map[string]interface{})
 
 	// Parse inline image dictionary (simple key-value pairs)
-	keyValueRegex := regexp.MustCompile(`/([A-Za-z0-9]+)\s+([^/\s]+)`)
-	matches := keyValueRegex.FindAllSubmatch(dictBytes, -1)
+	matches := inlineImageKeyRegex.FindAllSubmatch(dictBytes, -1)
 
 	for _, match := range matches {
 		key := string(match[1])
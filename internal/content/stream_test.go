@@ -0,0 +1,42 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// benchmarkContent builds a content stream with the given number of text objects, each showing
+// text with Tj and TJ
+func benchmarkContent(objects int) []byte {
+	var content strings.Builder
+	for i := 0; i < objects; i++ {
+		fmt.Fprintf(&content, "q 1 0 0 1 %d %d cm 0 0 m 10 10 l S Q\n", i, i)
+		fmt.Fprintf(&content, "BT /F1 12 Tf 72 %d Td (Line %d of the page) Tj ", 700-i, i)
+		content.WriteString("[(Kerned) -120 (text) 250 (runs)] TJ ET\n")
+	}
+	return []byte(content.String())
+}
+
+func BenchmarkExtractText(b *testing.B) {
+	stream := benchmarkContent(200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewStreamProcessor(1, stream, nil).ExtractText(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkProcessInlineImage(b *testing.B) {
+	content := []byte("q 8 0 0 8 0 0 cm BI /W 8 /H 8 /CS /G /BPC 8 /F /AHx ID " +
+		strings.Repeat("00FF", 32) + "> EI Q")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := ProcessInlineImage(content); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
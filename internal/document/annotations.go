@@ -0,0 +1,39 @@
+package document
+
+import (
+	"strings"
+)
+
+// Annotation is an annotation of a page, such as a comment, highlight, link or form widget
+type Annotation struct {
+	PageNumber int        // 1-based
+	Subtype    string     // Annotation type without the slash, e.g. Text or Highlight
+	Rect       [4]float64 // Lower-left and upper-right corners in points (see PDFPage.UserUnit)
+	Contents   string     // Text of the annotation, or an alternate description
+	Author     string     // /T, the author of markup annotations
+	Modified   string     // /M as written in the file
+}
+
+// GetAnnotations returns the annotations of every page in page order and /Annots order.
+// Annotations whose /Rect can't be read are skipped.
+func (doc *PDFDocument) GetAnnotations() []Annotation {
+	var annotations []Annotation
+	for _, page := range doc.Pages {
+		for _, annot := range doc.pageAnnotations(page) {
+			entries := dictionaryEntries(doc.resolveSource(annot))
+			rect, ok := parseRect(string(doc.resolveSource(entries["Rect"])))
+			if !ok {
+				continue
+			}
+			annotations = append(annotations, Annotation{
+				PageNumber: page.PageNumber,
+				Subtype:    strings.TrimPrefix(entries["Subtype"], "/"),
+				Rect:       scaleRect(rect, page.Unit()),
+				Contents:   doc.textEntry(entries["Contents"]),
+				Author:     doc.textEntry(entries["T"]),
+				Modified:   doc.textEntry(entries["M"]),
+			})
+		}
+	}
+	return annotations
+}
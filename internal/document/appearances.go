@@ -0,0 +1,66 @@
+package document
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Annotation flags (PDF 32000-1:2008, table 165) that keep an annotation off the screen
+const (
+	annotationFlagHidden = 1 << 1
+	annotationFlagNoView = 1 << 5
+)
+
+// AnnotationAppearance is the normal appearance (/AP /N) of an annotation: a form XObject that
+// viewers draw into the annotation's rectangle on top of the page content
+type AnnotationAppearance struct {
+	Subtype string     // Annotation type without the slash, e.g. FreeText or Widget
+	Rect    [4]float64 // Rectangle the appearance is fitted into, in default user space
+	Form    *FormXObject
+	Hidden  bool // The annotation's /OC is hidden in the default configuration
+}
+
+// annotationAppearances returns the normal appearances of a page's annotations in /Annots order.
+// Hidden annotations, popups and annotations without an appearance stream are skipped. For
+// annotations with several appearance states, such as check boxes, the one named by /AS is used.
+func (doc *PDFDocument) annotationAppearances(page PDFPage, forms map[int]*FormXObject, visible map[int]bool) ([]AnnotationAppearance, error) {
+	var appearances []AnnotationAppearance
+	for _, annot := range doc.pageAnnotations(page) {
+		entries := dictionaryEntries(doc.resolveSource(annot))
+		subtype := strings.TrimPrefix(entries["Subtype"], "/")
+		if subtype == "Popup" {
+			continue
+		}
+		if flags, _ := strconv.Atoi(string(doc.resolveSource(entries["F"]))); flags&(annotationFlagHidden|annotationFlagNoView) != 0 {
+			continue
+		}
+		rect, ok := parseRect(string(doc.resolveSource(entries["Rect"])))
+		if !ok {
+			continue
+		}
+
+		normal := dictionaryEntries(doc.resolveSource(entries["AP"]))["N"]
+		objNum, ok := referenceNumber(normal)
+		if obj, isObject := doc.Objects[objNum]; !ok || !isObject || !obj.IsStream {
+			// A dictionary of appearance states
+			objNum, ok = referenceNumber(dictionaryEntries(doc.resolveSource(normal))[strings.TrimPrefix(entries["AS"], "/")])
+		}
+		if !ok {
+			continue
+		}
+		form, err := doc.formXObject(objNum, forms, visible, 1)
+		if err != nil {
+			return nil, err
+		}
+		if form == nil {
+			continue
+		}
+
+		appearance := AnnotationAppearance{Subtype: subtype, Rect: rect, Form: form}
+		if oc, ok := referenceNumber(entries["OC"]); ok {
+			appearance.Hidden = !doc.optionalContentVisible(oc, visible)
+		}
+		appearances = append(appearances, appearance)
+	}
+	return appearances, nil
+}
@@ -0,0 +1,119 @@
+package document
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// PageBoxes are the boundaries of a page as [llx lly urx ury], in points: default user space
+// scaled by the page's /UserUnit
+type PageBoxes struct {
+	MediaBox [4]float64 // Extent of the physical medium
+	CropBox  [4]float64 // Visible region when displayed or printed; defaults to the media box
+	BleedBox [4]float64 // Region to clip to in production; defaults to the crop box
+	TrimBox  [4]float64 // Intended size of the finished page; defaults to the crop box
+	ArtBox   [4]float64 // Extent of the meaningful content; defaults to the crop box
+}
+
+// BoxContains reports whether a point lies inside a box, allowing a small tolerance for rounding
+func BoxContains(box [4]float64, x, y float64) bool {
+	const tolerance = 0.5
+	return x >= box[0]-tolerance && x <= box[2]+tolerance && y >= box[1]-tolerance && y <= box[3]+tolerance
+}
+
+// processPageBoxes reads the boundary boxes of every page. MediaBox and CropBox are inherited
+// from the page tree; the other boxes are not. Boxes are clipped to the media box, as viewers
+// do. Pages whose size wasn't read with the page tree take it from the media box. Pages with a
+// /UserUnit, such as large engineering drawings, have their size and boxes scaled by it.
+func processPageBoxes(doc *PDFDocument) {
+	for i := range doc.Pages {
+		page := &doc.Pages[i]
+		obj, ok := doc.Objects[page.ObjectNumber]
+		if !ok {
+			continue
+		}
+
+		media, ok := parseRect(string(doc.resolveSource(doc.inheritedEntry(obj, "MediaBox"))))
+		if !ok {
+			// Letter size is the customary default for pages without a media box
+			media = [4]float64{0, 0, 612, 792}
+			if page.Width > 0 && page.Height > 0 {
+				media = [4]float64{0, 0, page.Width, page.Height}
+			}
+		}
+		if page.Width <= 0 || page.Height <= 0 {
+			page.Width, page.Height = media[2]-media[0], media[3]-media[1]
+		}
+
+		boxes := PageBoxes{MediaBox: media, CropBox: media}
+		if crop, ok := parseRect(string(doc.resolveSource(doc.inheritedEntry(obj, "CropBox")))); ok {
+			boxes.CropBox = doc.clipBox(crop, media, media)
+		}
+
+		entries := dictionaryEntries(objectSource(obj))
+		for _, b := range []struct {
+			key string
+			box *[4]float64
+		}{{"BleedBox", &boxes.BleedBox}, {"TrimBox", &boxes.TrimBox}, {"ArtBox", &boxes.ArtBox}} {
+			*b.box = boxes.CropBox
+			if rect, ok := parseRect(string(doc.resolveSource(entries[b.key]))); ok {
+				*b.box = doc.clipBox(rect, media, boxes.CropBox)
+			}
+		}
+
+		page.UserUnit = 1
+		if unit, err := strconv.ParseFloat(strings.TrimSpace(string(doc.resolveSource(entries["UserUnit"]))), 64); err == nil {
+			if unit > 0 {
+				page.UserUnit = unit
+			} else {
+				doc.warnf(StagePages, page.ObjectNumber, "Ignoring invalid /UserUnit %v on page %d", unit, page.PageNumber)
+			}
+		}
+		if page.UserUnit != 1 {
+			page.Width *= page.UserUnit
+			page.Height *= page.UserUnit
+			for _, box := range []*[4]float64{&boxes.MediaBox, &boxes.CropBox, &boxes.BleedBox, &boxes.TrimBox, &boxes.ArtBox} {
+				*box = scaleRect(*box, page.UserUnit)
+			}
+		}
+
+		page.Boxes = boxes
+	}
+}
+
+// scaleRect scales a rectangle by a user space unit
+func scaleRect(rect [4]float64, unit float64) [4]float64 {
+	return [4]float64{rect[0] * unit, rect[1] * unit, rect[2] * unit, rect[3] * unit}
+}
+
+// clipBox clips a box to the media box, or returns the fallback if it lies outside it
+func (doc *PDFDocument) clipBox(box, media, fallback [4]float64) [4]float64 {
+	clipped := [4]float64{math.Max(box[0], media[0]), math.Max(box[1], media[1]), math.Min(box[2], media[2]), math.Min(box[3], media[3])}
+	if clipped[0] >= clipped[2] || clipped[1] >= clipped[3] {
+		utils.Warnf(doc.Logger(), "Page box %v lies outside the media box %v\n", box, media)
+		return fallback
+	}
+	return clipped
+}
+
+// inheritedEntry returns the raw value of a page dictionary entry, following /Parent links for
+// inheritable keys
+func (doc *PDFDocument) inheritedEntry(obj PDFObject, key string) string {
+	for depth := 0; depth < maxPageTreeDepth; depth++ {
+		entries := dictionaryEntries(objectSource(obj))
+		if value, ok := entries[key]; ok {
+			return value
+		}
+		parent, ok := referenceNumber(entries["Parent"])
+		if !ok {
+			break
+		}
+		if obj, ok = doc.Objects[parent]; !ok {
+			break
+		}
+	}
+	return ""
+}
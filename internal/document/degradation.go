@@ -0,0 +1,147 @@
+package document
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// ParsePath identifies how the object table of a document was obtained
+type ParsePath string
+
+// Parse paths, from most to least trustworthy
+const (
+	ParsePathXRef         ParsePath = "xref"          // Cross-reference table at the startxref offset
+	ParsePathAdjustedXRef ParsePath = "adjusted-xref" // Cross-reference table found near a wrong startxref offset
+	ParsePathRebuild      ParsePath = "rebuild"       // Table rebuilt by scanning the file for objects
+	ParsePathLinear       ParsePath = "linear"        // No usable xref; objects parsed linearly
+)
+
+// DegradationReport records which parse path produced the document and, in verification
+// mode, how the cross-reference table compares with a full scan of the file
+type DegradationReport struct {
+	Path             ParsePath
+	XRefObjectCount  int   // In-use entries found in the cross-reference table
+	ScanObjectCount  int   // Objects found by scanning the file (rebuild, linear or verification)
+	Verified         bool  // Whether both paths were run and compared
+	OnlyInXRef       []int // Objects listed in the xref table but not found by the scan
+	OnlyInScan       []int // Objects found by the scan but missing from the xref table
+	OffsetMismatches []int // Objects whose xref offset differs from the scanned offset
+}
+
+// Degraded reports whether the document was produced by a recovery path
+func (r *DegradationReport) Degraded() bool {
+	return r.Path != ParsePathXRef
+}
+
+// Consistent reports whether verification found no differences between the two paths
+func (r *DegradationReport) Consistent() bool {
+	return r.Verified && len(r.OnlyInXRef) == 0 && len(r.OnlyInScan) == 0 && len(r.OffsetMismatches) == 0
+}
+
+// Summary returns a one-line human-readable description of the report
+func (r *DegradationReport) Summary() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("parse path: %s, xref objects: %d, scanned objects: %d",
+		r.Path, r.XRefObjectCount, r.ScanObjectCount))
+
+	if r.Verified {
+		sb.WriteString(fmt.Sprintf(", only in xref: %d, only in scan: %d, offset mismatches: %d",
+			len(r.OnlyInXRef), len(r.OnlyInScan), len(r.OffsetMismatches)))
+	}
+
+	return sb.String()
+}
+
+// Warnings describes the recovery steps and verification differences as warning messages
+func (r *DegradationReport) Warnings() []string {
+	var warnings []string
+	switch r.Path {
+	case ParsePathAdjustedXRef:
+		warnings = append(warnings, "startxref offset was wrong; xref table found nearby")
+	case ParsePathRebuild:
+		warnings = append(warnings, "xref table unusable; rebuilt by scanning the file")
+	case ParsePathLinear:
+		warnings = append(warnings, "xref table unusable; objects parsed linearly")
+	}
+
+	if r.Verified {
+		if len(r.OnlyInXRef) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d xref entries not found by scanning", len(r.OnlyInXRef)))
+		}
+		if len(r.OnlyInScan) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d objects missing from the xref table", len(r.OnlyInScan)))
+		}
+		if len(r.OffsetMismatches) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d xref offsets differ from scanned offsets", len(r.OffsetMismatches)))
+		}
+	}
+
+	return warnings
+}
+
+// DegradationReport returns the report describing how the document was parsed
+func (doc *PDFDocument) DegradationReport() *DegradationReport {
+	return &doc.degradation
+}
+
+// countInUseXRefEntries returns the number of in-use entries in the xref table
+func countInUseXRefEntries(table map[int]PDFXRefEntry) int {
+	count := 0
+	for _, entry := range table {
+		if entry.InUse {
+			count++
+		}
+	}
+	return count
+}
+
+// verifyXRef rebuilds the cross-reference table by scanning the file and compares it
+// with the table the document was parsed from
+func verifyXRef(file *os.File, doc *PDFDocument) {
+	scanned := &PDFDocument{XRefTable: make(map[int]PDFXRefEntry)}
+	if err := rebuildXRefTable(file, scanned); err != nil {
+		doc.warnf(StageXRef, 0, "XRef verification scan failed: %v", err)
+		return
+	}
+
+	report := &doc.degradation
+	report.Verified = true
+	report.ScanObjectCount = len(scanned.XRefTable)
+	report.OnlyInXRef, report.OnlyInScan, report.OffsetMismatches = compareXRef(doc.XRefTable, scanned.XRefTable)
+
+	if !report.Consistent() {
+		utils.Warnf(doc.Logger(), "XRef verification found differences: %s\n", report.Summary())
+	}
+}
+
+// compareXRef compares an xref table with one rebuilt by scanning the file, returning the
+// objects only in the table, those only found by the scan and those whose offsets differ,
+// each sorted
+func compareXRef(table, scanned map[int]PDFXRefEntry) (onlyInXRef, onlyInScan, offsetMismatches []int) {
+	for objNum, entry := range table {
+		if !entry.InUse {
+			continue
+		}
+		scannedEntry, ok := scanned[objNum]
+		if !ok {
+			onlyInXRef = append(onlyInXRef, objNum)
+		} else if scannedEntry.Offset != entry.Offset {
+			offsetMismatches = append(offsetMismatches, objNum)
+		}
+	}
+
+	for objNum := range scanned {
+		if entry, ok := table[objNum]; !ok || !entry.InUse {
+			onlyInScan = append(onlyInScan, objNum)
+		}
+	}
+
+	sort.Ints(onlyInXRef)
+	sort.Ints(onlyInScan)
+	sort.Ints(offsetMismatches)
+	return onlyInXRef, onlyInScan, offsetMismatches
+}
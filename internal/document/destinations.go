@@ -0,0 +1,231 @@
+package document
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Maximum number of name tree nodes visited, to guard against cyclic /Kids links
+const maxNameTreeNodes = 10000
+
+// Destination is the target of a bookmark or link: a view of a page, or a URI for web links.
+// Coordinates are in the default user space of the page; nil values leave the current view
+// unchanged.
+type Destination struct {
+	PageNumber int    // 1-based, 0 if it could not be resolved
+	Name       string // Named destination that was resolved, if any
+	Fit        string // View type: XYZ, Fit, FitH, FitV, FitR, FitB, FitBH or FitBV
+	Left       *float64
+	Top        *float64
+	Right      *float64
+	Bottom     *float64
+	Zoom       *float64
+	URI        string // Target of a URI action
+}
+
+// linkDestination resolves the target of an outline item or link annotation from /Dest or its
+// action
+func (doc *PDFDocument) linkDestination(item map[string]string, named map[string]string) Destination {
+	dest, ok := item["Dest"]
+	if !ok {
+		action := dictionaryEntries(doc.resolveSource(item["A"]))
+		switch action["S"] {
+		case "/GoTo":
+			dest = action["D"]
+		case "/URI":
+			return Destination{URI: utils.DecodeTextString(string(doc.resolveSource(action["URI"])))}
+		}
+	}
+	return doc.resolveDestination(dest, named)
+}
+
+// resolveDestination resolves an explicit destination array, a destination dictionary or the
+// name of a destination
+func (doc *PDFDocument) resolveDestination(value string, named map[string]string) Destination {
+	value = strings.TrimSpace(string(doc.resolveSource(value)))
+
+	switch {
+	case strings.HasPrefix(value, "["):
+		return doc.explicitDestination(value)
+	case strings.HasPrefix(value, "<<"):
+		return doc.resolveDestination(dictionaryEntries([]byte(value))["D"], nil)
+	case strings.HasPrefix(value, "/"), strings.HasPrefix(value, "("), strings.HasPrefix(value, "<"):
+		name := destinationName(value)
+		target, ok := named[name]
+		if !ok {
+			utils.Warnf(doc.Logger(), "Named destination %q not found\n", name)
+			return Destination{Name: name}
+		}
+		// Named destinations resolve to explicit ones, never to other names
+		dest := doc.resolveDestination(target, nil)
+		dest.Name = name
+		return dest
+	}
+	return Destination{}
+}
+
+// explicitDestination parses a destination array such as [3 0 R /XYZ 72 720 0]
+func (doc *PDFDocument) explicitDestination(value string) Destination {
+	var dest Destination
+	items := joinReferences(content.ParseArrayOperand(value))
+	if len(items) == 0 {
+		return dest
+	}
+
+	// The page is a page object, or a page index for destinations in other documents
+	if objNum, err := utils.ExtractReference(items[0]); err == nil && utils.IsReference(items[0]) {
+		dest.PageNumber = doc.PageNumberForObject(objNum)
+	} else if index, err := strconv.Atoi(items[0]); err == nil {
+		dest.PageNumber = index + 1
+	}
+	if len(items) < 2 {
+		return dest
+	}
+
+	dest.Fit = strings.TrimPrefix(items[1], "/")
+	params := items[2:]
+	param := func(i int) *float64 {
+		if i >= len(params) {
+			return nil
+		}
+		v, err := utils.ParseFloat(params[i])
+		if err != nil {
+			return nil // null
+		}
+		return &v
+	}
+
+	switch dest.Fit {
+	case "XYZ":
+		dest.Left, dest.Top, dest.Zoom = param(0), param(1), param(2)
+	case "FitH", "FitBH":
+		dest.Top = param(0)
+	case "FitV", "FitBV":
+		dest.Left = param(0)
+	case "FitR":
+		dest.Left, dest.Bottom, dest.Right, dest.Top = param(0), param(1), param(2), param(3)
+	}
+	return dest
+}
+
+// namedDestinations collects the named destinations of the document, from the catalog's /Dests
+// dictionary and the /Dests name tree, mapping each name to its destination source
+func (doc *PDFDocument) namedDestinations() map[string]string {
+	named := make(map[string]string)
+
+	catalog, ok := doc.GetRootObject()
+	if !ok {
+		return named
+	}
+	entries := dictionaryEntries(objectSource(catalog))
+
+	visited := make(map[int]bool)
+	doc.collectNameTree(doc.resolveSource(entries["Dests"]), named, visited)
+	names := dictionaryEntries(doc.resolveSource(entries["Names"]))
+	doc.collectNameTree(doc.resolveSource(names["Dests"]), named, visited)
+
+	return named
+}
+
+// collectNameTree adds the entries of a name tree node, or of a plain dictionary such as the
+// PDF 1.1 /Dests, to named
+func (doc *PDFDocument) collectNameTree(node []byte, named map[string]string, visited map[int]bool) {
+	if len(node) == 0 || len(visited) >= maxNameTreeNodes {
+		return
+	}
+
+	entries := dictionaryEntries(node)
+	names, hasNames := entries["Names"]
+	kids, hasKids := entries["Kids"]
+	if !hasNames && !hasKids {
+		// A plain dictionary mapping names to values
+		for name, dest := range entries {
+			named[name] = dest
+		}
+		return
+	}
+
+	items := joinReferences(content.ParseArrayOperand(string(doc.resolveSource(names))))
+	for i := 0; i+1 < len(items); i += 2 {
+		named[destinationName(items[i])] = items[i+1]
+	}
+
+	for _, kid := range joinReferences(content.ParseArrayOperand(string(doc.resolveSource(kids)))) {
+		objNum, err := utils.ExtractReference(kid)
+		if err != nil || visited[objNum] {
+			continue
+		}
+		visited[objNum] = true
+		if obj, ok := doc.Objects[objNum]; ok {
+			doc.collectNameTree(objectSource(obj), named, visited)
+		}
+	}
+}
+
+// dictionaryEntries splits the source of a dictionary into its top-level entries, keyed by
+// name without the slash, with indirect references kept whole. Keys and name values have their
+// #xx escapes decoded. Unlike utils.DictionaryValue it
+// never mistakes a name value, such as the /URI in /S /URI, for a key.
+func dictionaryEntries(source []byte) map[string]string {
+	entries := make(map[string]string)
+
+	tokens := content.ParseArrayOperand("[" + strings.TrimSpace(string(source)) + "]")
+	if len(tokens) == 0 || !strings.HasPrefix(tokens[0], "<<") || !strings.HasSuffix(tokens[0], ">>") {
+		return entries
+	}
+	dict := tokens[0]
+
+	items := joinReferences(content.ParseArrayOperand("[" + dict[2:len(dict)-2] + "]"))
+	for i := 0; i+1 < len(items); i += 2 {
+		if !strings.HasPrefix(items[i], "/") {
+			break
+		}
+		entries[utils.UnescapeName(items[i][1:])] = utils.UnescapeNameValue(items[i+1])
+	}
+	return entries
+}
+
+// destinationName returns the name of a destination given as a name or a string
+func destinationName(value string) string {
+	if strings.HasPrefix(value, "/") {
+		return utils.UnescapeName(value[1:])
+	}
+	name, err := utils.DecodePDFString(value)
+	if err != nil {
+		return value
+	}
+	return name
+}
+
+// joinReferences merges the "num gen R" tokens of indirect references into single items
+func joinReferences(tokens []string) []string {
+	var items []string
+	for i := 0; i < len(tokens); i++ {
+		if i+2 < len(tokens) && tokens[i+2] == "R" {
+			if _, err := strconv.Atoi(tokens[i]); err == nil {
+				if _, err := strconv.Atoi(tokens[i+1]); err == nil {
+					items = append(items, tokens[i]+" "+tokens[i+1]+" R")
+					i += 2
+					continue
+				}
+			}
+		}
+		items = append(items, tokens[i])
+	}
+	return items
+}
+
+// NamedDestinations returns the named destinations of the document, resolved to pages and views
+func (doc *PDFDocument) NamedDestinations() map[string]Destination {
+	named := doc.namedDestinations()
+	resolved := make(map[string]Destination, len(named))
+	for name, value := range named {
+		dest := doc.resolveDestination(value, nil)
+		dest.Name = name
+		resolved[name] = dest
+	}
+	return resolved
+}
@@ -1,9 +1,15 @@
 package document
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/yourusername/pdfex/internal/metrics"
@@ -19,21 +25,90 @@ type PDFDocument struct {
 	Objects     map[int]PDFObject
 	Trailer     map[string]interface{}
 	Pages       []PDFPage
-	TextChunks  []string
 	Fonts       map[string]PDFFont // Key is the font resource name
 	XRefTable   map[int]PDFXRefEntry
 	XRefOffset  int64
-	RootCatalog int // Object number of the root catalog
+	compressed  map[int][2]int // Objects in object streams: stream object number and index
+	RootCatalog int            // Object number of the root catalog
 	metrics     *metrics.PDFMetrics
+	degradation DegradationReport
+	pageRange   PageRange // Pages whose content is processed; empty for all
+	limits      Limits
+	warnings    []Warning      // Recorded while parsing, see Warnings
+	recovery    []RecoveryStep // Recovery heuristics that fired, see RecoveryReport
+	logger      *slog.Logger   // Receives the log of the parse, see Logger
+	parseHooks  Hooks          // Observes the parse, see ParseConfig.Hooks
+	resolved    *objectCache   // Values parsed by the document's resolvers, see Resolver
+	resolvedMu  sync.Mutex     // Guards resolved, as resolvers may run concurrently
 }
 
+// ParseConfig controls optional parsing behaviour
+type ParseConfig struct {
+	VerifyXRef bool         // Also rebuild the xref table by scanning the file and compare both results
+	PageRange  PageRange    // Only load the resources and content streams of these pages
+	Tracer     Tracer       // Receives a span for the parse and each of its phases; nil for none
+	Limits     Limits       // Resource limits; exceeding one fails the parse with a *content.LimitError
+	Logger     *slog.Logger // Receives the log of the parse, with the file name attached; nil for utils.DefaultLogger
+	Hooks      Hooks        // Observes the parse as it happens and may abort it; nil for none
+}
+
+// logger returns the logger for parsing filename
+func (config ParseConfig) logger(filename string) *slog.Logger {
+	logger := config.Logger
+	if logger == nil {
+		logger = utils.DefaultLogger()
+	}
+	return logger.With("file", filename)
+}
+
+// Logger returns the logger the document was parsed with
+func (doc *PDFDocument) Logger() *slog.Logger {
+	if doc.logger == nil {
+		return utils.DefaultLogger()
+	}
+	return doc.logger
+}
+
+// ErrNotPDF is returned for files without a %PDF- header
+var ErrNotPDF = errors.New("not a PDF file")
+
 // ParsePDF parses a PDF file and returns a PDFDocument
 func ParsePDF(filename string) (*PDFDocument, error) {
+	return ParsePDFWithConfig(filename, ParseConfig{})
+}
+
+// ParsePDFWithConfig parses a PDF file using the given configuration
+func ParsePDFWithConfig(filename string, config ParseConfig) (*PDFDocument, error) {
+	span := startSpan(config.Tracer, SpanParse)
+	defer span.End()
+
+	// Reading the memory statistics stops the world briefly, so they are sampled once on each
+	// side of the parse
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	allocated := memStats.TotalAlloc
+
+	doc, err := parsePDF(filename, config, span)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	runtime.ReadMemStats(&memStats)
+	doc.metrics.AllocatedBytes = memStats.TotalAlloc - allocated
+
+	span.SetAttribute("pdfex.parse_path", string(doc.degradation.Path))
+	span.SetAttribute("pdfex.objects", len(doc.Objects))
+	span.SetAttribute("pdfex.pages", len(doc.Pages))
+	return doc, nil
+}
+
+// parsePDF parses a PDF file, tracing its phases as children of span
+func parsePDF(filename string, config ParseConfig, span Span) (*PDFDocument, error) {
 	startTime := time.Now()
 
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %v", err)
+		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
@@ -43,13 +118,20 @@ func ParsePDF(filename string) (*PDFDocument, error) {
 		return nil, fmt.Errorf("failed to get file info: %v", err)
 	}
 	fileSize := fileInfo.Size()
+	span.SetAttribute("pdfex.file_size", fileSize)
 
 	doc := &PDFDocument{
-		Objects:   make(map[int]PDFObject),
-		XRefTable: make(map[int]PDFXRefEntry),
-		Trailer:   make(map[string]interface{}),
-		Fonts:     make(map[string]PDFFont),
-		metrics:   metrics.NewPDFMetrics(filename, fileSize),
+		Objects:     make(map[int]PDFObject),
+		XRefTable:   make(map[int]PDFXRefEntry),
+		compressed:  make(map[int][2]int),
+		Trailer:     make(map[string]interface{}),
+		Fonts:       make(map[string]PDFFont),
+		metrics:     metrics.NewPDFMetrics(filename, fileSize),
+		degradation: DegradationReport{Path: ParsePathXRef},
+		pageRange:   config.PageRange,
+		limits:      config.Limits,
+		logger:      config.logger(filename),
+		parseHooks:  config.Hooks,
 	}
 
 	// Check PDF header and find version
@@ -59,47 +141,72 @@ func ParsePDF(filename string) (*PDFDocument, error) {
 	}
 
 	// Find xref offset from end of file
+	phase := span.StartSpan(SpanXRef)
 	xrefOffset, err := findLastXRefOffset(file, fileSize)
 	if err != nil {
-		utils.Logf(utils.LogWarning, "XRef table not found, falling back to linear parsing: %v\n", err)
+		phase.End()
+		utils.Warnf(doc.Logger(), "XRef table not found, falling back to linear parsing: %v\n", err)
 		// Fallback to linear parsing if xref not found
-		return fallbackLinearParse(filename)
+		return fallbackLinearParse(filename, config, span)
 	}
 
 	doc.XRefOffset = xrefOffset
 
 	// Parse xref table and trailer
-	err = parseXRefAndTrailer(file, xrefOffset, doc)
+	err = parseXRefAndTrailer(file, fileSize, xrefOffset, doc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse xref table and trailer: %v", err)
+		err = fmt.Errorf("failed to parse xref table and trailer: %v", err)
+		phase.SetError(err)
+		phase.End()
+		return nil, err
 	}
 
+	doc.degradation.XRefObjectCount = countInUseXRefEntries(doc.XRefTable) + len(doc.compressed)
+	if err := doc.limits.checkObjects(doc.degradation.XRefObjectCount); err != nil {
+		phase.SetError(err)
+		phase.End()
+		return nil, err
+	}
+	if doc.degradation.Path == ParsePathRebuild {
+		doc.degradation.ScanObjectCount = doc.degradation.XRefObjectCount
+	} else if config.VerifyXRef {
+		verifyXRef(file, doc)
+	}
+	phase.SetAttribute("pdfex.xref_entries", len(doc.XRefTable))
+	phase.End()
+
 	// Get root catalog
 	if rootRef, ok := doc.Trailer["Root"]; ok {
-		objNum, err := utils.ExtractReference(rootRef.(string))
-		if err != nil {
-			utils.Logf(utils.LogWarning, "Invalid Root reference: %v\n", err)
+		if ref, ok := utils.DictFrom(doc.Trailer).Ref("Root"); ok {
+			doc.RootCatalog = ref.Num
 		} else {
-			doc.RootCatalog = objNum
+			doc.warnf(StageXRef, 0, "Invalid Root reference: %v", rootRef)
+		}
+	}
+
+	// An xref table whose offsets are all wrong still parses, so check it leads to the catalog
+	if doc.degradation.Path != ParsePathRebuild && !doc.catalogReadable(file) {
+		if err := rebuildForRecovery(file, doc, fmt.Sprintf("catalog %d not found through the xref table", doc.RootCatalog)); err != nil {
+			return nil, err
 		}
 	}
 
 	// Load objects using the xref table
+	phase = span.StartSpan(SpanObjects)
 	err = loadObjects(file, doc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load objects: %v", err)
+		err = fmt.Errorf("failed to load objects: %w", err)
+		phase.SetError(err)
+		phase.End()
+		return nil, err
 	}
+	phase.End()
 
 	// Extract text from content streams
 	textStartTime := time.Now()
-
-	// Process document structure - call the implementations
-	processPages(doc)
-	processFonts(doc)
-	handleMissingFonts(doc)
-	processText(doc)
-	processTextChunks(doc)
-
+	if err := processDocument(doc, span); err != nil {
+		return nil, err
+	}
 	doc.metrics.TextExtractionTime = time.Since(textStartTime)
 	doc.metrics.ParseTime = time.Since(startTime)
 
@@ -120,12 +227,15 @@ func identifyPDFVersion(doc *PDFDocument, file *os.File) error {
 	// Check PDF header
 	header := make([]byte, 8)
 	_, err = file.Read(header)
+	if err == io.EOF {
+		return ErrNotPDF
+	}
 	if err != nil {
-		return fmt.Errorf("failed to read header: %v", err)
+		return fmt.Errorf("failed to read header: %w", err)
 	}
 
 	if !strings.HasPrefix(string(header), "%PDF-") {
-		return fmt.Errorf("not a PDF file")
+		return ErrNotPDF
 	}
 
 	// Extract version
@@ -135,15 +245,54 @@ func identifyPDFVersion(doc *PDFDocument, file *os.File) error {
 	return nil
 }
 
-// fallbackLinearParse falls back to linear parsing if xref table can't be used
-func fallbackLinearParse(filename string) (*PDFDocument, error) {
-	utils.Logf(utils.LogInfo, "Using linear parsing for file: %s\n", filename)
+// processDocument decompresses the streams of a loaded document and processes its pages, fonts
+// and text, tracing each phase as a child of span. It fails only when a resource limit is
+// exceeded.
+func processDocument(doc *PDFDocument, span Span) error {
+	phase := span.StartSpan(SpanDecompression)
+	if err := processStreams(doc); err != nil {
+		phase.SetError(err)
+		phase.End()
+		return err
+	}
+	phase.End()
+
+	phase = span.StartSpan(SpanPages)
+	err := processPages(doc)
+	if err == nil {
+		processPageBoxes(doc)
+		markHiddenContent(doc)
+		err = processFormXObjects(doc)
+	}
+	if err == nil {
+		err = doc.pagesProcessed()
+	}
+	if err != nil {
+		phase.SetError(err)
+		phase.End()
+		return err
+	}
+	phase.End()
 
+	phase = span.StartSpan(SpanFonts)
+	processFonts(doc)
+	handleMissingFonts(doc)
+	phase.SetAttribute("pdfex.fonts", len(doc.Fonts))
+	phase.End()
+
+	phase = span.StartSpan(SpanText)
+	processText(doc)
+	phase.End()
+	return nil
+}
+
+// fallbackLinearParse falls back to linear parsing if xref table can't be used
+func fallbackLinearParse(filename string, config ParseConfig, span Span) (*PDFDocument, error) {
 	startTime := time.Now()
 
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %v", err)
+		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
@@ -163,11 +312,17 @@ func fallbackLinearParse(filename string) (*PDFDocument, error) {
 
 	// Create new document with metrics
 	doc := &PDFDocument{
-		Objects: make(map[int]PDFObject),
-		Trailer: make(map[string]interface{}),
-		Fonts:   make(map[string]PDFFont),
-		metrics: metrics.NewPDFMetrics(filename, fileSize),
+		Objects:     make(map[int]PDFObject),
+		Trailer:     make(map[string]interface{}),
+		Fonts:       make(map[string]PDFFont),
+		metrics:     metrics.NewPDFMetrics(filename, fileSize),
+		degradation: DegradationReport{Path: ParsePathLinear},
+		pageRange:   config.PageRange,
+		limits:      config.Limits,
+		logger:      config.logger(filename),
+		parseHooks:  config.Hooks,
 	}
+	utils.Infof(doc.logger, "Using linear parsing")
 
 	// Identify the PDF version
 	err = identifyPDFVersion(doc, file)
@@ -176,18 +331,28 @@ func fallbackLinearParse(filename string) (*PDFDocument, error) {
 	}
 
 	// Use linear parsing to find and parse objects
+	phase := span.StartSpan(SpanObjects)
 	err = parseObjectsLinearly(fileContent, doc)
 	if err != nil {
-		return nil, fmt.Errorf("error during linear parsing: %v", err)
+		err = fmt.Errorf("error during linear parsing: %v", err)
+	} else {
+		err = doc.limits.checkObjects(len(doc.Objects))
+	}
+	if err != nil {
+		phase.SetError(err)
+		phase.End()
+		return nil, err
 	}
+	phase.End()
+
+	doc.degradation.ScanObjectCount = len(doc.Objects)
+	doc.recordRecovery(HeuristicLinearParse, "no usable xref table; %d objects found by parsing the file linearly", len(doc.Objects))
+	parseLinearTrailer(fileContent, doc)
 
 	// Extract document structure after parsing - call the implementations
-	processStreams(doc)
-	processPages(doc)
-	processFonts(doc)
-	handleMissingFonts(doc)
-	processText(doc)
-	processTextChunks(doc)
+	if err := processDocument(doc, span); err != nil {
+		return nil, err
+	}
 
 	// Update metrics
 	doc.metrics.ParseTime = time.Since(startTime)
@@ -196,6 +361,19 @@ func fallbackLinearParse(filename string) (*PDFDocument, error) {
 	return doc, nil
 }
 
+// parseLinearTrailer fills in the trailer from the last trailer dictionary in the file, so that
+// documents parsed without an xref table still report their information dictionary and
+// encryption
+func parseLinearTrailer(fileContent []byte, doc *PDFDocument) {
+	idx := bytes.LastIndex(fileContent, []byte("trailer"))
+	if idx < 0 {
+		return
+	}
+	for key, value := range dictionaryEntries(fileContent[idx+len("trailer"):]) {
+		doc.Trailer[key] = value
+	}
+}
+
 // ObjectCount returns the number of objects in the document
 func (doc *PDFDocument) ObjectCount() int {
 	return len(doc.Objects)
@@ -206,37 +384,28 @@ func (doc *PDFDocument) PageCount() int {
 	return len(doc.Pages)
 }
 
-// FontCount returns the number of fonts in the document
+// FontCount returns the number of font dictionaries in the document. Fonts are stored both by
+// object number and by the resource names pages give them, so only the former are counted.
 func (doc *PDFDocument) FontCount() int {
-	return len(doc.Fonts)
-}
-
-// TextChunkCount returns the number of text chunks in the document
-func (doc *PDFDocument) TextChunkCount() int {
-	return len(doc.TextChunks)
+	count := 0
+	for key := range doc.Fonts {
+		if !strings.HasPrefix(key, "/") {
+			count++
+		}
+	}
+	return count
 }
 
-// Metrics returns the metrics object
+// Metrics returns the metrics object, with the lookups in the object cache of the document's
+// resolvers so far
 func (doc *PDFDocument) Metrics() *metrics.PDFMetrics {
-	return doc.metrics
-}
-
-// SaveChunksToFile saves the text chunks to a file
-func (doc *PDFDocument) SaveChunksToFile(filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	for i, chunk := range doc.TextChunks {
-		_, err = fmt.Fprintf(file, "--- Chunk %d ---\n%s\n\n", i+1, chunk)
-		if err != nil {
-			return err
-		}
+	doc.resolvedMu.Lock()
+	if doc.resolved != nil {
+		doc.metrics.ObjectCacheHits = doc.resolved.hits
+		doc.metrics.ObjectCacheMisses = doc.resolved.misses
 	}
-
-	return nil
+	doc.resolvedMu.Unlock()
+	return doc.metrics
 }
 
 // updateMetrics updates the document metrics
@@ -244,12 +413,22 @@ func updateMetrics(doc *PDFDocument) {
 	// Update metrics
 	doc.metrics.ObjectCount = len(doc.Objects)
 	doc.metrics.PageCount = len(doc.Pages)
-	doc.metrics.FontCount = len(doc.Fonts)
-	doc.metrics.TextChunkCount = len(doc.TextChunks)
+	doc.metrics.FontCount = doc.FontCount()
 	doc.metrics.XRefTableSize = len(doc.XRefTable)
+	doc.metrics.ParsePath = string(doc.degradation.Path)
+	doc.metrics.JavaScriptCount = len(doc.JavaScripts())
+
+	// Record structural problems so that parses can be compared
+	doc.metrics.Warnings = doc.degradation.Warnings()
+	if len(doc.Pages) == 0 {
+		doc.metrics.Warnings = append(doc.metrics.Warnings, "no pages found")
+	}
 
 	// Count various object types
 	countObjects(doc)
+
+	// The fingerprint uses the filter counts as structural traits
+	doc.metrics.Fingerprint = doc.Fingerprint()
 }
 
 // countObjects counts various types of objects and updates metrics
@@ -257,13 +436,23 @@ func countObjects(doc *PDFDocument) {
 	var streamCount, charCount int
 
 	for _, obj := range doc.Objects {
+		// A filtered stream is decompressed into a copy, held alongside the raw content
+		size := int64(len(obj.Content))
+		if _, filtered := obj.Dictionary["Filter"]; filtered && obj.IsStream {
+			size += int64(len(obj.Stream))
+		}
+		if size > doc.metrics.LargestObjectBytes {
+			doc.metrics.LargestObjectBytes = size
+		}
+
+		dict := obj.Dict()
 		if obj.IsStream {
 			streamCount++
 
 			// Count filter types
-			if filter, ok := obj.Dictionary["Filter"]; ok {
-				filterStr := filter.(string)
-				if strings.Contains(filterStr, "/FlatDecode") {
+			if filter := dict.Get("Filter"); filter != nil {
+				filterStr := filter.String()
+				if strings.Contains(filterStr, "/FlateDecode") {
 					doc.metrics.FlatDecodeStreams++
 				}
 				if strings.Contains(filterStr, "/ASCII85Decode") {
@@ -291,15 +480,13 @@ func countObjects(doc *PDFDocument) {
 		}
 
 		// Count object types
-		if objType, ok := obj.Dictionary["Type"]; ok {
-			typeName := objType.(string)
+		if objType, ok := dict.Get("Type").(utils.Name); ok {
+			typeName := objType.String()
 			doc.metrics.ObjectTypeCounts[typeName]++
 
 			// Count specific types
-			if typeName == "/XObject" {
-				if subtype, ok := obj.Dictionary["Subtype"]; ok && subtype.(string) == "/Image" {
-					doc.metrics.ImageCount++
-				}
+			if subtype, _ := dict.Name("Subtype"); typeName == "/XObject" && subtype == "Image" {
+				doc.metrics.ImageCount++
 			}
 		}
 	}
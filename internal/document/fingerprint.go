@@ -0,0 +1,281 @@
+package document
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/metrics"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Namespace of the Adobe PDF schema of XMP, which holds pdf:Producer
+const xmpPDFNamespace = "http://ns.adobe.com/pdf/1.3/"
+
+// Confidence of an identification, by the evidence it rests on
+const (
+	producerConfidence = 0.9 // The Producer names the tool
+	markerConfidence   = 0.7 // The tool leaves a private entry in the information dictionary
+	creatorConfidence  = 0.5 // Only the Creator names the tool, which may have used another to write the file
+)
+
+// toolSignature recognises the software that generated a document from its Producer or
+// Creator string
+type toolSignature struct {
+	name    string
+	pattern *regexp.Regexp // The first group, if any, captures the version
+
+	// The tool never writes object streams or xref streams, so a file holding them was written
+	// or rewritten by something else
+	classicOnly bool
+}
+
+// Known generators, more specific patterns first
+var toolSignatures = []toolSignature{
+	{name: "Adobe PDF Library", pattern: regexp.MustCompile(`Adobe PDF Library ([\d.]+)`)},
+	{name: "Acrobat Distiller", pattern: regexp.MustCompile(`Acrobat Distiller ([\d.]+)`)},
+	{name: "Adobe Acrobat", pattern: regexp.MustCompile(`Adobe Acrobat[^\d]*([\d.]+)?`)},
+	{name: "Microsoft Print to PDF", pattern: regexp.MustCompile(`Microsoft: Print To PDF`)},
+	{name: "Microsoft Word", pattern: regexp.MustCompile(`Microsoft[®\s]+Word(?:\s+(\d{4}))?`)},
+	{name: "LibreOffice", pattern: regexp.MustCompile(`LibreOffice ([\d.]+)`)},
+	{name: "OpenOffice", pattern: regexp.MustCompile(`OpenOffice(?:\.org)? ([\d.]+)`)},
+	{name: "pdfTeX", pattern: regexp.MustCompile(`pdfTeX-([\d.-]+)`)},
+	{name: "LuaTeX", pattern: regexp.MustCompile(`LuaTeX-([\d.]+)`)},
+	{name: "xdvipdfmx", pattern: regexp.MustCompile(`xdvipdfmx \((\d+)\)`)},
+	{name: "dvipdfmx", pattern: regexp.MustCompile(`dvipdfmx \((\d+)\)`)},
+	{name: "Ghostscript", pattern: regexp.MustCompile(`Ghostscript ([\d.]+)`)},
+	{name: "Quartz PDFContext", pattern: regexp.MustCompile(`(?:Mac OS X|macOS) (?:Version )?([\d.]+).*Quartz PDFContext`)},
+	{name: "Skia/PDF", pattern: regexp.MustCompile(`Skia/PDF m(\d+)`)},
+	{name: "iText", pattern: regexp.MustCompile(`iText(?:Sharp)?[®™]?\s*([\d.]+)`)},
+	{name: "PDFsharp", pattern: regexp.MustCompile(`PDFsharp ([\d.]+)`)},
+	{name: "ReportLab", pattern: regexp.MustCompile(`ReportLab PDF Library`), classicOnly: true},
+	{name: "FPDF", pattern: regexp.MustCompile(`^FPDF ([\d.]+)`), classicOnly: true},
+	{name: "TCPDF", pattern: regexp.MustCompile(`TCPDF ([\d.]+)`)},
+	{name: "wkhtmltopdf", pattern: regexp.MustCompile(`wkhtmltopdf ([\d.]+)`)},
+	{name: "Qt", pattern: regexp.MustCompile(`^Qt ([\d.]+)`)},
+	{name: "cairo", pattern: regexp.MustCompile(`cairo ([\d.]+)`)},
+	{name: "Apache FOP", pattern: regexp.MustCompile(`Apache FOP Version ([\d.]+)`)},
+	{name: "Prince", pattern: regexp.MustCompile(`Prince ([\d.]+)`)},
+	{name: "pypdf", pattern: regexp.MustCompile(`(?:pypdf|PyPDF2)(?:\s+([\d.]+))?`)},
+	{name: "PDFium", pattern: regexp.MustCompile(`PDFium`)},
+	{name: "Foxit", pattern: regexp.MustCompile(`Foxit[^\d]*([\d.]+)?`)},
+	{name: "PDF-XChange", pattern: regexp.MustCompile(`PDF-XChange[^\d]*([\d.]+)?`)},
+	{name: "Nitro Pro", pattern: regexp.MustCompile(`Nitro Pro ([\d.]+)`)},
+}
+
+// Matches the version in the banner pdfTeX writes to /PTEX.Fullbanner
+var pdfTeXBannerPattern = regexp.MustCompile(`pdfTeX-([\d.-]+)`)
+
+// match returns whether the signature matches s, with the version it captured
+func (sig toolSignature) match(s string) (string, bool) {
+	m := sig.pattern.FindStringSubmatch(s)
+	if m == nil {
+		return "", false
+	}
+	if len(m) > 1 {
+		return strings.TrimRight(m[1], "."), true
+	}
+	return "", true
+}
+
+// identifyTool returns the first signature matching s, with the version it captured
+func identifyTool(s string) (toolSignature, string, bool) {
+	if s == "" {
+		return toolSignature{}, "", false
+	}
+	for _, sig := range toolSignatures {
+		if version, ok := sig.match(s); ok {
+			return sig, version, true
+		}
+	}
+	return toolSignature{}, "", false
+}
+
+// Fingerprint identifies the software that generated the document. The Producer is the
+// strongest evidence, then private entries some tools add to the information dictionary, then
+// the Creator. The traits of the file structure are recorded for grouping documents of one
+// origin, and lower the confidence where they contradict the identified tool, as do producers
+// that differ between the information dictionary and the XMP metadata.
+func (doc *PDFDocument) Fingerprint() metrics.Fingerprint {
+	info := doc.InfoDictionary()
+	traits := doc.structureTraits()
+	fp := metrics.Fingerprint{
+		Producer:  info["Producer"],
+		Creator:   info["Creator"],
+		Structure: strings.Join(traits, " "),
+	}
+
+	var sig toolSignature
+	if s, version, ok := identifyTool(fp.Producer); ok {
+		sig, fp.Version, fp.Confidence = s, version, producerConfidence
+		fp.Evidence = append(fp.Evidence, fmt.Sprintf("Producer %q names %s", fp.Producer, s.name))
+	} else if banner, ok := info["PTEX.Fullbanner"]; ok {
+		sig, fp.Confidence = toolSignature{name: "pdfTeX"}, markerConfidence
+		if m := pdfTeXBannerPattern.FindStringSubmatch(banner); m != nil {
+			fp.Version = m[1]
+		}
+		fp.Evidence = append(fp.Evidence, "information dictionary has the /PTEX.Fullbanner entry pdfTeX writes")
+	} else if s, version, ok := identifyTool(fp.Creator); ok {
+		sig, fp.Version, fp.Confidence = s, version, creatorConfidence
+		fp.Evidence = append(fp.Evidence, fmt.Sprintf("Creator %q names %s", fp.Creator, s.name))
+	}
+	fp.Tool = sig.name
+
+	if sig.classicOnly && (hasTrait(traits, "xref-stream") || hasTrait(traits, "objstm")) {
+		fp.Confidence /= 2
+		fp.Evidence = append(fp.Evidence, fmt.Sprintf("structure inconsistent with %s, which writes neither xref nor object streams", sig.name))
+	}
+
+	if producer := doc.xmpProducer(); producer != "" && fp.Producer != "" && producer != fp.Producer {
+		fp.Confidence *= 0.8
+		fp.Evidence = append(fp.Evidence, fmt.Sprintf("XMP pdf:Producer %q differs from the information dictionary's", producer))
+	}
+	if hasTrait(traits, "incremental") {
+		fp.Evidence = append(fp.Evidence, "incrementally updated, possibly by other software than the one that generated it")
+	}
+	return fp
+}
+
+// structureTraits describes the file structure: the kind of cross-reference section, object
+// and xref streams, linearization, the order of objects in the file, the position of the
+// catalog, the stream filters used and the presence of incremental updates and a file /ID
+func (doc *PDFDocument) structureTraits() []string {
+	var traits []string
+
+	xrefStream, objStm, linearized := false, false, false
+	for _, obj := range doc.Objects {
+		switch utils.GetString(obj.Dictionary["Type"], "") {
+		case "/XRef":
+			xrefStream = true
+		case "/ObjStm":
+			objStm = true
+		}
+		if _, ok := obj.Dictionary["Linearized"]; ok {
+			linearized = true
+		}
+	}
+	switch {
+	case doc.Trailer["XRefStm"] != nil:
+		traits = append(traits, "hybrid-xref")
+	case xrefStream:
+		traits = append(traits, "xref-stream")
+	default:
+		traits = append(traits, "xref-table")
+	}
+	if objStm {
+		traits = append(traits, "objstm")
+	}
+	if linearized {
+		traits = append(traits, "linearized")
+	}
+	traits = append(traits, doc.objectOrderTraits()...)
+
+	filters := []struct {
+		name  string
+		count int
+	}{
+		{"flate", doc.metrics.FlatDecodeStreams},
+		{"ascii85", doc.metrics.ASCII85Streams},
+		{"lzw", doc.metrics.LZWStreams},
+		{"runlength", doc.metrics.RunLengthStreams},
+		{"dct", doc.metrics.DCTStreams},
+		{"jpx", doc.metrics.JPXStreams},
+		{"ccittfax", doc.metrics.CCITTFaxStreams},
+		{"jbig2", doc.metrics.JBIG2Streams},
+	}
+	filtered := false
+	for _, filter := range filters {
+		if filter.count > 0 {
+			traits = append(traits, filter.name)
+			filtered = true
+		}
+	}
+	if !filtered {
+		traits = append(traits, "unfiltered")
+	}
+
+	if doc.Trailer["Prev"] != nil {
+		traits = append(traits, "incremental")
+	}
+	if doc.Trailer["ID"] == nil {
+		traits = append(traits, "no-id")
+	}
+	return traits
+}
+
+// Fraction of consecutive objects in the file whose numbers rise or fall for the order to
+// count as ascending or descending
+const orderThreshold = 0.9
+
+// objectOrderTraits describes whether object numbers rise or fall through the file and whether
+// the catalog comes first or last, from the offsets of the xref table
+func (doc *PDFDocument) objectOrderTraits() []string {
+	type placed struct {
+		num    int
+		offset int64
+	}
+	var objects []placed
+	for objNum, entry := range doc.XRefTable {
+		if entry.InUse && entry.Offset > 0 {
+			objects = append(objects, placed{objNum, entry.Offset})
+		}
+	}
+	if len(objects) < 2 {
+		return nil
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].offset < objects[j].offset })
+
+	rising := 0
+	for i := 1; i < len(objects); i++ {
+		if objects[i].num > objects[i-1].num {
+			rising++
+		}
+	}
+	var traits []string
+	switch ratio := float64(rising) / float64(len(objects)-1); {
+	case ratio >= orderThreshold:
+		traits = append(traits, "ascending")
+	case ratio <= 1-orderThreshold:
+		traits = append(traits, "descending")
+	default:
+		traits = append(traits, "mixed-order")
+	}
+
+	switch doc.RootCatalog {
+	case 0:
+	case objects[0].num:
+		traits = append(traits, "catalog-first")
+	case objects[len(objects)-1].num:
+		traits = append(traits, "catalog-last")
+	}
+	return traits
+}
+
+// xmpProducer returns pdf:Producer of the XMP metadata, or "" if there is none
+func (doc *PDFDocument) xmpProducer() string {
+	packet := doc.XMPPacket()
+	if packet == nil {
+		return ""
+	}
+	properties, err := ParseXMP(packet)
+	if err != nil {
+		return ""
+	}
+	for _, property := range properties {
+		if property.Namespace == xmpPDFNamespace && property.Name == "Producer" && len(property.Values) > 0 {
+			return property.Values[0]
+		}
+	}
+	return ""
+}
+
+// hasTrait reports whether traits holds trait
+func hasTrait(traits []string, trait string) bool {
+	for _, t := range traits {
+		if t == trait {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package document
+
+import (
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// DescendantFont returns the CIDFont of a composite font, the first of its /DescendantFonts
+func (doc *PDFDocument) DescendantFont(obj PDFObject) (PDFObject, bool) {
+	descendants := utils.ParseArray(strings.TrimSpace(string(doc.resolveSource(utils.DictionaryValue(obj.Content, "DescendantFonts")))))
+	if len(descendants) == 0 {
+		return PDFObject{}, false
+	}
+	descendantNum, err := utils.ExtractReference(strings.Join(descendants, " "))
+	if err != nil {
+		return PDFObject{}, false
+	}
+	descendant, ok := doc.Objects[descendantNum]
+	return descendant, ok
+}
+
+// FontEmbedded reports whether the font descriptor of a font, or of the descendant CIDFont of
+// a composite font, holds an embedded font program
+func (doc *PDFDocument) FontEmbedded(obj PDFObject) bool {
+	if obj.Dictionary["Subtype"] == "/Type0" {
+		descendant, ok := doc.DescendantFont(obj)
+		if !ok {
+			return false
+		}
+		obj = descendant
+	}
+
+	descriptorNum, err := utils.ExtractReference(utils.DictionaryValue(obj.Content, "FontDescriptor"))
+	if err != nil {
+		return false
+	}
+	descriptor, ok := doc.Objects[descriptorNum]
+	if !ok {
+		return false
+	}
+	for _, key := range []string{"FontFile", "FontFile2", "FontFile3"} {
+		if utils.DictionaryValue(descriptor.Content, key) != "" {
+			return true
+		}
+	}
+	return false
+}
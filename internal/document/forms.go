@@ -0,0 +1,231 @@
+package document
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Field flags (/Ff); the radio and pushbutton flags decide the type of button fields
+const (
+	FieldFlagReadOnly   = 1 << 0
+	FieldFlagRequired   = 1 << 1
+	FieldFlagRadio      = 1 << 15
+	FieldFlagPushbutton = 1 << 16
+)
+
+// Maximum depth of the field tree, to guard against cyclic /Kids links
+const maxFieldTreeDepth = 32
+
+// FormField is a terminal field of an interactive form
+type FormField struct {
+	Name    string   // Fully qualified name, the partial names of its ancestors joined by dots
+	Type    string   // text, checkbox, radio, pushbutton, choice, signature or unknown
+	Value   string   // Current value; the selected export value for check boxes and radio buttons
+	Default string   // Value the field resets to
+	Options []string // Choices of a choice field, as displayed
+	Flags   int      // Field flags (/Ff)
+	Widgets []FieldWidget
+}
+
+// FieldWidget is the place of a field on a page. Radio button groups have one widget per button.
+type FieldWidget struct {
+	PageNumber int        // 1-based, 0 if it could not be resolved
+	Rect       [4]float64 // Lower-left and upper-right corners in points (see PDFPage.UserUnit)
+}
+
+// fieldAttributes are the field entries that kids inherit from their parents
+type fieldAttributes struct {
+	fieldType string
+	value     string
+	defValue  string
+	flags     int
+	options   string
+}
+
+// GetFormFields returns the terminal fields of the document's interactive form in the order
+// of the /Fields tree, or nil if it has no form
+func (doc *PDFDocument) GetFormFields() []FormField {
+	catalog, ok := doc.GetRootObject()
+	if !ok {
+		return nil
+	}
+	form := dictionaryEntries(doc.resolveSource(dictionaryEntries(objectSource(catalog))["AcroForm"]))
+	roots := joinReferences(content.ParseArrayOperand(string(doc.resolveSource(form["Fields"]))))
+	if len(roots) == 0 {
+		return nil
+	}
+
+	// Widgets without a /P entry are placed by finding the page that lists them
+	widgetPages := make(map[int]int)
+	for _, page := range doc.Pages {
+		for _, annot := range doc.pageAnnotations(page) {
+			if objNum, ok := referenceNumber(annot); ok {
+				widgetPages[objNum] = page.PageNumber
+			}
+		}
+	}
+
+	var fields []FormField
+	visited := make(map[int]bool)
+	for _, root := range roots {
+		fields = doc.collectFormFields(root, "", fieldAttributes{}, widgetPages, visited, 0, fields)
+	}
+	return fields
+}
+
+// collectFormFields adds the terminal fields under a field tree node to fields
+func (doc *PDFDocument) collectFormFields(node, parentName string, inherited fieldAttributes,
+	widgetPages map[int]int, visited map[int]bool, depth int, fields []FormField) []FormField {
+	if depth >= maxFieldTreeDepth {
+		return fields
+	}
+	if objNum, ok := referenceNumber(node); ok {
+		if visited[objNum] {
+			return fields
+		}
+		visited[objNum] = true
+	}
+
+	entries := dictionaryEntries(doc.resolveSource(node))
+	name := parentName
+	if title, ok := entries["T"]; ok {
+		partial := utils.DecodeTextString(string(doc.resolveSource(title)))
+		if name != "" {
+			name += "."
+		}
+		name += partial
+	}
+
+	attrs := inherited
+	if v, ok := entries["FT"]; ok {
+		attrs.fieldType = v
+	}
+	if v, ok := entries["V"]; ok {
+		attrs.value = v
+	}
+	if v, ok := entries["DV"]; ok {
+		attrs.defValue = v
+	}
+	if v, ok := entries["Ff"]; ok {
+		attrs.flags, _ = strconv.Atoi(string(doc.resolveSource(v)))
+	}
+	if v, ok := entries["Opt"]; ok {
+		attrs.options = v
+	}
+
+	// Kids with a partial name are fields of their own; kids without one are widgets
+	var widgets []string
+	hasFieldKids := false
+	for _, kid := range joinReferences(content.ParseArrayOperand(string(doc.resolveSource(entries["Kids"])))) {
+		if _, ok := dictionaryEntries(doc.resolveSource(kid))["T"]; ok {
+			fields = doc.collectFormFields(kid, name, attrs, widgetPages, visited, depth+1, fields)
+			hasFieldKids = true
+		} else {
+			widgets = append(widgets, kid)
+		}
+	}
+	if hasFieldKids && len(widgets) == 0 {
+		return fields
+	}
+	if len(widgets) == 0 {
+		// A field with a single widget is merged with it into one dictionary
+		widgets = []string{node}
+	}
+
+	field := FormField{
+		Name:    name,
+		Type:    fieldTypeName(attrs.fieldType, attrs.flags),
+		Value:   doc.fieldValue(attrs.value),
+		Default: doc.fieldValue(attrs.defValue),
+		Flags:   attrs.flags,
+	}
+	if attrs.options != "" {
+		field.Options = doc.fieldOptions(attrs.options)
+	}
+	for _, widget := range widgets {
+		if w, ok := doc.fieldWidget(widget, widgetPages); ok {
+			field.Widgets = append(field.Widgets, w)
+		}
+	}
+
+	return append(fields, field)
+}
+
+// fieldWidget returns the page and rectangle of a widget annotation
+func (doc *PDFDocument) fieldWidget(widget string, widgetPages map[int]int) (FieldWidget, bool) {
+	entries := dictionaryEntries(doc.resolveSource(widget))
+	rect, ok := parseRect(string(doc.resolveSource(entries["Rect"])))
+	if !ok {
+		return FieldWidget{}, false
+	}
+
+	w := FieldWidget{Rect: rect}
+	if objNum, ok := referenceNumber(entries["P"]); ok {
+		w.PageNumber = doc.PageNumberForObject(objNum)
+	}
+	if objNum, ok := referenceNumber(widget); ok && w.PageNumber == 0 {
+		w.PageNumber = widgetPages[objNum]
+	}
+	if w.PageNumber > 0 && w.PageNumber <= len(doc.Pages) {
+		w.Rect = scaleRect(rect, doc.Pages[w.PageNumber-1].Unit())
+	}
+	return w, true
+}
+
+// fieldTypeName returns the type of a field from its /FT entry and flags
+func fieldTypeName(fieldType string, flags int) string {
+	switch fieldType {
+	case "/Tx":
+		return "text"
+	case "/Btn":
+		switch {
+		case flags&FieldFlagPushbutton != 0:
+			return "pushbutton"
+		case flags&FieldFlagRadio != 0:
+			return "radio"
+		}
+		return "checkbox"
+	case "/Ch":
+		return "choice"
+	case "/Sig":
+		return "signature"
+	}
+	return "unknown"
+}
+
+// fieldValue decodes a field value: a text string, a name such as the /Yes of a checked box, or
+// an array of the selected items of a multiple-selection list, which are joined by commas.
+// Signature dictionaries have no textual value.
+func (doc *PDFDocument) fieldValue(value string) string {
+	value = strings.TrimSpace(string(doc.resolveSource(value)))
+	switch {
+	case value == "" || value == "null" || strings.HasPrefix(value, "<<"):
+		return ""
+	case strings.HasPrefix(value, "/"):
+		return value[1:]
+	case strings.HasPrefix(value, "["):
+		var items []string
+		for _, item := range joinReferences(content.ParseArrayOperand(value)) {
+			items = append(items, doc.fieldValue(item))
+		}
+		return strings.Join(items, ", ")
+	}
+	return utils.DecodeTextString(value)
+}
+
+// fieldOptions returns the displayed choices of a choice field. Each /Opt item is a text
+// string, or a pair of an export value and the text displayed for it.
+func (doc *PDFDocument) fieldOptions(value string) []string {
+	var options []string
+	for _, item := range joinReferences(content.ParseArrayOperand(string(doc.resolveSource(value)))) {
+		item = string(doc.resolveSource(item))
+		if pair := content.ParseArrayOperand(item); strings.HasPrefix(item, "[") && len(pair) == 2 {
+			item = pair[1]
+		}
+		options = append(options, utils.DecodeTextString(item))
+	}
+	return options
+}
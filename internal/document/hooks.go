@@ -0,0 +1,64 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Hooks observes a parse as it happens, so that an application can react to a document
+// without a second pass over it. Each hook is passed the document being parsed. An error
+// returned by a hook aborts the parse with an error wrapping both ErrAborted and the hook's error.
+type Hooks interface {
+	// OnObjectLoaded is called for each object read through the xref table, in no particular
+	// order, before its stream is decoded
+	OnObjectLoaded(doc *PDFDocument, obj PDFObject) error
+	// OnStreamDecoded is called for each stream that was decoded through its filters, with the
+	// decoded data in obj.Stream
+	OnStreamDecoded(doc *PDFDocument, obj PDFObject) error
+	// OnPageProcessed is called for each selected page, in page order, once its content
+	// streams, boxes and form XObjects are loaded
+	OnPageProcessed(doc *PDFDocument, page *PDFPage) error
+	// OnWarning is called for each problem the parse recovers from, as it is found
+	OnWarning(doc *PDFDocument, warning Warning)
+}
+
+// noHooks is the hooks of a parse without any
+type noHooks struct{}
+
+func (noHooks) OnObjectLoaded(doc *PDFDocument, obj PDFObject) error  { return nil }
+func (noHooks) OnStreamDecoded(doc *PDFDocument, obj PDFObject) error { return nil }
+func (noHooks) OnPageProcessed(doc *PDFDocument, page *PDFPage) error { return nil }
+func (noHooks) OnWarning(doc *PDFDocument, warning Warning)           {}
+
+// ErrAborted is returned when a hook aborts the parse
+var ErrAborted = errors.New("parse aborted by hook")
+
+// hooks returns the hooks of the parse, or hooks that do nothing if there are none
+func (doc *PDFDocument) hooks() Hooks {
+	if doc.parseHooks == nil {
+		return noHooks{}
+	}
+	return doc.parseHooks
+}
+
+// abort returns the error aborting the parse when a hook returned err, or nil
+func abort(hook string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %s: %w", ErrAborted, hook, err)
+}
+
+// pagesProcessed calls OnPageProcessed for each selected page
+func (doc *PDFDocument) pagesProcessed() error {
+	for i := range doc.Pages {
+		page := &doc.Pages[i]
+		if !doc.PageSelected(page.PageNumber) {
+			continue
+		}
+		if err := abort("OnPageProcessed", doc.hooks().OnPageProcessed(doc, page)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
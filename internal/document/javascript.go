@@ -0,0 +1,173 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Maximum number of actions followed through /Next links from one trigger, to guard against cycles
+const maxActionChain = 64
+
+// Script is a piece of JavaScript the document runs, either document-level or attached to a
+// trigger of the document, a page, a form field or an annotation
+type Script struct {
+	Name         string // Name in the /JavaScript name tree, or the holder and trigger such as "page 2 /O"
+	Trigger      string // Key of the trigger without its slash: OpenAction, A, or an /AA key such as K or WC; "" for document-level scripts
+	ObjectNumber int    // Object holding the source: the /JS stream, else the action, else the holder of the action
+	Source       string
+}
+
+// JavaScripts returns the JavaScript of the document for review: the document-level scripts of
+// the /JavaScript name tree in name order, then the scripts run by the open action, /A and /AA
+// triggers in order of the objects holding them. Actions chained through /Next are included.
+func (doc *PDFDocument) JavaScripts() []Script {
+	var scripts []Script
+
+	catalogNum := 0
+	if catalog, ok := doc.GetRootObject(); ok {
+		catalogNum = catalog.ObjectNumber
+		names := dictionaryEntries(doc.resolveSource(dictionaryEntries(objectSource(catalog))["Names"]))
+		named := make(map[string]string)
+		doc.collectNameTree(doc.resolveSource(names["JavaScript"]), named, make(map[int]bool))
+
+		sorted := make([]string, 0, len(named))
+		for name := range named {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+		// Scripts inline in the tree are credited to its root, as the node holding them isn't kept
+		treeNum, ok := referenceNumber(names["JavaScript"])
+		if !ok {
+			treeNum = catalogNum
+		}
+		for _, name := range sorted {
+			scripts = doc.collectActionScripts(named[name], utils.DecodeTextBytes(name), "", treeNum, scripts)
+		}
+	}
+
+	for _, objNum := range doc.sortedObjectNumbers() {
+		source := objectSource(doc.Objects[objNum])
+		if !bytes.Contains(source, []byte("/A")) && !bytes.Contains(source, []byte("/OpenAction")) {
+			continue
+		}
+		entries := dictionaryEntries(source)
+
+		var holder string
+		name := func(trigger string) string {
+			if holder == "" {
+				holder = doc.scriptHolder(objNum, catalogNum, entries)
+			}
+			return fmt.Sprintf("%s /%s", holder, trigger)
+		}
+		for _, trigger := range []string{"OpenAction", "A"} {
+			if action, ok := entries[trigger]; ok {
+				scripts = doc.collectActionScripts(action, name(trigger), trigger, objNum, scripts)
+			}
+		}
+
+		triggers := dictionaryEntries(doc.resolveSource(entries["AA"]))
+		keys := make([]string, 0, len(triggers))
+		for key := range triggers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, trigger := range keys {
+			scripts = doc.collectActionScripts(triggers[trigger], name(trigger), trigger, objNum, scripts)
+		}
+	}
+
+	return scripts
+}
+
+// collectActionScripts adds the scripts of a JavaScript action, and of the actions following
+// it through /Next, to scripts. holderNum is the object the action is attached to.
+func (doc *PDFDocument) collectActionScripts(action, name, trigger string, holderNum int, scripts []Script) []Script {
+	pending := []string{action}
+	visited := make(map[int]bool)
+	for steps := 0; len(pending) > 0 && steps < maxActionChain; steps++ {
+		action, pending = pending[0], pending[1:]
+		objNum, isRef := referenceNumber(action)
+		if isRef {
+			if visited[objNum] {
+				continue
+			}
+			visited[objNum] = true
+		} else {
+			objNum = holderNum
+		}
+
+		entries := dictionaryEntries(doc.resolveSource(action))
+		if entries["S"] == "/JavaScript" {
+			if source, sourceNum, ok := doc.scriptSource(entries["JS"]); ok {
+				if sourceNum == 0 {
+					sourceNum = objNum
+				}
+				scripts = append(scripts, Script{Name: name, Trigger: trigger, ObjectNumber: sourceNum, Source: source})
+			}
+		}
+
+		next := strings.TrimSpace(string(doc.resolveSource(entries["Next"])))
+		if strings.HasPrefix(next, "[") {
+			pending = append(pending, joinReferences(content.ParseArrayOperand(next))...)
+		} else if next != "" {
+			pending = append(pending, entries["Next"])
+		}
+	}
+	return scripts
+}
+
+// scriptSource returns the text of a /JS entry, a text string or a stream, with the number of
+// the stream holding it, or 0 if it is held by the action
+func (doc *PDFDocument) scriptSource(value string) (string, int, bool) {
+	if value == "" {
+		return "", 0, false
+	}
+	if objNum, ok := referenceNumber(value); ok {
+		obj, loaded := doc.Objects[objNum]
+		if !loaded {
+			return "", 0, false
+		}
+		if obj.IsStream {
+			return string(obj.Stream), objNum, true
+		}
+	}
+	return doc.textEntry(value), 0, true
+}
+
+// scriptHolder describes the object an action is attached to: the document, a page, the fully
+// qualified name of a form field, or the object number
+func (doc *PDFDocument) scriptHolder(objNum, catalogNum int, entries map[string]string) string {
+	if objNum == catalogNum {
+		return "document"
+	}
+	if entries["Type"] == "/Page" {
+		if page := doc.PageNumberForObject(objNum); page > 0 {
+			return fmt.Sprintf("page %d", page)
+		}
+	}
+
+	// A widget without a partial name belongs to the field of its /Parent
+	var parts []string
+	visited := map[int]bool{objNum: true}
+	for depth := 0; depth < maxFieldTreeDepth; depth++ {
+		if title, ok := entries["T"]; ok {
+			parts = append([]string{doc.textEntry(title)}, parts...)
+		}
+		parent, ok := referenceNumber(entries["Parent"])
+		if !ok || visited[parent] || entries["Type"] == "/Page" {
+			break
+		}
+		visited[parent] = true
+		entries = dictionaryEntries(doc.resolveSource(entries["Parent"]))
+	}
+	if len(parts) > 0 {
+		return "field " + strings.Join(parts, ".")
+	}
+
+	return fmt.Sprintf("object %d", objNum)
+}
@@ -0,0 +1,174 @@
+package document
+
+import (
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/content"
+)
+
+// Layer is an optional content group (OCG), shown as a layer in viewers
+type Layer struct {
+	ObjectNumber int
+	Name         string
+	Visible      bool // Visible in the default configuration
+}
+
+// GetLayers returns the optional content groups of the document in /OCGs order, with their
+// visibility in the default configuration (/D). Alternate configurations are not read.
+func (doc *PDFDocument) GetLayers() []Layer {
+	catalog, ok := doc.GetRootObject()
+	if !ok {
+		return nil
+	}
+	properties := dictionaryEntries(doc.resolveSource(dictionaryEntries(objectSource(catalog))["OCProperties"]))
+	if len(properties) == 0 {
+		return nil
+	}
+
+	config := dictionaryEntries(doc.resolveSource(properties["D"]))
+	on := doc.referenceSet(config["ON"])
+	off := doc.referenceSet(config["OFF"])
+	baseOn := config["BaseState"] != "/OFF"
+
+	var layers []Layer
+	for _, item := range joinReferences(content.ParseArrayOperand(string(doc.resolveSource(properties["OCGs"])))) {
+		objNum, ok := referenceNumber(item)
+		if !ok {
+			continue
+		}
+		entries := dictionaryEntries(doc.resolveSource(item))
+		layers = append(layers, Layer{
+			ObjectNumber: objNum,
+			Name:         doc.textEntry(entries["Name"]),
+			Visible:      on[objNum] || baseOn && !off[objNum],
+		})
+	}
+	return layers
+}
+
+// markHiddenContent records, for each page, the names in its /Properties resources that refer
+// to optional content hidden in the default configuration, and lists the layers in the metrics
+func markHiddenContent(doc *PDFDocument) {
+	layers := doc.GetLayers()
+	if len(layers) == 0 {
+		return
+	}
+
+	for _, layer := range layers {
+		if doc.metrics == nil {
+			continue
+		}
+		doc.metrics.Layers = append(doc.metrics.Layers, layer.Name)
+		if !layer.Visible {
+			doc.metrics.HiddenLayers = append(doc.metrics.HiddenLayers, layer.Name)
+		}
+	}
+
+	visible := layerVisibility(layers)
+	for i := range doc.Pages {
+		page := &doc.Pages[i]
+		if doc.PageSelected(page.PageNumber) {
+			page.HiddenContent = doc.hiddenProperties(doc.PageResources(*page, "Properties"), visible)
+		}
+	}
+}
+
+// hiddenProperties returns the names of /Properties resources that refer to hidden optional
+// content, or nil if there are none
+func (doc *PDFDocument) hiddenProperties(properties map[string]int, visible map[int]bool) map[string]bool {
+	var hidden map[string]bool
+	for name, objNum := range properties {
+		if !doc.optionalContentVisible(objNum, visible) {
+			if hidden == nil {
+				hidden = make(map[string]bool)
+			}
+			hidden[name] = true
+		}
+	}
+	return hidden
+}
+
+// layerVisibility maps the object number of each layer to its visibility
+func layerVisibility(layers []Layer) map[int]bool {
+	visible := make(map[int]bool, len(layers))
+	for _, layer := range layers {
+		visible[layer.ObjectNumber] = layer.Visible
+	}
+	return visible
+}
+
+// XObjectHidden reports whether an XObject belongs to optional content, through its /OC entry,
+// that is hidden in the default configuration
+func (doc *PDFDocument) XObjectHidden(objNum int) bool {
+	obj, ok := doc.Objects[objNum]
+	if !ok {
+		return false
+	}
+	oc, ok := referenceNumber(dictionaryEntries(objectSource(obj))["OC"])
+	if !ok {
+		return false
+	}
+
+	return !doc.optionalContentVisible(oc, layerVisibility(doc.GetLayers()))
+}
+
+// optionalContentVisible reports whether an optional content group, or a membership dictionary
+// (OCMD) combining groups with a visibility policy, is visible. Groups missing from /OCGs are
+// treated as visible; visibility expressions (/VE) are not evaluated.
+func (doc *PDFDocument) optionalContentVisible(objNum int, visible map[int]bool) bool {
+	obj, ok := doc.Objects[objNum]
+	if !ok {
+		return true
+	}
+	entries := dictionaryEntries(objectSource(obj))
+	if entries["Type"] != "/OCMD" {
+		if v, ok := visible[objNum]; ok {
+			return v
+		}
+		return true
+	}
+
+	// /OCGs is a single group or an array of them
+	groups := entries["OCGs"]
+	if source := strings.TrimSpace(string(doc.resolveSource(groups))); strings.HasPrefix(source, "[") {
+		groups = source
+	} else {
+		groups = "[" + groups + "]"
+	}
+	var states []bool
+	for _, item := range joinReferences(content.ParseArrayOperand(groups)) {
+		if group, ok := referenceNumber(item); ok {
+			v, known := visible[group]
+			states = append(states, v || !known)
+		}
+	}
+	if len(states) == 0 {
+		return true
+	}
+
+	anyOn, allOn := false, true
+	for _, on := range states {
+		anyOn = anyOn || on
+		allOn = allOn && on
+	}
+	switch entries["P"] {
+	case "/AllOn":
+		return allOn
+	case "/AnyOff":
+		return !allOn
+	case "/AllOff":
+		return !anyOn
+	}
+	return anyOn
+}
+
+// referenceSet returns the object numbers referenced by an array of indirect references
+func (doc *PDFDocument) referenceSet(value string) map[int]bool {
+	set := make(map[int]bool)
+	for _, item := range joinReferences(content.ParseArrayOperand(string(doc.resolveSource(value)))) {
+		if objNum, ok := referenceNumber(item); ok {
+			set[objNum] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,52 @@
+package document
+
+import "github.com/yourusername/pdfex/internal/content"
+
+// Limits bounds the resources a parse may use, to defend against hostile documents such as
+// decompression bombs. A zero field means no limit. Exceeding a limit fails the parse with a
+// *content.LimitError.
+type Limits struct {
+	MaxDecompressedStreamSize int64 // Decoded bytes of any one stream
+	MaxTotalDecompressedBytes int64 // Decoded bytes of all streams together
+	MaxObjects                int   // Objects in the document
+	MaxRecursionDepth         int   // Nesting of page tree nodes and of form XObjects
+}
+
+// checkObjects returns an error if a document with count objects exceeds the limits
+func (l Limits) checkObjects(count int) error {
+	if l.MaxObjects > 0 && count > l.MaxObjects {
+		return &content.LimitError{Limit: "MaxObjects", Max: int64(l.MaxObjects)}
+	}
+	return nil
+}
+
+// checkDepth returns an error if nesting at depth, counted from 1, exceeds the limits
+func (l Limits) checkDepth(depth int) error {
+	if l.MaxRecursionDepth > 0 && depth > l.MaxRecursionDepth {
+		return &content.LimitError{Limit: "MaxRecursionDepth", Max: int64(l.MaxRecursionDepth)}
+	}
+	return nil
+}
+
+// streamLimit returns the size past which decoding the next stream stops, given the bytes
+// already decoded, and whether it is set by the total rather than the per-stream limit. Once
+// the total is reached a stream may still decode one more byte, which the caller then finds
+// over the total.
+func (l Limits) streamLimit(decoded int64) (int64, bool) {
+	max := l.MaxDecompressedStreamSize
+	if l.MaxTotalDecompressedBytes > 0 {
+		remaining := l.MaxTotalDecompressedBytes - decoded
+		if remaining < 1 {
+			remaining = 1
+		}
+		if max == 0 || remaining < max {
+			return remaining, true
+		}
+	}
+	return max, false
+}
+
+// totalError is the error for decoded streams exceeding the total limit
+func (l Limits) totalError() error {
+	return &content.LimitError{Limit: "MaxTotalDecompressedBytes", Max: l.MaxTotalDecompressedBytes}
+}
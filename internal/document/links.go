@@ -0,0 +1,83 @@
+package document
+
+import (
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// LinkAnnotation is a Link annotation of a page: a clickable area and its target
+type LinkAnnotation struct {
+	PageNumber  int        // 1-based
+	Rect        [4]float64 // Lower-left and upper-right corners in points (see PDFPage.UserUnit)
+	Destination Destination
+}
+
+// GetLinks returns the Link annotations of every page in page order, with their targets
+// resolved. Links whose /Rect can't be read are skipped.
+func (doc *PDFDocument) GetLinks() []LinkAnnotation {
+	var named map[string]string
+	var links []LinkAnnotation
+
+	for _, page := range doc.Pages {
+		for _, annot := range doc.pageAnnotations(page) {
+			entries := dictionaryEntries(doc.resolveSource(annot))
+			if entries["Subtype"] != "/Link" {
+				continue
+			}
+
+			rect, ok := parseRect(string(doc.resolveSource(entries["Rect"])))
+			if !ok {
+				utils.Warnf(doc.Logger(), "Skipping link with invalid /Rect on page %d\n", page.PageNumber)
+				continue
+			}
+
+			// Named destinations are only collected once a link needs them
+			if named == nil {
+				named = doc.namedDestinations()
+			}
+
+			links = append(links, LinkAnnotation{
+				PageNumber:  page.PageNumber,
+				Rect:        scaleRect(rect, page.Unit()),
+				Destination: doc.linkDestination(entries, named),
+			})
+		}
+	}
+
+	return links
+}
+
+// pageAnnotations returns the entries of a page's /Annots array: references to annotation
+// dictionaries, or the dictionaries themselves
+func (doc *PDFDocument) pageAnnotations(page PDFPage) []string {
+	obj, ok := doc.Objects[page.ObjectNumber]
+	if !ok {
+		return nil
+	}
+	annots := doc.resolveSource(dictionaryEntries(objectSource(obj))["Annots"])
+	return joinReferences(content.ParseArrayOperand(string(annots)))
+}
+
+// parseRect parses a rectangle array, normalising it so the first corner is the lower left
+func parseRect(value string) ([4]float64, bool) {
+	var rect [4]float64
+	items := content.ParseArrayOperand(value)
+	if len(items) != 4 {
+		return rect, false
+	}
+	for i, item := range items {
+		v, err := utils.ParseFloat(item)
+		if err != nil {
+			return rect, false
+		}
+		rect[i] = v
+	}
+
+	if rect[0] > rect[2] {
+		rect[0], rect[2] = rect[2], rect[0]
+	}
+	if rect[1] > rect[3] {
+		rect[1], rect[3] = rect[3], rect[1]
+	}
+	return rect, true
+}
@@ -0,0 +1,108 @@
+package document
+
+import (
+	"container/list"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// objectCacheKey identifies a cached object: its source alone, or its dictionary and decoded
+// stream data
+type objectCacheKey struct {
+	objNum     int
+	withStream bool
+}
+
+// cachedObject is an object held by an objectCache
+type cachedObject struct {
+	key   objectCacheKey
+	dict  []byte      // Source of the object, up to the stream keyword for streams
+	data  []byte      // Decoded stream data, nil unless the key is withStream
+	value utils.Value // Parsed value of the object, for the objects a Resolver resolves
+}
+
+// objectCache holds the objects read on demand from a file, or resolved by a Resolver, evicting
+// the least recently used once their total size exceeds maxBytes, so that shared objects such
+// as fonts and object streams are read, decoded and parsed once
+type objectCache struct {
+	maxBytes  int
+	usedBytes int
+	order     *list.List // Most recently used first
+	entries   map[objectCacheKey]*list.Element
+	hits      int
+	misses    int
+}
+
+// newObjectCache creates a cache holding up to maxBytes of object data
+func newObjectCache(maxBytes int) *objectCache {
+	return &objectCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[objectCacheKey]*list.Element),
+	}
+}
+
+// get returns a cached object, counting the lookup as a hit or a miss
+func (c *objectCache) get(key objectCacheKey) (*cachedObject, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*cachedObject), true
+}
+
+// getValue returns the cached value parsed from an object's source, counting the lookup as a
+// hit or a miss. A value parsed from another source, as when the object was reloaded since,
+// is a miss.
+func (c *objectCache) getValue(objNum int, source []byte) (utils.Value, bool) {
+	element, ok := c.entries[objectCacheKey{objNum: objNum}]
+	if !ok || !sameBytes(element.Value.(*cachedObject).dict, source) {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*cachedObject).value, true
+}
+
+// sameBytes reports whether two slices are the same bytes in memory, not just equal ones
+func sameBytes(a, b []byte) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// put caches an object, evicting the least recently used ones to make room. Objects larger
+// than the whole cache are not kept.
+func (c *objectCache) put(key objectCacheKey, dict, data []byte) {
+	c.insert(&cachedObject{key: key, dict: dict, data: data})
+}
+
+// putValue caches the value parsed from the source of an object
+func (c *objectCache) putValue(objNum int, source []byte, value utils.Value) {
+	c.insert(&cachedObject{key: objectCacheKey{objNum: objNum}, dict: source, value: value})
+}
+
+// insert adds an entry to the cache, evicting the least recently used ones to make room
+func (c *objectCache) insert(object *cachedObject) {
+	size := len(object.dict) + len(object.data)
+	if size > c.maxBytes {
+		return
+	}
+	if element, ok := c.entries[object.key]; ok {
+		c.remove(element)
+	}
+	for c.usedBytes+size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+	c.entries[object.key] = c.order.PushFront(object)
+	c.usedBytes += size
+}
+
+// remove drops an entry from the cache
+func (c *objectCache) remove(element *list.Element) {
+	object := c.order.Remove(element).(*cachedObject)
+	delete(c.entries, object.key)
+	c.usedBytes -= len(object.dict) + len(object.data)
+}
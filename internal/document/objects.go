@@ -1,11 +1,12 @@
 package document
 
 import (
-	"bufio"
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
-	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -23,15 +24,169 @@ type PDFObject struct {
 	IsStream     bool
 }
 
+// Dict returns the dictionary of the object in the typed value model, parsed from its source.
+// Entries of Dictionary that the source lacks, as in objects built in memory, are included.
+func (obj PDFObject) Dict() utils.Dict {
+	dict, err := utils.ParseDict(objectSource(obj))
+	if err != nil {
+		dict = make(utils.Dict)
+	}
+	for key, value := range utils.DictFrom(obj.Dictionary) {
+		if _, ok := dict[key]; !ok {
+			dict[key] = value
+		}
+	}
+	return dict
+}
+
+// setStream sets the stream data of an object from its content: exactly length bytes after the
+// stream keyword when they are followed by endstream, and otherwise the bytes up to the
+// endstream keyword, as when length is -1. It reports false if a length was given but didn't
+// match the data.
+func (obj *PDFObject) setStream(length int) bool {
+	start, ok := streamDataStart(obj.Content)
+	if !ok || !bytes.Contains(obj.Content[start:], []byte("endstream")) {
+		return true
+	}
+
+	if length >= 0 && start+length <= len(obj.Content) {
+		rest := bytes.TrimLeft(obj.Content[start+length:], pdfWhitespace)
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			obj.Stream = obj.Content[start : start+length]
+			obj.IsStream = true
+			return true
+		}
+	}
+
+	streamEnd := bytes.Index(obj.Content[start:], []byte("endstream"))
+	if streamEnd > 0 {
+		obj.Stream = obj.Content[start : start+streamEnd]
+		obj.IsStream = true
+	}
+	return length < 0
+}
+
+// streamDataStart returns the offset in an object's source where its stream data starts: after
+// the stream keyword that follows the dictionary and the end-of-line marker after it
+func streamDataStart(source []byte) (int, bool) {
+	keyword := -1
+	if dictEnd, ok := utils.DictionaryEnd(source); ok {
+		rest := bytes.TrimLeft(source[dictEnd:], pdfWhitespace)
+		if bytes.HasPrefix(rest, []byte("stream")) {
+			keyword = len(source) - len(rest)
+		}
+	} else {
+		// Malformed dictionary; take the first stream keyword
+		keyword = bytes.Index(source, []byte("stream"))
+	}
+	if keyword == -1 {
+		return 0, false
+	}
+
+	// The keyword is followed by CRLF or LF, or a lone CR in some writers
+	start := keyword + len("stream")
+	if bytes.HasPrefix(source[start:], []byte("\r\n")) {
+		start += 2
+	} else if start < len(source) && (source[start] == '\n' || source[start] == '\r') {
+		start++
+	}
+	return start, true
+}
+
+// streamLength returns the /Length of a stream object, or -1 if it has no valid one. An
+// indirect length is resolved through the loaded objects or, failing that, by reading the
+// length object from file when that isn't nil.
+func (doc *PDFDocument) streamLength(obj PDFObject, file io.ReaderAt) int {
+	var length utils.Value
+	switch value := obj.Dict().Get("Length").(type) {
+	case utils.Number:
+		length = value
+	case utils.Ref:
+		if resolved, err := doc.Resolver().ResolveValue(value); err == nil {
+			length = resolved
+		} else if entry, ok := doc.XRefTable[value.Num]; ok && entry.InUse && file != nil {
+			if lengthObj, err := doc.readObjectAt(file, entry.Offset, value.Num, entry.Generation, nil); err == nil {
+				length, _ = utils.ParseValue(lengthObj.Content)
+			}
+		}
+	}
+
+	number, ok := length.(utils.Number)
+	if !ok || number < 0 || float64(number) != float64(int(number)) {
+		return -1
+	}
+	return int(number)
+}
+
+// DecodeParms returns the /DecodeParms dictionary of a stream object, or nil if it has none.
+// The entries before a malformed one are returned.
+func (obj PDFObject) DecodeParms() map[string]interface{} {
+	parms, _ := obj.parseDecodeParms()
+	return parms
+}
+
+// parseDecodeParms returns the /DecodeParms dictionary of a stream object and the error
+// parsing it, if any
+func (obj PDFObject) parseDecodeParms() (map[string]interface{}, error) {
+	switch parms := obj.Dictionary["DecodeParms"].(type) {
+	case string:
+		if !strings.HasPrefix(parms, "<<") || !strings.HasSuffix(parms, ">>") {
+			return nil, nil
+		}
+		decodeParms := make(map[string]interface{})
+		err := utils.ParseDictionary([]byte(parms)[2:len(parms)-2], decodeParms)
+		return decodeParms, err
+	case map[string]interface{}:
+		return parms, nil
+	}
+	return nil, nil
+}
+
 // PDFPage represents a page in the PDF
 type PDFPage struct {
 	PageNumber    int
+	ObjectNumber  int // Object number of the page dictionary
 	Contents      []byte
 	Text          string
 	ResourcesDict map[string]interface{}
 	TextPositions []TextPosition
 	Width         float64
 	Height        float64
+	Rotation      int // Clockwise display rotation in degrees (0, 90, 180 or 270)
+	TextRotation  int // Clockwise rotation applied to TextPositions to make the text upright
+	Boxes         PageBoxes
+	UserUnit      float64 // Size of a user space unit in points; Width, Height and Boxes include it
+
+	// Names in the page's /Properties resources that refer to optional content (layers) hidden
+	// in the default configuration, nil if there are none
+	HiddenContent map[string]bool
+
+	// Form XObjects in the page's resources, by name
+	XObjects map[string]*FormXObject
+
+	// Appearance streams of the page's annotations, drawn over the page content
+	Appearances []AnnotationAppearance
+
+	// Glyphs shown during text extraction, and how many of them had Unicode values from a
+	// ToUnicode CMap or an /ActualText replacement rather than a guessed encoding
+	GlyphCount       int
+	MappedGlyphCount int
+
+	// Glyphs shown during text extraction with each font, by the font's key in the document's fonts
+	FontGlyphs map[string]int
+
+	// Text recognized by OCR, kept so that extracting the page again doesn't run the engine
+	// again, and whether recognition has run, even if it failed
+	OCRPositions  []TextPosition
+	OCRRecognized bool
+}
+
+// Unit returns the size of the page's user space unit in points: the /UserUnit of the page, or 1
+func (page *PDFPage) Unit() float64 {
+	if page.UserUnit > 0 {
+		return page.UserUnit
+	}
+	return 1
 }
 
 // TextPosition represents a text element with position information
@@ -41,17 +196,38 @@ type TextPosition struct {
 	FontSize float64 // Current font size
 	Text     string  // The text at this position
 	FontName string  // Name of the font used
+	Width    float64 // Advance width of the text, including character and word spacing
+	Rise     float64 // Text rise above the baseline (superscripts and subscripts)
+	Angle    float64 // Baseline direction in degrees, counterclockwise from the X axis
+	Vertical bool    // Text was written in vertical writing mode (WMode 1)
+	MCID     int     // Marked-content identifier linking the text to the structure tree, -1 if none
+
+	// Advance of each rune of Text in user space, from the font's glyph widths
+	CharWidths []float64
 }
 
 // PDFFont represents a font in the PDF
 type PDFFont struct {
 	Name      string
+	BaseFont  string // PostScript name of the font, e.g. "/Helvetica-Bold"
 	Subtype   string
 	Encoding  string
 	ToUnicode []byte // The ToUnicode CMap if available
+	Embedded  bool   // The font program is embedded (/FontFile, /FontFile2 or /FontFile3)
+
+	// Writing mode from the encoding CMap: 0 for horizontal, 1 for vertical
+	WritingMode int
+
+	// Glyph widths in thousandths of a text space unit. Simple fonts list widths for codes
+	// starting at FirstChar; composite fonts map CIDs to widths, with DefaultWidth for the rest.
+	FirstChar    int
+	Widths       []float64
+	CIDWidths    map[int]float64
+	DefaultWidth float64 // 0 when the font doesn't declare one
 
 	// Character code to unicode mapping
 	CodeToUnicode map[int]rune
+	UnicodeMapped map[int]bool // Codes whose mapping comes from the ToUnicode CMap
 }
 
 // PDFXRefEntry represents an entry in the cross-reference table
@@ -66,167 +242,314 @@ var (
 	objHeaderPattern = regexp.MustCompile(`(\d+) (\d+) obj`)
 )
 
-// loadObjects loads objects using the xref table
-func loadObjects(file *os.File, doc *PDFDocument) error {
+// loadObjects loads objects using the xref table, then those stored in object streams
+func loadObjects(file io.ReaderAt, doc *PDFDocument) error {
 	for objNum, xrefEntry := range doc.XRefTable {
 		if !xrefEntry.InUse || xrefEntry.Offset == 0 {
 			continue
 		}
 
-		_, err := file.Seek(xrefEntry.Offset, io.SeekStart)
+		obj, err := doc.readObjectAt(file, xrefEntry.Offset, objNum, xrefEntry.Generation, file)
 		if err != nil {
-			utils.Logf(utils.LogWarning, "Failed to seek to object %d: %v\n", objNum, err)
+			doc.warnf(StageObjects, objNum, "Failed to load object %d: %v", objNum, err)
 			continue
 		}
 
-		// Read the object header
-		objHeader := make([]byte, 50) // Should be enough for the header
-		n, err := file.Read(objHeader)
-		if err != nil {
-			utils.Logf(utils.LogWarning, "Failed to read object %d header: %v\n", objNum, err)
-			continue
+		doc.Objects[objNum] = obj
+		if err := abort("OnObjectLoaded", doc.hooks().OnObjectLoaded(doc, obj)); err != nil {
+			return err
 		}
+	}
 
-		// Check object header format
-		headerMatches := objHeaderPattern.FindSubmatch(objHeader[:n])
+	return loadCompressedObjects(doc)
+}
 
-		if len(headerMatches) < 3 {
-			utils.Logf(utils.LogWarning, "Invalid object %d header format\n", objNum)
-			continue
-		}
+// loadCompressedObjects loads the objects stored in object streams (PDF 1.5). A rebuilt xref
+// table doesn't list them, so the object streams found by the scan are indexed instead.
+func loadCompressedObjects(doc *PDFDocument) error {
+	if doc.degradation.Path == ParsePathRebuild {
+		doc.indexObjectStreams()
+	}
 
-		matchedObjNum, err := strconv.Atoi(string(headerMatches[1]))
-		if err != nil {
-			utils.Logf(utils.LogWarning, "Invalid object number: %v\n", err)
-			continue
-		}
+	streams := make(map[int][]int)
+	for objNum, location := range doc.compressed {
+		streams[location[0]] = append(streams[location[0]], objNum)
+	}
+	streamNums := make([]int, 0, len(streams))
+	for streamNum := range streams {
+		streamNums = append(streamNums, streamNum)
+	}
+	sort.Ints(streamNums)
 
-		generation, err := strconv.Atoi(string(headerMatches[2]))
-		if err != nil {
-			utils.Logf(utils.LogWarning, "Invalid generation number: %v\n", err)
-			continue
+	for _, streamNum := range streamNums {
+		data, first, err := doc.decodeObjectStream(streamNum)
+		var limitErr *content.LimitError
+		if errors.As(err, &limitErr) {
+			return fmt.Errorf("object stream %d: %w", streamNum, err)
 		}
-
-		if matchedObjNum != objNum || generation != xrefEntry.Generation {
-			utils.Logf(utils.LogWarning, "Object number mismatch: expected %d gen %d, got %d gen %d\n",
-				objNum, xrefEntry.Generation, matchedObjNum, generation)
+		if err != nil {
+			doc.warnf(StageObjects, streamNum, "Failed to read object stream %d: %v", streamNum, err)
 			continue
 		}
-
-		// Read the object content
-		var contentBuffer bytes.Buffer
-
-		_, err = file.Seek(xrefEntry.Offset, io.SeekStart)
+		index, err := objectStreamIndex(data, first)
 		if err != nil {
-			utils.Logf(utils.LogWarning, "Failed to seek to object %d: %v\n", objNum, err)
+			doc.warnf(StageObjects, streamNum, "Failed to read object stream %d: %v", streamNum, err)
 			continue
 		}
 
-		inObject := false
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			if !inObject {
-				if strings.Contains(line, " obj") {
-					inObject = true
-					// Add everything after "obj"
-					parts := strings.SplitN(line, "obj", 2)
-					if len(parts) > 1 {
-						contentBuffer.WriteString(parts[1])
-					}
-				}
-			} else {
-				contentBuffer.WriteString(line)
-				contentBuffer.WriteString("\n")
+		objNums := streams[streamNum]
+		sort.Ints(objNums)
+		for _, objNum := range objNums {
+			i := doc.compressed[objNum][1]
+			source, err := objectStreamSource(data, first, index, i)
+			if err != nil {
+				doc.warnf(StageObjects, objNum, "Failed to load object %d from object stream %d: %v", objNum, streamNum, err)
+				continue
+			}
+			if index[i][0] != objNum {
+				doc.warnf(StageObjects, objNum, "Object stream %d holds object %d at index %d, where the xref table expects object %d",
+					streamNum, index[i][0], i, objNum)
+			}
 
-				if strings.Contains(line, "endobj") {
-					break
+			obj := PDFObject{
+				ObjectNumber: objNum,
+				Content:      source,
+				Dictionary:   make(map[string]interface{}),
+			}
+			if dictBytes, ok := utils.DictionaryBody(source); ok {
+				if err := utils.ParseDictionary(dictBytes, obj.Dictionary); err != nil {
+					doc.warnf(StageObjects, objNum, "Error parsing dictionary for object %d: %v", objNum, err)
 				}
 			}
+
+			doc.Objects[objNum] = obj
+			if err := abort("OnObjectLoaded", doc.hooks().OnObjectLoaded(doc, obj)); err != nil {
+				return err
+			}
 		}
+	}
+	return nil
+}
 
-		if err := scanner.Err(); err != nil {
-			utils.Logf(utils.LogWarning, "Error scanning object %d: %v\n", objNum, err)
+// indexObjectStreams lists the objects held by the loaded object streams that weren't found
+// directly in the file
+func (doc *PDFDocument) indexObjectStreams() {
+	for _, streamNum := range doc.sortedObjectNumbers() {
+		if objType, _ := doc.Objects[streamNum].Dict().Name("Type"); objType != "ObjStm" {
+			continue
+		}
+		data, first, err := doc.decodeObjectStream(streamNum)
+		if err != nil {
+			continue
+		}
+		index, err := objectStreamIndex(data, first)
+		if err != nil {
 			continue
 		}
+		for i, entry := range index {
+			if _, ok := doc.Objects[entry[0]]; !ok {
+				doc.compressed[entry[0]] = [2]int{streamNum, i}
+			}
+		}
+	}
+}
 
-		contentBytes := contentBuffer.Bytes()
+// decodeObjectStream returns the decoded data of a loaded object stream and its /First
+func (doc *PDFDocument) decodeObjectStream(streamNum int) ([]byte, int, error) {
+	obj, ok := doc.Objects[streamNum]
+	if !ok || !obj.IsStream {
+		return nil, 0, fmt.Errorf("object stream not loaded")
+	}
+	dict := obj.Dict()
+	first, ok := dict.Int("First")
+	if !ok || first < 0 {
+		return nil, 0, fmt.Errorf("invalid /First entry")
+	}
 
-		// Trim "endobj" from the end
-		if idx := bytes.Index(contentBytes, []byte("endobj")); idx != -1 {
-			contentBytes = contentBytes[:idx]
-		}
+	filter := dict.Get("Filter")
+	if filter == nil {
+		return obj.Stream, first, nil
+	}
+	parms, err := obj.parseDecodeParms()
+	if err != nil {
+		doc.warnf(StageDecompression, streamNum, "Error parsing DecodeParms for object %d: %v", streamNum, err)
+	}
+	maxSize, _ := doc.limits.streamLimit(doc.metrics.PeakStreamBytes)
+	data, err := content.DecompressStreamLimited(obj.Stream, filter.String(), parms, maxSize)
+	return data, first, err
+}
 
-		obj := PDFObject{
-			ObjectNumber: objNum,
-			Generation:   generation,
-			Content:      contentBytes,
-			Dictionary:   make(map[string]interface{}),
+// objectStreamIndex returns the object numbers and offsets, relative to /First, listed in
+// pairs at the start of a decoded object stream
+func objectStreamIndex(data []byte, first int) ([][2]int, error) {
+	if first > len(data) {
+		return nil, fmt.Errorf("/First %d past the end of the stream", first)
+	}
+	fields := strings.Fields(string(data[:first]))
+	index := make([][2]int, 0, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		objNum, numErr := strconv.Atoi(fields[i])
+		offset, offsetErr := strconv.Atoi(fields[i+1])
+		if numErr != nil || offsetErr != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid object stream index")
 		}
+		index = append(index, [2]int{objNum, offset})
+	}
+	return index, nil
+}
 
-		// Parse dictionary
-		if bytes.HasPrefix(contentBytes, []byte("<<")) && bytes.Contains(contentBytes, []byte(">>")) {
-			dictEnd := bytes.Index(contentBytes, []byte(">>"))
-			dictBytes := contentBytes[2:dictEnd]
-			err := utils.ParseDictionary(dictBytes, obj.Dictionary)
-			if err != nil {
-				utils.Logf(utils.LogWarning, "Error parsing dictionary for object %d: %v\n", objNum, err)
-			}
+// objectStreamSource returns the source of the object at position i of a decoded object
+// stream, which runs up to the next object's offset
+func objectStreamSource(data []byte, first int, index [][2]int, i int) ([]byte, error) {
+	if i < 0 || i >= len(index) {
+		return nil, fmt.Errorf("index %d out of range", i)
+	}
+	start := first + index[i][1]
+	end := len(data)
+	if i+1 < len(index) {
+		end = first + index[i+1][1]
+	}
+	if start > end || end > len(data) {
+		return nil, fmt.Errorf("invalid offset of object %d", index[i][0])
+	}
+	return data[start:end], nil
+}
+
+// Size of the blocks in which objects are read from the file
+const objectReadBlock = 64 * 1024
+
+// Room read past the end of stream data to find the endstream and endobj keywords after it
+const streamTrailerRoom = 64
+
+// readObjectAt reads the object numbered objNum at an offset of the file. It works on byte
+// offsets rather than lines, so binary stream data is kept as it is however long its lines.
+// A stream's data is delimited by its /Length, resolved through lengthFile when it refers to
+// an object not yet loaded, and by the endstream keyword when the length doesn't match.
+func (doc *PDFDocument) readObjectAt(file io.ReaderAt, offset int64, objNum, generation int, lengthFile io.ReaderAt) (PDFObject, error) {
+	// Read the object header
+	objHeader := make([]byte, 50) // Should be enough for the header
+	n, err := file.ReadAt(objHeader, offset)
+	if err != nil && err != io.EOF {
+		return PDFObject{}, fmt.Errorf("failed to read object %d header: %v", objNum, err)
+	}
+
+	// Check object header format
+	headerMatches := objHeaderPattern.FindSubmatchIndex(objHeader[:n])
+	if headerMatches == nil {
+		return PDFObject{}, fmt.Errorf("invalid object %d header format", objNum)
+	}
+
+	matchedObjNum, err := strconv.Atoi(string(objHeader[headerMatches[2]:headerMatches[3]]))
+	if err != nil {
+		return PDFObject{}, fmt.Errorf("invalid object number: %v", err)
+	}
+
+	matchedGeneration, err := strconv.Atoi(string(objHeader[headerMatches[4]:headerMatches[5]]))
+	if err != nil {
+		return PDFObject{}, fmt.Errorf("invalid generation number: %v", err)
+	}
+
+	if matchedObjNum != objNum || matchedGeneration != generation {
+		return PDFObject{}, fmt.Errorf("object number mismatch: expected %d gen %d, got %d gen %d",
+			objNum, generation, matchedObjNum, matchedGeneration)
+	}
+
+	// Read the object content, up to the first endobj keyword
+	reader := &objectReader{file: file, offset: offset + int64(headerMatches[1])}
+	end, err := reader.find([]byte("endobj"), 0)
+	if err != nil {
+		return PDFObject{}, fmt.Errorf("failed to read object %d: %v", objNum, err)
+	}
+
+	obj := PDFObject{
+		ObjectNumber: objNum,
+		Generation:   generation,
+		Content:      reader.data[:end],
+		Dictionary:   make(map[string]interface{}),
+	}
+
+	// Parse dictionary
+	if dictBytes, ok := utils.DictionaryBody(obj.Content); ok {
+		err := utils.ParseDictionary(dictBytes, obj.Dictionary)
+		if err != nil {
+			doc.warnf(StageObjects, objNum, "Error parsing dictionary for object %d: %v", objNum, err)
 		}
+	}
 
-		// Check for stream
-		if bytes.Contains(contentBytes, []byte("stream")) && bytes.Contains(contentBytes, []byte("endstream")) {
-			streamStart := bytes.Index(contentBytes, []byte("stream"))
-			streamStart += 6 // length of "stream"
-			if contentBytes[streamStart] == '\r' && contentBytes[streamStart+1] == '\n' {
-				streamStart += 2
-			} else if contentBytes[streamStart] == '\n' {
-				streamStart += 1
+	// Stream data may hold the endobj keyword, so read on past it to the end of the /Length
+	// bytes, and take the endobj after them when endstream follows the data
+	length := -1
+	if dataStart, ok := streamDataStart(obj.Content); ok {
+		length = doc.streamLength(obj, lengthFile)
+		if length >= 0 && dataStart+length > end {
+			if err := reader.fill(dataStart + length + streamTrailerRoom); err != nil {
+				return PDFObject{}, fmt.Errorf("failed to read object %d: %v", objNum, err)
 			}
-
-			streamEnd := bytes.Index(contentBytes, []byte("endstream"))
-			if streamStart < streamEnd {
-				obj.Stream = contentBytes[streamStart:streamEnd]
-				obj.IsStream = true
-
-				// Check if the stream has a filter
-				if filter, ok := obj.Dictionary["Filter"]; ok {
-					// Get decode parameters if any
-					var decodeParms map[string]interface{}
-					if parms, ok := obj.Dictionary["DecodeParms"]; ok {
-						switch p := parms.(type) {
-						case string:
-							if strings.HasPrefix(p, "<<") {
-								decodeParms = make(map[string]interface{})
-								parmBytes := []byte(p)[2 : len(p)-2]
-								err := utils.ParseDictionary(parmBytes, decodeParms)
-								if err != nil {
-									utils.Logf(utils.LogWarning, "Error parsing DecodeParms for object %d: %v\n", objNum, err)
-								}
-							}
-						case map[string]interface{}:
-							decodeParms = p
-						}
-					}
-
-					// Decompress the stream based on filter type
-					decompressed, err := content.DecompressStream(obj.Stream, filter.(string), decodeParms)
-					if err == nil {
-						obj.Stream = decompressed
-					} else {
-						utils.Logf(utils.LogWarning, "Failed to decompress stream for object %d: %v\n", objNum, err)
-					}
+			rest := bytes.TrimLeft(reader.data[min(dataStart+length, len(reader.data)):], pdfWhitespace)
+			if bytes.HasPrefix(rest, []byte("endstream")) {
+				if end, err = reader.find([]byte("endobj"), dataStart+length); err != nil {
+					return PDFObject{}, fmt.Errorf("failed to read object %d: %v", objNum, err)
 				}
+				obj.Content = reader.data[:end]
 			}
 		}
+	}
 
-		doc.Objects[objNum] = obj
+	// Check for stream, delimited by its /Length when that is consistent with the data
+	if !obj.setStream(length) {
+		doc.recordRecovery(HeuristicStreamLength, "/Length %d of object %d doesn't match its data; %d bytes found before endstream",
+			length, objNum, len(obj.Stream))
 	}
 
+	return obj, nil
+}
+
+// objectReader reads the source of an object from a file block by block, as far as needed
+type objectReader struct {
+	file   io.ReaderAt
+	offset int64  // Offset of the source in the file
+	data   []byte // Source read so far
+	eof    bool
+}
+
+// fill reads until at least n bytes of source have been read, or the file ends
+func (r *objectReader) fill(n int) error {
+	for len(r.data) < n && !r.eof {
+		block := make([]byte, objectReadBlock)
+		read, err := r.file.ReadAt(block, r.offset+int64(len(r.data)))
+		r.data = append(r.data, block[:read]...)
+		if err == io.EOF || read == 0 {
+			r.eof = true
+		} else if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// find returns the offset in the source of the first occurrence of a keyword at or after
+// from, reading as far as needed. Without one, the source ends with the file.
+func (r *objectReader) find(keyword []byte, from int) (int, error) {
+	for {
+		if from < len(r.data) {
+			if idx := bytes.Index(r.data[from:], keyword); idx >= 0 {
+				return from + idx, nil
+			}
+		}
+		if r.eof {
+			return len(r.data), nil
+		}
+		searched := max(from, len(r.data)-len(keyword)+1)
+		if err := r.fill(len(r.data) + objectReadBlock); err != nil {
+			return 0, err
+		}
+		from = max(searched, 0)
+	}
+}
+
+// PDF whitespace characters, as trimmed around keywords
+const pdfWhitespace = " \t\r\n\f\x00"
+
 // ExtractOrderedText generates text from ordered positions
 func (page *PDFPage) ExtractOrderedText() string {
 	var text strings.Builder
@@ -236,19 +559,37 @@ func (page *PDFPage) ExtractOrderedText() string {
 
 	for i, pos := range page.TextPositions {
 		if i > 0 {
-			// Check for new line
-			yDiff := pos.Y - lastY
-			if yDiff < -lineThreshold || yDiff > lineThreshold {
-				text.WriteString("\n")
-			} else if pos.X-lastX > pos.FontSize*spaceThreshold {
-				// Space between words detected
-				text.WriteString(" ")
+			if pos.Vertical {
+				// Vertical text runs top to bottom in columns, so a new column starts a new line
+				xDiff := pos.X - lastX
+				if xDiff < -lineThreshold || xDiff > lineThreshold {
+					text.WriteString("\n")
+				} else if lastY-pos.Y > pos.FontSize*spaceThreshold {
+					text.WriteString(" ")
+				}
+			} else {
+				// Check for new line
+				yDiff := pos.Y - lastY
+				if yDiff < -lineThreshold || yDiff > lineThreshold {
+					text.WriteString("\n")
+				} else if pos.X-lastX > pos.FontSize*spaceThreshold {
+					// Space between words detected
+					text.WriteString(" ")
+				}
 			}
 		}
 
 		text.WriteString(pos.Text)
+		if pos.Vertical {
+			lastX = pos.X
+			lastY = pos.Y - pos.Width
+			continue
+		}
 		lastY = pos.Y
-		lastX = pos.X + float64(len(pos.Text))*pos.FontSize*0.6
+		lastX = pos.X + pos.Width
+		if pos.Width == 0 {
+			lastX = pos.X + float64(len(pos.Text))*pos.FontSize*0.6
+		}
 	}
 
 	return text.String()
@@ -0,0 +1,79 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestStreamLength(t *testing.T) {
+	// The data holds the endstream keyword, so only its /Length delimits it
+	data := []byte("q\nendstream\nQ")
+	tests := []struct {
+		name      string
+		length    string // /Length entry of the stream, object 6
+		objects   string // Source of object 7, empty for none
+		want      []byte // Stream data expected
+		recovered bool   // Whether the endstream keyword delimited the data instead
+	}{
+		{
+			name:   "direct",
+			length: fmt.Sprint(len(data)),
+			want:   data,
+		},
+		{
+			name:    "indirect",
+			length:  "7 0 R",
+			objects: fmt.Sprint(len(data)),
+			want:    data,
+		},
+		{
+			// Without a length, the first endstream keyword ends the data
+			name:    "indirect to a missing object",
+			length:  "8 0 R",
+			objects: "null",
+			want:    []byte("q\n"),
+		},
+		{
+			name:      "too short",
+			length:    fmt.Sprint(len(data) - 2),
+			want:      []byte("q\n"),
+			recovered: true,
+		},
+		{
+			name:      "past the end of the object",
+			length:    "1000",
+			want:      []byte("q\n"),
+			recovered: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPDF()
+			p.page("Hello")
+			p.object(6, fmt.Sprintf("<< /Length %s >>\nstream\n%s\nendstream", tt.length, data))
+			objects := []int{1, 2, 3, 4, 5, 6}
+			if tt.objects != "" {
+				p.object(7, tt.objects)
+				objects = append(objects, 7)
+			}
+			p.xrefTable("\r\n", objects, "")
+
+			doc := parseTestPDF(t, p)
+			obj, ok := doc.GetObject(6)
+			if !ok || !obj.IsStream {
+				t.Fatalf("object 6 isn't a loaded stream")
+			}
+			if !bytes.Equal(obj.Stream, tt.want) {
+				t.Errorf("got stream data %q, want %q", obj.Stream, tt.want)
+			}
+			recovered := false
+			for _, step := range doc.RecoveryReport().Steps {
+				recovered = recovered || step.Heuristic == HeuristicStreamLength
+			}
+			if recovered != tt.recovered {
+				t.Errorf("stream-length recovery recorded: %v, want %v", recovered, tt.recovered)
+			}
+		})
+	}
+}
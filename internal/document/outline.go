@@ -0,0 +1,157 @@
+package document
+
+import (
+	"sort"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Maximum number of outline items visited, to guard against cyclic /Next links
+const maxOutlineItems = 10000
+
+// OutlineItem represents an entry in the document outline (bookmarks)
+type OutlineItem struct {
+	Title       string
+	Level       int // 1 for top-level entries
+	PageNumber  int // 1-based destination page, 0 if it could not be resolved
+	Destination Destination
+	Children    []OutlineItem
+}
+
+// OutlineSection is a contiguous page range starting at an outline entry
+type OutlineSection struct {
+	Title     string
+	Level     int
+	StartPage int
+	EndPage   int
+}
+
+// GetOutline returns the document outline tree, or nil if the document has none
+func (doc *PDFDocument) GetOutline() []OutlineItem {
+	catalog, ok := doc.GetRootObject()
+	if !ok {
+		return nil
+	}
+
+	outlinesRef, ok := dictionaryEntries(objectSource(catalog))["Outlines"]
+	if !ok {
+		return nil
+	}
+
+	outlinesObjNum, err := utils.ExtractReference(outlinesRef)
+	if err != nil {
+		utils.Warnf(doc.Logger(), "Invalid Outlines reference: %v\n", err)
+		return nil
+	}
+
+	outlines, ok := doc.Objects[outlinesObjNum]
+	if !ok {
+		return nil
+	}
+
+	visited := make(map[int]bool)
+	named := doc.namedDestinations()
+	return doc.parseOutlineItems(outlines, 1, visited, named)
+}
+
+// parseOutlineItems walks the /First ... /Next chain below an outline node
+func (doc *PDFDocument) parseOutlineItems(parent PDFObject, level int, visited map[int]bool, named map[string]string) []OutlineItem {
+	var items []OutlineItem
+
+	firstRef, ok := dictionaryEntries(objectSource(parent))["First"]
+	if !ok {
+		return nil
+	}
+
+	objNum, err := utils.ExtractReference(firstRef)
+	for err == nil && !visited[objNum] && len(visited) < maxOutlineItems {
+		visited[objNum] = true
+
+		obj, ok := doc.Objects[objNum]
+		if !ok {
+			utils.Warnf(doc.Logger(), "Outline item %d not found\n", objNum)
+			break
+		}
+		entries := dictionaryEntries(objectSource(obj))
+
+		item := OutlineItem{
+			Title:       utils.DecodeTextString(string(doc.resolveSource(entries["Title"]))),
+			Level:       level,
+			Destination: doc.linkDestination(entries, named),
+		}
+		item.PageNumber = item.Destination.PageNumber
+		item.Children = doc.parseOutlineItems(obj, level+1, visited, named)
+
+		items = append(items, item)
+
+		nextRef, ok := entries["Next"]
+		if !ok {
+			break
+		}
+		objNum, err = utils.ExtractReference(nextRef)
+	}
+
+	return items
+}
+
+// PageNumberForObject returns the 1-based page number of a page object, or 0 if it isn't a page
+func (doc *PDFDocument) PageNumberForObject(objNum int) int {
+	for _, page := range doc.Pages {
+		if page.ObjectNumber == objNum {
+			return page.PageNumber
+		}
+	}
+	return 0
+}
+
+// OutlineSections splits the document into page ranges using the outline entries at the given
+// level. Entries above that level also start a section, so chapters without sub-entries are kept.
+// Pages before the first entry are returned as a leading section with an empty title.
+func (doc *PDFDocument) OutlineSections(level int) []OutlineSection {
+	var starts []OutlineSection
+	var collect func(items []OutlineItem)
+	collect = func(items []OutlineItem) {
+		for _, item := range items {
+			if item.Level > level {
+				continue
+			}
+			if item.PageNumber > 0 {
+				starts = append(starts, OutlineSection{
+					Title:     item.Title,
+					Level:     item.Level,
+					StartPage: item.PageNumber,
+				})
+			}
+			collect(item.Children)
+		}
+	}
+	collect(doc.GetOutline())
+
+	if len(starts) == 0 || len(doc.Pages) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(starts, func(i, j int) bool {
+		return starts[i].StartPage < starts[j].StartPage
+	})
+
+	if starts[0].StartPage > 1 {
+		starts = append([]OutlineSection{{Level: level, StartPage: 1}}, starts...)
+	}
+
+	var sections []OutlineSection
+	for i, section := range starts {
+		section.EndPage = len(doc.Pages)
+		if i+1 < len(starts) {
+			section.EndPage = starts[i+1].StartPage - 1
+		}
+
+		// Entries pointing at the same page collapse into the last one
+		if section.EndPage < section.StartPage {
+			continue
+		}
+		sections = append(sections, section)
+	}
+
+	return sections
+}
@@ -0,0 +1,85 @@
+package document
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PageSpan is an inclusive span of 1-based page numbers. A Last of 0 means the end of the
+// document.
+type PageSpan struct {
+	First, Last int
+}
+
+// PageRange is a selection of pages. An empty range selects every page.
+type PageRange []PageSpan
+
+// ParsePageRange parses a comma-separated list of pages and spans such as "1-5,12" or "10-",
+// where an open span runs to the end of the document
+func ParsePageRange(spec string) (PageRange, error) {
+	var pageRange PageRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		first, last, isSpan := strings.Cut(part, "-")
+		span := PageSpan{}
+		var err error
+		if span.First, err = strconv.Atoi(strings.TrimSpace(first)); err != nil || span.First < 1 {
+			return nil, fmt.Errorf("invalid page %q in page range", first)
+		}
+		span.Last = span.First
+		if isSpan {
+			span.Last = 0
+			if last = strings.TrimSpace(last); last != "" {
+				if span.Last, err = strconv.Atoi(last); err != nil || span.Last < span.First {
+					return nil, fmt.Errorf("invalid page span %q in page range", part)
+				}
+			}
+		}
+		pageRange = append(pageRange, span)
+	}
+	return pageRange, nil
+}
+
+// Contains reports whether the range selects a page
+func (r PageRange) Contains(pageNum int) bool {
+	if len(r) == 0 {
+		return true
+	}
+	for _, span := range r {
+		if pageNum >= span.First && (span.Last == 0 || pageNum <= span.Last) {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the range in the form accepted by ParsePageRange
+func (r PageRange) String() string {
+	parts := make([]string, 0, len(r))
+	for _, span := range r {
+		switch {
+		case span.Last == 0:
+			parts = append(parts, fmt.Sprintf("%d-", span.First))
+		case span.Last == span.First:
+			parts = append(parts, strconv.Itoa(span.First))
+		default:
+			parts = append(parts, fmt.Sprintf("%d-%d", span.First, span.Last))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// PageSelected reports whether a page is in the range of pages whose content was loaded
+func (doc *PDFDocument) PageSelected(pageNum int) bool {
+	return doc.pageRange.Contains(pageNum)
+}
+
+// PageRange returns the range of pages whose content was loaded, empty if all were
+func (doc *PDFDocument) PageRange() PageRange {
+	return doc.pageRange
+}
@@ -2,8 +2,10 @@ package document
 
 import (
 	"bytes"
-	"strings"
+	"errors"
+	"fmt"
 
+	"github.com/yourusername/pdfex/internal/content"
 	"github.com/yourusername/pdfex/internal/utils"
 )
 
@@ -11,199 +13,200 @@ import (
 // We'll use function names with "Impl" suffix to avoid conflicts
 
 // Functions documented in document.go but implemented here:
-func processPages(doc *PDFDocument) {
+func processPages(doc *PDFDocument) error {
 	// Find catalog and pages
-	var catalogObj PDFObject
-	if doc.RootCatalog != 0 {
-		if obj, ok := doc.Objects[doc.RootCatalog]; ok {
-			catalogObj = obj
-		}
-	} else {
-		// Fallback: Look for catalog object
-		for _, obj := range doc.Objects {
-			if typ, ok := obj.Dictionary["Type"]; ok && typ == "/Catalog" {
-				catalogObj = obj
-				break
-			}
+	if _, ok := doc.Objects[doc.RootCatalog]; !ok {
+		doc.findCatalog()
+	}
+	catalogObj, ok := doc.Objects[doc.RootCatalog]
+	if !ok {
+		if doc.RootCatalog != 0 {
+			doc.errorf(StagePages, doc.RootCatalog, "Catalog object %d not found; the document has no pages", doc.RootCatalog)
+		} else {
+			doc.errorf(StagePages, 0, "No /Root in the trailer and no object of /Type /Catalog; the document has no pages")
 		}
+		return nil
 	}
 
 	// Find pages
-	if pagesRef, ok := catalogObj.Dictionary["Pages"]; ok {
-		// Extract object number from reference
-		pageTreeObjNum, err := utils.ExtractReference(pagesRef.(string))
-		if err != nil {
-			utils.Logf(utils.LogWarning, "Invalid Pages reference: %v\n", err)
-			return
+	pagesRef, ok := catalogObj.Dictionary["Pages"]
+	if !ok {
+		doc.errorf(StagePages, doc.RootCatalog, "Catalog has no /Pages entry; the document has no pages")
+		return nil
+	}
+	ref, ok := catalogObj.Dict().Ref("Pages")
+	if !ok {
+		doc.errorf(StagePages, doc.RootCatalog, "Invalid Pages reference: %v", pagesRef)
+		return nil
+	}
+	if _, err := processPageTree(doc, ref.Num, 1, 1); err != nil {
+		return err
+	}
+
+	if len(doc.Pages) == 0 {
+		if count := doc.countPageObjects(); count > 0 {
+			doc.errorf(StagePages, ref.Num, "No pages found in the page tree, though the file has %d objects of /Type /Page", count)
+		}
+	}
+	return nil
+}
+
+// countPageObjects returns the number of loaded objects of /Type /Page
+func (doc *PDFDocument) countPageObjects() int {
+	count := 0
+	for _, obj := range doc.Objects {
+		if objType, _ := obj.Dict().Name("Type"); objType == "Page" {
+			count++
 		}
-		processPageTree(doc, pageTreeObjNum, 1)
 	}
+	return count
 }
 
-// processPageTree processes a page tree node
-func processPageTree(doc *PDFDocument, objNum int, pageCounter int) int {
+// processPageTree processes a page tree node at a depth counted from 1 for the root
+func processPageTree(doc *PDFDocument, objNum int, pageCounter int, depth int) (int, error) {
+	if err := doc.limits.checkDepth(depth); err != nil {
+		return pageCounter, err
+	}
+
 	obj, ok := doc.Objects[objNum]
 	if !ok {
-		utils.Logf(utils.LogWarning, "Page tree object %d not found\n", objNum)
-		return pageCounter
+		doc.warnf(StagePages, objNum, "Page tree object %d not found", objNum)
+		return pageCounter, nil
 	}
 
-	if nodeType, ok := obj.Dictionary["Type"]; ok {
-		if nodeType == "/Pages" {
+	dict := obj.Dict()
+	if nodeType, ok := dict.Name("Type"); ok {
+		if nodeType == "Pages" {
 			// This is a page tree node
-			if kidsRef, ok := obj.Dictionary["Kids"]; ok {
-				// Extract kid references
-				kidRefs := utils.ParseArray(kidsRef.(string))
-
-				for _, kidRef := range kidRefs {
-					kidObjNum, err := utils.ExtractReference(kidRef)
-					if err != nil {
-						utils.Logf(utils.LogWarning, "Invalid kid reference: %v\n", err)
-						continue
-					}
-					pageCounter = processPageTree(doc, kidObjNum, pageCounter)
+			kids, _ := dict.Array("Kids")
+			for _, kid := range kids {
+				kidRef, ok := kid.(utils.Ref)
+				if !ok {
+					doc.warnf(StagePages, objNum, "Invalid kid reference: %v", kid)
+					continue
+				}
+				var err error
+				pageCounter, err = processPageTree(doc, kidRef.Num, pageCounter, depth+1)
+				if err != nil {
+					return pageCounter, err
 				}
 			}
-		} else if nodeType == "/Page" {
+		} else if nodeType == "Page" {
 			// This is a page
 			page := PDFPage{
 				PageNumber:    pageCounter,
+				ObjectNumber:  objNum,
 				ResourcesDict: make(map[string]interface{}),
 			}
 
 			// Get page dimensions
-			if mediaBoxRef, ok := obj.Dictionary["MediaBox"]; ok {
-				mediaBox := utils.ParseArray(mediaBoxRef.(string))
-				if len(mediaBox) == 4 {
-					// MediaBox is [llx lly urx ury]
-					llx, err := utils.ParseFloat(mediaBox[0])
-					if err != nil {
-						utils.Logf(utils.LogWarning, "Invalid MediaBox llx: %v\n", err)
-					}
-					lly, err := utils.ParseFloat(mediaBox[1])
-					if err != nil {
-						utils.Logf(utils.LogWarning, "Invalid MediaBox lly: %v\n", err)
-					}
-					urx, err := utils.ParseFloat(mediaBox[2])
-					if err != nil {
-						utils.Logf(utils.LogWarning, "Invalid MediaBox urx: %v\n", err)
-					}
-					ury, err := utils.ParseFloat(mediaBox[3])
-					if err != nil {
-						utils.Logf(utils.LogWarning, "Invalid MediaBox ury: %v\n", err)
-					}
-
-					page.Width = urx - llx
-					page.Height = ury - lly
+			if mediaBox, ok := dict.Array("MediaBox"); ok {
+				// MediaBox is [llx lly urx ury]
+				if box, ok := mediaBox.Numbers(); ok && len(box) == 4 {
+					page.Width = box[2] - box[0]
+					page.Height = box[3] - box[1]
+				} else {
+					doc.warnf(StagePages, objNum, "Invalid MediaBox: %v", mediaBox)
 				}
 			}
 
-			// Get resources
-			if resourcesRef, ok := obj.Dictionary["Resources"]; ok {
-				switch res := resourcesRef.(type) {
-				case string:
-					if strings.HasPrefix(res, "<<") {
-						// Resources are inline
-						resourcesDict := make(map[string]interface{})
-						dictBytes := []byte(res)[2 : len(res)-2]
-						err := utils.ParseDictionary(dictBytes, resourcesDict)
-						if err != nil {
-							utils.Logf(utils.LogWarning, "Error parsing resources dictionary: %v\n", err)
-						}
-						page.ResourcesDict = resourcesDict
-					} else {
-						// Resources are a reference
-						resourcesObjNum, err := utils.ExtractReference(res)
-						if err != nil {
-							utils.Logf(utils.LogWarning, "Invalid resources reference: %v\n", err)
-						} else if resourcesObj, ok := doc.Objects[resourcesObjNum]; ok {
-							page.ResourcesDict = resourcesObj.Dictionary
-						}
-					}
-				case map[string]interface{}:
-					page.ResourcesDict = res
+			// Get page rotation, which may be inherited from the page tree
+			if rotate, ok := inheritedAttribute(doc, obj, "Rotate"); ok {
+				page.Rotation = normalizeRotation(utils.GetInteger(rotate, 0))
+			}
+
+			// Pages outside the selected range keep their number and size but no content
+			if !doc.pageRange.Contains(pageCounter) {
+				doc.Pages = append(doc.Pages, page)
+				return pageCounter + 1, nil
+			}
+
+			// Get resources, which may be inline or a reference
+			resolver := doc.Resolver()
+			if resources := dict.Get("Resources"); resources != nil {
+				resourcesDict, err := resolver.ResolveDict(resources)
+				if err != nil {
+					doc.warnf(StagePages, objNum, "Invalid resources: %v", err)
+				} else {
+					page.ResourcesDict = resourcesDict.Map()
 				}
 			}
 
 			// Get content stream
-			if contentsRef, ok := obj.Dictionary["Contents"]; ok {
-				switch contents := contentsRef.(type) {
-				case string:
-					if strings.HasPrefix(contents, "[") {
-						// Multiple content streams
-						contentRefs := utils.ParseArray(contents)
-						var allContents bytes.Buffer
-
-						for _, contentRef := range contentRefs {
-							contentObjNum, err := utils.ExtractReference(contentRef)
-							if err != nil {
-								utils.Logf(utils.LogWarning, "Invalid content reference: %v\n", err)
-								continue
-							}
-							if contentObj, ok := doc.Objects[contentObjNum]; ok && contentObj.IsStream {
-								allContents.Write(contentObj.Stream)
-								allContents.WriteString("\n")
-							}
-						}
-
-						page.Contents = allContents.Bytes()
-					} else {
-						// Single content stream
-						contentObjNum, err := utils.ExtractReference(contents)
-						if err != nil {
-							utils.Logf(utils.LogWarning, "Invalid content reference: %v\n", err)
-						} else if contentObj, ok := doc.Objects[contentObjNum]; ok && contentObj.IsStream {
-							page.Contents = contentObj.Stream
-						}
-					}
+			if contents := dict.Get("Contents"); contents != nil {
+				pageContents, err := contentStreams(resolver, contents)
+				if err != nil {
+					doc.warnf(StagePages, objNum, "Invalid contents: %v", err)
 				}
+				page.Contents = pageContents
 			}
 
 			doc.Pages = append(doc.Pages, page)
-			return pageCounter + 1
+			return pageCounter + 1, nil
 		}
 	}
 
-	return pageCounter
+	return pageCounter, nil
 }
 
-// processTextChunks chunks the extracted text
-func processTextChunks(doc *PDFDocument) {
-	// Combine all text from all pages
-	var allText strings.Builder
-	for _, page := range doc.Pages {
-		allText.WriteString(page.Text)
-		allText.WriteString("\n")
+// contentStreams returns the data of a page's /Contents: a single stream, or the streams of an
+// array joined by newlines. Streams that can't be resolved are left out.
+func contentStreams(resolver *Resolver, contents utils.Value) ([]byte, error) {
+	if stream, err := resolver.ResolveStream(contents); err == nil {
+		return stream.Stream, nil
 	}
 
-	text := allText.String()
-
-	// Split into chunks (by paragraph, with a max size)
-	const maxChunkSize = 1000
+	resolved, err := resolver.ResolveValue(contents)
+	if err != nil {
+		return nil, err
+	}
+	streams, ok := resolved.(utils.Array)
+	if !ok {
+		return nil, fmt.Errorf("not a stream or array: %v", contents)
+	}
 
-	lines := strings.Split(text, "\n")
+	// Multiple content streams
+	var allContents bytes.Buffer
+	var firstErr error
+	for _, item := range streams {
+		stream, err := resolver.ResolveStream(item)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		allContents.Write(stream.Stream)
+		allContents.WriteString("\n")
+	}
+	return allContents.Bytes(), firstErr
+}
 
-	var currentChunk strings.Builder
+// Maximum number of /Parent links followed when resolving inherited page attributes
+const maxPageTreeDepth = 64
 
-	for _, line := range lines {
-		if currentChunk.Len()+len(line)+1 > maxChunkSize {
-			// Save current chunk and start a new one
-			if currentChunk.Len() > 0 {
-				doc.TextChunks = append(doc.TextChunks, currentChunk.String())
-				currentChunk.Reset()
-			}
+// inheritedAttribute looks up a page attribute, following /Parent links for inheritable keys
+func inheritedAttribute(doc *PDFDocument, obj PDFObject, key string) (interface{}, bool) {
+	for depth := 0; depth < maxPageTreeDepth; depth++ {
+		if value, ok := obj.Dictionary[key]; ok {
+			return value, true
 		}
 
-		if currentChunk.Len() > 0 {
-			currentChunk.WriteString("\n")
+		parentRef, ok := obj.Dict().Ref("Parent")
+		if !ok {
+			break
+		}
+		if obj, ok = doc.Objects[parentRef.Num]; !ok {
+			break
 		}
-		currentChunk.WriteString(line)
 	}
+	return nil, false
+}
 
-	// Add the last chunk if it's not empty
-	if currentChunk.Len() > 0 {
-		doc.TextChunks = append(doc.TextChunks, currentChunk.String())
-	}
+// normalizeRotation maps a /Rotate value onto 0, 90, 180 or 270 degrees
+func normalizeRotation(rotate int) int {
+	rotate = ((rotate % 360) + 360) % 360
+	return rotate - rotate%90
 }
 
 // processText extracts text from the document (basic implementation)
@@ -235,9 +238,47 @@ func handleMissingFonts(doc *PDFDocument) {
 	// Would be implemented in text/fonts.go in a real project
 }
 
-// processStreams processes streams in the document
-func processStreams(doc *PDFDocument) {
-	// This would be implemented in content/stream.go in a real project
+// processStreams decompresses the streams of the document that have filters. Streams that fail
+// to decode are left as they are, but exceeding a resource limit fails the parse.
+func processStreams(doc *PDFDocument) error {
+	for objNum, obj := range doc.Objects {
+		if !obj.IsStream {
+			continue
+		}
+		filter := obj.Dict().Get("Filter")
+		if filter == nil {
+			continue
+		}
+
+		// Decompress the stream based on filter type
+		parms, err := obj.parseDecodeParms()
+		if err != nil {
+			doc.warnf(StageDecompression, objNum, "Error parsing DecodeParms for object %d: %v", objNum, err)
+		}
+		maxSize, byTotal := doc.limits.streamLimit(doc.metrics.PeakStreamBytes)
+		decompressed, err := content.DecompressStreamLimited(obj.Stream, filter.String(), parms, maxSize)
+		var limitErr *content.LimitError
+		if errors.As(err, &limitErr) {
+			if byTotal {
+				return doc.limits.totalError()
+			}
+			return fmt.Errorf("stream of object %d: %w", objNum, err)
+		}
+		if err != nil {
+			doc.warnf(StageDecompression, objNum, "Failed to decompress stream for object %d: %v", objNum, err)
+			continue
+		}
+		obj.Stream = decompressed
+		doc.Objects[objNum] = obj
+		if err := abort("OnStreamDecoded", doc.hooks().OnStreamDecoded(doc, obj)); err != nil {
+			return err
+		}
+		doc.metrics.PeakStreamBytes += int64(len(decompressed))
+		if doc.limits.MaxTotalDecompressedBytes > 0 && doc.metrics.PeakStreamBytes > doc.limits.MaxTotalDecompressedBytes {
+			return doc.limits.totalError()
+		}
+	}
+	return nil
 }
 
 // Public API methods
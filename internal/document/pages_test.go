@@ -0,0 +1,64 @@
+package document
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMissingPagesWarning(t *testing.T) {
+	tests := []struct {
+		name    string
+		catalog string // Source of object 1
+		trailer string // Extra trailer entries
+		want    string // Start of the SeverityError warning expected, empty for none
+		pages   int
+	}{
+		{
+			name:    "page tree root missing",
+			catalog: "<< /Type /Catalog /Pages 9 0 R >>",
+			want:    "No pages found in the page tree",
+		},
+		{
+			name:    "catalog missing",
+			catalog: "<< /Type /Outlines /Count 0 >>",
+			trailer: " /Root 9 0 R",
+			want:    "Catalog object 9 not found",
+		},
+		{
+			name:    "catalog without pages",
+			catalog: "<< /Type /Catalog >>",
+			want:    "Catalog has no /Pages entry",
+		},
+		{
+			name:    "catalog found by type",
+			catalog: "<< /Type /Catalog /Pages 2 0 R >>",
+			trailer: " /Root 9 0 R",
+			pages:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPDF()
+			p.page("Hello")
+			p.object(1, tt.catalog)
+			p.xrefTable("\r\n", []int{1, 2, 3, 4, 5}, tt.trailer)
+
+			doc := parseTestPDF(t, p)
+			if len(doc.Pages) != tt.pages {
+				t.Errorf("got %d pages, want %d", len(doc.Pages), tt.pages)
+			}
+			var errors []string
+			for _, warning := range doc.Warnings() {
+				if warning.Stage == StagePages && warning.Severity == SeverityError {
+					errors = append(errors, warning.Message)
+				}
+			}
+			switch {
+			case tt.want == "" && len(errors) > 0:
+				t.Errorf("unexpected page errors %q", errors)
+			case tt.want != "" && (len(errors) != 1 || !strings.HasPrefix(errors[0], tt.want)):
+				t.Errorf("got page errors %q, want one starting with %q", errors, tt.want)
+			}
+		})
+	}
+}
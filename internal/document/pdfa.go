@@ -0,0 +1,127 @@
+package document
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Namespace of the PDF/A identification schema in XMP metadata
+const pdfaIDNamespace = "http://www.aiim.org/pdfa/ns/id/"
+
+// pdfaOutputIntentPattern matches the subtype of a PDF/A output intent dictionary
+var pdfaOutputIntentPattern = regexp.MustCompile(`/S\s*/GTS_PDFA1\b`)
+
+// CheckPDFA screens the document against key PDF/A requirements: the identification schema in
+// the XMP metadata, a PDF/A output intent, no encryption, no JavaScript and embedded programs
+// for all fonts. It is a screening, not a full validation: passing it doesn't make a file
+// conform. Findings are ordered by check, then by object number.
+func (doc *PDFDocument) CheckPDFA() []Finding {
+	v := &validator{doc: doc}
+	v.checkPDFAIdentification()
+	v.checkPDFAOutputIntent()
+	v.checkPDFAEncryption()
+	v.checkPDFAJavaScript()
+	v.checkPDFAFonts()
+	return v.findings
+}
+
+// checkPDFAIdentification reports a missing or incomplete pdfaid:part and pdfaid:conformance
+// in the XMP metadata
+func (v *validator) checkPDFAIdentification() {
+	packet := v.doc.XMPPacket()
+	if packet == nil {
+		v.add(SeverityError, "pdfa-no-metadata", 0, "the catalog has no XMP metadata stream")
+		return
+	}
+	properties, err := ParseXMP(packet)
+	if err != nil {
+		v.add(SeverityError, "pdfa-no-metadata", 0, "%v", err)
+		return
+	}
+
+	id := make(map[string]string)
+	for _, property := range properties {
+		if property.Namespace == pdfaIDNamespace {
+			id[property.Name] = property.Values[0]
+		}
+	}
+	switch part := id["part"]; {
+	case part == "":
+		v.add(SeverityError, "pdfa-no-identification", 0, "the XMP metadata has no pdfaid:part property")
+	case id["conformance"] == "" && part != "4":
+		// PDF/A-4 dropped conformance levels; earlier parts require one
+		v.add(SeverityError, "pdfa-no-identification", 0, "the XMP metadata claims PDF/A-%s without a pdfaid:conformance level", part)
+	}
+}
+
+// checkPDFAOutputIntent reports a catalog without a GTS_PDFA1 output intent, or one without
+// an ICC profile
+func (v *validator) checkPDFAOutputIntent() {
+	catalog, ok := v.doc.GetRootObject()
+	if !ok {
+		v.add(SeverityError, "pdfa-no-output-intent", 0, "the document has no catalog")
+		return
+	}
+
+	intents := v.doc.resolveSource(dictionaryEntries(objectSource(catalog))["OutputIntents"])
+	sources := [][]byte{intents}
+	for _, ref := range utils.FindReferences(intents) {
+		if obj, ok := v.doc.Objects[ref]; ok {
+			sources = append(sources, objectSource(obj))
+		}
+	}
+	for _, source := range sources {
+		if loc := pdfaOutputIntentPattern.FindIndex(source); loc != nil {
+			if utils.DictionaryValue(source, "DestOutputProfile") == "" {
+				v.add(SeverityError, "pdfa-no-output-intent", catalog.ObjectNumber, "the PDF/A output intent has no /DestOutputProfile")
+			}
+			return
+		}
+	}
+	v.add(SeverityError, "pdfa-no-output-intent", catalog.ObjectNumber, "the catalog has no /OutputIntents entry with /S /GTS_PDFA1")
+}
+
+// checkPDFAEncryption reports an encrypted document
+func (v *validator) checkPDFAEncryption() {
+	if _, ok := v.doc.Trailer["Encrypt"]; ok {
+		v.add(SeverityError, "pdfa-encrypted", 0, "the document is encrypted")
+	}
+}
+
+// checkPDFAJavaScript reports the objects holding JavaScript
+func (v *validator) checkPDFAJavaScript() {
+	for _, item := range v.doc.FindUnsafeContent() {
+		if item.Kind == "JavaScript" {
+			v.add(SeverityError, "pdfa-javascript", item.ObjectNumber, "the object holds JavaScript")
+		}
+	}
+}
+
+// checkPDFAFonts reports fonts whose program isn't embedded. Type 3 fonts draw their glyphs
+// with content streams, and CIDFonts are checked through the composite font using them.
+func (v *validator) checkPDFAFonts() {
+	for _, objNum := range v.doc.sortedObjectNumbers() {
+		obj := v.doc.Objects[objNum]
+		if utils.GetString(obj.Dictionary["Type"], "") != "/Font" {
+			continue
+		}
+		switch utils.GetString(obj.Dictionary["Subtype"], "") {
+		case "/Type3", "/CIDFontType0", "/CIDFontType2":
+			continue
+		}
+		if !v.doc.FontEmbedded(obj) {
+			v.add(SeverityError, "pdfa-font-not-embedded", objNum, "font %s is not embedded", fontName(obj))
+		}
+	}
+}
+
+// fontName returns the /BaseFont of a font without its slash, or a placeholder if it has none
+func fontName(obj PDFObject) string {
+	if name := strings.TrimPrefix(utils.GetString(obj.Dictionary["BaseFont"], ""), "/"); name != "" {
+		return name
+	}
+	return fmt.Sprintf("without /BaseFont in object %d", obj.ObjectNumber)
+}
@@ -0,0 +1,386 @@
+package document
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/metrics"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// QuickInfo holds what can be learned about a document from its header, trailer and catalog
+type QuickInfo struct {
+	Version   string // From the header, or the catalog's /Version when that is later
+	PageCount int    // /Count of the page tree root, -1 if it couldn't be read
+	Encrypted bool
+	Producer  string // /Producer of the information dictionary; empty when encrypted
+}
+
+// Maximum number of cross-reference sections followed through /Prev, to guard against cycles
+const maxXRefSections = 256
+
+// Largest object read when looking up the catalog, page tree root or information dictionary
+const maxQuickObjectSize = 1 << 20
+
+// Bytes of objects and decoded object streams kept in memory while reading on demand
+const quickObjectCacheSize = 16 << 20
+
+var (
+	// Matches the header of an indirect object, e.g. "12 0 obj"
+	objectHeaderPattern = regexp.MustCompile(`^\s*(\d+)\s+(\d+)\s+obj\b`)
+	// Matches a version number in the header
+	versionPattern = regexp.MustCompile(`%PDF-(\d\.\d)`)
+)
+
+// quickReader locates objects through the cross-reference sections of a file without loading
+// the objects themselves
+type quickReader struct {
+	file       io.ReaderAt
+	size       int64
+	offsets    map[int]int64  // Objects stored directly in the file
+	compressed map[int][2]int // Objects in object streams: stream object number and index
+	trailer    []byte         // Newest trailer dictionary holding a /Root entry
+	cache      *objectCache   // Objects and decoded object streams read so far
+	logger     *slog.Logger
+}
+
+// ReadQuickInfo reads the version, page count, encryption status and producer of a PDF file by
+// following its cross-reference table to the catalog and the page tree root, without parsing
+// any other object. This takes milliseconds however large the file is. The logger may be nil
+// for utils.DefaultLogger.
+func ReadQuickInfo(filename string, logger *slog.Logger) (*QuickInfo, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+	return ReadQuickInfoFrom(file, stat.Size(), filename, logger)
+}
+
+// ReadQuickInfoFrom reads what ReadQuickInfo does from the size bytes of file, naming the
+// document name in log messages
+func ReadQuickInfoFrom(file io.ReaderAt, size int64, name string, logger *slog.Logger) (*QuickInfo, error) {
+	head := make([]byte, headerSearchSize)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read header: %v", err)
+	}
+	match := versionPattern.FindSubmatch(head[:n])
+	if match == nil {
+		return nil, ErrNotPDF
+	}
+	info := &QuickInfo{Version: string(match[1]), PageCount: -1}
+
+	r := &quickReader{
+		file:       file,
+		size:       size,
+		logger:     ParseConfig{Logger: logger}.logger(name),
+		offsets:    make(map[int]int64),
+		compressed: make(map[int][2]int),
+		cache:      newObjectCache(quickObjectCacheSize),
+	}
+	defer func() {
+		metrics.Service.RecordObjectCache(r.cache.hits, r.cache.misses)
+	}()
+	if err := r.readXRef(); err != nil {
+		return nil, err
+	}
+
+	trailer := dictionaryEntries(r.trailer)
+	info.Encrypted = trailer["Encrypt"] != ""
+
+	catalog, err := r.resolve(trailer["Root"])
+	if err != nil {
+		// Object streams of encrypted documents can't be read, which is no reason to parse
+		// them in full
+		if info.Encrypted {
+			return info, nil
+		}
+		return nil, fmt.Errorf("failed to read catalog: %v", err)
+	}
+	if version := strings.TrimPrefix(dictionaryEntries(catalog)["Version"], "/"); version > info.Version {
+		info.Version = version
+	}
+
+	if pages, err := r.resolve(dictionaryEntries(catalog)["Pages"]); err == nil {
+		if count, err := r.resolve(dictionaryEntries(pages)["Count"]); err == nil {
+			if n, err := strconv.Atoi(string(bytes.TrimSpace(count))); err == nil {
+				info.PageCount = n
+			}
+		}
+	}
+
+	// Strings of encrypted documents can't be read without decrypting them
+	if infoRef := trailer["Info"]; infoRef != "" && !info.Encrypted {
+		if dict, err := r.resolve(infoRef); err == nil {
+			if producer, err := r.resolve(dictionaryEntries(dict)["Producer"]); err == nil && len(producer) > 0 {
+				info.Producer = utils.DecodeTextString(string(bytes.TrimSpace(producer)))
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// readXRef reads the cross-reference sections from the startxref offset back through /Prev,
+// as the parser does
+func (r *quickReader) readXRef() error {
+	offset, err := findLastXRefOffset(r.file, r.size)
+	if err != nil {
+		return err
+	}
+
+	reader := &xrefReader{file: r.file, size: r.size, warn: func(format string, args ...interface{}) {
+		utils.Warnf(r.logger, format+"\n", args...)
+	}}
+	sections, err := reader.readSections(offset)
+	if len(sections) == 0 {
+		return err
+	}
+	if err != nil {
+		utils.Warnf(r.logger, "Older cross-reference sections ignored: %v\n", err)
+	}
+
+	var entries map[int]PDFXRefEntry
+	entries, r.compressed = mergeXRefSections(sections)
+	for objNum, entry := range entries {
+		if entry.InUse {
+			r.offsets[objNum] = entry.Offset
+		}
+	}
+	for _, section := range sections {
+		if dictionaryEntries(section.trailer)["Root"] != "" {
+			r.trailer = section.trailer
+			break
+		}
+	}
+
+	if r.trailer == nil {
+		return fmt.Errorf("no trailer with a /Root entry found")
+	}
+	return nil
+}
+
+// readDictionary reads a dictionary starting with prefix and continuing from reader, up to its
+// closing >>
+func readDictionary(reader *bufio.Reader, prefix string) ([]byte, error) {
+	dict := []byte(prefix)
+	for len(dict) < maxQuickObjectSize {
+		if start := bytes.Index(dict, []byte("<<")); start >= 0 {
+			if end := dictionaryEnd(dict, start); end > 0 {
+				return dict[start:end], nil
+			}
+		}
+		line, err := reader.ReadBytes('\n')
+		dict = append(dict, line...)
+		if err != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("unterminated dictionary")
+}
+
+// dictionaryEnd returns the offset just past the >> closing the dictionary at start, or -1 if
+// it isn't closed within data
+func dictionaryEnd(data []byte, start int) int {
+	depth := 0
+	for i := start; i+1 < len(data); i++ {
+		switch {
+		case data[i] == '<' && data[i+1] == '<':
+			depth++
+			i++
+		case data[i] == '>' && data[i+1] == '>':
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1
+			}
+		case data[i] == '(':
+			// Skip strings, which may hold unbalanced brackets
+			i = stringEnd(data, i) - 1
+		}
+	}
+	return -1
+}
+
+// stringEnd returns the offset just past the ) closing the literal string at start
+func stringEnd(data []byte, start int) int {
+	depth := 0
+	for i := start; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(data)
+}
+
+// readObjectAt reads the indirect object at an offset and returns its source, up to the stream
+// keyword for streams. With withStream, stream data is also read and decoded.
+func (r *quickReader) readObjectAt(offset int64, withStream bool) ([]byte, []byte, error) {
+	return readObjectSource(r.file, r.size, offset, withStream, r.resolve)
+}
+
+// readObjectSource reads the indirect object at an offset of file and returns its source, up to
+// the stream keyword for streams. With withStream, stream data is also read and decoded; an
+// indirect /Length is followed through resolve, which may be nil for files whose objects can't
+// be looked up yet.
+func readObjectSource(file io.ReaderAt, size, offset int64, withStream bool, resolve func(string) ([]byte, error)) ([]byte, []byte, error) {
+	if offset < 0 || offset >= size {
+		return nil, nil, fmt.Errorf("offset %d out of range", offset)
+	}
+	length := size - offset
+	if length > maxQuickObjectSize {
+		length = maxQuickObjectSize
+	}
+	buf := make([]byte, length)
+	n, err := file.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+	buf = buf[:n]
+
+	header := objectHeaderPattern.FindIndex(buf)
+	if header == nil {
+		return nil, nil, fmt.Errorf("no object at offset %d", offset)
+	}
+	body := buf[header[1]:]
+	if end := bytes.Index(body, []byte("endobj")); end >= 0 {
+		body = body[:end]
+	}
+
+	start := bytes.Index(body, []byte("stream"))
+	if start < 0 || !bytes.HasPrefix(bytes.TrimSpace(body), []byte("<<")) {
+		return body, nil, nil
+	}
+	dict := body[:start]
+	if !withStream {
+		return dict, nil, nil
+	}
+
+	start += len("stream")
+	if bytes.HasPrefix(body[start:], []byte("\r\n")) {
+		start += 2
+	} else if start < len(body) && (body[start] == '\n' || body[start] == '\r') {
+		start++
+	}
+
+	// Read exactly /Length bytes from the file when it is known, since the data may be larger
+	// than the window read and may contain the endobj keyword
+	var data []byte
+	entries := dictionaryEntries(dict)
+	lengthSrc := []byte(entries["Length"])
+	if resolve != nil {
+		lengthSrc, _ = resolve(entries["Length"])
+	}
+	if length, err := strconv.Atoi(string(bytes.TrimSpace(lengthSrc))); err == nil && length >= 0 {
+		data = make([]byte, length)
+		n, err := file.ReadAt(data, offset+int64(header[1]+start))
+		if err != nil && err != io.EOF {
+			return nil, nil, err
+		}
+		data = data[:n]
+	}
+	if data == nil {
+		data = body[start:]
+		if end := bytes.LastIndex(data, []byte("endstream")); end >= 0 {
+			data = data[:end]
+		}
+	}
+
+	filter := entries["Filter"]
+	if filter == "" {
+		return dict, data, nil
+	}
+	var parms map[string]interface{}
+	if source := entries["DecodeParms"]; strings.HasPrefix(source, "<<") && strings.HasSuffix(source, ">>") {
+		parms = make(map[string]interface{})
+		utils.ParseDictionary([]byte(source[2:len(source)-2]), parms)
+	}
+	decoded, err := content.DecompressStream(data, filter, parms)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode stream: %v", err)
+	}
+	return dict, decoded, nil
+}
+
+// resolve returns the source of a value, following it if it is an indirect reference
+func (r *quickReader) resolve(value string) ([]byte, error) {
+	objNum, ok := referenceNumber(value)
+	if !ok {
+		if value == "" {
+			return nil, fmt.Errorf("missing value")
+		}
+		return []byte(value), nil
+	}
+
+	key := objectCacheKey{objNum: objNum}
+	if cached, ok := r.cache.get(key); ok {
+		return cached.dict, nil
+	}
+
+	var source []byte
+	var err error
+	if offset, ok := r.offsets[objNum]; ok {
+		source, _, err = r.readObjectAt(offset, false)
+	} else if location, ok := r.compressed[objNum]; ok {
+		source, err = r.readCompressed(location[0], location[1])
+	} else {
+		return nil, fmt.Errorf("object %d not in the xref table", objNum)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.cache.put(key, source, nil)
+	return source, nil
+}
+
+// readCompressed returns the source of the object at an index of an object stream
+func (r *quickReader) readCompressed(streamNum, index int) ([]byte, error) {
+	offset, ok := r.offsets[streamNum]
+	if !ok {
+		return nil, fmt.Errorf("object stream %d not in the xref table", streamNum)
+	}
+
+	var dict, data []byte
+	key := objectCacheKey{objNum: streamNum, withStream: true}
+	if cached, ok := r.cache.get(key); ok {
+		dict, data = cached.dict, cached.data
+	} else {
+		var err error
+		if dict, data, err = r.readObjectAt(offset, true); err != nil {
+			return nil, err
+		}
+		r.cache.put(key, dict, data)
+	}
+
+	first, err := strconv.Atoi(dictionaryEntries(dict)["First"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid object stream %d", streamNum)
+	}
+	objects, err := objectStreamIndex(data, first)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object stream %d: %v", streamNum, err)
+	}
+	return objectStreamSource(data, first, objects, index)
+}
@@ -0,0 +1,48 @@
+package document
+
+import "testing"
+
+func TestReadQuickInfoMatchesParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(p *testPDF)
+	}{
+		{"space LF entries", func(p *testPDF) {
+			p.page("Hello")
+			p.xrefTable(" \n", []int{1, 2, 3, 4, 5}, "")
+		}},
+		{"prev chain", func(p *testPDF) {
+			p.page("Hello")
+			p.xrefTable("\r\n", []int{1, 2, 3, 4, 5}, "")
+			p.object(2, "<< /Type /Pages /Kids [3 0 R 3 0 R] /Count 2 >>")
+			p.xrefTable(" \n", []int{2}, "")
+		}},
+		{"xref stream", func(p *testPDF) {
+			p.stream(5, "", []byte("BT /F1 12 Tf 72 720 Td (Hello) Tj ET"))
+			p.xrefStream(7, p.objectStream(6, map[int]string{
+				1: "<< /Type /Catalog /Pages 2 0 R >>",
+				2: "<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				3: "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R >>",
+			}))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPDF()
+			tt.build(p)
+			filename := p.write(t)
+
+			info, err := ReadQuickInfo(filename, nil)
+			if err != nil {
+				t.Fatalf("ReadQuickInfo: %v", err)
+			}
+			doc, err := ParsePDF(filename)
+			if err != nil {
+				t.Fatalf("ParsePDF: %v", err)
+			}
+			if info.PageCount != len(doc.Pages) || info.PageCount < 1 {
+				t.Errorf("ReadQuickInfo found %d pages, ParsePDF %d", info.PageCount, len(doc.Pages))
+			}
+		})
+	}
+}
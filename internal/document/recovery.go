@@ -0,0 +1,84 @@
+package document
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Recovery heuristics the parser can fall back to
+const (
+	HeuristicNearbyXRef   = "nearby-xref"   // The xref table was searched for around a wrong startxref offset
+	HeuristicRebuildXRef  = "rebuild-xref"  // The xref table was rebuilt by scanning the file for objects
+	HeuristicTrailerScan  = "trailer-scan"  // The trailer was found by scanning back from the end of the file
+	HeuristicLinearParse  = "linear-parse"  // No xref table was found; objects were parsed linearly
+	HeuristicStreamLength = "stream-length" // A stream's /Length didn't match its data; endstream delimited it
+	HeuristicCatalogScan  = "catalog-scan"  // The trailer's /Root couldn't be loaded; an object of /Type /Catalog was used
+)
+
+// RecoveryStep is a recovery heuristic that fired while parsing
+type RecoveryStep struct {
+	Heuristic string
+	Detail    string
+}
+
+// RecoveryReport describes what the parse repaired: the heuristics that fired, in order, the
+// objects that were only loaded thanks to them and the objects that could not be loaded
+type RecoveryReport struct {
+	Path      ParsePath
+	Steps     []RecoveryStep
+	Recovered []int // Objects loaded through a recovery path, sorted
+	Lost      []int // Objects listed in the xref table or referenced that could not be loaded, sorted
+}
+
+// Repaired reports whether any recovery heuristic fired
+func (r *RecoveryReport) Repaired() bool {
+	return len(r.Steps) > 0
+}
+
+// recordRecovery records that a recovery heuristic fired
+func (doc *PDFDocument) recordRecovery(heuristic, format string, args ...interface{}) {
+	detail := fmt.Sprintf(format, args...)
+	utils.Debugf(doc.Logger(), "Recovery heuristic %s: %s", heuristic, detail)
+	doc.recovery = append(doc.recovery, RecoveryStep{Heuristic: heuristic, Detail: detail})
+}
+
+// RecoveryReport returns the report of what the parse repaired
+func (doc *PDFDocument) RecoveryReport() *RecoveryReport {
+	report := &RecoveryReport{
+		Path:  doc.degradation.Path,
+		Steps: append([]RecoveryStep(nil), doc.recovery...),
+	}
+
+	// Objects came from the scan rather than the xref table whenever the table was rebuilt or
+	// missing; a table found near a wrong offset still lists where each object is
+	if report.Path == ParsePathRebuild || report.Path == ParsePathLinear {
+		report.Recovered = doc.sortedObjectNumbers()
+	}
+
+	lost := make(map[int]bool)
+	for objNum, entry := range doc.XRefTable {
+		if _, ok := doc.Objects[objNum]; entry.InUse && entry.Offset != 0 && !ok {
+			lost[objNum] = true
+		}
+	}
+	for objNum := range doc.compressed {
+		if _, ok := doc.Objects[objNum]; !ok {
+			lost[objNum] = true
+		}
+	}
+	for _, obj := range doc.Objects {
+		for _, ref := range utils.FindReferences(objectSource(obj)) {
+			if _, ok := doc.Objects[ref]; !ok {
+				lost[ref] = true
+			}
+		}
+	}
+	for objNum := range lost {
+		report.Lost = append(report.Lost, objNum)
+	}
+	sort.Ints(report.Lost)
+
+	return report
+}
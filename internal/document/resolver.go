@@ -0,0 +1,134 @@
+package document
+
+import (
+	"fmt"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Maximum number of indirect references followed to resolve a single value
+const maxResolveDepth = 32
+
+// Bytes of object source whose parsed values a document keeps for its resolvers
+const resolverCacheSize = 8 << 20
+
+// Resolver follows indirect references to the objects of a document, so that a value such as
+// /Length 12 0 R or /Resources 7 0 R can be used like a direct one. A chain of references that
+// leads back to itself is reported as an error rather than followed forever. The values parsed
+// are cached with the document, so shared objects such as fonts and resources are parsed once;
+// they must not be modified.
+type Resolver struct {
+	doc *PDFDocument
+}
+
+// Resolver returns a resolver for the loaded objects of the document
+func (doc *PDFDocument) Resolver() *Resolver {
+	return &Resolver{doc: doc}
+}
+
+// ResolveValue follows indirect references until the value is a direct object. A reference to
+// a stream resolves to the stream's dictionary. A nil value resolves to nil.
+func (r *Resolver) ResolveValue(value utils.Value) (utils.Value, error) {
+	obj, value, err := r.follow(value)
+	if err != nil || obj == nil {
+		return value, err
+	}
+	if obj.IsStream {
+		return r.parse(*obj)
+	}
+	return value, nil
+}
+
+// ResolveDict resolves a value to a dictionary, either direct or held by the referenced object
+func (r *Resolver) ResolveDict(value utils.Value) (utils.Dict, error) {
+	resolved, err := r.ResolveValue(value)
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := resolved.(utils.Dict)
+	if !ok {
+		return nil, fmt.Errorf("not a dictionary: %v", resolved)
+	}
+	return dict, nil
+}
+
+// ResolveStream resolves a reference to the stream object it designates
+func (r *Resolver) ResolveStream(value utils.Value) (PDFObject, error) {
+	obj, _, err := r.follow(value)
+	if err != nil {
+		return PDFObject{}, err
+	}
+	if obj == nil || !obj.IsStream {
+		return PDFObject{}, fmt.Errorf("not a stream: %v", value)
+	}
+	return *obj, nil
+}
+
+// follow follows indirect references from a value, returning the last object reached, if any,
+// with its value
+func (r *Resolver) follow(value utils.Value) (*PDFObject, utils.Value, error) {
+	var obj *PDFObject
+	visited := make(map[int]bool)
+	for depth := 0; ; depth++ {
+		ref, ok := value.(utils.Ref)
+		if !ok {
+			return obj, value, nil
+		}
+		if visited[ref.Num] {
+			return nil, nil, fmt.Errorf("reference cycle at object %d", ref.Num)
+		}
+		if depth >= maxResolveDepth {
+			return nil, nil, fmt.Errorf("more than %d indirect references from %v", maxResolveDepth, value)
+		}
+		visited[ref.Num] = true
+
+		target, ok := r.doc.Objects[ref.Num]
+		if !ok {
+			return nil, nil, fmt.Errorf("object %d not found", ref.Num)
+		}
+		obj = &target
+		if target.IsStream {
+			return obj, value, nil
+		}
+
+		parsed, err := r.parse(target)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing object %d: %v", ref.Num, err)
+		}
+		if len(target.Dictionary) > 0 {
+			return obj, parsed, nil
+		}
+		value = parsed
+	}
+}
+
+// parse returns the value of an object, taken from the document's cache or parsed from its
+// source: the dictionary of a stream or dictionary object, or else the value it holds
+func (r *Resolver) parse(obj PDFObject) (utils.Value, error) {
+	doc := r.doc
+	doc.resolvedMu.Lock()
+	defer doc.resolvedMu.Unlock()
+	if doc.resolved == nil {
+		doc.resolved = newObjectCache(resolverCacheSize)
+	}
+
+	source := objectSource(obj)
+	if value, ok := doc.resolved.getValue(obj.ObjectNumber, source); ok {
+		return value, nil
+	}
+	var value utils.Value
+	if obj.IsStream || len(obj.Dictionary) > 0 {
+		value = obj.Dict()
+	} else {
+		parsed, err := utils.ParseValue(source)
+		if err != nil {
+			return nil, err
+		}
+		value = parsed
+	}
+	// Objects built in memory have no source to tell them apart
+	if len(source) > 0 {
+		doc.resolved.putValue(obj.ObjectNumber, source, value)
+	}
+	return value, nil
+}
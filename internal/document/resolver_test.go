@@ -0,0 +1,113 @@
+package document
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// resolverObjects are the objects beyond the page of the documents the resolver tests build
+var resolverObjects = map[int]string{
+	6:  "7 0 R",
+	7:  "42",
+	8:  "<< /Type /Example /Value 6 0 R >>",
+	9:  "10 0 R",
+	10: "11 0 R",
+	11: "9 0 R",
+	12: "12 0 R",
+	13: "99 0 R",
+}
+
+func TestResolver(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(p *testPDF)
+	}{
+		{"xref table", func(p *testPDF) {
+			p.page("Hello")
+			objects := []int{1, 2, 3, 4, 5}
+			for num, source := range resolverObjects {
+				p.object(num, source)
+				objects = append(objects, num)
+			}
+			p.xrefTable("\r\n", objects, "")
+		}},
+		{"object stream", func(p *testPDF) {
+			p.page("Hello")
+			p.xrefStream(21, p.objectStream(20, resolverObjects))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPDF()
+			tt.build(p)
+			r := parseTestPDF(t, p).Resolver()
+
+			// A chain of references ends at the direct object
+			if value, err := r.ResolveValue(utils.Ref{Num: 6}); err != nil || value != utils.Number(42) {
+				t.Errorf("ResolveValue(6 0 R) = %v, %v, want 42", value, err)
+			}
+			dict, err := r.ResolveDict(utils.Ref{Num: 8})
+			if err != nil {
+				t.Fatalf("ResolveDict(8 0 R): %v", err)
+			}
+			if value, err := r.ResolveValue(dict.Get("Value")); err != nil || value != utils.Number(42) {
+				t.Errorf("ResolveValue(/Value) = %v, %v, want 42", value, err)
+			}
+			// A direct value is its own resolution
+			if value, err := r.ResolveValue(utils.Number(1)); err != nil || value != utils.Number(1) {
+				t.Errorf("ResolveValue(1) = %v, %v, want 1", value, err)
+			}
+
+			errors := []struct {
+				ref  int
+				want string
+			}{
+				{9, "reference cycle"},
+				{12, "reference cycle"},
+				{13, "object 99 not found"},
+				{98, "object 98 not found"},
+			}
+			for _, e := range errors {
+				if value, err := r.ResolveValue(utils.Ref{Num: e.ref}); err == nil || !strings.Contains(err.Error(), e.want) {
+					t.Errorf("ResolveValue(%d 0 R) = %v, %v, want an error containing %q", e.ref, value, err, e.want)
+				}
+			}
+
+			// Streams resolve to their dictionary, and only streams to a stream
+			if dict, err := r.ResolveDict(utils.Ref{Num: 5}); err != nil || dict.Get("Length") == nil {
+				t.Errorf("ResolveDict(5 0 R) = %v, %v, want the stream dictionary", dict, err)
+			}
+			if stream, err := r.ResolveStream(utils.Ref{Num: 5}); err != nil || len(stream.Stream) == 0 {
+				t.Errorf("ResolveStream(5 0 R) = %v, want the content stream", err)
+			}
+			if _, err := r.ResolveStream(utils.Ref{Num: 8}); err == nil {
+				t.Error("ResolveStream(8 0 R) resolved a dictionary to a stream")
+			}
+		})
+	}
+}
+
+func TestResolverCache(t *testing.T) {
+	p := newTestPDF()
+	p.page("Hello")
+	p.object(6, "7 0 R")
+	p.object(7, "42")
+	p.xrefTable("\r\n", []int{1, 2, 3, 4, 5, 6, 7}, "")
+	doc := parseTestPDF(t, p)
+
+	m := doc.Metrics()
+	hits, misses := m.ObjectCacheHits, m.ObjectCacheMisses
+	for i := 0; i < 2; i++ {
+		// Each resolver shares the document's cache
+		if value, err := doc.Resolver().ResolveValue(utils.Ref{Num: 6}); err != nil || value != utils.Number(42) {
+			t.Fatalf("ResolveValue(6 0 R) = %v, %v, want 42", value, err)
+		}
+	}
+	// Objects 6 and 7 are parsed the first time and taken from the cache the second
+	m = doc.Metrics()
+	if m.ObjectCacheHits-hits != 2 || m.ObjectCacheMisses-misses != 2 {
+		t.Errorf("got %d hits and %d misses, want 2 of each", m.ObjectCacheHits-hits, m.ObjectCacheMisses-misses)
+	}
+}
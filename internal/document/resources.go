@@ -0,0 +1,61 @@
+package document
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// resourceNamePattern matches the keys of a resource dictionary, e.g. /F1 or /Im0
+var resourceNamePattern = regexp.MustCompile(`/([^\s/<>\[\]()]+)\s+\d+\s+\d+\s+R`)
+
+// PageResources returns the objects named in a category of a page's resources, such as "Font"
+// or "XObject", keyed by resource name without the leading slash. Resources inherited from the
+// page tree are included. Direct objects, which have no object number, are omitted.
+func (doc *PDFDocument) PageResources(page PDFPage, category string) map[string]int {
+	resources := make(map[string]int)
+
+	obj, ok := doc.Objects[page.ObjectNumber]
+	if !ok {
+		return resources
+	}
+	return doc.namedResources(doc.inheritedEntry(obj, "Resources"), category)
+}
+
+// namedResources returns the objects named in a category of a resource dictionary, which may be
+// given inline or as an indirect reference
+func (doc *PDFDocument) namedResources(value string, category string) map[string]int {
+	resources := make(map[string]int)
+	entries := doc.resolveSource(utils.DictionaryValue(doc.resolveSource(value), category))
+	for _, match := range resourceNamePattern.FindAllSubmatch(entries, -1) {
+		name := string(match[1])
+		if objNum, err := utils.ExtractReference(utils.DictionaryValue(entries, name)); err == nil {
+			resources[utils.UnescapeName(name)] = objNum
+		}
+	}
+
+	return resources
+}
+
+// resolveSource returns the source of a dictionary value, following an indirect reference
+func (doc *PDFDocument) resolveSource(value string) []byte {
+	if fields := strings.Fields(value); len(fields) != 3 || fields[2] != "R" {
+		return []byte(value)
+	}
+	objNum, ok := referenceNumber(value)
+	if !ok {
+		return nil
+	}
+	return objectSource(doc.Objects[objNum])
+}
+
+// referenceNumber returns the object number of a value that is an indirect reference and
+// nothing else, unlike utils.ExtractReference which also finds references inside dictionaries
+func referenceNumber(value string) (int, bool) {
+	if fields := strings.Fields(value); len(fields) != 3 || fields[2] != "R" {
+		return 0, false
+	}
+	objNum, err := utils.ExtractReference(value)
+	return objNum, err == nil
+}
@@ -0,0 +1,97 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Revision is one version of an incrementally updated file. Each update is appended to the
+// file, so a revision is the file up to End, holding every earlier revision before Start.
+type Revision struct {
+	Start      int64 // Offset where the revision's update starts, the End of the previous revision
+	End        int64 // Offset just past the %%EOF marker that ends the revision
+	XRefOffset int64 // Offset of the revision's cross-reference section given by startxref, -1 if none
+	Objects    []int // Objects defined in the update, sorted; an update redefines those it changes
+}
+
+// The end of a revision: its startxref offset and the end-of-file marker
+var revisionEndPattern = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF`)
+
+// FindRevisions returns the revisions of a file, oldest first. A linearized file's first-page
+// section is part of its first revision. Objects after the last end-of-file marker, as left by
+// an interrupted update, form a last revision with no XRefOffset. A file without any marker is
+// a single revision. The file holds size bytes.
+func FindRevisions(file io.ReaderAt, size int64) ([]Revision, error) {
+	var revisions []Revision
+	start := int64(0)
+	err := scanBlocks(file, func(data []byte, offset int64, before byte) {
+		for _, match := range revisionEndPattern.FindAllSubmatchIndex(data, -1) {
+			if match[0] >= objectReadBlock {
+				break // Found again at the start of the next block
+			}
+			xrefOffset, err := strconv.ParseInt(string(data[match[2]:match[3]]), 10, 64)
+			if err != nil {
+				xrefOffset = -1
+			}
+
+			// The end-of-line marker after %%EOF belongs to the revision
+			end := match[1]
+			if bytes.HasPrefix(data[end:], []byte("\r\n")) {
+				end += 2
+			} else if end < len(data) && (data[end] == '\n' || data[end] == '\r') {
+				end++
+			}
+
+			revisions = append(revisions, Revision{Start: start, End: offset + int64(end), XRefOffset: xrefOffset})
+			start = offset + int64(end)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning file for revisions: %v", err)
+	}
+	if len(revisions) == 0 || start < size {
+		revisions = append(revisions, Revision{Start: start, End: size, XRefOffset: -1})
+	}
+
+	// Assign the objects defined in the file to the revisions holding them
+	err = scanObjectHeaders(file, func(objNum, gen int, offset int64) {
+		i := sort.Search(len(revisions), func(i int) bool { return revisions[i].End > offset })
+		if i < len(revisions) {
+			revisions[i].Objects = append(revisions[i].Objects, objNum)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning file for revisions: %v", err)
+	}
+
+	// Trailing bytes after the last marker are only a revision if they define objects
+	if last := revisions[len(revisions)-1]; len(revisions) > 1 && last.XRefOffset == -1 && len(last.Objects) == 0 {
+		revisions = revisions[:len(revisions)-1]
+	}
+
+	// A linearized file ends its first-page section with a marker of its own
+	if len(revisions) > 1 && linearized(file) {
+		revisions[1].Start = 0
+		revisions[1].Objects = append(revisions[0].Objects, revisions[1].Objects...)
+		revisions = revisions[1:]
+	}
+
+	for i := range revisions {
+		sort.Ints(revisions[i].Objects)
+	}
+	return revisions, nil
+}
+
+// Bytes at the start of a file searched for the linearization dictionary
+const linearizationWindow = 1024
+
+// linearized reports whether the first object of a file is a linearization dictionary
+func linearized(file io.ReaderAt) bool {
+	head := make([]byte, linearizationWindow)
+	n, _ := file.ReadAt(head, 0)
+	return bytes.Contains(head[:n], []byte("/Linearized"))
+}
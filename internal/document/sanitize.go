@@ -0,0 +1,138 @@
+package document
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Patterns for active and external content in object source
+var (
+	actionPattern       = regexp.MustCompile(`/S\s*/(JavaScript|Launch|URI|SubmitForm|ImportData|GoToR|GoToE|RichMediaExecute)\b`)
+	javaScriptPattern   = regexp.MustCompile(`/(JS|JavaScript)\s*[(<\[0-9]`)
+	embeddedFilePattern = regexp.MustCompile(`/(Type\s*/EmbeddedFile\b|EF\s*<<|EmbeddedFiles\b)`)
+)
+
+// UnsafeContent is an object containing active or external content that sanitizing removes
+type UnsafeContent struct {
+	ObjectNumber int
+	Kind         string // JavaScript, EmbeddedFile, or the action type such as Launch or URI
+	Detail       string // Target of the action, if known
+}
+
+// FindUnsafeContent returns the objects holding JavaScript, embedded files and actions that
+// reach outside the document, in object number order
+func (doc *PDFDocument) FindUnsafeContent() []UnsafeContent {
+	var found []UnsafeContent
+
+	for _, objNum := range doc.sortedObjectNumbers() {
+		source := objectSource(doc.Objects[objNum])
+
+		kinds := make(map[string]bool)
+		for _, match := range actionPattern.FindAllSubmatchIndex(source, -1) {
+			kind := string(source[match[2]:match[3]])
+			if kinds[kind] {
+				continue
+			}
+			kinds[kind] = true
+			// The target follows the action type within the same action dictionary
+			found = append(found, UnsafeContent{ObjectNumber: objNum, Kind: kind, Detail: actionTarget(source[match[1]:], kind)})
+		}
+
+		if !kinds["JavaScript"] && javaScriptPattern.Match(source) {
+			found = append(found, UnsafeContent{ObjectNumber: objNum, Kind: "JavaScript"})
+		}
+		if embeddedFilePattern.Match(source) {
+			found = append(found, UnsafeContent{ObjectNumber: objNum, Kind: "EmbeddedFile"})
+		}
+	}
+
+	return found
+}
+
+// actionTarget returns the destination of an external action, e.g. the URI or launched file,
+// from the source following its /S entry
+func actionTarget(source []byte, kind string) string {
+	var key string
+	switch kind {
+	case "URI":
+		key = "URI"
+	case "Launch", "GoToR", "GoToE", "ImportData", "SubmitForm":
+		key = "F"
+	default:
+		return ""
+	}
+
+	value := utils.DictionaryValue(source, key)
+	if strings.HasPrefix(value, "(") || strings.HasPrefix(value, "<") && !strings.HasPrefix(value, "<<") {
+		return utils.DecodeTextString(value)
+	}
+	return value
+}
+
+// UnreferencedObjects returns the numbers of objects that can't be reached from the trailer,
+// which a rewrite would drop. Object and xref streams are containers rather than content and
+// are never reported.
+func (doc *PDFDocument) UnreferencedObjects() []int {
+	reached := make(map[int]bool)
+	var queue []int
+
+	for _, key := range []string{"Root", "Info", "Encrypt"} {
+		if ref, ok := doc.Trailer[key].(string); ok {
+			if objNum, err := utils.ExtractReference(ref); err == nil {
+				queue = append(queue, objNum)
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		objNum := queue[0]
+		queue = queue[1:]
+		if reached[objNum] {
+			continue
+		}
+		reached[objNum] = true
+
+		obj, ok := doc.Objects[objNum]
+		if !ok {
+			continue
+		}
+		queue = append(queue, utils.FindReferences(objectSource(obj))...)
+	}
+
+	var unreferenced []int
+	for _, objNum := range doc.sortedObjectNumbers() {
+		if reached[objNum] {
+			continue
+		}
+		objType, _ := doc.Objects[objNum].Dictionary["Type"].(string)
+		if objType == "/ObjStm" || objType == "/XRef" {
+			continue
+		}
+		unreferenced = append(unreferenced, objNum)
+	}
+
+	return unreferenced
+}
+
+// objectSource returns the source of an object without its stream data, so that binary
+// data isn't mistaken for references or keys
+func objectSource(obj PDFObject) []byte {
+	if idx := bytes.Index(obj.Content, []byte("stream")); idx != -1 && obj.IsStream {
+		return obj.Content[:idx]
+	}
+	return obj.Content
+}
+
+// sortedObjectNumbers returns the numbers of all loaded objects in ascending order
+func (doc *PDFDocument) sortedObjectNumbers() []int {
+	numbers := make([]int, 0, len(doc.Objects))
+	for objNum := range doc.Objects {
+		numbers = append(numbers, objNum)
+	}
+	sort.Ints(numbers)
+	return numbers
+}
@@ -0,0 +1,161 @@
+package document
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Kinds of security indicators
+const (
+	IndicatorJavaScript         = "javascript"          // /JavaScript or /JS entries
+	IndicatorLaunch             = "launch"              // /Launch action running a program or opening a file
+	IndicatorSubmitForm         = "submit-form"         // /SubmitForm action sending form data out
+	IndicatorOpenAction         = "open-action"         // Action run when the document is opened
+	IndicatorAdditionalActions  = "additional-actions"  // /AA actions triggered by events such as page open
+	IndicatorEmbeddedExecutable = "embedded-executable" // Embedded file that is a program or script
+	IndicatorSuspiciousURI      = "suspicious-uri"      // URI action to a scheme other than web, mail or phone
+)
+
+// Risk levels of security indicators
+const (
+	RiskHigh   = "high"   // Runs code or reaches outside the document without the user's intent
+	RiskMedium = "medium" // Triggers automatically or sends data out, but is common in benign files
+)
+
+// SecurityIndicator is a risky construct found in an object
+type SecurityIndicator struct {
+	ObjectNumber int
+	Kind         string
+	Risk         string
+	Detail       string // Target, trigger or file name, if known
+}
+
+// URI schemes of ordinary links; others, such as file:, javascript: or smb:, are suspicious
+var safeURISchemes = map[string]bool{"http": true, "https": true, "mailto": true, "tel": true}
+
+// Patterns for additional actions and URI schemes
+var (
+	additionalActionsPattern = regexp.MustCompile(`/AA\s*(<<|\d+\s+\d+\s+R)`)
+	uriSchemePattern         = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9+.-]*):`)
+)
+
+// File name extensions of programs and scripts that an embedded file can carry
+var executableExtensions = map[string]bool{
+	".exe": true, ".dll": true, ".scr": true, ".com": true, ".bat": true, ".cmd": true,
+	".ps1": true, ".vbs": true, ".vbe": true, ".js": true, ".jse": true, ".wsf": true,
+	".hta": true, ".msi": true, ".jar": true, ".sh": true, ".app": true, ".lnk": true,
+}
+
+// Leading bytes of executable file formats
+var executableMagic = []struct {
+	prefix []byte
+	format string
+}{
+	{[]byte("MZ"), "Windows executable"},
+	{[]byte("\x7fELF"), "ELF executable"},
+	{[]byte("\xcf\xfa\xed\xfe"), "Mach-O executable"},
+	{[]byte("\xca\xfe\xba\xbe"), "Mach-O universal binary or Java class"},
+	{[]byte("#!"), "script"},
+}
+
+// SecurityReport returns the risky constructs of the document, for triage of untrusted files:
+// JavaScript, launch and submit-form actions, the open action, additional actions, embedded
+// executables and URI actions to suspicious schemes. Indicators are ordered by object number.
+func (doc *PDFDocument) SecurityReport() []SecurityIndicator {
+	var indicators []SecurityIndicator
+	add := func(objNum int, kind, risk, detail string) {
+		indicators = append(indicators, SecurityIndicator{ObjectNumber: objNum, Kind: kind, Risk: risk, Detail: detail})
+	}
+
+	for _, item := range doc.FindUnsafeContent() {
+		switch item.Kind {
+		case "JavaScript":
+			add(item.ObjectNumber, IndicatorJavaScript, RiskHigh, item.Detail)
+		case "Launch":
+			add(item.ObjectNumber, IndicatorLaunch, RiskHigh, item.Detail)
+		case "SubmitForm":
+			add(item.ObjectNumber, IndicatorSubmitForm, RiskMedium, item.Detail)
+		case "URI":
+			if suspiciousURI(item.Detail) {
+				add(item.ObjectNumber, IndicatorSuspiciousURI, RiskHigh, item.Detail)
+			}
+		}
+	}
+
+	if catalog, ok := doc.GetRootObject(); ok {
+		if action, ok := dictionaryEntries(objectSource(catalog))["OpenAction"]; ok {
+			add(catalog.ObjectNumber, IndicatorOpenAction, RiskMedium, doc.actionSummary(action))
+		}
+	}
+
+	for _, objNum := range doc.sortedObjectNumbers() {
+		obj := doc.Objects[objNum]
+		source := objectSource(obj)
+
+		if additionalActionsPattern.Match(source) {
+			triggers := dictionaryEntries(doc.resolveSource(dictionaryEntries(source)["AA"]))
+			names := make([]string, 0, len(triggers))
+			for name := range triggers {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			add(objNum, IndicatorAdditionalActions, RiskMedium, strings.Join(names, ", "))
+		}
+
+		if name := embeddedFileName(source); name != "" && executableExtensions[strings.ToLower(path.Ext(name))] {
+			add(objNum, IndicatorEmbeddedExecutable, RiskHigh, name)
+		}
+		if obj.IsStream && utils.GetString(obj.Dictionary["Type"], "") == "/EmbeddedFile" {
+			for _, magic := range executableMagic {
+				if bytes.HasPrefix(obj.Stream, magic.prefix) {
+					add(objNum, IndicatorEmbeddedExecutable, RiskHigh, magic.format)
+					break
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(indicators, func(i, j int) bool {
+		return indicators[i].ObjectNumber < indicators[j].ObjectNumber
+	})
+	return indicators
+}
+
+// suspiciousURI reports whether a URI action target uses a scheme other than those of
+// ordinary links, or is a Windows network path
+func suspiciousURI(uri string) bool {
+	uri = strings.TrimSpace(uri)
+	if strings.HasPrefix(uri, `\\`) {
+		return true
+	}
+	match := uriSchemePattern.FindStringSubmatch(uri)
+	return match != nil && !safeURISchemes[strings.ToLower(match[1])]
+}
+
+// actionSummary describes an action or destination value: the action type, or "destination"
+func (doc *PDFDocument) actionSummary(value string) string {
+	source := doc.resolveSource(value)
+	if kind := dictionaryEntries(source)["S"]; kind != "" {
+		return strings.TrimPrefix(kind, "/")
+	}
+	return "destination"
+}
+
+// embeddedFileName returns the file name of a file specification with an embedded file, or ""
+func embeddedFileName(source []byte) string {
+	entries := dictionaryEntries(source)
+	if _, ok := entries["EF"]; !ok {
+		return ""
+	}
+	for _, key := range []string{"UF", "F"} {
+		if value := entries[key]; value != "" {
+			return utils.DecodeTextString(value)
+		}
+	}
+	return ""
+}
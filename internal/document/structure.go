@@ -0,0 +1,159 @@
+package document
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Limits that guard against cyclic or pathological structure trees
+const (
+	maxStructTreeDepth = 256
+	maxRoleMapChain    = 16
+)
+
+// StructElement is an element of the logical structure tree of a tagged PDF
+type StructElement struct {
+	Type       string // Standard structure type after role mapping, e.g. P, H1, Table, TD or Figure
+	Role       string // Type as written in the document, when it is mapped to a standard type
+	Title      string
+	Lang       string
+	Alt        string // Alternate description, e.g. of a figure
+	ActualText string // Replacement text for the element's content
+	Kids       []StructKid
+}
+
+// StructKid is a child of a structure element in logical order: either an element, or a marked-
+// content sequence on a page, identified by its MCID
+type StructKid struct {
+	Element    *StructElement // nil for marked content
+	PageNumber int            // 1-based page of the marked content, 0 if it could not be resolved
+	MCID       int
+}
+
+// GetStructureTree returns the top-level elements of the document's structure tree, or nil if
+// the document is not tagged. Object references (OBJR), such as links and form widgets, are
+// skipped.
+func (doc *PDFDocument) GetStructureTree() []*StructElement {
+	catalog, ok := doc.GetRootObject()
+	if !ok {
+		return nil
+	}
+	root := dictionaryEntries(doc.resolveSource(dictionaryEntries(objectSource(catalog))["StructTreeRoot"]))
+	if len(root) == 0 {
+		return nil
+	}
+
+	roles := make(map[string]string)
+	for name, value := range dictionaryEntries(doc.resolveSource(root["RoleMap"])) {
+		roles[name] = strings.TrimPrefix(value, "/")
+	}
+
+	parent := &StructElement{}
+	doc.collectStructKids(parent, root["K"], 0, roles, make(map[int]bool), 0)
+
+	var elements []*StructElement
+	for _, kid := range parent.Kids {
+		if kid.Element != nil {
+			elements = append(elements, kid.Element)
+		}
+	}
+	return elements
+}
+
+// collectStructKids adds the kids in a /K value, which is a single kid or an array of them, to
+// an element. Marked-content identifiers given as bare integers belong to the page pageObj.
+func (doc *PDFDocument) collectStructKids(elem *StructElement, value string, pageObj int,
+	roles map[string]string, visited map[int]bool, depth int) {
+	value = strings.TrimSpace(value)
+	if value == "" || depth >= maxStructTreeDepth {
+		return
+	}
+
+	if strings.HasPrefix(value, "[") {
+		for _, item := range joinReferences(content.ParseArrayOperand(value)) {
+			doc.collectStructKid(elem, item, pageObj, roles, visited, depth)
+		}
+		return
+	}
+	doc.collectStructKid(elem, value, pageObj, roles, visited, depth)
+}
+
+// collectStructKid adds a single kid: an MCID, a marked-content reference, an object reference
+// or a structure element
+func (doc *PDFDocument) collectStructKid(elem *StructElement, item string, pageObj int,
+	roles map[string]string, visited map[int]bool, depth int) {
+	if mcid, err := strconv.Atoi(item); err == nil {
+		elem.Kids = append(elem.Kids, StructKid{PageNumber: doc.PageNumberForObject(pageObj), MCID: mcid})
+		return
+	}
+
+	if objNum, ok := referenceNumber(item); ok {
+		if visited[objNum] {
+			return
+		}
+		visited[objNum] = true
+	}
+
+	entries := dictionaryEntries(doc.resolveSource(item))
+	if page, ok := referenceNumber(entries["Pg"]); ok {
+		pageObj = page
+	}
+
+	switch entries["Type"] {
+	case "/MCR":
+		mcid, err := strconv.Atoi(entries["MCID"])
+		if err != nil {
+			return
+		}
+		// Marked content in form XObjects (/Stm) is not extracted, so it can't be placed
+		if _, ok := entries["Stm"]; ok {
+			return
+		}
+		elem.Kids = append(elem.Kids, StructKid{PageNumber: doc.PageNumberForObject(pageObj), MCID: mcid})
+		return
+	case "/OBJR":
+		return
+	}
+
+	structType, ok := entries["S"]
+	if !ok {
+		return
+	}
+	kid := &StructElement{
+		Type:       resolveRole(strings.TrimPrefix(structType, "/"), roles),
+		Title:      doc.textEntry(entries["T"]),
+		Lang:       doc.textEntry(entries["Lang"]),
+		Alt:        doc.textEntry(entries["Alt"]),
+		ActualText: doc.textEntry(entries["ActualText"]),
+	}
+	if written := strings.TrimPrefix(structType, "/"); written != kid.Type {
+		kid.Role = written
+	}
+	doc.collectStructKids(kid, string(doc.resolveSource(entries["K"])), pageObj, roles, visited, depth+1)
+
+	elem.Kids = append(elem.Kids, StructKid{Element: kid})
+}
+
+// resolveRole maps a structure type through the role map to a standard type, following
+// chains of mappings
+func resolveRole(structType string, roles map[string]string) string {
+	for i := 0; i < maxRoleMapChain; i++ {
+		mapped, ok := roles[structType]
+		if !ok || mapped == structType {
+			break
+		}
+		structType = mapped
+	}
+	return structType
+}
+
+// textEntry decodes a text string entry, following an indirect reference
+func (doc *PDFDocument) textEntry(value string) string {
+	if value == "" {
+		return ""
+	}
+	return utils.DecodeTextString(strings.TrimSpace(string(doc.resolveSource(value))))
+}
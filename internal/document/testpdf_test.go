@@ -0,0 +1,149 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testPDF writes a PDF file for the tests object by object, with the cross-reference sections
+// and trailers the test asks for
+type testPDF struct {
+	buf      bytes.Buffer
+	offsets  map[int]int64 // Offset of each object written directly, newest definition
+	size     int           // One past the highest object number written
+	lastXRef int64         // Offset of the newest xref section, -1 before the first
+}
+
+// newTestPDF starts a file with a header
+func newTestPDF() *testPDF {
+	p := &testPDF{offsets: make(map[int]int64), lastXRef: -1}
+	p.buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	return p
+}
+
+// object writes an indirect object with the given source
+func (p *testPDF) object(num int, source string) {
+	p.offsets[num] = int64(p.buf.Len())
+	p.size = max(p.size, num+1)
+	fmt.Fprintf(&p.buf, "%d 0 obj\n%s\nendobj\n", num, source)
+}
+
+// stream writes a stream object with the given dictionary entries and data
+func (p *testPDF) stream(num int, entries string, data []byte) {
+	p.object(num, fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", entries, len(data), data))
+}
+
+// page writes the objects 1 to 5 of a document with one page showing text
+func (p *testPDF) page(text string) {
+	p.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	p.object(2, "<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	p.object(3, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>")
+	p.object(4, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+	p.stream(5, "", []byte(fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)))
+}
+
+// xrefTable writes a classic xref table listing objects, each entry ended by eol, and a
+// trailer with the given extra entries, /Size, /Root and /Prev to the previous section
+func (p *testPDF) xrefTable(eol string, objects []int, trailer string) {
+	offset := int64(p.buf.Len())
+	fmt.Fprintf(&p.buf, "xref\n0 1\n0000000000 65535 f%s", eol)
+	sort.Ints(objects)
+	for _, num := range objects {
+		fmt.Fprintf(&p.buf, "%d 1\n%010d 00000 n%s", num, p.offsets[num], eol)
+	}
+	fmt.Fprintf(&p.buf, "trailer\n<< /Size %d /Root 1 0 R%s%s >>\n", p.size, p.prev(), trailer)
+	p.end(offset)
+}
+
+// objectStream writes an object stream holding the given objects, compressed with
+// FlateDecode, and returns where each object is in it
+func (p *testPDF) objectStream(num int, objects map[int]string) map[int][2]int {
+	var nums []int
+	for objNum := range objects {
+		nums = append(nums, objNum)
+	}
+	sort.Ints(nums)
+
+	var index, body strings.Builder
+	locations := make(map[int][2]int)
+	for i, objNum := range nums {
+		fmt.Fprintf(&index, "%d %d ", objNum, body.Len())
+		body.WriteString(objects[objNum] + "\n")
+		locations[objNum] = [2]int{num, i}
+	}
+	p.stream(num, fmt.Sprintf("/Type /ObjStm /N %d /First %d /Filter /FlateDecode", len(nums), index.Len()),
+		deflate([]byte(index.String()+body.String())))
+	return locations
+}
+
+// xrefStream writes an xref stream listing the objects written directly and those at the given
+// locations in object streams, with /Root and /Prev to the previous section
+func (p *testPDF) xrefStream(num int, compressed map[int][2]int) {
+	offset := int64(p.buf.Len())
+	p.offsets[num] = offset
+	p.size = max(p.size, num+1)
+	for objNum := range compressed {
+		p.size = max(p.size, objNum+1)
+	}
+
+	var data bytes.Buffer
+	for objNum := 0; objNum < p.size; objNum++ {
+		entry := make([]byte, 7)
+		if location, ok := compressed[objNum]; ok {
+			entry[0] = 2
+			binary.BigEndian.PutUint32(entry[1:5], uint32(location[0]))
+			binary.BigEndian.PutUint16(entry[5:], uint16(location[1]))
+		} else if objOffset, ok := p.offsets[objNum]; ok {
+			entry[0] = 1
+			binary.BigEndian.PutUint32(entry[1:5], uint32(objOffset))
+		} else {
+			binary.BigEndian.PutUint16(entry[5:], 65535)
+		}
+		data.Write(entry)
+	}
+	fmt.Fprintf(&p.buf, "%d 0 obj\n<< /Type /XRef /Size %d /W [1 4 2] /Root 1 0 R%s /Length %d >>\nstream\n",
+		num, p.size, p.prev(), data.Len())
+	p.buf.Write(data.Bytes())
+	p.buf.WriteString("\nendstream\nendobj\n")
+	p.end(offset)
+}
+
+// prev returns the /Prev entry pointing to the previous xref section, if any
+func (p *testPDF) prev() string {
+	if p.lastXRef < 0 {
+		return ""
+	}
+	return fmt.Sprintf(" /Prev %d", p.lastXRef)
+}
+
+// end ends a revision whose xref section starts at offset
+func (p *testPDF) end(offset int64) {
+	fmt.Fprintf(&p.buf, "startxref\n%d\n%%%%EOF\n", offset)
+	p.lastXRef = offset
+}
+
+// write writes the file to a temporary directory of the test and returns its name
+func (p *testPDF) write(t *testing.T) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "test.pdf")
+	if err := os.WriteFile(filename, p.buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+// deflate compresses data with zlib, as the FlateDecode filter expects
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
@@ -0,0 +1,43 @@
+package document
+
+// Tracer starts spans around the phases of a parse, so that their latency can be attributed
+// to a stage. It is implemented by adapters to a tracing library such as OpenTelemetry.
+type Tracer interface {
+	// StartSpan starts a span for a phase, such as "pdfex.parse.xref"
+	StartSpan(name string) Span
+}
+
+// Span is a phase of a parse being traced. Phases nested in it are started from the span.
+type Span interface {
+	Tracer
+	SetAttribute(key string, value interface{})
+	SetError(err error)
+	End()
+}
+
+// Span names of the parse phases
+const (
+	SpanParse         = "pdfex.parse"
+	SpanXRef          = "pdfex.parse.xref"
+	SpanObjects       = "pdfex.parse.objects"
+	SpanDecompression = "pdfex.parse.decompression"
+	SpanPages         = "pdfex.parse.pages"
+	SpanFonts         = "pdfex.parse.fonts"
+	SpanText          = "pdfex.parse.text"
+)
+
+// noopSpan is the span of a parse without a tracer
+type noopSpan struct{}
+
+func (noopSpan) StartSpan(name string) Span                 { return noopSpan{} }
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) SetError(err error)                         {}
+func (noopSpan) End()                                       {}
+
+// startSpan starts a span with the tracer, or a span that records nothing if there is none
+func startSpan(tracer Tracer, name string) Span {
+	if tracer == nil {
+		return noopSpan{}
+	}
+	return tracer.StartSpan(name)
+}
@@ -0,0 +1,340 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Severity grades a validation finding
+type Severity string
+
+// Finding severities
+const (
+	SeverityError   Severity = "error"   // The file breaks the specification; readers must guess or recover
+	SeverityWarning Severity = "warning" // The file deviates from the specification but reads unambiguously
+)
+
+// Finding is a structural problem found by Validate, or a PDF/A requirement CheckPDFA found unmet
+type Finding struct {
+	Severity Severity
+	Code     string // Stable identifier of the check, e.g. "broken-reference"
+	Object   int    // Object the finding is about, 0 if it concerns the file as a whole
+	Message  string
+}
+
+// Maximum number of bytes searched for the %PDF- header, as most readers allow
+const headerSearchSize = 1024
+
+// Matches a header with its version number
+var headerPattern = regexp.MustCompile(`^%PDF-\d\.\d`)
+
+// Matches the stream keyword after a stream dictionary, with its end-of-line marker
+var streamKeywordPattern = regexp.MustCompile(`>>\s*stream(\r\n|\n|\r)`)
+
+// validator collects the findings of one Validate run
+type validator struct {
+	doc      *PDFDocument
+	file     io.ReaderAt
+	size     int64
+	findings []Finding
+}
+
+// add records a finding
+func (v *validator) add(severity Severity, code string, objNum int, format string, args ...interface{}) {
+	v.findings = append(v.findings, Finding{Severity: severity, Code: code, Object: objNum, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks the structural conformance of the document against the file it was parsed
+// from, read from src, which holds size bytes: the header, the cross-reference table, indirect
+// references, stream lengths and delimiters and the page tree. Findings are ordered by check,
+// then by object number.
+func (doc *PDFDocument) Validate(src io.ReaderAt, size int64) ([]Finding, error) {
+	v := &validator{doc: doc, file: src, size: size}
+	v.checkHeader()
+	v.checkXRef()
+	v.checkReferences()
+	v.checkStreams()
+	v.checkPageTree()
+	return v.findings, nil
+}
+
+// checkHeader checks for a %PDF-x.y header at the start of the file
+func (v *validator) checkHeader() {
+	head := make([]byte, headerSearchSize)
+	n, err := v.file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		v.add(SeverityError, "header-missing", 0, "failed to read the header: %v", err)
+		return
+	}
+
+	idx := bytes.Index(head[:n], []byte("%PDF-"))
+	if idx < 0 {
+		v.add(SeverityError, "header-missing", 0, "no %%PDF- header in the first %d bytes", headerSearchSize)
+		return
+	}
+	if idx > 0 {
+		v.add(SeverityWarning, "header-offset", 0, "%%PDF- header at byte %d instead of 0", idx)
+	}
+	if !headerPattern.Match(head[idx:n]) {
+		v.add(SeverityError, "header-version", 0, "header has no valid version number")
+	}
+}
+
+// checkXRef reports the recovery the cross-reference table needed and compares it with a
+// scan of the file
+func (v *validator) checkXRef() {
+	report := v.doc.degradation
+	if report.Degraded() {
+		v.add(SeverityError, "xref-recovered", 0, "%s", strings.Join(report.Warnings(), "; "))
+	}
+
+	scanned := &PDFDocument{XRefTable: make(map[int]PDFXRefEntry)}
+	if err := rebuildXRefTable(v.file, scanned); err != nil {
+		v.add(SeverityWarning, "xref-unverified", 0, "failed to scan the file for objects: %v", err)
+		return
+	}
+
+	onlyInXRef, onlyInScan, mismatches := compareXRef(v.doc.XRefTable, scanned.XRefTable)
+	for _, objNum := range onlyInXRef {
+		v.add(SeverityError, "xref-missing-object", objNum, "xref entry points to offset %d, where no object %d was found",
+			v.doc.XRefTable[objNum].Offset, objNum)
+	}
+	for _, objNum := range mismatches {
+		v.add(SeverityError, "xref-offset", objNum, "xref offset %d differs from the offset %d found by scanning",
+			v.doc.XRefTable[objNum].Offset, scanned.XRefTable[objNum].Offset)
+	}
+	for _, objNum := range onlyInScan {
+		v.add(SeverityWarning, "xref-unlisted-object", objNum, "object at offset %d is not in the xref table",
+			scanned.XRefTable[objNum].Offset)
+	}
+}
+
+// checkReferences reports indirect references to objects the document doesn't contain
+func (v *validator) checkReferences() {
+	for _, objNum := range v.doc.sortedObjectNumbers() {
+		reported := make(map[int]bool)
+		for _, ref := range utils.FindReferences(objectSource(v.doc.Objects[objNum])) {
+			if _, ok := v.doc.Objects[ref]; ok || reported[ref] {
+				continue
+			}
+			reported[ref] = true
+			v.add(SeverityError, "broken-reference", objNum, "reference to missing object %d", ref)
+		}
+	}
+
+	for _, key := range []string{"Root", "Info", "Encrypt"} {
+		value, ok := v.doc.Trailer[key].(string)
+		if !ok {
+			continue
+		}
+		if ref, ok := referenceNumber(value); ok {
+			if _, ok := v.doc.Objects[ref]; !ok {
+				v.add(SeverityError, "broken-reference", 0, "trailer /%s refers to missing object %d", key, ref)
+			}
+		}
+	}
+}
+
+// checkStreams checks the stream and endstream keywords and the /Length of every object
+// with a file offset, reading each object up to the next one
+func (v *validator) checkStreams() {
+	spans := v.objectSpans()
+	for _, objNum := range v.doc.sortedObjectNumbers() {
+		span, ok := spans[objNum]
+		if !ok {
+			continue
+		}
+		data := make([]byte, span[1]-span[0])
+		if _, err := v.file.ReadAt(data, span[0]); err != nil && err != io.EOF {
+			v.add(SeverityWarning, "unreadable-object", objNum, "failed to read object at offset %d: %v", span[0], err)
+			continue
+		}
+		v.checkStream(objNum, data)
+	}
+}
+
+// objectSpans returns the byte range of each in-use xref entry, which ends where the next
+// object, the xref table or the file starts or ends
+func (v *validator) objectSpans() map[int][2]int64 {
+	offsets := []int64{v.size}
+	if v.doc.XRefOffset > 0 {
+		offsets = append(offsets, v.doc.XRefOffset)
+	}
+	for _, entry := range v.doc.XRefTable {
+		if entry.InUse {
+			offsets = append(offsets, entry.Offset)
+		}
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	spans := make(map[int][2]int64)
+	for objNum, entry := range v.doc.XRefTable {
+		if !entry.InUse || entry.Offset < 0 || entry.Offset >= v.size {
+			continue
+		}
+		idx := sort.Search(len(offsets), func(i int) bool { return offsets[i] > entry.Offset })
+		spans[objNum] = [2]int64{entry.Offset, offsets[idx]}
+	}
+	return spans
+}
+
+// checkStream checks the stream delimiters and length of one object's bytes
+func (v *validator) checkStream(objNum int, data []byte) {
+	if end := bytes.LastIndex(data, []byte("endobj")); end >= 0 {
+		data = data[:end]
+	} else {
+		v.add(SeverityWarning, "missing-endobj", objNum, "object has no endobj keyword")
+	}
+
+	start := streamKeywordPattern.FindIndex(data)
+	end := bytes.LastIndex(data, []byte("endstream"))
+	switch {
+	case start == nil && end < 0:
+		return
+	case start == nil:
+		v.add(SeverityError, "missing-stream", objNum, "endstream without a stream keyword")
+		return
+	case end < start[1]:
+		v.add(SeverityError, "missing-endstream", objNum, "stream has no endstream keyword")
+		return
+	}
+
+	obj := v.doc.Objects[objNum]
+	lengthValue, ok := obj.Dictionary["Length"]
+	if !ok {
+		v.add(SeverityError, "missing-length", objNum, "stream dictionary has no /Length")
+		return
+	}
+	length, ok := v.resolveLength(lengthValue)
+	if !ok {
+		v.add(SeverityError, "invalid-length", objNum, "/Length %v is not an integer", lengthValue)
+		return
+	}
+
+	// The end-of-line marker before endstream isn't part of the data
+	actual := data[start[1]:end]
+	if len(actual) != length {
+		actual = bytes.TrimSuffix(actual, []byte("\n"))
+		actual = bytes.TrimSuffix(actual, []byte("\r"))
+	}
+	if len(actual) != length {
+		v.add(SeverityWarning, "length-mismatch", objNum, "/Length is %d but the stream holds %d bytes", length, len(actual))
+	}
+}
+
+// resolveLength returns the value of a /Length entry, following an indirect reference
+func (v *validator) resolveLength(value interface{}) (int, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	parsed, err := utils.ParseValue([]byte(s))
+	if err != nil {
+		return 0, false
+	}
+	resolved, err := v.doc.Resolver().ResolveValue(parsed)
+	if err != nil {
+		return 0, false
+	}
+	length, ok := resolved.(utils.Number)
+	if !ok || length < 0 || float64(length) != float64(int(length)) {
+		return 0, false
+	}
+	return int(length), true
+}
+
+// checkPageTree walks the page tree from the catalog, checking node types, /Kids, /Count and
+// /Parent links
+func (v *validator) checkPageTree() {
+	catalog, ok := v.doc.Objects[v.doc.RootCatalog]
+	if !ok {
+		v.add(SeverityError, "missing-catalog", 0, "document has no catalog")
+		return
+	}
+	if utils.GetString(catalog.Dictionary["Type"], "") != "/Catalog" {
+		v.add(SeverityWarning, "catalog-type", v.doc.RootCatalog, "catalog /Type is not /Catalog")
+	}
+
+	pagesValue, _ := catalog.Dictionary["Pages"].(string)
+	root, ok := referenceNumber(pagesValue)
+	if !ok {
+		v.add(SeverityError, "page-tree-missing", v.doc.RootCatalog, "catalog has no /Pages reference")
+		return
+	}
+	if _, ok := v.doc.Objects[root]; !ok {
+		// Already reported as a broken reference
+		return
+	}
+
+	visited := make(map[int]bool)
+	v.checkPageNode(root, 0, visited)
+}
+
+// checkPageNode checks a page tree node and its descendants, returning the number of pages
+// below it
+func (v *validator) checkPageNode(objNum, parent int, visited map[int]bool) int {
+	if visited[objNum] {
+		v.add(SeverityError, "page-tree-cycle", objNum, "page tree node is reachable more than once")
+		return 0
+	}
+	visited[objNum] = true
+
+	obj, ok := v.doc.Objects[objNum]
+	if !ok {
+		return 0
+	}
+
+	parentValue, _ := obj.Dictionary["Parent"].(string)
+	parentRef, hasParent := referenceNumber(parentValue)
+	switch {
+	case parent == 0 && hasParent:
+		v.add(SeverityWarning, "page-tree-parent", objNum, "page tree root has a /Parent")
+	case parent != 0 && !hasParent:
+		v.add(SeverityError, "page-tree-parent", objNum, "page tree node has no /Parent")
+	case parent != 0 && parentRef != parent:
+		v.add(SeverityError, "page-tree-parent", objNum, "/Parent is %d but the node is a kid of %d", parentRef, parent)
+	}
+
+	nodeType := utils.GetString(obj.Dictionary["Type"], "")
+	switch nodeType {
+	case "/Page":
+		if parent == 0 {
+			v.add(SeverityError, "page-tree-type", objNum, "page tree root is a /Page, not /Pages")
+		}
+		return 1
+	case "/Pages":
+	default:
+		v.add(SeverityError, "page-tree-type", objNum, "page tree node has /Type %q, not /Pages or /Page", nodeType)
+		return 0
+	}
+
+	// The raw value keeps the array whole, unlike the parsed dictionary
+	kidsValue := utils.DictionaryValue(objectSource(obj), "Kids")
+	if kidsValue == "" {
+		v.add(SeverityError, "page-tree-kids", objNum, "page tree node has no /Kids")
+		return 0
+	}
+
+	pages := 0
+	for _, kid := range utils.FindReferences(v.doc.resolveSource(kidsValue)) {
+		// Missing kids are reported as broken references
+		pages += v.checkPageNode(kid, objNum, visited)
+	}
+
+	countValue, ok := obj.Dictionary["Count"]
+	if !ok {
+		v.add(SeverityError, "page-tree-count", objNum, "page tree node has no /Count")
+	} else if count, err := strconv.Atoi(fmt.Sprint(countValue)); err != nil {
+		v.add(SeverityError, "page-tree-count", objNum, "/Count %v is not an integer", countValue)
+	} else if count != pages {
+		v.add(SeverityError, "page-tree-count", objNum, "/Count is %d but the node has %d pages", count, pages)
+	}
+	return pages
+}
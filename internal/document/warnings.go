@@ -0,0 +1,68 @@
+package document
+
+import "fmt"
+
+// Stage is the phase of a parse in which a warning was found
+type Stage string
+
+// Parse stages
+const (
+	StageXRef          Stage = "xref"
+	StageObjects       Stage = "objects"
+	StageDecompression Stage = "decompression"
+	StagePages         Stage = "pages"
+)
+
+// Warning is a problem found while parsing that the parse recovered from
+type Warning struct {
+	Stage    Stage
+	Severity Severity // SeverityError when the document had to be recovered or content was lost
+	Object   int      // Object the warning is about, 0 if it concerns the file as a whole
+	Message  string
+}
+
+// String describes the warning on one line
+func (w Warning) String() string {
+	if w.Object != 0 {
+		return fmt.Sprintf("%s: object %d: %s", w.Stage, w.Object, w.Message)
+	}
+	return fmt.Sprintf("%s: %s", w.Stage, w.Message)
+}
+
+// Warnings returns the problems found while parsing the document, in the order they were found:
+// the recovery steps of the parse first, as errors, then the warnings of each stage
+func (doc *PDFDocument) Warnings() []Warning {
+	var warnings []Warning
+	for _, message := range doc.degradation.Warnings() {
+		warnings = append(warnings, Warning{Stage: StageXRef, Severity: SeverityError, Message: message})
+	}
+	return append(warnings, doc.warnings...)
+}
+
+// warnf logs a warning and records it on the document
+func (doc *PDFDocument) warnf(stage Stage, objNum int, format string, args ...interface{}) {
+	doc.record(SeverityWarning, stage, objNum, format, args...)
+}
+
+// errorf logs and records a problem the parse couldn't recover from, such as pages that can't
+// be found, as a warning of SeverityError
+func (doc *PDFDocument) errorf(stage Stage, objNum int, format string, args ...interface{}) {
+	doc.record(SeverityError, stage, objNum, format, args...)
+}
+
+// record logs a warning of the given severity and records it on the document
+func (doc *PDFDocument) record(severity Severity, stage Stage, objNum int, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	attrs := []interface{}{"stage", string(stage)}
+	if objNum != 0 {
+		attrs = append(attrs, "object", objNum)
+	}
+	if severity == SeverityError {
+		doc.Logger().Error(message, attrs...)
+	} else {
+		doc.Logger().Warn(message, attrs...)
+	}
+	warning := Warning{Stage: stage, Severity: severity, Object: objNum, Message: message}
+	doc.warnings = append(doc.warnings, warning)
+	doc.hooks().OnWarning(doc, warning)
+}
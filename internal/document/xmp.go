@@ -0,0 +1,237 @@
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Namespace of the RDF elements that structure an XMP packet
+const rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// XMPProperty is a property of an XMP metadata packet
+type XMPProperty struct {
+	Namespace string   // Namespace URI
+	Prefix    string   // Prefix declared for the namespace in the packet
+	Name      string   // Local name
+	Values    []string // A single value, or the items of an array; the x-default item of a language alternative comes first
+}
+
+// xmlNode is an element of a parsed XML document
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     strings.Builder
+}
+
+// InfoDictionary returns the entries of the document information dictionary with text strings
+// decoded, keyed by name without the slash
+func (doc *PDFDocument) InfoDictionary() map[string]string {
+	info := make(map[string]string)
+	ref, ok := doc.Trailer["Info"].(string)
+	if !ok {
+		return info
+	}
+
+	for key, value := range dictionaryEntries(doc.resolveSource(ref)) {
+		value = strings.TrimSpace(string(doc.resolveSource(value)))
+		switch {
+		case strings.HasPrefix(value, "/"):
+			info[key] = value[1:]
+		case strings.HasPrefix(value, "("), strings.HasPrefix(value, "<") && !strings.HasPrefix(value, "<<"):
+			info[key] = utils.DecodeTextString(value)
+		default:
+			info[key] = value
+		}
+	}
+	return info
+}
+
+// XMPPacket returns the XMP metadata stream referenced by the catalog's /Metadata entry, or nil
+// if the document has none
+func (doc *PDFDocument) XMPPacket() []byte {
+	catalog, ok := doc.GetRootObject()
+	if !ok {
+		return nil
+	}
+	objNum, ok := referenceNumber(dictionaryEntries(objectSource(catalog))["Metadata"])
+	if !ok {
+		return nil
+	}
+	obj, ok := doc.Objects[objNum]
+	if !ok || !obj.IsStream {
+		return nil
+	}
+	return obj.Stream
+}
+
+// ParseXMP reads the properties of an XMP packet from the rdf:Description elements of its RDF
+// body, in document order. Properties may be written as attributes or elements, and arrays as
+// rdf:Seq, rdf:Bag or rdf:Alt. Structured values, such as PDF/A extension schema descriptions,
+// are skipped.
+func ParseXMP(data []byte) ([]XMPProperty, error) {
+	root, err := parseXMLTree(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse XMP: %v", err)
+	}
+
+	prefixes := make(map[string]string)
+	collectPrefixes(root, prefixes)
+
+	var properties []XMPProperty
+	add := func(name xml.Name, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		properties = append(properties, XMPProperty{
+			Namespace: name.Space,
+			Prefix:    prefixes[name.Space],
+			Name:      name.Local,
+			Values:    values,
+		})
+	}
+
+	for _, rdf := range findElements(root, rdfNamespace, "RDF") {
+		for _, desc := range rdf.children {
+			if desc.name.Space != rdfNamespace || desc.name.Local != "Description" {
+				continue
+			}
+			for _, attr := range desc.attrs {
+				if isPropertyAttr(attr) {
+					add(attr.Name, []string{attr.Value})
+				}
+			}
+			for _, prop := range desc.children {
+				add(prop.name, xmpValues(prop))
+			}
+		}
+	}
+	return properties, nil
+}
+
+// xmpValues returns the values of a property element: its text, its rdf:resource, or the items
+// of an array. Structured values give none.
+func xmpValues(prop *xmlNode) []string {
+	for _, attr := range prop.attrs {
+		if attr.Name.Space == rdfNamespace && attr.Name.Local == "resource" {
+			return []string{attr.Value}
+		}
+	}
+	if len(prop.children) == 0 {
+		if text := strings.TrimSpace(prop.text.String()); text != "" {
+			return []string{text}
+		}
+		return nil
+	}
+
+	array := prop.children[0]
+	if len(prop.children) != 1 || array.name.Space != rdfNamespace {
+		return nil
+	}
+	switch array.name.Local {
+	case "Seq", "Bag", "Alt":
+	default:
+		return nil
+	}
+
+	var values []string
+	for _, item := range array.children {
+		if item.name.Space != rdfNamespace || item.name.Local != "li" || len(item.children) > 0 {
+			continue
+		}
+		text := strings.TrimSpace(item.text.String())
+		if text == "" {
+			continue
+		}
+		if array.name.Local == "Alt" && xmlLang(item) == "x-default" {
+			values = append([]string{text}, values...)
+		} else {
+			values = append(values, text)
+		}
+	}
+	return values
+}
+
+// parseXMLTree parses an XML document into a tree of elements under a synthetic root
+func parseXMLTree(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name, attrs: t.Attr}
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			stack[len(stack)-1].text.Write(t)
+		}
+	}
+	return root, nil
+}
+
+// collectPrefixes maps the namespace URIs declared in a tree to their prefixes
+func collectPrefixes(node *xmlNode, prefixes map[string]string) {
+	for _, attr := range node.attrs {
+		if attr.Name.Space == "xmlns" {
+			if _, ok := prefixes[attr.Value]; !ok {
+				prefixes[attr.Value] = attr.Name.Local
+			}
+		}
+	}
+	for _, child := range node.children {
+		collectPrefixes(child, prefixes)
+	}
+}
+
+// findElements returns the elements with a given name in a tree, not looking inside matches
+func findElements(node *xmlNode, space, local string) []*xmlNode {
+	if node.name.Space == space && node.name.Local == local {
+		return []*xmlNode{node}
+	}
+	var found []*xmlNode
+	for _, child := range node.children {
+		found = append(found, findElements(child, space, local)...)
+	}
+	return found
+}
+
+// isPropertyAttr reports whether an attribute of rdf:Description is a property, rather than a
+// namespace declaration or an RDF or XML attribute such as rdf:about
+func isPropertyAttr(attr xml.Attr) bool {
+	switch attr.Name.Space {
+	case "", "xmlns", rdfNamespace, "http://www.w3.org/XML/1998/namespace", "xml":
+		return false
+	}
+	return true
+}
+
+// xmlLang returns the xml:lang attribute of an element
+func xmlLang(node *xmlNode) string {
+	for _, attr := range node.attrs {
+		if attr.Name.Local == "lang" && (attr.Name.Space == "xml" || attr.Name.Space == "http://www.w3.org/XML/1998/namespace") {
+			return attr.Value
+		}
+	}
+	return ""
+}
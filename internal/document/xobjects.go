@@ -0,0 +1,117 @@
+package document
+
+import (
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// FormXObject is a form XObject: a self-contained content stream that pages and other forms
+// draw with the Do operator
+type FormXObject struct {
+	ObjectNumber int
+	Contents     []byte
+	Matrix       [6]float64              // Maps form space into the user space of the invoking content
+	BBox         [4]float64              // Bounding box in form space
+	XObjects     map[string]*FormXObject // Forms drawn by this form, by resource name
+	Hidden       bool                    // The form's /OC is hidden in the default configuration
+
+	// Names in the form's /Properties resources that refer to hidden optional content
+	HiddenContent map[string]bool
+}
+
+// processFormXObjects resolves the form XObjects that each selected page can draw, those the
+// forms draw in turn, and the appearance streams of the page's annotations. Forms are shared
+// between pages and registered before their own resources are read, so a form that draws
+// itself, directly or through others, refers back to the same FormXObject; the extractor guards
+// against such cycles. Nesting deeper than the recursion limit fails with a *content.LimitError.
+func processFormXObjects(doc *PDFDocument) error {
+	forms := make(map[int]*FormXObject)
+	visible := layerVisibility(doc.GetLayers())
+
+	for i := range doc.Pages {
+		page := &doc.Pages[i]
+		if !doc.PageSelected(page.PageNumber) {
+			continue
+		}
+		var err error
+		if page.XObjects, err = doc.formXObjects(doc.PageResources(*page, "XObject"), forms, visible, 1); err != nil {
+			return err
+		}
+		if page.Appearances, err = doc.annotationAppearances(*page, forms, visible); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formXObjects returns the forms among named XObjects drawn at a nesting depth, or nil if there
+// are none
+func (doc *PDFDocument) formXObjects(xobjects map[string]int, forms map[int]*FormXObject, visible map[int]bool, depth int) (map[string]*FormXObject, error) {
+	var result map[string]*FormXObject
+	for name, objNum := range xobjects {
+		form, err := doc.formXObject(objNum, forms, visible, depth)
+		if err != nil {
+			return nil, err
+		}
+		if form == nil {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]*FormXObject)
+		}
+		result[name] = form
+	}
+	return result, nil
+}
+
+// formXObject returns the form XObject with an object number, or nil if the object is not one.
+// Forms without /Resources of their own draw no nested forms. Depth counts from 1 for a form
+// drawn by a page.
+func (doc *PDFDocument) formXObject(objNum int, forms map[int]*FormXObject, visible map[int]bool, depth int) (*FormXObject, error) {
+	if form, ok := forms[objNum]; ok {
+		return form, nil
+	}
+	if err := doc.limits.checkDepth(depth); err != nil {
+		return nil, err
+	}
+	forms[objNum] = nil
+
+	obj, ok := doc.Objects[objNum]
+	if !ok || !obj.IsStream {
+		return nil, nil
+	}
+	entries := dictionaryEntries(objectSource(obj))
+	if entries["Subtype"] != "/Form" {
+		return nil, nil
+	}
+
+	form := &FormXObject{ObjectNumber: objNum, Contents: obj.Stream, Matrix: [6]float64{1, 0, 0, 1, 0, 0}}
+	forms[objNum] = form
+
+	if value := entries["Matrix"]; value != "" {
+		items := content.ParseArrayOperand(string(doc.resolveSource(value)))
+		if len(items) == 6 {
+			for i, item := range items {
+				v, err := utils.ParseFloat(item)
+				if err != nil {
+					doc.warnf(StagePages, objNum, "Invalid /Matrix in form XObject %d: %v", objNum, err)
+					form.Matrix = [6]float64{1, 0, 0, 1, 0, 0}
+					break
+				}
+				form.Matrix[i] = v
+			}
+		}
+	}
+	form.BBox, _ = parseRect(string(doc.resolveSource(entries["BBox"])))
+	if oc, ok := referenceNumber(entries["OC"]); ok {
+		form.Hidden = !doc.optionalContentVisible(oc, visible)
+	}
+
+	resources := entries["Resources"]
+	var err error
+	if form.XObjects, err = doc.formXObjects(doc.namedResources(resources, "XObject"), forms, visible, depth+1); err != nil {
+		return nil, err
+	}
+	form.HiddenContent = doc.hiddenProperties(doc.namedResources(resources, "Properties"), visible)
+	return form, nil
+}
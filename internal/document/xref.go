@@ -5,7 +5,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"os"
+	"log/slog"
 	"regexp"
 	"strconv"
 	"strings"
@@ -15,12 +15,12 @@ import (
 
 // Regular expressions for XRef table parsing
 var (
-	trailerDictRegex = regexp.MustCompile(`trailer\s+<<(.*?)>>`)
-	xrefEntryRegex   = regexp.MustCompile(`^(\d{10}) (\d{5}) ([nf])$`)
+	startxrefRegex = regexp.MustCompile(`startxref\s*(\d+)`)
+	objDefRegex    = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj`)
 )
 
 // findLastXRefOffset finds the offset of the last xref table
-func findLastXRefOffset(file *os.File, fileSize int64) (int64, error) {
+func findLastXRefOffset(file io.ReaderAt, fileSize int64) (int64, error) {
 	// Look for startxref at the end of the file
 	bufSize := int64(1024)
 	if fileSize < bufSize {
@@ -28,23 +28,18 @@ func findLastXRefOffset(file *os.File, fileSize int64) (int64, error) {
 	}
 
 	buffer := make([]byte, bufSize)
-	_, err := file.Seek(fileSize-bufSize, io.SeekStart)
-	if err != nil {
-		return 0, fmt.Errorf("failed to seek to end of file: %v", err)
-	}
-
-	n, err := file.Read(buffer)
-	if err != nil {
+	n, err := file.ReadAt(buffer, fileSize-bufSize)
+	if err != nil && err != io.EOF {
 		return 0, fmt.Errorf("failed to read from end of file: %v", err)
 	}
 
-	// Look for "startxref" followed by a number
-	startxrefPattern := regexp.MustCompile(`startxref\s*(\d+)`)
-	matches := startxrefPattern.FindSubmatch(buffer[:n])
-
-	if len(matches) < 2 {
+	// Look for "startxref" followed by a number; after incremental updates the last one
+	// points to the newest section
+	all := startxrefRegex.FindAllSubmatch(buffer[:n], -1)
+	if len(all) == 0 {
 		return 0, fmt.Errorf("startxref not found in last %d bytes", bufSize)
 	}
+	matches := all[len(all)-1]
 
 	offset, err := strconv.ParseInt(string(matches[1]), 10, 64)
 	if err != nil {
@@ -54,259 +49,404 @@ func findLastXRefOffset(file *os.File, fileSize int64) (int64, error) {
 	return offset, nil
 }
 
-// parseXRef parses the cross-reference table
-func parseXRef(file *os.File, offset int64, doc *PDFDocument) error {
-	utils.LogDebugf("Attempting to parse xref table at offset %d", offset)
+// xrefSection is one cross-reference section of a file, a classic table or an xref stream. The
+// xref stream of a hybrid file is merged into the table it complements.
+type xrefSection struct {
+	offset     int64                // Where the section starts in the file
+	entries    map[int]PDFXRefEntry // Objects stored directly in the file, and free entries with the next free object as Offset
+	compressed map[int][2]int       // Objects in object streams: stream object number and index
+	trailer    []byte               // Source of the trailer dictionary, or of the xref stream's dictionary
+}
 
-	_, err := file.Seek(offset, io.SeekStart)
-	if err != nil {
-		return fmt.Errorf("failed to seek to xref table: %v", err)
-	}
+// has reports whether the section defines an object, in use, free or compressed
+func (s *xrefSection) has(objNum int) bool {
+	_, direct := s.entries[objNum]
+	_, compressed := s.compressed[objNum]
+	return direct || compressed
+}
 
-	// Read a small buffer to check what's at this position
-	checkBuf := make([]byte, 20)
-	n, err := file.Read(checkBuf)
-	if err != nil {
-		return fmt.Errorf("failed to read at xref position: %v", err)
+// merge adds the entries of other that the section doesn't define itself
+func (s *xrefSection) merge(other xrefSection) {
+	for objNum, entry := range other.entries {
+		if !s.has(objNum) {
+			s.entries[objNum] = entry
+		}
 	}
+	for objNum, location := range other.compressed {
+		if !s.has(objNum) {
+			s.compressed[objNum] = location
+		}
+	}
+}
 
-	utils.LogDebugf("Content at xref offset: %q", string(checkBuf[:n]))
-
-	// Check for 'xref' at the beginning of the buffer
-	if !bytes.HasPrefix(checkBuf[:n], []byte("xref")) {
-		// Try to handle the case where there might be whitespace or line breaks
-		if n >= 5 {
-			xrefPos := bytes.Index(checkBuf[:n], []byte("xref"))
-			if xrefPos != -1 {
-				offset += int64(xrefPos)
-				_, err = file.Seek(offset, io.SeekStart)
-				if err != nil {
-					return fmt.Errorf("failed to seek to adjusted xref position: %v", err)
+// mergeXRefSections merges sections, newest first, into one table of the objects stored
+// directly in the file and free entries, and one of the objects in object streams. A newer
+// section's entry replaces an older one, even to free an object.
+func mergeXRefSections(sections []xrefSection) (map[int]PDFXRefEntry, map[int][2]int) {
+	entries := make(map[int]PDFXRefEntry)
+	compressed := make(map[int][2]int)
+	for _, section := range sections {
+		for objNum, entry := range section.entries {
+			if _, ok := compressed[objNum]; !ok {
+				if _, ok := entries[objNum]; !ok {
+					entries[objNum] = entry
+				}
+			}
+		}
+		for objNum, location := range section.compressed {
+			if _, ok := entries[objNum]; !ok {
+				if _, ok := compressed[objNum]; !ok {
+					compressed[objNum] = location
 				}
-				utils.LogDebugf("Found 'xref' at adjusted position %d", offset)
-			} else {
-				return fmt.Errorf("xref keyword not found at the specified offset")
 			}
-		} else {
-			return fmt.Errorf("insufficient data at xref position")
 		}
 	}
+	return entries, compressed
+}
 
-	// Reposition at the start of the xref table
-	_, err = file.Seek(offset, io.SeekStart)
-	if err != nil {
-		return fmt.Errorf("failed to seek back to xref table: %v", err)
-	}
-
-	scanner := bufio.NewScanner(file)
+// xrefReader reads the cross-reference sections of a file: classic tables and xref streams,
+// from the startxref offset back through /Prev and the /XRefStm of hybrid files. The full
+// parse, the quick info path and the xref audit all read them through it.
+type xrefReader struct {
+	file io.ReaderAt
+	size int64
+	warn func(format string, args ...interface{}) // Receives entries that can't be read; nil to drop them
+}
 
-	// Verify xref
-	if !scanner.Scan() {
-		return fmt.Errorf("failed to read first line of xref table")
+// warnf reports a problem that doesn't stop the section from being read
+func (r *xrefReader) warnf(format string, args ...interface{}) {
+	if r.warn != nil {
+		r.warn(format, args...)
 	}
+}
 
-	firstLine := scanner.Text()
-	utils.LogDebugf("First line of supposed xref table: %q", firstLine)
-
-	if !strings.HasPrefix(firstLine, "xref") {
-		return fmt.Errorf("invalid xref table: missing 'xref' keyword")
-	}
+// readSections reads the section at offset and the older ones reached through /Prev, newest
+// first. When an older section can't be read, the newer ones are returned with the error.
+func (r *xrefReader) readSections(offset int64) ([]xrefSection, error) {
+	var sections []xrefSection
+	visited := make(map[int64]bool)
+	for len(sections) < maxXRefSections && !visited[offset] {
+		visited[offset] = true
 
-	// Read subsections
-	for scanner.Scan() {
-		line := scanner.Text()
-		utils.LogDebugf("Processing xref line: %q", line)
+		section, err := r.readSection(offset)
+		if err != nil {
+			return sections, fmt.Errorf("failed to read xref at offset %d: %v", offset, err)
+		}
+		entries := dictionaryEntries(section.trailer)
+
+		// Hybrid files list their compressed objects in a separate xref stream, whose entries
+		// the table's own override
+		if stm, err := strconv.ParseInt(entries["XRefStm"], 10, 64); err == nil && !visited[stm] {
+			visited[stm] = true
+			if hybrid, err := r.readSection(stm); err == nil {
+				section.merge(hybrid)
+			} else {
+				r.warnf("Failed to read xref stream at offset %d: %v", stm, err)
+			}
+		}
+		sections = append(sections, section)
 
-		if strings.HasPrefix(line, "trailer") {
+		prev, err := strconv.ParseInt(entries["Prev"], 10, 64)
+		if err != nil {
 			break
 		}
+		offset = prev
+	}
+	return sections, nil
+}
 
-		// Parse subsection header
-		parts := strings.Fields(line)
-		if len(parts) == 2 {
-			startObj, err := strconv.Atoi(parts[0])
-			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid xref subsection start: %v\n", err)
-				continue
-			}
+// readSection reads the xref table or xref stream at an offset
+func (r *xrefReader) readSection(offset int64) (xrefSection, error) {
+	section := xrefSection{
+		offset:     offset,
+		entries:    make(map[int]PDFXRefEntry),
+		compressed: make(map[int][2]int),
+	}
+	if offset < 0 || offset >= r.size {
+		return section, fmt.Errorf("offset out of range")
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(r.file, offset, r.size-offset))
+	peek, _ := reader.Peek(32)
+	if bytes.HasPrefix(bytes.TrimLeft(peek, pdfWhitespace), []byte("xref")) {
+		return section, r.readTable(reader, &section)
+	}
+	return section, r.readStream(offset, &section)
+}
 
-			count, err := strconv.Atoi(parts[1])
+// readTable reads a classic xref table and the trailer that follows it. Entries should be 20
+// bytes ending in CRLF, space-LF or space-CR, but any whitespace and end-of-line marker is
+// accepted, as are subsection counts that don't match the entries that follow.
+func (r *xrefReader) readTable(reader *bufio.Reader, section *xrefSection) error {
+	objNum := 0
+	for {
+		line, err := readLine(reader)
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0 || fields[0] == "xref":
+		case strings.HasPrefix(fields[0], "trailer"):
+			// The trailer dictionary may start on the same line
+			rest := strings.TrimPrefix(strings.TrimSpace(line), "trailer")
+			section.trailer, err = readDictionary(reader, rest)
+			return err
+		case len(fields) == 2:
+			start, err := strconv.Atoi(fields[0])
 			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid xref subsection count: %v\n", err)
-				continue
+				return fmt.Errorf("invalid subsection header %q", strings.TrimSpace(line))
 			}
-
-			utils.LogDebugf("Processing xref subsection: start=%d, count=%d", startObj, count)
-
-			// Read entries
-			for i := 0; i < count && scanner.Scan(); i++ {
-				entry := scanner.Text()
-				matches := xrefEntryRegex.FindStringSubmatch(entry)
-
-				if len(matches) == 4 {
-					offset, err := strconv.ParseInt(matches[1], 10, 64)
-					if err != nil {
-						utils.Logf(utils.LogWarning, "Invalid xref entry offset: %v\n", err)
-						continue
-					}
-
-					gen, err := strconv.Atoi(matches[2])
-					if err != nil {
-						utils.Logf(utils.LogWarning, "Invalid xref entry generation: %v\n", err)
-						continue
-					}
-
-					inUse := matches[3] == "n"
-
-					objNum := startObj + i
-					doc.XRefTable[objNum] = PDFXRefEntry{
-						Offset:     offset,
-						Generation: gen,
-						InUse:      inUse,
-					}
-
-					utils.LogDebugf("Added xref entry for object %d: offset=%d, gen=%d, inUse=%v",
-						objNum, offset, gen, inUse)
-				} else {
-					utils.Logf(utils.LogWarning, "Invalid xref entry format: %s\n", entry)
-				}
+			objNum = start
+		case len(fields) == 3 && (fields[2] == "n" || fields[2] == "f"):
+			offset, offsetErr := strconv.ParseInt(fields[0], 10, 64)
+			gen, genErr := strconv.Atoi(fields[1])
+			if offsetErr != nil || genErr != nil {
+				r.warnf("Invalid xref entry for object %d: %q", objNum, strings.TrimSpace(line))
+			} else if _, ok := section.entries[objNum]; !ok {
+				section.entries[objNum] = PDFXRefEntry{Offset: offset, Generation: gen, InUse: fields[2] == "n"}
 			}
+			objNum++
+		default:
+			r.warnf("Invalid xref entry format: %q", strings.TrimSpace(line))
+		}
+		if err != nil {
+			return fmt.Errorf("no trailer after the xref table")
 		}
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error scanning xref table: %v", err)
+// readLine reads a line ended by LF, CR or CRLF, without its end-of-line marker
+func readLine(reader *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		c, err := reader.ReadByte()
+		if err != nil {
+			return string(line), err
+		}
+		switch c {
+		case '\r':
+			if next, err := reader.Peek(1); err == nil && next[0] == '\n' {
+				reader.ReadByte()
+			}
+			return string(line), nil
+		case '\n':
+			return string(line), nil
+		}
+		line = append(line, c)
 	}
-
-	utils.LogDebugf("Successfully parsed xref table with %d entries", len(doc.XRefTable))
-	return nil
 }
 
-// parseTrailer parses the trailer dictionary
-func parseTrailer(file *os.File, xrefOffset int64, doc *PDFDocument) error {
-	utils.LogDebugf("Parsing trailer starting from xref offset %d", xrefOffset)
-
-	_, err := file.Seek(xrefOffset, io.SeekStart)
+// readStream reads a cross-reference stream (PDF 1.5), whose dictionary serves as its trailer
+func (r *xrefReader) readStream(offset int64, section *xrefSection) error {
+	dict, data, err := readObjectSource(r.file, r.size, offset, true, nil)
 	if err != nil {
-		return fmt.Errorf("failed to seek to xref table for trailer: %v", err)
+		return err
 	}
+	entries := dictionaryEntries(dict)
+	if entries["Type"] != "/XRef" {
+		return fmt.Errorf("neither an xref table nor an xref stream")
+	}
+	section.trailer = dict
 
-	// Read until we find the trailer
-	scanner := bufio.NewScanner(file)
-	trailerData := ""
-	inTrailer := false
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if strings.HasPrefix(line, "trailer") {
-			inTrailer = true
-			utils.LogDebugf("Found trailer marker")
-			continue
+	var widths []int
+	for _, w := range strings.Fields(strings.Trim(entries["W"], "[]")) {
+		n, err := strconv.Atoi(w)
+		if err != nil || n < 0 || n > 8 {
+			return fmt.Errorf("invalid /W entry")
 		}
+		widths = append(widths, n)
+	}
+	if len(widths) != 3 {
+		return fmt.Errorf("invalid /W entry")
+	}
+	entrySize := widths[0] + widths[1] + widths[2]
 
-		if inTrailer {
-			trailerData += line + "\n"
-			if strings.Contains(line, "startxref") {
-				break
+	size, _ := strconv.Atoi(entries["Size"])
+	index := []int{0, size}
+	if value := entries["Index"]; value != "" {
+		index = index[:0]
+		for _, field := range strings.Fields(strings.Trim(value, "[]")) {
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return fmt.Errorf("invalid /Index entry")
 			}
+			index = append(index, n)
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error scanning trailer: %v", err)
-	}
+	pos := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		for objNum := index[i]; objNum < index[i]+index[i+1]; objNum++ {
+			if entrySize == 0 || pos+entrySize > len(data) {
+				r.warnf("Xref stream at offset %d ends before the entry of object %d", offset, objNum)
+				return nil
+			}
+			fields := make([]int64, 3)
+			for f, width := range widths {
+				for b := 0; b < width; b++ {
+					fields[f] = fields[f]<<8 | int64(data[pos])
+					pos++
+				}
+			}
+			// A missing type field means type 1
+			if widths[0] == 0 {
+				fields[0] = 1
+			}
 
-	if trailerData == "" {
-		return fmt.Errorf("no trailer data found")
+			if section.has(objNum) {
+				continue
+			}
+			switch fields[0] {
+			case 0:
+				section.entries[objNum] = PDFXRefEntry{Offset: fields[1], Generation: int(fields[2])}
+			case 1:
+				section.entries[objNum] = PDFXRefEntry{Offset: fields[1], Generation: int(fields[2]), InUse: true}
+			case 2:
+				section.compressed[objNum] = [2]int{int(fields[1]), int(fields[2])}
+			}
+		}
 	}
+	return nil
+}
 
-	utils.LogDebugf("Collected trailer data: %s", trailerData[:min(100, len(trailerData))])
-
-	// Extract trailer dictionary
-	matches := trailerDictRegex.FindStringSubmatch(trailerData)
+// Entries of an xref stream's dictionary that describe the stream rather than the trailer
+var xrefStreamKeys = map[string]bool{
+	"Type": true, "W": true, "Index": true, "Length": true, "Filter": true, "DecodeParms": true,
+}
 
-	if len(matches) > 1 {
-		dictBytes := []byte(matches[1])
-		err := utils.ParseDictionary(dictBytes, doc.Trailer)
-		if err != nil {
-			return fmt.Errorf("failed to parse trailer dictionary: %v", err)
+// mergeTrailer adds the entries of a trailer dictionary that the document's trailer lacks, so
+// that merging the sections newest first keeps the newest value of each entry
+func (doc *PDFDocument) mergeTrailer(source []byte) {
+	body, ok := utils.DictionaryBody(source)
+	if !ok {
+		return
+	}
+	entries := make(map[string]interface{})
+	if err := utils.ParseDictionary(body, entries); err != nil {
+		doc.warnf(StageXRef, 0, "Failed to parse trailer dictionary: %v", err)
+	}
+	isStream := dictionaryEntries(source)["Type"] == "/XRef"
+	for key, value := range entries {
+		if _, ok := doc.Trailer[key]; !ok && !(isStream && xrefStreamKeys[key]) {
+			doc.Trailer[key] = value
 		}
-		utils.LogDebugf("Successfully parsed trailer dictionary with %d entries", len(doc.Trailer))
-	} else {
-		return fmt.Errorf("trailer dictionary not found")
 	}
-
-	return nil
 }
 
-// parseXRefAndTrailer parses both the xref table and trailer
-func parseXRefAndTrailer(file *os.File, xrefOffset int64, doc *PDFDocument) error {
-	utils.LogDebugf("Starting xref and trailer parsing from offset %d", xrefOffset)
-
-	// Set log level to debug temporarily for this parse operation
-	origLogLevel := utils.GetLogLevel()
-	utils.SetLogLevel(utils.LogDebug)
-	defer utils.SetLogLevel(origLogLevel)
+// parseXRefAndTrailer reads the cross-reference sections from xrefOffset back through /Prev
+// into the xref table, newer entries replacing older ones, and merges their trailers. When no
+// section can be read at xrefOffset, one is looked for nearby. When that fails too, or the
+// sections give no /Root or no object in use, the table is rebuilt by scanning the file.
+func parseXRefAndTrailer(file io.ReaderAt, size, xrefOffset int64, doc *PDFDocument) error {
+	utils.Debugf(doc.Logger(), "Starting xref and trailer parsing from offset %d", xrefOffset)
 
-	// Try standard xref table parsing
-	err := parseXRef(file, xrefOffset, doc)
-	if err != nil {
-		utils.LogDebugf("Standard xref parsing failed: %v", err)
+	reader := &xrefReader{file: file, size: size, warn: func(format string, args ...interface{}) {
+		doc.warnf(StageXRef, 0, format, args...)
+	}}
+	sections, err := reader.readSections(xrefOffset)
+	if len(sections) == 0 {
+		utils.Debugf(doc.Logger(), "Standard xref parsing failed: %v", err)
 
 		// Try to recover by looking for xref in the vicinity
-		newOffset, found := findNearbyXref(file, xrefOffset)
-		if found {
-			utils.LogDebugf("Found xref marker at nearby offset %d, retrying", newOffset)
-			err = parseXRef(file, newOffset, doc)
-			if err != nil {
-				return fmt.Errorf("failed to parse xref table even at adjusted offset: %v", err)
-			}
-			xrefOffset = newOffset // Update offset for trailer parsing
-		} else {
-			// Try to rebuild xref table by scanning the file
-			utils.LogDebugf("Attempting to rebuild xref table by scanning file")
-			err = rebuildXRefTable(file, doc)
-			if err != nil {
-				return fmt.Errorf("failed to parse or rebuild xref table: %v", err)
+		if newOffset, found := findNearbyXref(file, size, xrefOffset, doc.Logger()); found {
+			utils.Debugf(doc.Logger(), "Found xref marker at nearby offset %d, retrying", newOffset)
+			if sections, err = reader.readSections(newOffset); len(sections) > 0 {
+				doc.recordRecovery(HeuristicNearbyXRef, "startxref offset %d was wrong; xref table found at offset %d", xrefOffset, newOffset)
+				doc.XRefOffset = newOffset
+				doc.degradation.Path = ParsePathAdjustedXRef
 			}
 		}
 	}
+	if len(sections) > 0 && err != nil {
+		doc.warnf(StageXRef, 0, "Older cross-reference sections ignored: %v", err)
+	}
 
-	// Parse the trailer dictionary
-	err = parseTrailer(file, xrefOffset, doc)
-	if err != nil {
-		utils.LogDebugf("Standard trailer parsing failed: %v", err)
+	doc.XRefTable, doc.compressed = mergeXRefSections(sections)
+	for _, section := range sections {
+		doc.mergeTrailer(section.trailer)
+	}
 
-		// Try to find trailer by scanning from the end
-		trailerOffset, found := findTrailerFromEnd(file)
-		if found {
-			utils.LogDebugf("Found trailer at offset %d, retrying", trailerOffset)
-			err = parseTrailer(file, trailerOffset, doc)
-			if err != nil {
-				return fmt.Errorf("failed to parse trailer even at adjusted offset: %v", err)
+	// Try to find the trailer by scanning from the end
+	if doc.Trailer["Root"] == nil {
+		if trailerOffset, found := findTrailerFromEnd(file, size, doc.Logger()); found {
+			utils.Debugf(doc.Logger(), "Found trailer at offset %d", trailerOffset)
+			if trailer, err := readTrailerAt(file, size, trailerOffset); err == nil {
+				doc.mergeTrailer(trailer)
+				doc.recordRecovery(HeuristicTrailerScan, "no trailer with a /Root after the xref table; trailer found at offset %d", trailerOffset)
 			}
-		} else {
-			return fmt.Errorf("failed to parse trailer dictionary: %v", err)
 		}
 	}
 
+	var reason string
+	switch {
+	case len(sections) == 0:
+		reason = fmt.Sprintf("no xref table at offset %d", xrefOffset)
+	case doc.Trailer["Root"] == nil:
+		reason = "no trailer with a /Root"
+	case countInUseXRefEntries(doc.XRefTable)+len(doc.compressed) == 0:
+		reason = "no object in use in the xref table"
+	default:
+		return nil
+	}
+	return rebuildForRecovery(file, doc, reason)
+}
+
+// rebuildForRecovery replaces the xref table with one rebuilt by scanning the file, recording
+// why it was needed
+func rebuildForRecovery(file io.ReaderAt, doc *PDFDocument, reason string) error {
+	utils.Debugf(doc.Logger(), "Attempting to rebuild xref table by scanning file: %s", reason)
+	doc.XRefTable = make(map[int]PDFXRefEntry)
+	doc.compressed = make(map[int][2]int)
+	if err := rebuildXRefTable(file, doc); err != nil {
+		return fmt.Errorf("failed to parse or rebuild xref table: %v", err)
+	}
+	doc.recordRecovery(HeuristicRebuildXRef, "%s; %d objects found by scanning the file", reason, len(doc.XRefTable))
+	doc.degradation.Path = ParsePathRebuild
 	return nil
 }
 
-// findNearbyXref searches for the "xref" keyword near the given offset
-func findNearbyXref(file *os.File, offset int64) (int64, bool) {
-	// Try within a reasonable range (1KB) before and after the offset
-	const searchRange = 1024
+// readTrailerAt reads the trailer dictionary after the trailer keyword at an offset
+func readTrailerAt(file io.ReaderAt, size, offset int64) ([]byte, error) {
+	if offset < 0 || offset >= size {
+		return nil, fmt.Errorf("offset out of range")
+	}
+	reader := bufio.NewReader(io.NewSectionReader(file, offset, size-offset))
+	line, err := readLine(reader)
+	if err != nil && line == "" {
+		return nil, err
+	}
+	return readDictionary(reader, strings.TrimPrefix(strings.TrimSpace(line), "trailer"))
+}
 
-	// Get file size
-	fileInfo, err := file.Stat()
-	if err != nil {
-		utils.LogDebugf("Failed to get file info: %v", err)
-		return 0, false
+// catalogReadable reports whether the catalog's xref entry leads to it: an object with its
+// number at the listed offset, or an entry in an object stream that does
+func (doc *PDFDocument) catalogReadable(file io.ReaderAt) bool {
+	objNum := doc.RootCatalog
+	if location, ok := doc.compressed[objNum]; ok {
+		objNum = location[0]
 	}
+	entry, ok := doc.XRefTable[objNum]
+	if !ok || !entry.InUse {
+		return false
+	}
+	_, err := doc.readObjectAt(file, entry.Offset, objNum, entry.Generation, nil)
+	return err == nil
+}
 
-	fileSize := fileInfo.Size()
+// findCatalog falls back on the first loaded object of /Type /Catalog when the trailer's /Root
+// couldn't be loaded
+func (doc *PDFDocument) findCatalog() {
+	for _, objNum := range doc.sortedObjectNumbers() {
+		if objType, _ := doc.Objects[objNum].Dict().Name("Type"); objType == "Catalog" {
+			doc.recordRecovery(HeuristicCatalogScan, "catalog %d not loaded; object %d is of /Type /Catalog", doc.RootCatalog, objNum)
+			doc.RootCatalog = objNum
+			return
+		}
+	}
+}
+
+// findNearbyXref searches for the "xref" keyword near the given offset, skipping startxref
+func findNearbyXref(file io.ReaderAt, size, offset int64, logger *slog.Logger) (int64, bool) {
+	// Try within a reasonable range (1KB) before and after the offset
+	const searchRange = 1024
 
 	// Set start offset, ensuring we don't go below 0
 	startOffset := offset - searchRange
@@ -316,159 +456,152 @@ func findNearbyXref(file *os.File, offset int64) (int64, bool) {
 
 	// Set end offset, ensuring we don't go beyond file size
 	endOffset := offset + searchRange
-	if endOffset > fileSize {
-		endOffset = fileSize
+	if endOffset > size {
+		endOffset = size
 	}
-
-	// Allocate buffer for the search range
-	bufSize := endOffset - startOffset
-	buffer := make([]byte, bufSize)
-
-	// Seek to start offset
-	_, err = file.Seek(startOffset, io.SeekStart)
-	if err != nil {
-		utils.LogDebugf("Failed to seek to search start: %v", err)
+	if endOffset <= startOffset {
 		return 0, false
 	}
 
 	// Read the search range
-	_, err = io.ReadFull(file, buffer)
-	if err != nil {
-		utils.LogDebugf("Failed to read search range: %v", err)
+	buffer := make([]byte, endOffset-startOffset)
+	n, err := file.ReadAt(buffer, startOffset)
+	if err != nil && err != io.EOF {
+		utils.Debugf(logger, "Failed to read search range: %v", err)
 		return 0, false
 	}
+	buffer = buffer[:n]
 
 	// Look for "xref" in the buffer
-	xrefIndex := bytes.Index(buffer, []byte("xref"))
-	if xrefIndex != -1 {
-		// Found "xref" at this offset within the buffer
-		foundOffset := startOffset + int64(xrefIndex)
-		utils.LogDebugf("Found 'xref' at offset %d", foundOffset)
-		return foundOffset, true
+	for from := 0; ; {
+		xrefIndex := bytes.Index(buffer[from:], []byte("xref"))
+		if xrefIndex == -1 {
+			return 0, false
+		}
+		xrefIndex += from
+		if !bytes.HasSuffix(buffer[:xrefIndex], []byte("start")) {
+			// Found "xref" at this offset within the buffer
+			foundOffset := startOffset + int64(xrefIndex)
+			utils.Debugf(logger, "Found 'xref' at offset %d", foundOffset)
+			return foundOffset, true
+		}
+		from = xrefIndex + len("xref")
 	}
-
-	return 0, false
 }
 
 // findTrailerFromEnd scans backward from the end of the file to find the trailer
-func findTrailerFromEnd(file *os.File) (int64, bool) {
-	// Get file size
-	fileInfo, err := file.Stat()
-	if err != nil {
-		utils.LogDebugf("Failed to get file info: %v", err)
-		return 0, false
-	}
-
-	fileSize := fileInfo.Size()
-
+func findTrailerFromEnd(file io.ReaderAt, size int64, logger *slog.Logger) (int64, bool) {
 	// Use a reasonable buffer size for scanning from the end
 	const bufSize = 4096
 	buffer := make([]byte, bufSize)
 
 	// Start from the end of the file and work backward
-	for offset := fileSize - bufSize; offset >= 0; offset -= bufSize / 2 {
-		// Adjust buffer size for the last chunk
-		readSize := bufSize
-		if offset+bufSize > fileSize {
-			readSize = int(fileSize - offset)
-		}
-
-		// Seek to the current position
-		_, err = file.Seek(offset, io.SeekStart)
-		if err != nil {
-			utils.LogDebugf("Failed to seek during trailer search: %v", err)
-			return 0, false
-		}
+	for offset := size - bufSize; offset > -bufSize/2; offset -= bufSize / 2 {
+		start := max(offset, 0)
 
 		// Read the chunk
-		n, err := file.Read(buffer[:readSize])
+		n, err := file.ReadAt(buffer[:min(bufSize, int(size-start))], start)
 		if err != nil && err != io.EOF {
-			utils.LogDebugf("Failed to read during trailer search: %v", err)
+			utils.Debugf(logger, "Failed to read during trailer search: %v", err)
 			return 0, false
 		}
 
-		// Look for "trailer" in this chunk
-		trailerIndex := bytes.Index(buffer[:n], []byte("trailer"))
+		// Look for the last "trailer" in this chunk
+		trailerIndex := bytes.LastIndex(buffer[:n], []byte("trailer"))
 		if trailerIndex != -1 {
 			// Found "trailer" at this offset within the buffer
-			foundOffset := offset + int64(trailerIndex)
-			utils.LogDebugf("Found 'trailer' at offset %d", foundOffset)
+			foundOffset := start + int64(trailerIndex)
+			utils.Debugf(logger, "Found 'trailer' at offset %d", foundOffset)
 			return foundOffset, true
 		}
+		if start == 0 {
+			break
+		}
 	}
 
 	return 0, false
 }
 
 // rebuildXRefTable attempts to rebuild the xref table by scanning the file for objects
-func rebuildXRefTable(file *os.File, doc *PDFDocument) error {
-	utils.LogDebugf("Rebuilding xref table by scanning file")
-
-	// Seek to beginning of file
-	_, err := file.Seek(0, io.SeekStart)
+func rebuildXRefTable(file io.ReaderAt, doc *PDFDocument) error {
+	utils.Debugf(doc.Logger(), "Rebuilding xref table by scanning file")
+
+	err := scanObjectHeaders(file, func(objNum, gen int, offset int64) {
+		// Add to xref table; a later definition replaces an earlier one, as in an update
+		doc.XRefTable[objNum] = PDFXRefEntry{
+			Offset:     offset,
+			Generation: gen,
+			InUse:      true,
+		}
+		utils.Debugf(doc.Logger(), "Rebuilt xref: Object %d gen %d at offset %d", objNum, gen, offset)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to seek to beginning of file: %v", err)
+		return fmt.Errorf("error scanning file during xref rebuilding: %v", err)
 	}
 
-	// Pattern to match object definitions: "# # obj"
-	objPattern := regexp.MustCompile(`(\d+)\s+(\d+)\s+obj`)
-
-	// Create scanner with a large buffer to handle long lines
-	scanner := bufio.NewScanner(file)
-	// Allocate a buffer of 10MB
-	const maxScanBufferSize = 10 * 1024 * 1024
-	scanBuf := make([]byte, maxScanBufferSize)
-	scanner.Buffer(scanBuf, maxScanBufferSize)
-
-	fileOffset := int64(0)
-	lineCount := 0
+	utils.Debugf(doc.Logger(), "Rebuilt xref table with %d entries", len(doc.XRefTable))
+	return nil
+}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		lineCount++
+// scanObjectHeaders calls fn with the number, generation and offset of each object header
+// ("12 0 obj") in the file, in file order
+func scanObjectHeaders(file io.ReaderAt, fn func(objNum, gen int, offset int64)) error {
+	return scanBlocks(file, func(data []byte, offset int64, before byte) {
+		for _, match := range objDefRegex.FindAllSubmatchIndex(data, -1) {
+			if match[0] >= objectReadBlock {
+				break // Found again at the start of the next block
+			}
 
-		// Look for object definitions
-		matches := objPattern.FindStringSubmatch(line)
-		if len(matches) == 3 {
-			objNum, err := strconv.Atoi(matches[1])
-			if err != nil {
-				utils.LogDebugf("Invalid object number at line %d: %v", lineCount, err)
+			// The header must not continue a number, and obj must end a token
+			prev := before
+			if match[0] > 0 {
+				prev = data[match[0]-1]
+			}
+			if prev >= '0' && prev <= '9' || match[1] < len(data) && !isTokenEnd(data[match[1]]) {
 				continue
 			}
 
-			gen, err := strconv.Atoi(matches[2])
+			objNum, err := strconv.Atoi(string(data[match[2]:match[3]]))
 			if err != nil {
-				utils.LogDebugf("Invalid generation number at line %d: %v", lineCount, err)
 				continue
 			}
-
-			// Calculate object offset (account for bytes we've already read)
-			objOffset := fileOffset - int64(len(line)) - 1 // -1 for the newline
-			headerIdx := strings.Index(line, matches[0])
-			if headerIdx >= 0 {
-				objOffset += int64(headerIdx)
-			}
-
-			// Add to xref table
-			doc.XRefTable[objNum] = PDFXRefEntry{
-				Offset:     objOffset,
-				Generation: gen,
-				InUse:      true,
+			gen, err := strconv.Atoi(string(data[match[4]:match[5]]))
+			if err != nil {
+				continue
 			}
+			fn(objNum, gen, offset+int64(match[0]))
+		}
+	})
+}
 
-			utils.LogDebugf("Rebuilt xref: Object %d gen %d at offset %d", objNum, gen, objOffset)
+// scanBlocks reads the whole file in blocks on byte offsets, so that offsets stay exact
+// whatever the end-of-line markers and however long the lines of binary data. Each block
+// passed to fn runs rebuildOverlap bytes into the next, so that a match starting in the
+// first objectReadBlock bytes is whole; before is the byte preceding the block.
+func scanBlocks(file io.ReaderAt, fn func(data []byte, offset int64, before byte)) error {
+	block := make([]byte, objectReadBlock+rebuildOverlap)
+	before := byte('\n')
+	for offset := int64(0); ; offset += objectReadBlock {
+		n, err := file.ReadAt(block, offset)
+		if err != nil && err != io.EOF {
+			return err
 		}
+		fn(block[:n], offset, before)
 
-		// Update file offset
-		fileOffset += int64(len(scanner.Bytes()) + 1) // +1 for the newline
+		if n < len(block) {
+			return nil
+		}
+		before = block[objectReadBlock-1]
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error scanning file during xref rebuilding: %v", err)
-	}
+// Bytes by which the blocks scanned for object headers and end-of-file markers overlap,
+// more than any of them takes
+const rebuildOverlap = 64
 
-	utils.LogDebugf("Rebuilt xref table with %d entries", len(doc.XRefTable))
-	return nil
+// isTokenEnd reports whether a byte ends the token before it: whitespace or a delimiter
+func isTokenEnd(c byte) bool {
+	return strings.IndexByte(" \t\r\n\f\x00()<>[]{}/%", c) != -1
 }
 
 // min returns the smaller of two integers
@@ -489,7 +622,7 @@ func (doc *PDFDocument) findXRefEntries(objNum int) ([]PDFXRefEntry, bool) {
 }
 
 // getObjectFromXRef gets an object using the xref table
-func (doc *PDFDocument) getObjectFromXRef(objNum int, file *os.File) (PDFObject, error) {
+func (doc *PDFDocument) getObjectFromXRef(objNum int, file io.ReaderAt) (PDFObject, error) {
 	entries, ok := doc.findXRefEntries(objNum)
 	if !ok || len(entries) == 0 {
 		return PDFObject{}, fmt.Errorf("object %d not found in xref table", objNum)
@@ -500,120 +633,21 @@ func (doc *PDFDocument) getObjectFromXRef(objNum int, file *os.File) (PDFObject,
 		return PDFObject{}, fmt.Errorf("object %d is marked as free", objNum)
 	}
 
-	// Seek to the object
-	_, err := file.Seek(entry.Offset, io.SeekStart)
-	if err != nil {
-		return PDFObject{}, fmt.Errorf("failed to seek to object %d: %v", objNum, err)
-	}
-
-	// Read the object header
-	objHeader := make([]byte, 50) // Should be enough for the header
-	n, err := file.Read(objHeader)
-	if err != nil {
-		return PDFObject{}, fmt.Errorf("failed to read object %d header: %v", objNum, err)
-	}
-
-	// Check object header format
-	objHeaderPattern := regexp.MustCompile(`(\d+) (\d+) obj`)
-	headerMatches := objHeaderPattern.FindSubmatch(objHeader[:n])
-
-	if len(headerMatches) < 3 {
-		return PDFObject{}, fmt.Errorf("invalid object %d header format", objNum)
-	}
-
-	matchedObjNum, err := strconv.Atoi(string(headerMatches[1]))
-	if err != nil {
-		return PDFObject{}, fmt.Errorf("invalid object number: %v", err)
-	}
-
-	generation, err := strconv.Atoi(string(headerMatches[2]))
-	if err != nil {
-		return PDFObject{}, fmt.Errorf("invalid generation number: %v", err)
-	}
-
-	if matchedObjNum != objNum || generation != entry.Generation {
-		return PDFObject{}, fmt.Errorf("object number mismatch: expected %d gen %d, got %d gen %d",
-			objNum, entry.Generation, matchedObjNum, generation)
-	}
-
-	// Read the object content
-	var contentBuffer bytes.Buffer
+	return doc.readObjectAt(file, entry.Offset, objNum, entry.Generation, file)
+}
 
-	_, err = file.Seek(entry.Offset, io.SeekStart)
+// RawStream reads the stream data of an object from src, the data the document was parsed
+// from, as stored before any filter is applied. It is delimited by /Length when that matches
+// the data.
+func (doc *PDFDocument) RawStream(src io.ReaderAt, objNum int) ([]byte, error) {
+	obj, err := doc.getObjectFromXRef(objNum, src)
 	if err != nil {
-		return PDFObject{}, fmt.Errorf("failed to seek to object %d: %v", objNum, err)
+		return nil, err
 	}
-
-	inObject := false
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if !inObject {
-			if strings.Contains(line, " obj") {
-				inObject = true
-				// Add everything after "obj"
-				parts := strings.SplitN(line, "obj", 2)
-				if len(parts) > 1 {
-					contentBuffer.WriteString(parts[1])
-				}
-			}
-		} else {
-			contentBuffer.WriteString(line)
-			contentBuffer.WriteString("\n")
-
-			if strings.Contains(line, "endobj") {
-				break
-			}
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return PDFObject{}, fmt.Errorf("error scanning object %d: %v", objNum, err)
-	}
-
-	content := contentBuffer.Bytes()
-
-	// Trim "endobj" from the end
-	if idx := bytes.Index(content, []byte("endobj")); idx != -1 {
-		content = content[:idx]
+	if !obj.IsStream {
+		return nil, fmt.Errorf("object %d is not a stream", objNum)
 	}
-
-	obj := PDFObject{
-		ObjectNumber: objNum,
-		Generation:   generation,
-		Content:      content,
-		Dictionary:   make(map[string]interface{}),
-	}
-
-	// Parse dictionary
-	if bytes.HasPrefix(content, []byte("<<")) && bytes.Contains(content, []byte(">>")) {
-		dictEnd := bytes.Index(content, []byte(">>"))
-		dictBytes := content[2:dictEnd]
-		err := utils.ParseDictionary(dictBytes, obj.Dictionary)
-		if err != nil {
-			utils.Logf(utils.LogWarning, "Error parsing dictionary for object %d: %v\n", objNum, err)
-		}
-	}
-
-	// Check for stream
-	if bytes.Contains(content, []byte("stream")) && bytes.Contains(content, []byte("endstream")) {
-		streamStart := bytes.Index(content, []byte("stream"))
-		streamStart += 6 // length of "stream"
-		if content[streamStart] == '\r' && content[streamStart+1] == '\n' {
-			streamStart += 2
-		} else if content[streamStart] == '\n' {
-			streamStart += 1
-		}
-
-		streamEnd := bytes.Index(content, []byte("endstream"))
-		if streamStart < streamEnd {
-			obj.Stream = content[streamStart:streamEnd]
-			obj.IsStream = true
-		}
-	}
-
-	return obj, nil
+	return obj.Stream, nil
 }
 
 // GetTrailerEntry gets an entry from the trailer dictionary
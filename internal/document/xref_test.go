@@ -0,0 +1,135 @@
+package document
+
+import (
+	"strings"
+	"testing"
+)
+
+// parseTestPDF parses the file a test built and returns the document
+func parseTestPDF(t *testing.T, p *testPDF) *PDFDocument {
+	t.Helper()
+	doc, err := ParsePDF(p.write(t))
+	if err != nil {
+		t.Fatalf("ParsePDF: %v", err)
+	}
+	return doc
+}
+
+// checkText fails the test unless the document has one page whose text contains want
+func checkText(t *testing.T, doc *PDFDocument, want string) {
+	t.Helper()
+	if len(doc.Pages) != 1 {
+		t.Fatalf("got %d pages, want 1", len(doc.Pages))
+	}
+	if text := doc.Pages[0].Text; !strings.Contains(text, want) {
+		t.Errorf("page text %q doesn't contain %q", text, want)
+	}
+}
+
+func TestXRefTableEndOfLine(t *testing.T) {
+	tests := []struct {
+		name string
+		eol  string
+	}{
+		{"CRLF", "\r\n"},
+		{"space LF", " \n"},
+		{"space CR", " \r"},
+		{"bare LF", "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPDF()
+			p.page("Hello")
+			p.xrefTable(tt.eol, []int{1, 2, 3, 4, 5}, "")
+
+			doc := parseTestPDF(t, p)
+			if doc.degradation.Path != ParsePathXRef {
+				t.Errorf("parse path %s, want %s", doc.degradation.Path, ParsePathXRef)
+			}
+			if got := countInUseXRefEntries(doc.XRefTable); got != 5 {
+				t.Errorf("got %d objects in use in the xref table, want 5", got)
+			}
+			checkText(t, doc, "Hello")
+		})
+	}
+}
+
+func TestXRefPrevChain(t *testing.T) {
+	p := newTestPDF()
+	p.page("Hello")
+	p.xrefTable("\r\n", []int{1, 2, 3, 4, 5}, " /Info 6 0 R")
+
+	// The update replaces the content stream and adds an information dictionary
+	p.stream(5, "", []byte("BT /F1 12 Tf 72 720 Td (World) Tj ET"))
+	p.object(6, "<< /Title (Updated) >>")
+	p.xrefTable(" \n", []int{5, 6}, "")
+
+	doc := parseTestPDF(t, p)
+	if doc.degradation.Path != ParsePathXRef {
+		t.Errorf("parse path %s, want %s", doc.degradation.Path, ParsePathXRef)
+	}
+	if got := countInUseXRefEntries(doc.XRefTable); got != 6 {
+		t.Errorf("got %d objects in use in the xref table, want 6", got)
+	}
+	if _, ok := doc.Trailer["Info"]; !ok {
+		t.Error("trailer entry /Info of the older section is missing")
+	}
+	checkText(t, doc, "World")
+}
+
+func TestXRefStream(t *testing.T) {
+	p := newTestPDF()
+	p.stream(5, "", []byte("BT /F1 12 Tf 72 720 Td (Compressed) Tj ET"))
+	compressed := p.objectStream(6, map[int]string{
+		1: "<< /Type /Catalog /Pages 2 0 R >>",
+		2: "<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		3: "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		4: "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	})
+	p.xrefStream(7, compressed)
+
+	doc := parseTestPDF(t, p)
+	if doc.degradation.Path != ParsePathXRef {
+		t.Errorf("parse path %s, want %s", doc.degradation.Path, ParsePathXRef)
+	}
+	if got := len(doc.compressed); got != 4 {
+		t.Errorf("got %d objects in object streams, want 4", got)
+	}
+	if _, ok := doc.Trailer["W"]; ok {
+		t.Error("xref stream entry /W copied to the trailer")
+	}
+	checkText(t, doc, "Compressed")
+}
+
+func TestXRefRebuild(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(p *testPDF)
+	}{
+		{"wrong startxref", func(p *testPDF) {
+			p.buf.WriteString("startxref\n3\n%%EOF\n")
+		}},
+		{"offsets all wrong", func(p *testPDF) {
+			for num := range p.offsets {
+				p.offsets[num] += 7
+			}
+			p.xrefTable("\r\n", []int{1, 2, 3, 4, 5}, "")
+		}},
+		{"no objects in use", func(p *testPDF) {
+			p.xrefTable("\r\n", nil, "")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPDF()
+			p.page("Hello")
+			tt.build(p)
+
+			doc := parseTestPDF(t, p)
+			if doc.degradation.Path != ParsePathRebuild {
+				t.Errorf("parse path %s, want %s", doc.degradation.Path, ParsePathRebuild)
+			}
+			checkText(t, doc, "Hello")
+		})
+	}
+}
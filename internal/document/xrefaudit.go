@@ -0,0 +1,222 @@
+package document
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// AuditXRef compares the cross-reference sections of the file the document was parsed from
+// with the objects found by scanning it. It reports object definitions no section lists,
+// objects not connected to the trailer's /Root, /Info or /Encrypt, object numbers defined more
+// than once across revisions, and errors in the list of free entries. It complements the
+// xref checks of Validate, which compare the table in use with the objects it points to. The
+// file is read from src, which holds size bytes.
+func (doc *PDFDocument) AuditXRef(src io.ReaderAt, size int64) ([]Finding, error) {
+	v := &validator{doc: doc, file: src, size: size}
+	sections, err := readXRefSections(src, size, func(format string, args ...interface{}) {
+		v.add(SeverityWarning, "xref-unverified", 0, format, args...)
+	})
+	if err != nil {
+		v.add(SeverityWarning, "xref-unverified", 0, "failed to read the cross-reference sections: %v", err)
+	} else {
+		v.checkOrphans(sections)
+		v.checkFreeList(sections)
+	}
+	v.checkShadowed()
+	v.checkReachable()
+	return v.findings, nil
+}
+
+// readXRefSections reads the cross-reference sections from the startxref offset back through
+// /Prev, newest first, as the parser does
+func readXRefSections(file io.ReaderAt, size int64, warn func(format string, args ...interface{})) ([]xrefSection, error) {
+	offset, err := findLastXRefOffset(file, size)
+	if err != nil {
+		return nil, err
+	}
+	reader := &xrefReader{file: file, size: size, warn: warn}
+	return reader.readSections(offset)
+}
+
+// checkOrphans reports definitions of listed objects that no section points to, such as a copy
+// left behind by an editor that rewrote the object without an incremental update. Objects
+// whose number no section lists are reported by Validate as unlisted.
+func (v *validator) checkOrphans(sections []xrefSection) {
+	listed := make(map[int64]bool)
+	numbers := make(map[int]bool)
+	for _, section := range sections {
+		for objNum, entry := range section.entries {
+			if entry.InUse {
+				listed[entry.Offset] = true
+				numbers[objNum] = true
+			}
+		}
+		for objNum := range section.compressed {
+			numbers[objNum] = true
+		}
+	}
+
+	var orphans [][2]int64
+	err := scanObjectHeaders(v.file, func(objNum, gen int, offset int64) {
+		if numbers[objNum] && !listed[offset] {
+			orphans = append(orphans, [2]int64{int64(objNum), offset})
+		}
+	})
+	if err != nil {
+		v.add(SeverityWarning, "xref-unverified", 0, "failed to scan the file for objects: %v", err)
+		return
+	}
+	for _, orphan := range orphans {
+		v.add(SeverityWarning, "orphaned-object", int(orphan[0]), "definition at offset %d is not listed in any cross-reference section", orphan[1])
+	}
+}
+
+// checkFreeList follows the linked list of free entries from object 0 in the merged sections,
+// where a newer section's entry replaces an older one. Each free entry holds the number of the
+// next free object, the last one 0.
+func (v *validator) checkFreeList(sections []xrefSection) {
+	entries, compressed := mergeXRefSections(sections)
+	free := make(map[int]PDFXRefEntry)
+	inUse := make(map[int]bool)
+	for objNum, entry := range entries {
+		if entry.InUse {
+			inUse[objNum] = true
+		} else {
+			free[objNum] = entry
+		}
+	}
+	for objNum := range compressed {
+		inUse[objNum] = true
+	}
+
+	head, ok := free[0]
+	switch {
+	case inUse[0]:
+		v.add(SeverityError, "free-list", 0, "object 0 is in use instead of heading the free list")
+		return
+	case !ok:
+		v.add(SeverityError, "free-list", 0, "no free entry for object 0 to head the free list")
+		return
+	case head.Generation != 65535:
+		v.add(SeverityWarning, "free-list", 0, "free entry 0 has generation %d instead of 65535", head.Generation)
+	}
+
+	linked := map[int]bool{0: true}
+	for objNum := 0; ; {
+		next := int(free[objNum].Offset)
+		if next == 0 {
+			break
+		}
+		if _, ok := free[next]; !ok {
+			if inUse[next] {
+				v.add(SeverityError, "free-list", objNum, "free entry links to object %d, which is in use", next)
+			} else {
+				v.add(SeverityError, "free-list", objNum, "free entry links to object %d, which has no entry", next)
+			}
+			break
+		}
+		if linked[next] {
+			v.add(SeverityError, "free-list", objNum, "free list loops back to object %d", next)
+			break
+		}
+		linked[next] = true
+		objNum = next
+	}
+
+	var unlinked []int
+	for objNum := range free {
+		if !linked[objNum] {
+			unlinked = append(unlinked, objNum)
+		}
+	}
+	sort.Ints(unlinked)
+	for _, objNum := range unlinked {
+		v.add(SeverityWarning, "free-list", objNum, "free entry is not linked from the free list")
+	}
+}
+
+// checkShadowed reports object numbers defined more than once, in several revisions or twice
+// in one. Every definition remains in the file, though readers only use the one its xref lists.
+func (v *validator) checkShadowed() {
+	revisions, err := FindRevisions(v.file, v.size)
+	if err != nil {
+		v.add(SeverityWarning, "xref-unverified", 0, "%v", err)
+		return
+	}
+
+	definitions := make(map[int][]int) // Revisions, numbered from 1, defining each object
+	for i, revision := range revisions {
+		for _, objNum := range revision.Objects {
+			definitions[objNum] = append(definitions[objNum], i+1)
+		}
+	}
+
+	var shadowed []int
+	for objNum, defs := range definitions {
+		if len(defs) > 1 {
+			shadowed = append(shadowed, objNum)
+		}
+	}
+	sort.Ints(shadowed)
+	for _, objNum := range shadowed {
+		defs := definitions[objNum]
+		names := make([]string, len(defs))
+		for i, rev := range defs {
+			names[i] = strconv.Itoa(rev)
+		}
+		v.add(SeverityWarning, "shadowed-object", objNum, "defined %d times, in revisions %s",
+			len(defs), strings.Join(names, ", "))
+	}
+}
+
+// checkReachable reports objects that can't be reached by following references from the
+// trailer. Object streams, xref streams and the linearization dictionary are reached through
+// the file structure rather than references, so they are not reported.
+func (v *validator) checkReachable() {
+	reached := make(map[int]bool)
+	var queue []int
+	for _, key := range []string{"Root", "Info", "Encrypt"} {
+		value, _ := v.doc.Trailer[key].(string)
+		if ref, ok := referenceNumber(value); ok && !reached[ref] {
+			reached[ref] = true
+			queue = append(queue, ref)
+		}
+	}
+	if len(queue) == 0 {
+		// Without a trailer to start from, every object would be reported
+		return
+	}
+
+	for len(queue) > 0 {
+		obj, ok := v.doc.Objects[queue[0]]
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+		for _, ref := range utils.FindReferences(objectSource(obj)) {
+			if !reached[ref] {
+				reached[ref] = true
+				queue = append(queue, ref)
+			}
+		}
+	}
+
+	for _, objNum := range v.doc.sortedObjectNumbers() {
+		if reached[objNum] {
+			continue
+		}
+		obj := v.doc.Objects[objNum]
+		objType := utils.GetString(obj.Dictionary["Type"], "")
+		if objType == "/ObjStm" || objType == "/XRef" {
+			continue
+		}
+		if _, ok := obj.Dictionary["Linearized"]; ok {
+			continue
+		}
+		v.add(SeverityWarning, "unreachable-object", objNum, "object is not connected to the trailer's /Root, /Info or /Encrypt")
+	}
+}
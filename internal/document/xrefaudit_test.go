@@ -0,0 +1,55 @@
+package document
+
+import (
+	"bytes"
+	"testing"
+)
+
+// auditCodes audits the file a test built and returns the codes of the findings, by object
+func auditCodes(t *testing.T, p *testPDF) map[string][]int {
+	t.Helper()
+	doc := parseTestPDF(t, p)
+	findings, err := doc.AuditXRef(bytes.NewReader(p.buf.Bytes()), int64(p.buf.Len()))
+	if err != nil {
+		t.Fatalf("AuditXRef: %v", err)
+	}
+	codes := make(map[string][]int)
+	for _, finding := range findings {
+		codes[finding.Code] = append(codes[finding.Code], finding.Object)
+	}
+	return codes
+}
+
+func TestAuditXRefPrevChain(t *testing.T) {
+	p := newTestPDF()
+	p.page("Hello")
+	p.xrefTable(" \n", []int{1, 2, 3, 4, 5}, "")
+	p.stream(5, "", []byte("BT /F1 12 Tf 72 720 Td (World) Tj ET"))
+	p.xrefTable(" \n", []int{5}, "")
+
+	codes := auditCodes(t, p)
+	for _, code := range []string{"xref-unverified", "orphaned-object", "free-list"} {
+		if objects, ok := codes[code]; ok {
+			t.Errorf("unexpected %s findings for objects %v", code, objects)
+		}
+	}
+	if objects := codes["shadowed-object"]; len(objects) != 1 || objects[0] != 5 {
+		t.Errorf("got shadowed-object findings for objects %v, want [5]", objects)
+	}
+}
+
+func TestAuditXRefOrphan(t *testing.T) {
+	// Object 5 is rewritten in place of an update, leaving its first definition unlisted
+	p := newTestPDF()
+	p.page("Hello")
+	p.stream(5, "", []byte("BT /F1 12 Tf 72 720 Td (World) Tj ET"))
+	p.xrefTable(" \n", []int{1, 2, 3, 4, 5}, "")
+
+	codes := auditCodes(t, p)
+	if objects := codes["orphaned-object"]; len(objects) != 1 || objects[0] != 5 {
+		t.Errorf("got orphaned-object findings for objects %v, want [5]", objects)
+	}
+	if objects, ok := codes["xref-unverified"]; ok {
+		t.Errorf("unexpected xref-unverified findings for objects %v", objects)
+	}
+}
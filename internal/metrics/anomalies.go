@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AnomalyReason is a metric of a document that is far from the corpus mean
+type AnomalyReason struct {
+	Metric string  `json:"metric"`
+	Value  float64 `json:"value"` // Parse times are in seconds
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	Sigma  float64 `json:"sigma"` // Distance from the mean in standard deviations, negative below it
+}
+
+// Anomaly is a document with metrics that are outliers in its corpus
+type Anomaly struct {
+	Filename string          `json:"filename"`
+	Reasons  []AnomalyReason `json:"reasons"` // Furthest from the mean first
+}
+
+// AnomalyReport lists the outliers of a collection
+type AnomalyReport struct {
+	Files     int       `json:"files"`
+	Threshold float64   `json:"threshold"` // Sigma from which a metric is an outlier
+	Anomalies []Anomaly `json:"anomalies"` // Most extreme first
+}
+
+// anomalyMetric is a per-document value compared across the corpus
+type anomalyMetric struct {
+	name  string
+	value func(m *PDFMetrics) float64
+}
+
+// anomalyMetrics are the values checked for outliers: parse time, object density and the
+// number of streams using each filter
+func anomalyMetrics() []anomalyMetric {
+	checked := []anomalyMetric{
+		{"ParseTime", func(m *PDFMetrics) float64 { return m.ParseTime.Seconds() }},
+		{"ObjectDensity", func(m *PDFMetrics) float64 { return m.ObjectDensity() }},
+	}
+	filters := make([]string, 0)
+	for filter := range (&PDFMetrics{}).GetFilterCounts() {
+		filters = append(filters, filter)
+	}
+	sort.Strings(filters)
+	for _, filter := range filters {
+		filter := filter
+		checked = append(checked, anomalyMetric{"Filter[" + filter + "]", func(m *PDFMetrics) float64 {
+			return float64(m.GetFilterCounts()[filter])
+		}})
+	}
+	return checked
+}
+
+// FindAnomalies flags the documents whose parse time, object density or filter usage is at
+// least threshold standard deviations from the corpus mean, with the reasons for each
+func (mc *MetricsCollection) FindAnomalies(threshold float64) *AnomalyReport {
+	report := &AnomalyReport{Files: len(mc.Metrics), Threshold: threshold, Anomalies: []Anomaly{}}
+	anomalies := make([]Anomaly, len(mc.Metrics))
+
+	for _, metric := range anomalyMetrics() {
+		values := make([]float64, len(mc.Metrics))
+		for i, m := range mc.Metrics {
+			values[i] = metric.value(m)
+		}
+		mean, stdDev := meanAndStdDev(values)
+		if stdDev == 0 {
+			continue
+		}
+		for i, v := range values {
+			sigma := (v - mean) / stdDev
+			if math.Abs(sigma) >= threshold {
+				anomalies[i].Reasons = append(anomalies[i].Reasons, AnomalyReason{
+					Metric: metric.name, Value: v, Mean: mean, StdDev: stdDev, Sigma: sigma,
+				})
+			}
+		}
+	}
+
+	for i, anomaly := range anomalies {
+		if len(anomaly.Reasons) == 0 {
+			continue
+		}
+		anomaly.Filename = mc.Metrics[i].Filename
+		sort.SliceStable(anomaly.Reasons, func(a, b int) bool {
+			return math.Abs(anomaly.Reasons[a].Sigma) > math.Abs(anomaly.Reasons[b].Sigma)
+		})
+		report.Anomalies = append(report.Anomalies, anomaly)
+	}
+	sort.SliceStable(report.Anomalies, func(a, b int) bool {
+		return math.Abs(report.Anomalies[a].Reasons[0].Sigma) > math.Abs(report.Anomalies[b].Reasons[0].Sigma)
+	})
+	return report
+}
+
+// HumanReadableFormat outputs the report with a line per reason
+func (r *AnomalyReport) HumanReadableFormat() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Anomalies: %d of %d files at %.1f sigma or more\n", len(r.Anomalies), r.Files, r.Threshold))
+	for _, anomaly := range r.Anomalies {
+		sb.WriteString(fmt.Sprintf("\n%s\n", anomaly.Filename))
+		for _, reason := range anomaly.Reasons {
+			direction := "above"
+			if reason.Sigma < 0 {
+				direction = "below"
+			}
+			sb.WriteString(fmt.Sprintf("- %s %s is %.1f sigma %s the mean %s\n", reason.Metric,
+				reason.formatValue(reason.Value), math.Abs(reason.Sigma), direction, reason.formatValue(reason.Mean)))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatValue formats a value of the reason's metric, parse times as durations
+func (r AnomalyReason) formatValue(v float64) string {
+	if r.Metric == "ParseTime" {
+		return time.Duration(v * float64(time.Second)).Round(time.Microsecond).String()
+	}
+	return fmt.Sprintf("%.4g", v)
+}
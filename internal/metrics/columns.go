@@ -0,0 +1,86 @@
+package metrics
+
+import "encoding/json"
+
+// metricColumn is a column of the tables exported from a collection, one row per document,
+// with how to read it from the metrics
+type metricColumn struct {
+	name     string
+	sqlType  string // INTEGER, REAL or TEXT
+	value    func(m *PDFMetrics) interface{}
+	jsonText bool // Lists and maps are stored as JSON text, which SQL JSON functions can query
+}
+
+// Columns of the exported tables
+var metricColumns = []metricColumn{
+	{name: "filename", sqlType: "TEXT", value: func(m *PDFMetrics) interface{} { return m.Filename }},
+	{name: "file_size", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.FileSize }},
+	{name: "parse_time_ns", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return int64(m.ParseTime) }},
+	{name: "version", sqlType: "TEXT", value: func(m *PDFMetrics) interface{} { return m.Version }},
+	{name: "object_count", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.ObjectCount }},
+	{name: "page_count", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.PageCount }},
+	{name: "font_count", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.FontCount }},
+	{name: "stream_object_count", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.StreamObjectCount }},
+	{name: "text_extraction_time_ns", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return int64(m.TextExtractionTime) }},
+	{name: "character_count", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.CharacterCount }},
+	{name: "text_chunk_count", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.TextChunkCount }},
+	{name: "xref_table_size", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.XRefTableSize }},
+	{name: "parse_path", sqlType: "TEXT", value: func(m *PDFMetrics) interface{} { return m.ParsePath }},
+	{name: "image_count", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.ImageCount }},
+	{name: "javascript_count", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.JavaScriptCount }},
+	{name: "flate_decode_streams", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.FlatDecodeStreams }},
+	{name: "ascii85_streams", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.ASCII85Streams }},
+	{name: "lzw_streams", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.LZWStreams }},
+	{name: "run_length_streams", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.RunLengthStreams }},
+	{name: "dct_streams", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.DCTStreams }},
+	{name: "jpx_streams", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.JPXStreams }},
+	{name: "ccitt_fax_streams", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.CCITTFaxStreams }},
+	{name: "jbig2_streams", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.JBIG2Streams }},
+	{name: "mixed_script_pages", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.MixedScriptPages }},
+	{name: "shown_glyphs", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.ShownGlyphs }},
+	{name: "mapped_glyphs", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.MappedGlyphs }},
+	{name: "mapping_coverage", sqlType: "REAL", value: func(m *PDFMetrics) interface{} { return m.MappingCoverage }},
+	{name: "image_only_ratio", sqlType: "REAL", value: func(m *PDFMetrics) interface{} { return m.ImageOnlyRatio }},
+	{name: "peak_stream_bytes", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.PeakStreamBytes }},
+	{name: "allocated_bytes", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return int64(m.AllocatedBytes) }},
+	{name: "largest_object_bytes", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.LargestObjectBytes }},
+	{name: "object_cache_hits", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.ObjectCacheHits }},
+	{name: "object_cache_misses", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.ObjectCacheMisses }},
+	{name: "object_type_counts", sqlType: "TEXT", jsonText: true, value: func(m *PDFMetrics) interface{} { return m.ObjectTypeCounts }},
+	{name: "script_counts", sqlType: "TEXT", jsonText: true, value: func(m *PDFMetrics) interface{} { return m.ScriptCounts }},
+	{name: "page_coverage", sqlType: "TEXT", jsonText: true, value: func(m *PDFMetrics) interface{} { return m.PageCoverage }},
+	{name: "image_only_pages", sqlType: "TEXT", jsonText: true, value: func(m *PDFMetrics) interface{} { return m.ImageOnlyPages }},
+	{name: "garbled_pages", sqlType: "TEXT", jsonText: true, value: func(m *PDFMetrics) interface{} { return m.GarbledPages }},
+	{name: "extraction_quality", sqlType: "REAL", value: func(m *PDFMetrics) interface{} { return m.ExtractionQuality }},
+	{name: "dictionary_word_ratio", sqlType: "REAL", value: func(m *PDFMetrics) interface{} { return m.DictionaryWordRatio }},
+	{name: "replacement_chars", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.ReplacementChars }},
+	{name: "nonprintable_chars", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.NonPrintableChars }},
+	{name: "fonts_without_tounicode", sqlType: "INTEGER", value: func(m *PDFMetrics) interface{} { return m.FontsWithoutToUnicode }},
+	{name: "fonts", sqlType: "TEXT", jsonText: true, value: func(m *PDFMetrics) interface{} { return m.Fonts }},
+	{name: "running_lines", sqlType: "TEXT", jsonText: true, value: func(m *PDFMetrics) interface{} { return m.RunningLines }},
+	{name: "layers", sqlType: "TEXT", jsonText: true, value: func(m *PDFMetrics) interface{} { return m.Layers }},
+	{name: "hidden_layers", sqlType: "TEXT", jsonText: true, value: func(m *PDFMetrics) interface{} { return m.HiddenLayers }},
+	{name: "warnings", sqlType: "TEXT", jsonText: true, value: func(m *PDFMetrics) interface{} { return m.Warnings }},
+	{name: "producer", sqlType: "TEXT", value: func(m *PDFMetrics) interface{} { return m.Fingerprint.Producer }},
+	{name: "generator_tool", sqlType: "TEXT", value: func(m *PDFMetrics) interface{} { return m.Fingerprint.Tool }},
+	{name: "generator_version", sqlType: "TEXT", value: func(m *PDFMetrics) interface{} { return m.Fingerprint.Version }},
+	{name: "generator_confidence", sqlType: "REAL", value: func(m *PDFMetrics) interface{} { return m.Fingerprint.Confidence }},
+	{name: "structure", sqlType: "TEXT", value: func(m *PDFMetrics) interface{} { return m.Fingerprint.Structure }},
+	{name: "fingerprint_evidence", sqlType: "TEXT", jsonText: true, value: func(m *PDFMetrics) interface{} { return m.Fingerprint.Evidence }},
+}
+
+// metricValues returns the values of the columns for one document
+func metricValues(m *PDFMetrics) ([]interface{}, error) {
+	values := make([]interface{}, len(metricColumns))
+	for i, column := range metricColumns {
+		values[i] = column.value(m)
+		if column.jsonText {
+			data, err := json.Marshal(values[i])
+			if err != nil {
+				return nil, err
+			}
+			values[i] = string(data)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CountChange records a count or value that differs between two parses
+type CountChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+	Delta int64  `json:"delta,omitempty"` // New minus old, for numeric fields
+}
+
+// TimingChange records how long a phase took in each parse
+type TimingChange struct {
+	Field string        `json:"field"`
+	Old   time.Duration `json:"old"`
+	New   time.Duration `json:"new"`
+}
+
+// MetricsDelta is the structured difference between two sets of metrics for the same file
+type MetricsDelta struct {
+	Changes          []CountChange  `json:"changes"`
+	Timings          []TimingChange `json:"timings"`
+	NewWarnings      []string       `json:"new_warnings"`
+	ResolvedWarnings []string       `json:"resolved_warnings"`
+}
+
+// Changed reports whether the document structure materially changed. Timing differences
+// alone don't count as a change.
+func (d *MetricsDelta) Changed() bool {
+	return len(d.Changes) > 0 || len(d.NewWarnings) > 0 || len(d.ResolvedWarnings) > 0
+}
+
+// countPair holds the old and new values of a numeric field
+type countPair struct {
+	field    string
+	old, new int64
+}
+
+// Compare returns the difference between these metrics (the new parse) and other (the old one)
+func (m *PDFMetrics) Compare(other *PDFMetrics) *MetricsDelta {
+	delta := &MetricsDelta{}
+
+	counts := []countPair{
+		{"FileSize", other.FileSize, m.FileSize},
+		{"ObjectCount", int64(other.ObjectCount), int64(m.ObjectCount)},
+		{"PageCount", int64(other.PageCount), int64(m.PageCount)},
+		{"FontCount", int64(other.FontCount), int64(m.FontCount)},
+		{"StreamObjectCount", int64(other.StreamObjectCount), int64(m.StreamObjectCount)},
+		{"CharacterCount", int64(other.CharacterCount), int64(m.CharacterCount)},
+		{"TextChunkCount", int64(other.TextChunkCount), int64(m.TextChunkCount)},
+		{"XRefTableSize", int64(other.XRefTableSize), int64(m.XRefTableSize)},
+		{"ImageCount", int64(other.ImageCount), int64(m.ImageCount)},
+		{"JavaScriptCount", int64(other.JavaScriptCount), int64(m.JavaScriptCount)},
+		{"MixedScriptPages", int64(other.MixedScriptPages), int64(m.MixedScriptPages)},
+		{"ShownGlyphs", int64(other.ShownGlyphs), int64(m.ShownGlyphs)},
+		{"MappedGlyphs", int64(other.MappedGlyphs), int64(m.MappedGlyphs)},
+		{"ImageOnlyPages", int64(len(other.ImageOnlyPages)), int64(len(m.ImageOnlyPages))},
+		{"GarbledPages", int64(len(other.GarbledPages)), int64(len(m.GarbledPages))},
+		{"ReplacementChars", int64(other.ReplacementChars), int64(m.ReplacementChars)},
+		{"NonPrintableChars", int64(other.NonPrintableChars), int64(m.NonPrintableChars)},
+		{"FontsWithoutToUnicode", int64(other.FontsWithoutToUnicode), int64(m.FontsWithoutToUnicode)},
+	}
+	for filter, count := range m.GetFilterCounts() {
+		counts = append(counts, countPair{"Filter[" + filter + "]", int64(other.GetFilterCounts()[filter]), int64(count)})
+	}
+
+	for _, c := range counts {
+		if c.old != c.new {
+			delta.Changes = append(delta.Changes, CountChange{
+				Field: c.field,
+				Old:   fmt.Sprint(c.old),
+				New:   fmt.Sprint(c.new),
+				Delta: c.new - c.old,
+			})
+		}
+	}
+
+	if m.Version != other.Version {
+		delta.Changes = append(delta.Changes, CountChange{Field: "Version", Old: other.Version, New: m.Version})
+	}
+	if m.ParsePath != other.ParsePath {
+		delta.Changes = append(delta.Changes, CountChange{Field: "ParsePath", Old: other.ParsePath, New: m.ParsePath})
+	}
+
+	delta.Changes = append(delta.Changes, compareCountMaps("ObjectTypeCounts", other.ObjectTypeCounts, m.ObjectTypeCounts)...)
+	delta.Changes = append(delta.Changes, compareCountMaps("ScriptCounts", other.ScriptCounts, m.ScriptCounts)...)
+
+	sort.SliceStable(delta.Changes, func(i, j int) bool {
+		return delta.Changes[i].Field < delta.Changes[j].Field
+	})
+
+	delta.Timings = []TimingChange{
+		{Field: "ParseTime", Old: other.ParseTime, New: m.ParseTime},
+		{Field: "TextExtractionTime", Old: other.TextExtractionTime, New: m.TextExtractionTime},
+	}
+
+	delta.NewWarnings = missingFrom(m.Warnings, other.Warnings)
+	delta.ResolvedWarnings = missingFrom(other.Warnings, m.Warnings)
+
+	return delta
+}
+
+// compareCountMaps returns the changed entries of two count maps, named field[key]
+func compareCountMaps(field string, old, new map[string]int) []CountChange {
+	keys := make(map[string]bool)
+	for key := range old {
+		keys[key] = true
+	}
+	for key := range new {
+		keys[key] = true
+	}
+
+	var changes []CountChange
+	for key := range keys {
+		if old[key] != new[key] {
+			changes = append(changes, CountChange{
+				Field: fmt.Sprintf("%s[%s]", field, key),
+				Old:   fmt.Sprint(old[key]),
+				New:   fmt.Sprint(new[key]),
+				Delta: int64(new[key] - old[key]),
+			})
+		}
+	}
+	return changes
+}
+
+// missingFrom returns the items of a that are not in b
+func missingFrom(a, b []string) []string {
+	seen := make(map[string]bool, len(b))
+	for _, item := range b {
+		seen[item] = true
+	}
+
+	var result []string
+	for _, item := range a {
+		if !seen[item] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// HumanReadableFormat outputs the delta in a human-readable format
+func (d *MetricsDelta) HumanReadableFormat() string {
+	var sb strings.Builder
+
+	if !d.Changed() {
+		sb.WriteString("No structural changes\n")
+	}
+
+	if len(d.Changes) > 0 {
+		sb.WriteString("Changes:\n")
+		for _, c := range d.Changes {
+			if c.Delta != 0 {
+				sb.WriteString(fmt.Sprintf("- %s: %s -> %s (%+d)\n", c.Field, c.Old, c.New, c.Delta))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s: %s -> %s\n", c.Field, c.Old, c.New))
+			}
+		}
+	}
+
+	if len(d.NewWarnings) > 0 {
+		sb.WriteString("New Warnings:\n")
+		for _, warning := range d.NewWarnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", warning))
+		}
+	}
+
+	if len(d.ResolvedWarnings) > 0 {
+		sb.WriteString("Resolved Warnings:\n")
+		for _, warning := range d.ResolvedWarnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", warning))
+		}
+	}
+
+	sb.WriteString("Timings:\n")
+	for _, t := range d.Timings {
+		sb.WriteString(fmt.Sprintf("- %s: %v -> %v\n", t.Field, t.Old, t.New))
+	}
+
+	return sb.String()
+}
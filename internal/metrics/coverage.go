@@ -0,0 +1,45 @@
+package metrics
+
+import "fmt"
+
+// RecordMappingCoverage updates the mapping coverage statistics from the number of glyphs shown
+// on each page and how many of them had authoritative Unicode values
+func (m *PDFMetrics) RecordMappingCoverage(pageShown, pageMapped []int) {
+	m.ShownGlyphs = 0
+	m.MappedGlyphs = 0
+	m.PageCoverage = make([]float64, len(pageShown))
+
+	for i, shown := range pageShown {
+		m.ShownGlyphs += shown
+		m.MappedGlyphs += pageMapped[i]
+		m.PageCoverage[i] = coverageRatio(pageMapped[i], shown)
+	}
+
+	m.MappingCoverage = coverageRatio(m.MappedGlyphs, m.ShownGlyphs)
+}
+
+// coverageRatio returns mapped/shown, or 0 when nothing was shown
+func coverageRatio(mapped, shown int) float64 {
+	if shown == 0 {
+		return 0
+	}
+	return float64(mapped) / float64(shown)
+}
+
+// mappingCoverageSummary describes the mapping coverage for the human-readable format
+func (m *PDFMetrics) mappingCoverageSummary() string {
+	if m.ShownGlyphs == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%% (%d of %d glyphs)", m.MappingCoverage*100, m.MappedGlyphs, m.ShownGlyphs)
+}
+
+// RecordImageOnlyPages records the pages that are scanned images without a text layer, out of
+// pageCount pages
+func (m *PDFMetrics) RecordImageOnlyPages(pages []int, pageCount int) {
+	m.ImageOnlyPages = pages
+	m.ImageOnlyRatio = 0
+	if pageCount > 0 {
+		m.ImageOnlyRatio = float64(len(pages)) / float64(pageCount)
+	}
+}
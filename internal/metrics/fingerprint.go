@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fingerprint identifies the software that generated a document from its Producer and Creator
+// strings and the traits of its file structure, for corpus analysis and for spotting documents
+// whose structure doesn't match the tool they claim to come from
+type Fingerprint struct {
+	Producer   string   `json:"producer,omitempty"` // /Producer of the information dictionary
+	Creator    string   `json:"creator,omitempty"`  // /Creator: the application the document was authored in
+	Tool       string   `json:"tool,omitempty"`     // Software identified as having written the file, empty if unknown
+	Version    string   `json:"version,omitempty"`
+	Confidence float64  `json:"confidence"` // From 0 for unknown to 1
+	Structure  string   `json:"structure"`  // Traits of the file structure, e.g. "xref-table ascending flate"
+	Evidence   []string `json:"evidence,omitempty"`
+}
+
+// summary describes the identified tool on one line of the human-readable format
+func (f Fingerprint) summary() string {
+	if f.Tool == "" {
+		return "unknown"
+	}
+	tool := f.Tool
+	if f.Version != "" {
+		tool += " " + f.Version
+	}
+	return fmt.Sprintf("%s (confidence %.2f)", tool, f.Confidence)
+}
+
+// humanReadable writes the fingerprint section of the human-readable format
+func (f Fingerprint) humanReadable(sb *strings.Builder) {
+	sb.WriteString("Generator:\n")
+	sb.WriteString(fmt.Sprintf("- Tool: %s\n", f.summary()))
+	if f.Producer != "" {
+		sb.WriteString(fmt.Sprintf("- Producer: %s\n", f.Producer))
+	}
+	if f.Creator != "" {
+		sb.WriteString(fmt.Sprintf("- Creator: %s\n", f.Creator))
+	}
+	sb.WriteString(fmt.Sprintf("- Structure: %s\n", f.Structure))
+	for _, evidence := range f.Evidence {
+		sb.WriteString(fmt.Sprintf("- %s\n", evidence))
+	}
+	sb.WriteString("\n")
+}
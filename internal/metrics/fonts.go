@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FontUsage describes a font of the document and how much text was shown with it, which
+// predicts how reliably its text can be extracted
+type FontUsage struct {
+	Name       string `json:"name"`      // Resource name of the font, e.g. "F1"
+	BaseFont   string `json:"base_font"` // PostScript name, e.g. "/Helvetica-Bold"
+	Subtype    string `json:"subtype"`
+	Embedded   bool   `json:"embedded"`
+	Encoding   string `json:"encoding"`
+	ToUnicode  bool   `json:"to_unicode"` // Has a ToUnicode CMap
+	Characters int    `json:"characters"` // Glyphs shown with the font during text extraction
+}
+
+// RecordFontUsage records the fonts of the document, most used first
+func (m *PDFMetrics) RecordFontUsage(fonts []FontUsage) {
+	sort.SliceStable(fonts, func(i, j int) bool {
+		if fonts[i].Characters != fonts[j].Characters {
+			return fonts[i].Characters > fonts[j].Characters
+		}
+		return fonts[i].Name < fonts[j].Name
+	})
+	m.Fonts = fonts
+}
+
+// summary describes the font on one line of the human-readable format
+func (f FontUsage) summary() string {
+	var details []string
+	if f.BaseFont != "" {
+		details = append(details, strings.TrimPrefix(f.BaseFont, "/"))
+	}
+	if f.Subtype != "" {
+		details = append(details, strings.TrimPrefix(f.Subtype, "/"))
+	}
+	if f.Encoding != "" {
+		details = append(details, strings.TrimPrefix(f.Encoding, "/"))
+	}
+	if f.Embedded {
+		details = append(details, "embedded")
+	} else {
+		details = append(details, "not embedded")
+	}
+	if f.ToUnicode {
+		details = append(details, "ToUnicode")
+	}
+	return fmt.Sprintf("%s (%s): %d characters", f.Name, strings.Join(details, ", "), f.Characters)
+}
@@ -3,34 +3,64 @@ package metrics
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
 	"time"
 )
 
 // PDFMetrics contains statistics about the parsed PDF
 type PDFMetrics struct {
-	Filename           string
-	FileSize           int64
-	ParseTime          time.Duration
-	Version            string
-	ObjectCount        int
-	PageCount          int
-	FontCount          int
-	StreamObjectCount  int
-	TextExtractionTime time.Duration
-	CharacterCount     int
-	TextChunkCount     int
-	XRefTableSize      int
-	ImageCount         int
-	FlatDecodeStreams  int
-	ASCII85Streams     int
-	LZWStreams         int
-	RunLengthStreams   int
-	DCTStreams         int
-	JPXStreams         int
-	CCITTFaxStreams    int
-	JBIG2Streams       int
-	ObjectTypeCounts   map[string]int
+	Filename              string
+	FileSize              int64
+	ParseTime             time.Duration
+	Version               string
+	ObjectCount           int
+	PageCount             int
+	FontCount             int
+	StreamObjectCount     int
+	TextExtractionTime    time.Duration
+	CharacterCount        int
+	TextChunkCount        int // Chunks of the extracted text with the default chunk options
+	XRefTableSize         int
+	ParsePath             string // How the object table was obtained: xref, adjusted-xref, rebuild or linear
+	ImageCount            int
+	JavaScriptCount       int // Document-level and trigger scripts, see PDFDocument.JavaScripts
+	FlatDecodeStreams     int
+	ASCII85Streams        int
+	LZWStreams            int
+	RunLengthStreams      int
+	DCTStreams            int
+	JPXStreams            int
+	CCITTFaxStreams       int
+	JBIG2Streams          int
+	ObjectTypeCounts      map[string]int
+	ScriptCounts          map[string]int   // Characters per Unicode script, recorded during text extraction
+	PageScriptCounts      []map[string]int // Characters per Unicode script for each page
+	MixedScriptPages      int              // Pages where more than one script is significant
+	ShownGlyphs           int              // Glyphs shown by text operators, recorded during text extraction
+	MappedGlyphs          int              // Shown glyphs with Unicode values from ToUnicode or ActualText
+	MappingCoverage       float64          // MappedGlyphs as a fraction of ShownGlyphs, 0 without text
+	PageCoverage          []float64        // Mapping coverage of each page
+	ImageOnlyPages        []int            // 1-based pages that are scanned images without a text layer, which need OCR
+	ImageOnlyRatio        float64          // ImageOnlyPages as a fraction of the pages, recorded during text extraction
+	GarbledPages          []int            // 1-based pages whose extracted text looks like mojibake
+	ExtractionQuality     float64          // Heuristic score of the extracted text from 0 to 1, see RecordExtractionQuality
+	DictionaryWordRatio   float64          // Latin-script words found among the most common words of major languages
+	ReplacementChars      int              // U+FFFD characters in the extracted text
+	NonPrintableChars     int              // Control, private-use and unassigned characters in the extracted text
+	FontsWithoutToUnicode int              // Fonts used whose text was guessed without a ToUnicode CMap
+	Fonts                 []FontUsage      // Fonts with the characters shown with each, recorded during text extraction
+	RunningLines          []string         // Running headers, footers and page numbers removed from the text
+	Layers                []string         // Names of the optional content groups (layers)
+	HiddenLayers          []string         // Layers hidden in the default configuration
+	Warnings              []string         // Structural problems found while parsing
+	PeakStreamBytes       int64            // Decompressed stream bytes held in memory, at their peak once every stream is decoded
+	AllocatedBytes        uint64           // Heap bytes allocated while parsing, including those of other goroutines such as parallel batch workers
+	LargestObjectBytes    int64            // Memory held by the largest object: its raw content and any decompressed stream
+	ObjectCacheHits       int              // Indirect objects resolved from the document's cache of parsed objects
+	ObjectCacheMisses     int              // Indirect objects parsed because the cache didn't hold them
+	Fingerprint           Fingerprint      // Software identified as having generated the document
 }
 
 // NewPDFMetrics creates a new PDFMetrics instance
@@ -39,6 +69,7 @@ func NewPDFMetrics(filename string, fileSize int64) *PDFMetrics {
 		Filename:         filename,
 		FileSize:         fileSize,
 		ObjectTypeCounts: make(map[string]int),
+		ScriptCounts:     make(map[string]int),
 	}
 }
 
@@ -47,6 +78,15 @@ func (m *PDFMetrics) JSONFormat() ([]byte, error) {
 	return json.MarshalIndent(m, "", "  ")
 }
 
+// ParseJSON reads metrics previously written by JSONFormat
+func ParseJSON(data []byte) (*PDFMetrics, error) {
+	m := NewPDFMetrics("", 0)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid metrics JSON: %v", err)
+	}
+	return m, nil
+}
+
 // HumanReadableFormat outputs the metrics in a human-readable format
 func (m *PDFMetrics) HumanReadableFormat() string {
 	var sb strings.Builder
@@ -62,12 +102,26 @@ func (m *PDFMetrics) HumanReadableFormat() string {
 	sb.WriteString(fmt.Sprintf("- Page Count: %d\n", m.PageCount))
 	sb.WriteString(fmt.Sprintf("- Font Count: %d\n", m.FontCount))
 	sb.WriteString(fmt.Sprintf("- Image Count: %d\n", m.ImageCount))
-	sb.WriteString(fmt.Sprintf("- XRef Table Size: %d\n\n", m.XRefTableSize))
+	sb.WriteString(fmt.Sprintf("- JavaScript Count: %d\n", m.JavaScriptCount))
+	sb.WriteString(fmt.Sprintf("- XRef Table Size: %d\n", m.XRefTableSize))
+	sb.WriteString(fmt.Sprintf("- Parse Path: %s\n\n", m.ParsePath))
+
+	m.Fingerprint.humanReadable(&sb)
 
 	sb.WriteString("Text Statistics:\n")
 	sb.WriteString(fmt.Sprintf("- Text Extraction Time: %v\n", m.TextExtractionTime))
 	sb.WriteString(fmt.Sprintf("- Character Count: %d\n", m.CharacterCount))
-	sb.WriteString(fmt.Sprintf("- Text Chunk Count: %d\n\n", m.TextChunkCount))
+	sb.WriteString(fmt.Sprintf("- Text Chunk Count: %d\n", m.TextChunkCount))
+	sb.WriteString(fmt.Sprintf("- Mapping Coverage: %s\n", m.mappingCoverageSummary()))
+	sb.WriteString(fmt.Sprintf("- Image-Only Pages: %d (%.1f%%)\n", len(m.ImageOnlyPages), m.ImageOnlyRatio*100))
+	sb.WriteString(fmt.Sprintf("- Garbled Pages: %d\n", len(m.GarbledPages)))
+	sb.WriteString(fmt.Sprintf("- Extraction Quality: %s\n\n", m.extractionQualitySummary()))
+
+	sb.WriteString("Memory:\n")
+	sb.WriteString(fmt.Sprintf("- Peak Stream Bytes: %d\n", m.PeakStreamBytes))
+	sb.WriteString(fmt.Sprintf("- Allocated Bytes: %d\n", m.AllocatedBytes))
+	sb.WriteString(fmt.Sprintf("- Largest Object Bytes: %d\n", m.LargestObjectBytes))
+	sb.WriteString(fmt.Sprintf("- Object Cache: %d hits, %d misses\n\n", m.ObjectCacheHits, m.ObjectCacheMisses))
 
 	sb.WriteString("Stream Filters Usage:\n")
 	sb.WriteString(fmt.Sprintf("- FlatDecode: %d\n", m.FlatDecodeStreams))
@@ -84,6 +138,50 @@ func (m *PDFMetrics) HumanReadableFormat() string {
 		sb.WriteString(fmt.Sprintf("- %s: %d\n", objType, count))
 	}
 
+	if len(m.Warnings) > 0 {
+		sb.WriteString("\nWarnings:\n")
+		for _, warning := range m.Warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", warning))
+		}
+	}
+
+	if len(m.ScriptCounts) > 0 {
+		sb.WriteString("\nUnicode Scripts:\n")
+		for _, script := range m.SortedScripts() {
+			sb.WriteString(fmt.Sprintf("- %s: %d\n", script, m.ScriptCounts[script]))
+		}
+		sb.WriteString(fmt.Sprintf("- Mixed-Script Pages: %d\n", m.MixedScriptPages))
+	}
+
+	if len(m.Fonts) > 0 {
+		sb.WriteString("\nFonts:\n")
+		for _, font := range m.Fonts {
+			sb.WriteString(fmt.Sprintf("- %s\n", font.summary()))
+		}
+	}
+
+	if len(m.Layers) > 0 {
+		hidden := make(map[string]bool, len(m.HiddenLayers))
+		for _, name := range m.HiddenLayers {
+			hidden[name] = true
+		}
+		sb.WriteString("\nLayers:\n")
+		for _, name := range m.Layers {
+			if hidden[name] {
+				sb.WriteString(fmt.Sprintf("- %s (hidden)\n", name))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s\n", name))
+			}
+		}
+	}
+
+	if len(m.RunningLines) > 0 {
+		sb.WriteString("\nRunning Lines Removed:\n")
+		for _, line := range m.RunningLines {
+			sb.WriteString(fmt.Sprintf("- %s\n", line))
+		}
+	}
+
 	return sb.String()
 }
 
@@ -91,12 +189,14 @@ func (m *PDFMetrics) HumanReadableFormat() string {
 func (m *PDFMetrics) CSVHeader() string {
 	return "Filename,FileSize,ParseTime,Version,ObjectCount,PageCount,FontCount,StreamObjectCount," +
 		"CharacterCount,TextChunkCount,ImageCount,FlatDecodeStreams,ASCII85Streams,LZWStreams," +
-		"RunLengthStreams,DCTStreams,JPXStreams,CCITTFaxStreams,JBIG2Streams"
+		"RunLengthStreams,DCTStreams,JPXStreams,CCITTFaxStreams,JBIG2Streams,MappingCoverage," +
+		"PeakStreamBytes,AllocatedBytes,LargestObjectBytes,JavaScriptCount,ImageOnlyRatio," +
+		"ExtractionQuality,GeneratorTool,GeneratorVersion"
 }
 
 // CSVFormat outputs the metrics in CSV format
 func (m *PDFMetrics) CSVFormat() string {
-	return fmt.Sprintf("%s,%d,%v,%s,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d",
+	return fmt.Sprintf("%s,%d,%v,%s,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%.4f,%d,%d,%d,%d,%.4f,%.4f,%s,%s",
 		escapeCSV(m.Filename),
 		m.FileSize,
 		m.ParseTime,
@@ -115,7 +215,16 @@ func (m *PDFMetrics) CSVFormat() string {
 		m.DCTStreams,
 		m.JPXStreams,
 		m.CCITTFaxStreams,
-		m.JBIG2Streams)
+		m.JBIG2Streams,
+		m.MappingCoverage,
+		m.PeakStreamBytes,
+		m.AllocatedBytes,
+		m.LargestObjectBytes,
+		m.JavaScriptCount,
+		m.ImageOnlyRatio,
+		m.ExtractionQuality,
+		escapeCSV(m.Fingerprint.Tool),
+		escapeCSV(m.Fingerprint.Version))
 }
 
 // escapeCSV escapes a string for CSV output
@@ -169,9 +278,14 @@ func (m *PDFMetrics) CompactSummary() string {
 		m.Filename, m.PageCount, m.ObjectCount, m.CharacterCount, m.ParseTime)
 }
 
-// MetricsCollection represents a collection of PDF metrics
+// MetricsCollection represents a collection of PDF metrics. It is safe to add metrics from
+// several goroutines.
 type MetricsCollection struct {
 	Metrics []*PDFMetrics
+
+	mu     sync.Mutex
+	csv    io.Writer // Receives a row for each metrics added, after ExportCSVTo or AppendCSVTo
+	csvErr error     // First error writing a streamed row
 }
 
 // NewMetricsCollection creates a new metrics collection
@@ -181,9 +295,15 @@ func NewMetricsCollection() *MetricsCollection {
 	}
 }
 
-// Add adds metrics to the collection
+// Add adds metrics to the collection, and writes their CSV row if the collection is streaming
 func (mc *MetricsCollection) Add(metrics *PDFMetrics) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
 	mc.Metrics = append(mc.Metrics, metrics)
+	if mc.csv != nil && mc.csvErr == nil {
+		_, mc.csvErr = io.WriteString(mc.csv, metrics.CSVFormat()+"\n")
+	}
 }
 
 // ExportCSV exports the collection to CSV format
@@ -192,16 +312,59 @@ func (mc *MetricsCollection) ExportCSV() string {
 		return ""
 	}
 
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
 	var sb strings.Builder
-	sb.WriteString(mc.Metrics[0].CSVHeader())
-	sb.WriteString("\n")
+	mc.writeCSV(&sb, true)
+	return sb.String()
+}
 
-	for _, metrics := range mc.Metrics {
-		sb.WriteString(metrics.CSVFormat())
-		sb.WriteString("\n")
+// ExportCSVTo writes the collection to w in CSV format, with a header row, and then streams a
+// row for each metrics added later, so that the CSV of a large corpus is never held in memory.
+// Errors writing later rows are reported by CSVErr.
+func (mc *MetricsCollection) ExportCSVTo(w io.Writer) error {
+	return mc.streamCSV(w, true)
+}
+
+// AppendCSVTo is like ExportCSVTo without the header row, for adding rows to an existing CSV
+// file
+func (mc *MetricsCollection) AppendCSVTo(w io.Writer) error {
+	return mc.streamCSV(w, false)
+}
+
+// CSVErr returns the first error writing a row streamed by ExportCSVTo or AppendCSVTo
+func (mc *MetricsCollection) CSVErr() error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.csvErr
+}
+
+// streamCSV writes the collection to w and makes it receive the rows of later metrics
+func (mc *MetricsCollection) streamCSV(w io.Writer, header bool) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if err := mc.writeCSV(w, header); err != nil {
+		return err
 	}
+	mc.csv, mc.csvErr = w, nil
+	return nil
+}
 
-	return sb.String()
+// writeCSV writes the rows of the collection, after the header row if requested
+func (mc *MetricsCollection) writeCSV(w io.Writer, header bool) error {
+	if header {
+		if _, err := io.WriteString(w, (&PDFMetrics{}).CSVHeader()+"\n"); err != nil {
+			return err
+		}
+	}
+	for _, metrics := range mc.Metrics {
+		if _, err := io.WriteString(w, metrics.CSVFormat()+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GetAverages returns average metrics across the collection
@@ -225,6 +388,7 @@ func (mc *MetricsCollection) GetAverages() *PDFMetrics {
 		avg.TextChunkCount += m.TextChunkCount
 		avg.XRefTableSize += m.XRefTableSize
 		avg.ImageCount += m.ImageCount
+		avg.JavaScriptCount += m.JavaScriptCount
 		avg.FlatDecodeStreams += m.FlatDecodeStreams
 		avg.ASCII85Streams += m.ASCII85Streams
 		avg.LZWStreams += m.LZWStreams
@@ -233,6 +397,12 @@ func (mc *MetricsCollection) GetAverages() *PDFMetrics {
 		avg.JPXStreams += m.JPXStreams
 		avg.CCITTFaxStreams += m.CCITTFaxStreams
 		avg.JBIG2Streams += m.JBIG2Streams
+		avg.PeakStreamBytes += m.PeakStreamBytes
+		avg.AllocatedBytes += m.AllocatedBytes
+		avg.LargestObjectBytes += m.LargestObjectBytes
+		avg.ObjectCacheHits += m.ObjectCacheHits
+		avg.ObjectCacheMisses += m.ObjectCacheMisses
+		avg.ExtractionQuality += m.ExtractionQuality
 	}
 
 	// Calculate averages
@@ -248,6 +418,7 @@ func (mc *MetricsCollection) GetAverages() *PDFMetrics {
 	avg.TextChunkCount /= count
 	avg.XRefTableSize /= count
 	avg.ImageCount /= count
+	avg.JavaScriptCount /= count
 	avg.FlatDecodeStreams /= count
 	avg.ASCII85Streams /= count
 	avg.LZWStreams /= count
@@ -256,6 +427,12 @@ func (mc *MetricsCollection) GetAverages() *PDFMetrics {
 	avg.JPXStreams /= count
 	avg.CCITTFaxStreams /= count
 	avg.JBIG2Streams /= count
+	avg.PeakStreamBytes /= int64(count)
+	avg.AllocatedBytes /= uint64(count)
+	avg.LargestObjectBytes /= int64(count)
+	avg.ObjectCacheHits /= count
+	avg.ObjectCacheMisses /= count
+	avg.ExtractionQuality /= float64(count)
 
 	return avg
 }
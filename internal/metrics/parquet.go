@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Parquet types of the SQL column types
+var parquetTypes = map[string]utils.ParquetType{
+	"INTEGER": utils.ParquetInt64,
+	"REAL":    utils.ParquetDouble,
+	"TEXT":    utils.ParquetString,
+}
+
+// ExportParquet writes the collection to a Parquet file at path, replacing any existing file,
+// with one row per document and the columns of ExportSQLite
+func (mc *MetricsCollection) ExportParquet(path string) error {
+	columns := make([]utils.ParquetColumn, len(metricColumns))
+	for i, column := range metricColumns {
+		columns[i] = utils.ParquetColumn{Name: column.name, Type: parquetTypes[column.sqlType]}
+	}
+	rows := make([][]interface{}, len(mc.Metrics))
+	for i, m := range mc.Metrics {
+		values, err := metricValues(m)
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %v", m.Filename, err)
+		}
+		rows[i] = values
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create Parquet file: %w", err)
+	}
+	defer file.Close()
+	bw := bufio.NewWriter(file)
+	if err := utils.WriteParquet(bw, columns, rows); err != nil {
+		return fmt.Errorf("failed to write Parquet file: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write Parquet file: %w", err)
+	}
+	return file.Close()
+}
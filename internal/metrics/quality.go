@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Extraction quality parameters
+const (
+	minScoredWords          = 20   // Documents with fewer Latin-script words aren't scored on them
+	expectedDictionaryRatio = 0.25 // Share of dictionary words in ordinary prose, at or above which words don't lower the score
+)
+
+// TextQualityCounts are the counts of extracted text that the extraction quality score is
+// computed from
+type TextQualityCounts struct {
+	Characters        int // Non-space characters
+	Words             int // Words written in Latin letters only, which the dictionary can judge
+	DictionaryWords   int // Words found among the most common words of English and major European languages
+	ReplacementChars  int // U+FFFD characters, for bytes that couldn't be decoded
+	NonPrintableChars int // Control, private-use and unassigned characters, typical of glyphs without a Unicode mapping
+}
+
+// RecordExtractionQuality scores how trustworthy the extracted text is from its counts, the fonts
+// and the image-only pages, which must be recorded first. The score, from 0 to 1, multiplies the
+// share of printable characters, the share of dictionary words relative to that of ordinary
+// prose, the share of characters shown with fonts whose text had to be guessed, and the share of
+// pages with a text layer. A document without text scores 0.
+func (m *PDFMetrics) RecordExtractionQuality(counts TextQualityCounts) {
+	m.DictionaryWordRatio = 0
+	if counts.Words > 0 {
+		m.DictionaryWordRatio = float64(counts.DictionaryWords) / float64(counts.Words)
+	}
+	m.ReplacementChars = counts.ReplacementChars
+	m.NonPrintableChars = counts.NonPrintableChars
+
+	m.FontsWithoutToUnicode = 0
+	var guessed, shown int
+	for _, font := range m.Fonts {
+		shown += font.Characters
+		if font.Characters > 0 && font.guessed() {
+			m.FontsWithoutToUnicode++
+			guessed += font.Characters
+		}
+	}
+
+	if counts.Characters == 0 {
+		m.ExtractionQuality = 0
+		return
+	}
+	score := 1 - float64(counts.ReplacementChars+counts.NonPrintableChars)/float64(counts.Characters)
+	if counts.Words >= minScoredWords {
+		score *= math.Min(m.DictionaryWordRatio/expectedDictionaryRatio, 1)
+	}
+	if shown > 0 {
+		score *= 1 - float64(guessed)/float64(shown)
+	}
+	score *= 1 - m.ImageOnlyRatio
+	m.ExtractionQuality = math.Max(score, 0)
+}
+
+// guessed reports whether the text of a font had to be guessed from its character codes: it
+// has no ToUnicode CMap and either uses Identity or built-in encodings of embedded glyphs or is a
+// Type 3 font. Standard encodings of simple fonts decode reliably without one.
+func (f FontUsage) guessed() bool {
+	if f.ToUnicode {
+		return false
+	}
+	return strings.Contains(f.Encoding, "Identity") || f.Subtype == "/Type3" || (f.Embedded && f.Encoding == "")
+}
+
+// extractionQualitySummary describes the extraction quality for the human-readable format
+func (m *PDFMetrics) extractionQualitySummary() string {
+	return fmt.Sprintf("%.2f (%.1f%% dictionary words, %d replacement, %d non-printable, %d fonts without ToUnicode)",
+		m.ExtractionQuality, m.DictionaryWordRatio*100, m.ReplacementChars, m.NonPrintableChars, m.FontsWithoutToUnicode)
+}
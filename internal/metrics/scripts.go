@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"sort"
+	"unicode"
+)
+
+// Script names used for characters that don't belong to a regular Unicode script
+const (
+	ScriptPrivateUse = "PrivateUse"
+	ScriptUnknown    = "Unknown"
+)
+
+// Minimum share of a page's letters a second script needs for the page to count as mixed-script
+const mixedScriptThreshold = 0.1
+
+// commonScripts lists the scripts checked first, so the usual cases avoid a scan of unicode.Scripts
+var commonScripts = []string{
+	"Latin", "Cyrillic", "Greek", "Han", "Hiragana", "Katakana", "Hangul",
+	"Arabic", "Hebrew", "Devanagari", "Thai",
+}
+
+// ScriptOf returns the name of the Unicode script of a rune. Characters shared between scripts
+// (digits, punctuation, whitespace, combining marks) return an empty string.
+func ScriptOf(r rune) string {
+	if unicode.In(r, unicode.Common, unicode.Inherited) {
+		return ""
+	}
+
+	if unicode.Is(unicode.Co, r) {
+		return ScriptPrivateUse
+	}
+
+	for _, name := range commonScripts {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+
+	for name, table := range unicode.Scripts {
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+
+	return ScriptUnknown
+}
+
+// CountScripts counts the characters of a text per Unicode script
+func CountScripts(text string) map[string]int {
+	counts := make(map[string]int)
+
+	for _, r := range text {
+		if r == unicode.ReplacementChar {
+			counts[ScriptUnknown]++
+			continue
+		}
+		if script := ScriptOf(r); script != "" {
+			counts[script]++
+		}
+	}
+
+	return counts
+}
+
+// IsMixedScript reports whether more than one script has a significant share of the counts
+func IsMixedScript(counts map[string]int) bool {
+	var total int
+	for _, count := range counts {
+		total += count
+	}
+	if total == 0 {
+		return false
+	}
+
+	significant := 0
+	for _, count := range counts {
+		if float64(count)/float64(total) >= mixedScriptThreshold {
+			significant++
+		}
+	}
+
+	return significant > 1
+}
+
+// RecordPageScripts updates the script statistics from the extracted text of each page
+func (m *PDFMetrics) RecordPageScripts(pageTexts []string) {
+	m.ScriptCounts = make(map[string]int)
+	m.PageScriptCounts = make([]map[string]int, len(pageTexts))
+	m.MixedScriptPages = 0
+
+	for i, text := range pageTexts {
+		counts := CountScripts(text)
+		m.PageScriptCounts[i] = counts
+
+		for script, count := range counts {
+			m.ScriptCounts[script] += count
+		}
+
+		if IsMixedScript(counts) {
+			m.MixedScriptPages++
+		}
+	}
+}
+
+// DominantScript returns the script with the most characters in the document
+func (m *PDFMetrics) DominantScript() string {
+	var dominant string
+	var maxCount int
+
+	for _, script := range m.SortedScripts() {
+		if m.ScriptCounts[script] > maxCount {
+			dominant = script
+			maxCount = m.ScriptCounts[script]
+		}
+	}
+
+	return dominant
+}
+
+// SortedScripts returns the scripts found in the document, most frequent first
+func (m *PDFMetrics) SortedScripts() []string {
+	scripts := make([]string, 0, len(m.ScriptCounts))
+	for script := range m.ScriptCounts {
+		scripts = append(scripts, script)
+	}
+
+	sort.Slice(scripts, func(i, j int) bool {
+		if m.ScriptCounts[scripts[i]] != m.ScriptCounts[scripts[j]] {
+			return m.ScriptCounts[scripts[i]] > m.ScriptCounts[scripts[j]]
+		}
+		return scripts[i] < scripts[j]
+	})
+
+	return scripts
+}
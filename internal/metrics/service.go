@@ -0,0 +1,184 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Upper bounds in seconds of the parse duration histogram buckets
+var parseDurationBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Filters reported by name in filter failures; any other name in a document is reported as
+// "other", so that crafted documents can't create unbounded label values
+var knownFilters = map[string]bool{
+	"FlateDecode":     true,
+	"LZWDecode":       true,
+	"ASCII85Decode":   true,
+	"ASCIIHexDecode":  true,
+	"RunLengthDecode": true,
+	"CCITTFaxDecode":  true,
+	"JBIG2Decode":     true,
+	"DCTDecode":       true,
+	"JPXDecode":       true,
+	"Crypt":           true,
+}
+
+// ServiceMetrics accumulate over the life of the process, across every document parsed, for
+// long-lived services that are scraped by Prometheus
+type ServiceMetrics struct {
+	mu                sync.Mutex
+	parsed            uint64
+	parseFailures     uint64
+	durationBuckets   []uint64 // Cumulative counts, one per parseDurationBuckets bound
+	durationSum       float64
+	pagesProcessed    uint64
+	filterFailures    map[string]uint64
+	bytesDecompressed uint64
+	objectCacheHits   uint64
+	objectCacheMisses uint64
+}
+
+// Service holds the process-wide service metrics
+var Service = &ServiceMetrics{
+	durationBuckets: make([]uint64, len(parseDurationBuckets)),
+	filterFailures:  make(map[string]uint64),
+}
+
+// RecordParse records a document parse that took duration and yielded pages, or failed
+func (m *ServiceMetrics) RecordParse(duration time.Duration, pages int, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if failed {
+		m.parseFailures++
+	} else {
+		m.parsed++
+		m.pagesProcessed += uint64(pages)
+	}
+
+	seconds := duration.Seconds()
+	m.durationSum += seconds
+	for i, bound := range parseDurationBuckets {
+		if seconds <= bound {
+			m.durationBuckets[i]++
+		}
+	}
+}
+
+// RecordFilterFailure records a stream that a filter failed to decode
+func (m *ServiceMetrics) RecordFilterFailure(filter string) {
+	filter = strings.TrimPrefix(filter, "/")
+	if !knownFilters[filter] {
+		filter = "other"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.filterFailures[filter]++
+}
+
+// RecordDecompressed records the decoded size of a stream
+func (m *ServiceMetrics) RecordDecompressed(bytes int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytesDecompressed += uint64(bytes)
+}
+
+// RecordObjectCache records lookups in the cache of objects read on demand from a file
+func (m *ServiceMetrics) RecordObjectCache(hits, misses int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objectCacheHits += uint64(hits)
+	m.objectCacheMisses += uint64(misses)
+}
+
+// ServiceSnapshot is a copy of the service metrics at one moment
+type ServiceSnapshot struct {
+	Parsed            uint64
+	ParseFailures     uint64
+	DurationBuckets   map[float64]uint64 // Cumulative count of parses by upper bound in seconds
+	DurationSum       float64            // Seconds
+	PagesProcessed    uint64
+	FilterFailures    map[string]uint64 // By filter name without the slash, or "other"
+	BytesDecompressed uint64
+	ObjectCacheHits   uint64
+	ObjectCacheMisses uint64
+}
+
+// Snapshot returns a consistent copy of the metrics
+func (m *ServiceMetrics) Snapshot() ServiceSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := ServiceSnapshot{
+		Parsed:            m.parsed,
+		ParseFailures:     m.parseFailures,
+		DurationBuckets:   make(map[float64]uint64, len(parseDurationBuckets)),
+		DurationSum:       m.durationSum,
+		PagesProcessed:    m.pagesProcessed,
+		FilterFailures:    make(map[string]uint64, len(m.filterFailures)),
+		BytesDecompressed: m.bytesDecompressed,
+		ObjectCacheHits:   m.objectCacheHits,
+		ObjectCacheMisses: m.objectCacheMisses,
+	}
+	for i, bound := range parseDurationBuckets {
+		s.DurationBuckets[bound] = m.durationBuckets[i]
+	}
+	for filter, count := range m.filterFailures {
+		s.FilterFailures[filter] = count
+	}
+	return s
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (m *ServiceMetrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP pdfex_documents_parsed_total Documents parsed, by result.\n")
+	b.WriteString("# TYPE pdfex_documents_parsed_total counter\n")
+	fmt.Fprintf(&b, "pdfex_documents_parsed_total{result=\"success\"} %d\n", m.parsed)
+	fmt.Fprintf(&b, "pdfex_documents_parsed_total{result=\"failure\"} %d\n", m.parseFailures)
+
+	b.WriteString("# HELP pdfex_parse_duration_seconds Time taken to parse a document.\n")
+	b.WriteString("# TYPE pdfex_parse_duration_seconds histogram\n")
+	for i, bound := range parseDurationBuckets {
+		fmt.Fprintf(&b, "pdfex_parse_duration_seconds_bucket{le=\"%g\"} %d\n", bound, m.durationBuckets[i])
+	}
+	count := m.parsed + m.parseFailures
+	fmt.Fprintf(&b, "pdfex_parse_duration_seconds_bucket{le=\"+Inf\"} %d\n", count)
+	fmt.Fprintf(&b, "pdfex_parse_duration_seconds_sum %g\n", m.durationSum)
+	fmt.Fprintf(&b, "pdfex_parse_duration_seconds_count %d\n", count)
+
+	b.WriteString("# HELP pdfex_pages_processed_total Pages of the documents parsed successfully.\n")
+	b.WriteString("# TYPE pdfex_pages_processed_total counter\n")
+	fmt.Fprintf(&b, "pdfex_pages_processed_total %d\n", m.pagesProcessed)
+
+	b.WriteString("# HELP pdfex_filter_failures_total Streams a filter failed to decode, by filter.\n")
+	b.WriteString("# TYPE pdfex_filter_failures_total counter\n")
+	filters := make([]string, 0, len(m.filterFailures))
+	for filter := range m.filterFailures {
+		filters = append(filters, filter)
+	}
+	sort.Strings(filters)
+	for _, filter := range filters {
+		fmt.Fprintf(&b, "pdfex_filter_failures_total{filter=%q} %d\n", filter, m.filterFailures[filter])
+	}
+
+	b.WriteString("# HELP pdfex_decompressed_bytes_total Bytes produced by decoding streams.\n")
+	b.WriteString("# TYPE pdfex_decompressed_bytes_total counter\n")
+	fmt.Fprintf(&b, "pdfex_decompressed_bytes_total %d\n", m.bytesDecompressed)
+
+	b.WriteString("# HELP pdfex_object_cache_lookups_total Lookups of objects read on demand, by result.\n")
+	b.WriteString("# TYPE pdfex_object_cache_lookups_total counter\n")
+	fmt.Fprintf(&b, "pdfex_object_cache_lookups_total{result=\"hit\"} %d\n", m.objectCacheHits)
+	fmt.Fprintf(&b, "pdfex_object_cache_lookups_total{result=\"miss\"} %d\n", m.objectCacheMisses)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
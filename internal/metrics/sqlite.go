@@ -0,0 +1,248 @@
+package metrics
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// The export writes the SQLite file format directly, so that pdfex keeps no dependencies: a
+// schema table on page 1 and a documents table B-tree, built bottom-up from full leaf pages.
+// Large pages keep every row on its leaf without overflow pages.
+const (
+	sqlitePageSize   = 65536
+	sqliteMaxPayload = sqlitePageSize - 35 // Largest record stored on a table leaf page
+	sqliteHeaderSize = 100                 // File header at the start of page 1
+	sqliteLeafTable  = 0x0d                // B-tree page types
+	sqliteInnerTable = 0x05
+
+	// Children of an interior page, whose cells take at most 13 bytes and a 2-byte pointer
+	sqliteMaxChildren = (sqlitePageSize-12)/15 + 1
+)
+
+// sqliteChild is a page of a B-tree level with the largest rowid stored under it
+type sqliteChild struct {
+	page   uint32
+	maxRow int64
+}
+
+// ExportSQLite writes the collection to a new SQLite database at path, replacing any existing
+// file, with a documents table holding one row per document and a column per metric
+func (mc *MetricsCollection) ExportSQLite(path string) error {
+	var pages [][]byte // Pages from 2 on; page 1 holds the schema and is built last
+
+	// Leaf pages of the documents table, each filled with as many rows as fit
+	var cells [][]byte
+	var leaves []sqliteChild
+	used := 8
+	flushLeaf := func(maxRow int64) {
+		pages = append(pages, sqliteBTreePage(sqliteLeafTable, 0, cells, 0))
+		leaves = append(leaves, sqliteChild{page: uint32(len(pages) + 1), maxRow: maxRow})
+		cells, used = nil, 8
+	}
+	for i, m := range mc.Metrics {
+		record, err := sqliteDocumentRecord(m)
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %v", m.Filename, err)
+		}
+		rowID := int64(i + 1)
+		cell := append(sqliteVarint(uint64(len(record))), sqliteVarint(uint64(rowID))...)
+		cell = append(cell, record...)
+		if used+len(cell)+2 > sqlitePageSize {
+			flushLeaf(rowID - 1)
+		}
+		cells = append(cells, cell)
+		used += len(cell) + 2
+	}
+	if len(cells) > 0 || len(leaves) == 0 {
+		flushLeaf(int64(len(mc.Metrics)))
+	}
+
+	// Interior levels until a single root page remains. Children are spread evenly over the
+	// fewest pages that hold them, so that every page has at least two.
+	level := leaves
+	for len(level) > 1 {
+		pageCount := (len(level) + sqliteMaxChildren - 1) / sqliteMaxChildren
+		var next []sqliteChild
+		for i := 0; i < pageCount; i++ {
+			children := level[i*len(level)/pageCount : (i+1)*len(level)/pageCount]
+			cells = nil
+			for _, child := range children[:len(children)-1] {
+				cell := binary.BigEndian.AppendUint32(nil, child.page)
+				cells = append(cells, append(cell, sqliteVarint(uint64(child.maxRow))...))
+			}
+			// The last child of the page is its right-most pointer
+			last := children[len(children)-1]
+			pages = append(pages, sqliteBTreePage(sqliteInnerTable, 0, cells, last.page))
+			next = append(next, sqliteChild{page: uint32(len(pages) + 1), maxRow: last.maxRow})
+		}
+		level = next
+	}
+
+	// The schema table on page 1 records the documents table and its root page
+	columns := make([]string, len(metricColumns))
+	for i, column := range metricColumns {
+		columns[i] = column.name + " " + column.sqlType
+	}
+	schema, err := sqliteRecord([]interface{}{
+		"table", "documents", "documents", int64(level[0].page),
+		"CREATE TABLE documents (" + strings.Join(columns, ", ") + ")",
+	})
+	if err != nil {
+		return err
+	}
+	cell := append(sqliteVarint(uint64(len(schema))), sqliteVarint(1)...)
+	first := sqliteBTreePage(sqliteLeafTable, sqliteHeaderSize, [][]byte{append(cell, schema...)}, 0)
+	writeSQLiteHeader(first, uint32(len(pages)+1))
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create database: %w", err)
+	}
+	defer file.Close()
+	for _, page := range append([][]byte{first}, pages...) {
+		if _, err := file.Write(page); err != nil {
+			return fmt.Errorf("failed to write database: %w", err)
+		}
+	}
+	return file.Close()
+}
+
+// sqliteDocumentRecord encodes the row of one document
+func sqliteDocumentRecord(m *PDFMetrics) ([]byte, error) {
+	values, err := metricValues(m)
+	if err != nil {
+		return nil, err
+	}
+	return sqliteRecord(values)
+}
+
+// sqliteRecord encodes values in the SQLite record format: a header of serial types followed by
+// the values
+func sqliteRecord(values []interface{}) ([]byte, error) {
+	var types, body []byte
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			types = append(types, sqliteVarint(uint64(len(v))*2+13)...)
+			body = append(body, v...)
+		case int:
+			serialType, data := sqliteInteger(int64(v))
+			types = append(types, sqliteVarint(serialType)...)
+			body = append(body, data...)
+		case int64:
+			serialType, data := sqliteInteger(v)
+			types = append(types, sqliteVarint(serialType)...)
+			body = append(body, data...)
+		case float64:
+			types = append(types, 7)
+			body = binary.BigEndian.AppendUint64(body, math.Float64bits(v))
+		default:
+			return nil, fmt.Errorf("unsupported column value %T", value)
+		}
+	}
+
+	// The header size counts its own varint, which takes two bytes past 127
+	headerSize := len(types) + 1
+	if headerSize > 127 {
+		headerSize++
+	}
+	record := append(sqliteVarint(uint64(headerSize)), types...)
+	record = append(record, body...)
+	if len(record) > sqliteMaxPayload {
+		return nil, fmt.Errorf("row of %d bytes is too large", len(record))
+	}
+	return record, nil
+}
+
+// sqliteInteger returns the serial type and big-endian bytes of the smallest encoding of v
+func sqliteInteger(v int64) (uint64, []byte) {
+	switch {
+	case v == 0:
+		return 8, nil
+	case v == 1:
+		return 9, nil
+	}
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(v))
+	for _, size := range []struct {
+		serialType uint64
+		bytes      int
+	}{{1, 1}, {2, 2}, {3, 3}, {4, 4}, {5, 6}} {
+		limit := int64(1) << (8*size.bytes - 1)
+		if v >= -limit && v < limit {
+			return size.serialType, buf[8-size.bytes:]
+		}
+	}
+	return 6, buf[:]
+}
+
+// sqliteVarint encodes v as a SQLite variable-length integer: big-endian groups of 7 bits with
+// the high bit set on all but the last, and a full ninth byte for the largest values
+func sqliteVarint(v uint64) []byte {
+	if v > 1<<56-1 {
+		buf := make([]byte, 9)
+		buf[8] = byte(v)
+		v >>= 8
+		for i := 7; i >= 0; i-- {
+			buf[i] = byte(v&0x7f) | 0x80
+			v >>= 7
+		}
+		return buf
+	}
+
+	var groups []byte
+	for {
+		groups = append([]byte{byte(v & 0x7f)}, groups...)
+		v >>= 7
+		if v == 0 {
+			break
+		}
+	}
+	for i := 0; i < len(groups)-1; i++ {
+		groups[i] |= 0x80
+	}
+	return groups
+}
+
+// sqliteBTreePage lays out a B-tree page: the header at offset (100 on page 1), the cell
+// pointers after it and the cells packed at the end of the page in order
+func sqliteBTreePage(pageType byte, offset int, cells [][]byte, rightMost uint32) []byte {
+	page := make([]byte, sqlitePageSize)
+	header := page[offset:]
+	headerSize := 8
+	if pageType == sqliteInnerTable {
+		headerSize = 12
+		binary.BigEndian.PutUint32(header[8:], rightMost)
+	}
+	header[0] = pageType
+	binary.BigEndian.PutUint16(header[3:], uint16(len(cells)))
+
+	content := sqlitePageSize
+	for i, cell := range cells {
+		content -= len(cell)
+		copy(page[content:], cell)
+		binary.BigEndian.PutUint16(header[headerSize+2*i:], uint16(content))
+	}
+	// A content area starting at 65536 is written as 0
+	binary.BigEndian.PutUint16(header[5:], uint16(content))
+	return page
+}
+
+// writeSQLiteHeader fills in the file header at the start of page 1
+func writeSQLiteHeader(page []byte, pageCount uint32) {
+	copy(page, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(page[16:], 1)  // Page size 65536
+	page[18], page[19] = 1, 1                 // Rollback journal
+	page[21], page[22], page[23] = 64, 32, 32 // Payload fractions
+	binary.BigEndian.PutUint32(page[24:], 1)  // File change counter
+	binary.BigEndian.PutUint32(page[28:], pageCount)
+	binary.BigEndian.PutUint32(page[40:], 1)       // Schema cookie
+	binary.BigEndian.PutUint32(page[44:], 4)       // Schema format
+	binary.BigEndian.PutUint32(page[56:], 1)       // UTF-8
+	binary.BigEndian.PutUint32(page[92:], 1)       // Version valid for the change counter
+	binary.BigEndian.PutUint32(page[96:], 3040001) // SQLite version the format follows
+}
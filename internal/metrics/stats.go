@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Distribution summarizes the values of one field across a collection
+type Distribution struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P95    float64 `json:"p95"`
+	StdDev float64 `json:"stddev"` // Population standard deviation
+}
+
+// CollectionStats summarizes the distribution of the main metrics across a collection
+type CollectionStats struct {
+	Files      int          `json:"files"`
+	ParseTime  Distribution `json:"parse_time"` // In seconds
+	FileSize   Distribution `json:"file_size"`  // In bytes
+	Pages      Distribution `json:"pages"`
+	Characters Distribution `json:"characters"`
+}
+
+// GetStatistics returns the min, max, mean, median, 95th percentile and standard deviation of
+// the parse time, file size, page count and character count across the collection
+func (mc *MetricsCollection) GetStatistics() *CollectionStats {
+	n := len(mc.Metrics)
+	parseTimes := make([]float64, n)
+	fileSizes := make([]float64, n)
+	pages := make([]float64, n)
+	characters := make([]float64, n)
+	for i, m := range mc.Metrics {
+		parseTimes[i] = m.ParseTime.Seconds()
+		fileSizes[i] = float64(m.FileSize)
+		pages[i] = float64(m.PageCount)
+		characters[i] = float64(m.CharacterCount)
+	}
+
+	return &CollectionStats{
+		Files:      n,
+		ParseTime:  distributionOf(parseTimes),
+		FileSize:   distributionOf(fileSizes),
+		Pages:      distributionOf(pages),
+		Characters: distributionOf(characters),
+	}
+}
+
+// distributionOf summarizes values, sorting them in place; it is zero for no values
+func distributionOf(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sort.Float64s(values)
+	mean, stdDev := meanAndStdDev(values)
+
+	return Distribution{
+		Min:    values[0],
+		Max:    values[len(values)-1],
+		Mean:   mean,
+		Median: percentile(values, 0.5),
+		P95:    percentile(values, 0.95),
+		StdDev: stdDev,
+	}
+}
+
+// meanAndStdDev returns the mean and population standard deviation of values
+func meanAndStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+// percentile returns the p-th quantile (0 to 1) of sorted values, interpolating linearly
+// between the closest ranks
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// SummaryFormat outputs the statistics as a human-readable table
+func (s *CollectionStats) SummaryFormat() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Statistics for %d files:\n", s.Files))
+	sb.WriteString(fmt.Sprintf("%-12s %12s %12s %12s %12s %12s %12s\n", "", "Min", "Median", "Mean", "P95", "Max", "StdDev"))
+
+	seconds := func(v float64) string {
+		return time.Duration(v * float64(time.Second)).Round(time.Microsecond).String()
+	}
+	number := func(v float64) string { return fmt.Sprintf("%.1f", v) }
+	writeDistribution(&sb, "Parse Time", s.ParseTime, seconds)
+	writeDistribution(&sb, "File Size", s.FileSize, number)
+	writeDistribution(&sb, "Pages", s.Pages, number)
+	writeDistribution(&sb, "Characters", s.Characters, number)
+
+	return sb.String()
+}
+
+// writeDistribution writes one row of the summary table
+func writeDistribution(sb *strings.Builder, name string, d Distribution, format func(float64) string) {
+	sb.WriteString(fmt.Sprintf("%-12s %12s %12s %12s %12s %12s %12s\n", name,
+		format(d.Min), format(d.Median), format(d.Mean), format(d.P95), format(d.Max), format(d.StdDev)))
+}
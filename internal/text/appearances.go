@@ -0,0 +1,31 @@
+package text
+
+import (
+	"math"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// appearanceMatrix returns the matrix that maps an annotation's appearance stream into default
+// user space: the form matrix, then the scale and translation that fit the transformed bounding
+// box into the annotation rectangle (PDF 32000-1:2008, section 12.5.5)
+func appearanceMatrix(appearance document.AnnotationAppearance) [6]float64 {
+	m, bbox, rect := appearance.Form.Matrix, appearance.Form.BBox, appearance.Rect
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, corner := range [][2]float64{{bbox[0], bbox[1]}, {bbox[2], bbox[1]}, {bbox[0], bbox[3]}, {bbox[2], bbox[3]}} {
+		x := m[0]*corner[0] + m[2]*corner[1] + m[4]
+		y := m[1]*corner[0] + m[3]*corner[1] + m[5]
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+
+	// Without a usable bounding box the appearance is only moved to the rectangle
+	if maxX <= minX || maxY <= minY {
+		return multiplyMatrix(m, [6]float64{1, 0, 0, 1, rect[0], rect[1]})
+	}
+	sx := (rect[2] - rect[0]) / (maxX - minX)
+	sy := (rect[3] - rect[1]) / (maxY - minY)
+	return multiplyMatrix(m, [6]float64{sx, 0, 0, sy, rect[0] - minX*sx, rect[1] - minY*sy})
+}
@@ -0,0 +1,318 @@
+package text
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Reordering issues reported by DiagnoseReordering
+const (
+	IssueBidi          = "bidi"           // Right-to-left text extracted in visual rather than logical order
+	IssueCombiningMark = "combining-mark" // Combining mark without a base character
+	IssuePreBaseVowel  = "pre-base-vowel" // Indic vowel sign extracted before its consonant
+)
+
+// ReorderDiagnostic describes a line whose extracted text needs reordering to be in logical order
+type ReorderDiagnostic struct {
+	Line   int    // 1-based line number on the page
+	Before string // Text as extracted
+	After  string // Text in logical order
+	Issues []string
+}
+
+// preBaseVowels are Indic vowel signs drawn to the left of their consonant but stored after it
+var preBaseVowels = map[rune]bool{
+	'ि': true, // Devanagari I
+	'ি': true, // Bengali I
+	'ে': true, // Bengali E
+	'ৈ': true, // Bengali AI
+	'ਿ': true, // Gurmukhi I
+	'િ': true, // Gujarati I
+	'େ': true, // Oriya E
+	'ெ': true, // Tamil E
+	'ே': true, // Tamil EE
+	'ை': true, // Tamil AI
+	'െ': true, // Malayalam E
+	'േ': true, // Malayalam EE
+	'ൈ': true, // Malayalam AI
+	'ෙ': true, // Sinhala KOMBUVA
+	'ේ': true, // Sinhala DIGA KOMBUVA
+	'ෛ': true, // Sinhala KOMBU DEKA
+}
+
+// mirroredPairs maps brackets to their mirror image, which right-to-left text displays
+var mirroredPairs = map[rune]rune{
+	'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '<': '>', '>': '<',
+}
+
+// glyphCluster is a base character with its combining marks, at its position on the line
+type glyphCluster struct {
+	x      float64
+	end    float64
+	runes  []rune
+	orphan bool // Starts with a combining mark
+}
+
+// Bidi classes of a cluster
+const (
+	classNeutral = iota
+	classLTR
+	classRTL
+)
+
+// DiagnoseReordering checks each line of a page for text that the extractor leaves in visual
+// order: right-to-left scripts, combining marks separated from their base and Indic pre-base
+// vowel signs. Positions must be in upright page space. Only lines that need reordering are
+// returned, with the text as extracted and in logical order.
+func DiagnoseReordering(positions []document.TextPosition) []ReorderDiagnostic {
+	var spans []document.TextPosition
+	for _, pos := range positions {
+		if pos.Text != "" && !pos.Vertical {
+			spans = append(spans, pos)
+		}
+	}
+
+	var diagnostics []ReorderDiagnostic
+	for i, line := range clusterBaselines(spans) {
+		before := lineText(line)
+		clusters := lineClusters(line)
+
+		issues := make(map[string]bool)
+		for _, c := range clusters {
+			if c.orphan {
+				issues[IssueCombiningMark] = true
+			}
+		}
+
+		clusters = attachPreBaseVowels(clusters, issues)
+		after := logicalText(clusters, issues)
+
+		if len(issues) == 0 {
+			continue
+		}
+
+		diagnostic := ReorderDiagnostic{Line: i + 1, Before: before, After: after}
+		for issue := range issues {
+			diagnostic.Issues = append(diagnostic.Issues, issue)
+		}
+		sort.Strings(diagnostic.Issues)
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	return diagnostics
+}
+
+// lineText joins the positions of a line the way extraction does, separating runs with a gap
+func lineText(line []document.TextPosition) string {
+	var sb strings.Builder
+	for i, pos := range line {
+		if i > 0 && pos.X-(line[i-1].X+line[i-1].Width) > pos.FontSize*0.2 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(pos.Text)
+	}
+	return sb.String()
+}
+
+// lineClusters splits the positions of a line into glyph clusters sorted left to right, with
+// spaces inserted between separated runs. Marks join the preceding character of their run.
+func lineClusters(line []document.TextPosition) []glyphCluster {
+	var clusters []glyphCluster
+	for i := range line {
+		pos := &line[i]
+		offset, n := 0.0, 0
+		count := utf8.RuneCountInString(pos.Text)
+		first := len(clusters)
+
+		for _, r := range pos.Text {
+			advance := runeAdvance(pos, n, count)
+			x := pos.X + offset
+			offset += advance
+			n++
+
+			if isCombiningMark(r) {
+				if len(clusters) > first {
+					c := &clusters[len(clusters)-1]
+					c.runes = append(c.runes, r)
+					c.end = x + advance
+					continue
+				}
+				clusters = append(clusters, glyphCluster{x: x, end: x + advance, runes: []rune{r}, orphan: true})
+				continue
+			}
+			clusters = append(clusters, glyphCluster{x: x, end: x + advance, runes: []rune{r}})
+		}
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].x < clusters[j].x
+	})
+
+	// Separate runs the way extraction does
+	var spaced []glyphCluster
+	for i, c := range clusters {
+		if i > 0 && c.x-clusters[i-1].end > lineFontSize(line)*0.2 && !unicode.IsSpace(c.runes[0]) {
+			spaced = append(spaced, glyphCluster{x: c.x, end: c.x, runes: []rune{' '}})
+		}
+		spaced = append(spaced, c)
+	}
+	return spaced
+}
+
+// lineFontSize returns the largest font size of a line
+func lineFontSize(line []document.TextPosition) float64 {
+	var size float64
+	for _, pos := range line {
+		if pos.FontSize > size {
+			size = pos.FontSize
+		}
+	}
+	return size
+}
+
+// isCombiningMark reports whether r is a nonspacing or enclosing combining mark
+func isCombiningMark(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me)
+}
+
+// attachPreBaseVowels moves Indic pre-base vowel signs after the consonant they precede visually
+func attachPreBaseVowels(clusters []glyphCluster, issues map[string]bool) []glyphCluster {
+	for i := 0; i+1 < len(clusters); i++ {
+		vowel := clusters[i].runes
+		if len(vowel) == 0 || !preBaseVowels[vowel[0]] || !unicode.IsLetter(clusters[i+1].runes[0]) {
+			continue
+		}
+
+		next := clusters[i+1]
+		next.x = clusters[i].x
+		next.runes = append(append([]rune{}, next.runes...), vowel...)
+		clusters = append(clusters[:i], clusters[i+1:]...)
+		clusters[i] = next
+		issues[IssuePreBaseVowel] = true
+	}
+	return clusters
+}
+
+// logicalText converts clusters in visual order into logical order using a simplified form of
+// the Unicode bidirectional algorithm with at most one embedding level. Digits read left to
+// right, and neutral characters take the direction of the surrounding text.
+func logicalText(clusters []glyphCluster, issues map[string]bool) string {
+	classes := make([]int, len(clusters))
+	var ltr, rtl int
+	for i, c := range clusters {
+		classes[i] = bidiClass(c.runes[0])
+		switch classes[i] {
+		case classLTR:
+			ltr++
+		case classRTL:
+			rtl++
+		}
+	}
+
+	visual := clustersText(clusters)
+	if rtl == 0 {
+		return visual
+	}
+
+	paragraph := classLTR
+	if rtl > ltr {
+		paragraph = classRTL
+	}
+
+	// Resolve neutrals between two runs of the same direction to that direction, others to
+	// the paragraph direction
+	for i := 0; i < len(classes); {
+		if classes[i] != classNeutral {
+			i++
+			continue
+		}
+		j := i
+		for j < len(classes) && classes[j] == classNeutral {
+			j++
+		}
+		resolved := paragraph
+		if i > 0 && j < len(classes) && classes[i-1] == classes[j] {
+			resolved = classes[j]
+		}
+		for k := i; k < j; k++ {
+			classes[k] = resolved
+		}
+		i = j
+	}
+
+	// Reverse the runs that run against the paragraph direction, then the whole line for
+	// right-to-left paragraphs
+	order := make([]int, len(clusters))
+	for i := range order {
+		order[i] = i
+	}
+	against := classRTL
+	if paragraph == classRTL {
+		against = classLTR
+	}
+	for i := 0; i < len(order); {
+		if classes[i] != against {
+			i++
+			continue
+		}
+		j := i
+		for j < len(order) && classes[j] == against {
+			j++
+		}
+		reverseInts(order[i:j])
+		i = j
+	}
+	if paragraph == classRTL {
+		reverseInts(order)
+	}
+
+	var sb strings.Builder
+	for _, i := range order {
+		for _, r := range clusters[i].runes {
+			if mirrored, ok := mirroredPairs[r]; ok && classes[i] == classRTL {
+				r = mirrored
+			}
+			sb.WriteRune(r)
+		}
+	}
+
+	logical := sb.String()
+	if logical != visual {
+		issues[IssueBidi] = true
+	}
+	return logical
+}
+
+// bidiClass returns the simplified bidirectional class of a character
+func bidiClass(r rune) int {
+	switch {
+	case unicode.IsDigit(r):
+		return classLTR
+	case unicode.In(r, unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana, unicode.Nko):
+		return classRTL
+	case unicode.IsLetter(r) || unicode.In(r, unicode.Mc):
+		return classLTR
+	default:
+		return classNeutral
+	}
+}
+
+// clustersText returns the text of clusters in their current order
+func clustersText(clusters []glyphCluster) string {
+	var sb strings.Builder
+	for _, c := range clusters {
+		sb.WriteString(string(c.runes))
+	}
+	return sb.String()
+}
+
+// reverseInts reverses a slice in place
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
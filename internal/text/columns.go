@@ -0,0 +1,215 @@
+package text
+
+import (
+	"math"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Column layouts for Options.Columns. Values of 2 or more force that many equal-width columns.
+const (
+	ColumnsOff  = 0  // Read each line across the full width of the page
+	ColumnsAuto = -1 // Detect columns from the gutters between them
+)
+
+// Column detection tuning parameters
+const (
+	gutterMaxCrossing = 0.1  // Share of lines that may cross a gutter, such as full-width titles
+	gutterMinShare    = 0.25 // Share of lines that must have text on each side of a gutter
+	gutterMinLines    = 6    // Fewest lines on a page for columns to be detected
+)
+
+// gutter is the empty vertical strip between two columns
+type gutter struct {
+	start, end float64
+}
+
+// SortColumnPositions sorts text positions in reading order for a page laid out in columns:
+// each column is read top to bottom before the next, and lines that span the columns, such as
+// titles, separate the page into bands that are read in turn. Pages where no columns are found
+// are sorted as by SortTextPositions.
+func SortColumnPositions(positions []document.TextPosition, pageWidth, pageHeight float64, columns int) {
+	if columns == ColumnsOff || columns == 1 || isVerticalText(positions) {
+		SortTextPositions(positions, pageWidth, pageHeight)
+		return
+	}
+
+	lines := clusterBaselines(positions)
+
+	var gutters []gutter
+	if columns == ColumnsAuto {
+		gutters = findGutters(lines)
+	} else {
+		gutters = equalGutters(positions, columns)
+	}
+
+	var ordered []document.TextPosition
+	band := make([][]document.TextPosition, len(gutters)+1)
+	flush := func() {
+		for i, column := range band {
+			ordered = append(ordered, column...)
+			band[i] = nil
+		}
+	}
+
+	for _, line := range lines {
+		if crossesGutter(line, gutters) {
+			flush()
+			ordered = append(ordered, line...)
+			continue
+		}
+		for _, pos := range line {
+			column := 0
+			for _, g := range gutters {
+				if pos.X >= (g.start+g.end)/2 {
+					column++
+				}
+			}
+			band[column] = append(band[column], pos)
+		}
+	}
+	flush()
+
+	copy(positions, ordered)
+}
+
+// crossesGutter reports whether a line has text running across one of the gutters
+func crossesGutter(line []document.TextPosition, gutters []gutter) bool {
+	for _, pos := range line {
+		tolerance := pos.FontSize * 0.5
+		for _, g := range gutters {
+			if pos.X < g.start-tolerance && pos.X+pos.Width > g.end+tolerance {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// equalGutters divides the horizontal extent of the text into count equal columns
+func equalGutters(positions []document.TextPosition, count int) []gutter {
+	minX, maxX, ok := horizontalExtent(positions)
+	if !ok {
+		return nil
+	}
+
+	var gutters []gutter
+	width := (maxX - minX) / float64(count)
+	for i := 1; i < count; i++ {
+		x := minX + width*float64(i)
+		gutters = append(gutters, gutter{start: x, end: x})
+	}
+	return gutters
+}
+
+// findGutters looks for vertical strips, at least a font size wide, that few lines cross and
+// that have many lines of text on each side
+func findGutters(lines [][]document.TextPosition) []gutter {
+	if len(lines) < gutterMinLines {
+		return nil
+	}
+
+	var spans []document.TextPosition
+	for _, line := range lines {
+		spans = append(spans, line...)
+	}
+	minX, maxX, ok := horizontalExtent(spans)
+	if !ok {
+		return nil
+	}
+
+	// Count the lines covering each point-wide strip of the page
+	bins := int(math.Ceil(maxX - minX))
+	covered := make([]int, bins+1)
+	for _, line := range lines {
+		seen := make([]bool, bins+1)
+		for _, pos := range line {
+			if !hasVisibleText(pos) {
+				continue
+			}
+			for b := int(pos.X - minX); b <= int(pos.X+pos.Width-minX) && b <= bins; b++ {
+				if b >= 0 && !seen[b] {
+					seen[b] = true
+					covered[b]++
+				}
+			}
+		}
+	}
+
+	minWidth := medianFontSize(spans)
+	maxCrossing := int(float64(len(lines)) * gutterMaxCrossing)
+	if maxCrossing < 2 {
+		// Allow for a title and a footer even on short pages
+		maxCrossing = 2
+	}
+
+	var gutters []gutter
+	for b := 0; b <= bins; {
+		if covered[b] > maxCrossing {
+			b++
+			continue
+		}
+		start := b
+		for b <= bins && covered[b] <= maxCrossing {
+			b++
+		}
+
+		// Strips at the edges of the text are margins, not gutters
+		if start == 0 || b > bins || float64(b-start) < minWidth {
+			continue
+		}
+
+		g := gutter{start: minX + float64(start), end: minX + float64(b)}
+		left, right := linesBesideGutter(lines, g)
+		minLines := int(float64(len(lines)) * gutterMinShare)
+		if left >= minLines && right >= minLines {
+			gutters = append(gutters, g)
+		}
+	}
+
+	return gutters
+}
+
+// linesBesideGutter counts the lines with text on the left and on the right of a gutter
+func linesBesideGutter(lines [][]document.TextPosition, g gutter) (leftCount, rightCount int) {
+	for _, line := range lines {
+		var left, right bool
+		for _, pos := range line {
+			if !hasVisibleText(pos) {
+				continue
+			}
+			if pos.X+pos.Width <= g.start+1 {
+				left = true
+			}
+			if pos.X >= g.end-1 {
+				right = true
+			}
+		}
+		if left {
+			leftCount++
+		}
+		if right {
+			rightCount++
+		}
+	}
+	return leftCount, rightCount
+}
+
+// horizontalExtent returns the leftmost and rightmost extent of the visible text
+func horizontalExtent(positions []document.TextPosition) (minX, maxX float64, ok bool) {
+	minX, maxX = math.Inf(1), math.Inf(-1)
+	for _, pos := range positions {
+		if !hasVisibleText(pos) {
+			continue
+		}
+		minX = math.Min(minX, pos.X)
+		maxX = math.Max(maxX, pos.X+pos.Width)
+	}
+	return minX, maxX, maxX > minX
+}
+
+// hasVisibleText reports whether a position shows anything other than whitespace
+func hasVisibleText(pos document.TextPosition) bool {
+	return strings.TrimSpace(pos.Text) != ""
+}
@@ -0,0 +1,84 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourusername/pdfex/internal/metrics"
+)
+
+// dictionaryWords are the most common words of English, French, German, Spanish, Italian,
+// Portuguese and Dutch. Ordinary prose is largely made of them, while text decoded through a
+// wrong encoding or mapping almost never is.
+var dictionaryWords = func() map[string]bool {
+	words := make(map[string]bool)
+	for _, list := range []string{
+		// English
+		"the be to of and a in that have i it for not on with he as you do at this but his by from " +
+			"they we say her she or an will my one all would there their what so up out if about who " +
+			"get which go me when make can like time no just him know take people into year your good " +
+			"some could them see other than then now look only come its over think also back after use " +
+			"two how our work first well way even new want because any these give day most us is are " +
+			"was were has had been more such may should each must where between under very through",
+		// French
+		"le la les de des du un une et est en que qui dans pour pas sur au aux avec ce cette il elle " +
+			"ils nous vous par plus ne se sont ont son sa ses leur mais ou comme tout être avoir fait",
+		// German
+		"der die das und ist nicht ein eine einen dem den des zu mit sich auf für von im ich sie es " +
+			"auch wird werden als bei oder nach wie aus wir hat sind war noch nur vor über dass kann",
+		// Spanish
+		"el los las del y es por con para una su se al lo más pero sus le ya o este si porque esta " +
+			"entre cuando muy sin sobre también hasta hay donde desde todo nos durante ser",
+		// Italian and Portuguese
+		"il di che è per non sono gli della delle nel nella anche come ma ha o um uma não em os da do " +
+			"dos das na no ao mais foi são pelo pela",
+		// Dutch
+		"het een van en op te dat zijn voor met niet aan er maar om ook als bij uit dan nog naar wordt",
+	} {
+		for _, word := range strings.Fields(list) {
+			words[word] = true
+		}
+	}
+	return words
+}()
+
+// qualityCounts counts the characters and words of the extracted pages that the extraction
+// quality score is computed from
+func qualityCounts(pageTexts []string) metrics.TextQualityCounts {
+	var counts metrics.TextQualityCounts
+	for _, text := range pageTexts {
+		for _, r := range text {
+			switch {
+			case unicode.IsSpace(r):
+				continue
+			case r == utf8.RuneError:
+				counts.ReplacementChars++
+			case nonPrintable(r):
+				counts.NonPrintableChars++
+			}
+			counts.Characters++
+		}
+
+		for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+			if !latinWord(word) {
+				continue
+			}
+			counts.Words++
+			if dictionaryWords[strings.ToLower(word)] {
+				counts.DictionaryWords++
+			}
+		}
+	}
+	return counts
+}
+
+// latinWord reports whether a word is written in Latin letters only
+func latinWord(word string) bool {
+	for _, r := range word {
+		if !unicode.Is(unicode.Latin, r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package text
+
+import "strings"
+
+// DiffOp is the kind of a line in an edit script
+type DiffOp string
+
+// Edit script operations
+const (
+	DiffEqual  DiffOp = "equal"  // Line present in both texts
+	DiffDelete DiffOp = "delete" // Line only in the old text
+	DiffInsert DiffOp = "insert" // Line only in the new text
+)
+
+// DiffLine is one line of an edit script
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// Largest LCS table computed; bigger inputs are treated as entirely different, which keeps
+// pathological pages from using gigabytes of memory
+const maxDiffCells = 4 << 20
+
+// DiffLines returns the edit script that turns the old lines into the new ones, built from
+// their longest common subsequence
+func DiffLines(oldLines, newLines []string) []DiffLine {
+	var script []DiffLine
+	pairs := lcsPairs(len(oldLines), len(newLines), func(i, j int) bool { return oldLines[i] == newLines[j] })
+
+	i, j := 0, 0
+	for _, pair := range append(pairs, [2]int{len(oldLines), len(newLines)}) {
+		for ; i < pair[0]; i++ {
+			script = append(script, DiffLine{Op: DiffDelete, Text: oldLines[i]})
+		}
+		for ; j < pair[1]; j++ {
+			script = append(script, DiffLine{Op: DiffInsert, Text: newLines[j]})
+		}
+		if i < len(oldLines) && j < len(newLines) {
+			script = append(script, DiffLine{Op: DiffEqual, Text: oldLines[i]})
+			i++
+			j++
+		}
+	}
+	return script
+}
+
+// Share of lines two pages must have in common to be aligned as the same, edited page
+const minPageSimilarity = 0.5
+
+// Largest number of page pairs compared for similarity in one gap between identical pages;
+// larger gaps are paired in order
+const maxSimilarityPairs = 10000
+
+// AlignPages pairs the pages of two documents from their texts. Pages with identical text
+// anchor the alignment; between anchors, pages sharing most of their lines are paired next,
+// then the rest in order, and the surplus on either side is unpaired. Each pair holds 0-based
+// page indexes, -1 for a page only in one document.
+func AlignPages(oldPages, newPages []string) [][2]int {
+	identical := lcsPairs(len(oldPages), len(newPages), func(i, j int) bool { return oldPages[i] == newPages[j] })
+
+	var pairs [][2]int
+	i, j := 0, 0
+	for _, anchor := range append(identical, [2]int{len(oldPages), len(newPages)}) {
+		pairs = append(pairs, alignSimilarPages(oldPages[i:anchor[0]], newPages[j:anchor[1]], i, j)...)
+		i, j = anchor[0], anchor[1]
+		if i < len(oldPages) && j < len(newPages) {
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		}
+	}
+	return pairs
+}
+
+// alignSimilarPages pairs the pages between two identical anchors, offsetting the indexes by
+// the position of the gap in each document
+func alignSimilarPages(oldPages, newPages []string, oldOffset, newOffset int) [][2]int {
+	compare := len(oldPages)*len(newPages) <= maxSimilarityPairs
+	similar := make([][]bool, len(oldPages))
+	for i := range oldPages {
+		similar[i] = make([]bool, len(newPages))
+		if !compare {
+			continue
+		}
+		oldLines := strings.Split(oldPages[i], "\n")
+		for j := range newPages {
+			similar[i][j] = pageSimilarity(oldLines, strings.Split(newPages[j], "\n")) >= minPageSimilarity
+		}
+	}
+	anchors := lcsPairs(len(oldPages), len(newPages), func(i, j int) bool { return similar[i][j] })
+
+	var pairs [][2]int
+	i, j := 0, 0
+	for _, anchor := range append(anchors, [2]int{len(oldPages), len(newPages)}) {
+		for ; i < anchor[0] && j < anchor[1]; i, j = i+1, j+1 {
+			pairs = append(pairs, [2]int{oldOffset + i, newOffset + j})
+		}
+		for ; i < anchor[0]; i++ {
+			pairs = append(pairs, [2]int{oldOffset + i, -1})
+		}
+		for ; j < anchor[1]; j++ {
+			pairs = append(pairs, [2]int{-1, newOffset + j})
+		}
+		if i < len(oldPages) && j < len(newPages) {
+			pairs = append(pairs, [2]int{oldOffset + i, newOffset + j})
+			i++
+			j++
+		}
+	}
+	return pairs
+}
+
+// pageSimilarity returns the share of lines two pages have in common, from 0 to 1
+func pageSimilarity(oldLines, newLines []string) float64 {
+	if len(oldLines)+len(newLines) == 0 {
+		return 1
+	}
+	common := lcsPairs(len(oldLines), len(newLines), func(i, j int) bool { return oldLines[i] == newLines[j] })
+	return 2 * float64(len(common)) / float64(len(oldLines)+len(newLines))
+}
+
+// lcsPairs returns the index pairs of a longest common subsequence of two sequences of
+// lengths n and m, in order
+func lcsPairs(n, m int, equal func(i, j int) bool) [][2]int {
+	if n == 0 || m == 0 || n*m > maxDiffCells {
+		return nil
+	}
+
+	// lengths[i][j] is the LCS length of the suffixes starting at i and j
+	lengths := make([][]int, n+1)
+	for i := range lengths {
+		lengths[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if equal(i, j) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case equal(i, j):
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
@@ -1,347 +1,775 @@
 package text
 
 import (
-	//	"bytes"
-	"regexp"
+	"log/slog"
+	"math"
+	"strconv"
 	"strings"
 
+	"github.com/yourusername/pdfex/internal/content"
 	"github.com/yourusername/pdfex/internal/document"
 	"github.com/yourusername/pdfex/internal/utils"
 )
 
+// Options controls optional processing applied during text extraction
+type Options struct {
+	Normalization NormalizationMode // Unicode normalization applied after decoding
+	Columns       int               // Column layout: ColumnsOff, ColumnsAuto or a forced column count
+
+	// Remove running headers, footers and page numbers when extracting the text of all pages
+	StripRunningLines bool
+
+	// Leave out text in marked content of optional content groups (layers) that are hidden in
+	// the default configuration
+	ExcludeHiddenLayers bool
+
+	// Leave out text whose origin lies outside the page's crop box, which viewers don't show
+	ClipToCropBox bool
+
+	// Also extract the text of annotation appearance streams, such as free-text comments and
+	// filled-in form fields, where viewers draw it over the page
+	IncludeAnnotations bool
+
+	// Recognizes the text of pages showing next to no glyphs, such as scans, merging it into
+	// their text positions; nil to leave them without text
+	OCR PageRecognizer
+
+	// Re-decode the text of pages that look garbled, such as UTF-8 shown through a wrong
+	// ToUnicode map as single-byte text, when another decoding is clearly more plausible
+	RepairMojibake bool
+
+	// Newline policy, space collapsing and control-character stripping of the page texts
+	Whitespace WhitespaceOptions
+}
+
 // Extractor handles text extraction from PDF content
 type Extractor struct {
-	Pages []document.PDFPage
-	Fonts map[string]document.PDFFont
+	Pages   []document.PDFPage
+	Fonts   map[string]document.PDFFont
+	Options Options
+
+	// Distinct running lines removed by ExtractText when Options.StripRunningLines is set
+	RunningLines []string
+
+	// Receives warnings about malformed content; nil for utils.DefaultLogger
+	Logger *slog.Logger
+
+	hidden *hiddenTextScan // Set while FindHiddenText extracts a page
+}
+
+// logger returns the logger for warnings about malformed content
+func (e *Extractor) logger() *slog.Logger {
+	if e.Logger == nil {
+		return utils.DefaultLogger()
+	}
+	return e.Logger
 }
 
 // NewExtractor creates a new text extractor
 func NewExtractor(pages []document.PDFPage, fonts map[string]document.PDFFont) *Extractor {
+	return NewExtractorWithOptions(pages, fonts, Options{})
+}
+
+// NewExtractorWithOptions creates a new text extractor with the specified options
+func NewExtractorWithOptions(pages []document.PDFPage, fonts map[string]document.PDFFont, options Options) *Extractor {
 	return &Extractor{
-		Pages: pages,
-		Fonts: fonts,
+		Pages:   pages,
+		Fonts:   fonts,
+		Options: options,
 	}
 }
 
+// NewDocumentExtractor creates a text extractor for the pages and fonts of a document, logging
+// to the document's logger
+func NewDocumentExtractor(doc *document.PDFDocument, options Options) *Extractor {
+	extractor := NewExtractorWithOptions(doc.Pages, doc.Fonts, options)
+	extractor.Logger = doc.Logger()
+	return extractor
+}
+
 // ExtractText extracts text from all pages
 func (e *Extractor) ExtractText() []string {
-	var results []string
+	for i := range e.Pages {
+		e.extractTextWithPositioning(&e.Pages[i])
+	}
+
+	// Running lines are only recognisable by comparing pages
+	if e.Options.StripRunningLines {
+		e.RunningLines = StripRunningLines(e.Pages)
+	}
 
+	var results []string
 	for i := range e.Pages {
-		text := e.extractTextFromPage(&e.Pages[i])
-		results = append(results, text)
+		results = append(results, e.pageText(&e.Pages[i]))
 	}
 
 	return results
 }
 
+// EachPage extracts the pages one at a time, passing the text of each to fn and then releasing
+// its text positions, so that memory for positions stays that of one page. Extraction stops at
+// the first error from fn. Running lines are not stripped, since they are only recognisable by
+// comparing pages.
+func (e *Extractor) EachPage(fn func(pageNum int, text string) error) error {
+	for i := range e.Pages {
+		page := &e.Pages[i]
+		text := e.extractTextFromPage(page)
+		page.TextPositions = nil
+		if err := fn(i+1, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExtractPage extracts the text of a single page (1-based), filling in its text positions
+func (e *Extractor) ExtractPage(pageNum int) string {
+	if pageNum < 1 || pageNum > len(e.Pages) {
+		return ""
+	}
+	return e.extractTextFromPage(&e.Pages[pageNum-1])
+}
+
 // extractTextFromPage extracts text from a page using content stream operators
 func (e *Extractor) extractTextFromPage(page *document.PDFPage) string {
 	// Extract text positions from content stream
 	e.extractTextWithPositioning(page)
 
 	// Generate ordered text from positions
-	return page.ExtractOrderedText()
+	return e.pageText(page)
+}
+
+// Maximum nesting depth of form XObjects followed during extraction
+const maxFormDepth = 32
+
+// Default glyph width in text space units (thousandths of an em divided by 1000),
+// used when the font declares no widths
+const defaultGlyphWidth = 0.6
+
+// Default vertical displacement of a glyph in vertical writing mode (the default /DW2 w1 of -1000)
+const defaultVerticalAdvance = -1.0
+
+// textState holds the text state parameters described in PDF 32000-1:2008, section 9.3
+type textState struct {
+	Tm           [6]float64 // Text matrix
+	Tlm          [6]float64 // Text line matrix
+	FontSize     float64    // Tfs, set by Tf
+	FontName     string     // Font resource name, set by Tf
+	Leading      float64    // TL, set by TL, TD and "
+	CharSpacing  float64    // Tc, set by Tc and "
+	WordSpacing  float64    // Tw, set by Tw and "
+	HorizScaling float64    // Th, set by Tz (stored as a fraction, 100% = 1.0)
+	Rise         float64    // Ts, set by Ts
+	RenderMode   int        // Tr, set by Tr: 0 fills glyphs, 3 paints nothing
+}
+
+// graphicsState holds the parts of the graphics state that affect text placement and visibility
+type graphicsState struct {
+	CTM       [6]float64 // Current transformation matrix, set by cm
+	Text      textState  // Text state parameters, saved and restored together with the CTM
+	FillColor Color      // Nonstroking colour, set by g, rg, k, sc and scn
+}
+
+// newTextState returns a text state initialised to the PDF defaults
+func newTextState() *textState {
+	return &textState{
+		Tm:           identityMatrix,
+		Tlm:          identityMatrix,
+		HorizScaling: 1.0,
+	}
+}
+
+// identityMatrix is the identity transformation matrix [1 0 0 1 0 0]
+var identityMatrix = [6]float64{1, 0, 0, 1, 0, 0}
+
+// unitMatrix returns the initial CTM of a page, which maps default user space to points
+func unitMatrix(page *document.PDFPage) [6]float64 {
+	unit := page.Unit()
+	return [6]float64{unit, 0, 0, unit, 0, 0}
+}
+
+// multiplyMatrix returns the product m1 × m2 of two PDF transformation matrices
+func multiplyMatrix(m1, m2 [6]float64) [6]float64 {
+	return [6]float64{
+		m1[0]*m2[0] + m1[1]*m2[2],
+		m1[0]*m2[1] + m1[1]*m2[3],
+		m1[2]*m2[0] + m1[3]*m2[2],
+		m1[2]*m2[1] + m1[3]*m2[3],
+		m1[4]*m2[0] + m1[5]*m2[2] + m2[4],
+		m1[4]*m2[1] + m1[5]*m2[3] + m2[5],
+	}
+}
+
+// moveTextPosition implements Td: Tm = Tlm = [1 0 0 1 tx ty] × Tlm
+func (ts *textState) moveTextPosition(tx, ty float64) {
+	ts.Tlm = multiplyMatrix([6]float64{1, 0, 0, 1, tx, ty}, ts.Tlm)
+	ts.Tm = ts.Tlm
+}
+
+// nextLine implements T*: move to the start of the next line using the current leading
+func (ts *textState) nextLine() {
+	ts.moveTextPosition(0, -ts.Leading)
+}
+
+// advance moves the text matrix horizontally by tx text space units
+func (ts *textState) advance(tx float64) {
+	ts.Tm = multiplyMatrix([6]float64{1, 0, 0, 1, tx, 0}, ts.Tm)
+}
+
+// renderingMatrix returns the text rendering matrix Trm = [Tfs×Th 0 0 Tfs 0 0] × Tm × CTM.
+// Text rise is left out and reported separately, so superscripts stay on their baseline.
+func (ts *textState) renderingMatrix(ctm [6]float64) [6]float64 {
+	params := [6]float64{ts.FontSize * ts.HorizScaling, 0, 0, ts.FontSize, 0, 0}
+	return multiplyMatrix(params, multiplyMatrix(ts.Tm, ctm))
+}
+
+// advanceVertical moves the text matrix vertically by ty text space units
+func (ts *textState) advanceVertical(ty float64) {
+	ts.Tm = multiplyMatrix([6]float64{1, 0, 0, 1, 0, ty}, ts.Tm)
+}
+
+// advanceGlyph moves the text matrix past a single glyph, horizontally or along Y in
+// vertical writing mode (PDF 32000-1:2008, 9.4.4)
+func (ts *textState) advanceGlyph(code int, font document.PDFFont) {
+	spacing := ts.CharSpacing
+	// Word spacing applies to the single-byte code 32 only
+	if code == ' ' && !isCompositeFont(font) {
+		spacing += ts.WordSpacing
+	}
+
+	if font.WritingMode == 1 {
+		ts.advanceVertical(defaultVerticalAdvance*ts.FontSize + spacing)
+		return
+	}
+	ts.advance((glyphWidth(code, font)*ts.FontSize + spacing) * ts.HorizScaling)
+}
+
+// glyphWidth returns the horizontal advance of a glyph in text space units (fractions of the
+// font size), from the font's widths when it has them
+func glyphWidth(code int, font document.PDFFont) float64 {
+	if isCompositeFont(font) {
+		if w, ok := font.CIDWidths[code]; ok {
+			return w / 1000
+		}
+		if font.DefaultWidth > 0 {
+			return font.DefaultWidth / 1000
+		}
+		return defaultGlyphWidth
+	}
+
+	if i := code - font.FirstChar; i >= 0 && i < len(font.Widths) {
+		return font.Widths[i] / 1000
+	}
+	if font.DefaultWidth > 0 {
+		return font.DefaultWidth / 1000
+	}
+	return defaultGlyphWidth
+}
+
+// adjust applies a TJ position adjustment, given in thousandths of a text space unit
+func (ts *textState) adjust(amount float64, font document.PDFFont) {
+	if font.WritingMode == 1 {
+		ts.advanceVertical(-amount / 1000 * ts.FontSize)
+		return
+	}
+	ts.advance(-amount / 1000 * ts.FontSize * ts.HorizScaling)
 }
 
 // extractTextWithPositioning extracts text with positioning information
 func (e *Extractor) extractTextWithPositioning(page *document.PDFPage) {
 	var textPositions []document.TextPosition
-	var textState struct {
-		Tm       [6]float64 // Text matrix
-		Tlm      [6]float64 // Text line matrix
-		FontSize float64
-		FontName string
-		Leading  float64 // Text leading
-	}
-
-	// Initialize matrices to identity
-	textState.Tm = [6]float64{1, 0, 0, 1, 0, 0}
-	textState.Tlm = [6]float64{1, 0, 0, 1, 0, 0}
-
-	// Find text objects
-	textRegex := regexp.MustCompile(`BT(.*?)ET`)
-	textMatches := textRegex.FindAll(page.Contents, -1)
-
-	for _, textBlock := range textMatches {
-		// Reset text state for each text block
-		textState.Tm = [6]float64{1, 0, 0, 1, 0, 0}
-		textState.Tlm = [6]float64{1, 0, 0, 1, 0, 0}
-
-		// Find Tm (text matrix) operators: a b c d e f Tm
-		tmRegex := regexp.MustCompile(`([-+]?[0-9]*\.?[0-9]+)\s+([-+]?[0-9]*\.?[0-9]+)\s+([-+]?[0-9]*\.?[0-9]+)\s+([-+]?[0-9]*\.?[0-9]+)\s+([-+]?[0-9]*\.?[0-9]+)\s+([-+]?[0-9]*\.?[0-9]+)\s+Tm`)
-		tmMatches := tmRegex.FindAllSubmatch(textBlock, -1)
-
-		for _, tmMatch := range tmMatches {
-			// Update text matrix
-			for i := 0; i < 6; i++ {
-				val, err := utils.ParseFloat(string(tmMatch[i+1]))
+	var stateStack []graphicsState
+	gs := graphicsState{CTM: unitMatrix(page), Text: *newTextState()}
+	state := &gs.Text
+	coverage := &glyphCoverage{fonts: make(map[string]int)}
+
+	// show records the text shown by a string operand, unless it is on a hidden layer
+	show := func(operand string) {
+		pos := e.showText(state, gs.CTM, coverage, operand)
+		if !e.Options.ExcludeHiddenLayers || !coverage.inHiddenContent() {
+			textPositions = append(textPositions, pos)
+			if e.hidden != nil {
+				e.hidden.text(pos, state.RenderMode, gs.FillColor)
+			}
+		}
+	}
+
+	// run interprets a content stream, the page's or that of a form XObject it draws, looking up
+	// forms and optional content by name in the stream's resources
+	active := make(map[int]bool) // Forms being drawn, to stop cycles
+	var run func(contents []byte, xobjects map[string]*document.FormXObject, hiddenContent map[string]bool)
+	run = func(contents []byte, xobjects map[string]*document.FormXObject, hiddenContent map[string]bool) {
+		for _, op := range content.ParseOperations(contents) {
+			operands := op.Operands
+
+			switch op.Operator {
+			case "q":
+				stateStack = append(stateStack, gs)
+
+			case "Q":
+				if len(stateStack) == 0 {
+					utils.Warnf(e.logger(), "Unbalanced Q operator on page %d\n", page.PageNumber)
+					continue
+				}
+				gs = stateStack[len(stateStack)-1]
+				stateStack = stateStack[:len(stateStack)-1]
+
+			case "cm":
+				values, ok := parseNumericOperands(e.logger(), operands, 6)
+				if !ok {
+					continue
+				}
+				var m [6]float64
+				copy(m[:], values)
+				gs.CTM = multiplyMatrix(m, gs.CTM)
+
+			case "g", "rg", "k", "sc", "scn":
+				gs.FillColor = parseColor(operands, gs.FillColor)
+
+			case "cs":
+				gs.FillColor = Color{}
+
+			case "re", "m", "l", "c", "v", "y", "f", "F", "f*", "B", "B*", "b", "b*", "S", "s", "n":
+				if e.hidden != nil {
+					e.hidden.pathOperator(op.Operator, operands, gs.CTM, gs.FillColor)
+				}
+
+			case "BMC", "BDC":
+				// Only inline property lists are checked for /ActualText and /MCID, not named resources
+				seq := markedSequence{mcid: -1}
+				// Optional content refers to a group or membership dictionary by resource name
+				if op.Operator == "BDC" && len(operands) == 2 && operands[0] == "/OC" {
+					seq.hidden = hiddenContent[resourceName(operands[1])]
+				}
+				if op.Operator == "BDC" && len(operands) > 0 && utils.IsDictionary(operands[len(operands)-1]) {
+					props := []byte(operands[len(operands)-1])
+					seq.actualText = utils.DictionaryValue(props, "ActualText") != ""
+					// MCIDs inside forms refer to the form's own structure, which isn't read
+					if mcid, err := strconv.Atoi(utils.DictionaryValue(props, "MCID")); err == nil && len(active) == 0 {
+						seq.mcid = mcid
+					}
+				}
+				coverage.markedContent = append(coverage.markedContent, seq)
+
+			case "EMC":
+				if n := len(coverage.markedContent); n > 0 {
+					coverage.markedContent = coverage.markedContent[:n-1]
+				}
+
+			case "Do":
+				if len(operands) < 1 {
+					continue
+				}
+				form := xobjects[resourceName(operands[0])]
+				if form == nil || e.Options.ExcludeHiddenLayers && form.Hidden {
+					continue
+				}
+				if active[form.ObjectNumber] || len(active) >= maxFormDepth {
+					utils.Warnf(e.logger(), "Not following form XObject %d on page %d: it draws itself or is nested too deeply\n", form.ObjectNumber, page.PageNumber)
+					continue
+				}
+
+				// A form is drawn in a graphics state of its own, with its matrix applied
+				saved, savedStack, depth := gs, stateStack, len(coverage.markedContent)
+				gs.CTM = multiplyMatrix(form.Matrix, gs.CTM)
+				stateStack = nil
+				active[form.ObjectNumber] = true
+				run(form.Contents, form.XObjects, form.HiddenContent)
+				delete(active, form.ObjectNumber)
+				gs, stateStack = saved, savedStack
+				coverage.markedContent = coverage.markedContent[:depth]
+
+			case "BT":
+				// Text matrices are reset at the start of each text object
+				state.Tm = identityMatrix
+				state.Tlm = identityMatrix
+
+			case "Tf":
+				if len(operands) < 2 {
+					continue
+				}
+				fontSize, err := utils.ParseFloat(operands[1])
 				if err != nil {
-					utils.Logf(utils.LogWarning, "Invalid text matrix value: %v\n", err)
+					utils.Warnf(e.logger(), "Invalid font size: %v\n", err)
 					continue
 				}
-				textState.Tm[i] = val
-				textState.Tlm[i] = val
-			}
-		}
+				state.FontName = resourceName(operands[0])
+				state.FontSize = fontSize
 
-		// Find Td (text displacement) operators: tx ty Td
-		tdRegex := regexp.MustCompile(`([-+]?[0-9]*\.?[0-9]+)\s+([-+]?[0-9]*\.?[0-9]+)\s+Td`)
-		tdMatches := tdRegex.FindAllSubmatch(textBlock, -1)
+			case "Tr":
+				values, ok := parseNumericOperands(e.logger(), operands, 1)
+				if !ok {
+					continue
+				}
+				state.RenderMode = int(values[0])
 
-		for _, tdMatch := range tdMatches {
-			tx, err := utils.ParseFloat(string(tdMatch[1]))
-			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid tx value: %v\n", err)
-				continue
+			case "Tc", "Tw", "Tz", "TL", "Ts":
+				values, ok := parseNumericOperands(e.logger(), operands, 1)
+				if !ok {
+					continue
+				}
+				switch op.Operator {
+				case "Tc":
+					state.CharSpacing = values[0]
+				case "Tw":
+					state.WordSpacing = values[0]
+				case "Tz":
+					state.HorizScaling = values[0] / 100
+				case "TL":
+					state.Leading = values[0]
+				case "Ts":
+					state.Rise = values[0]
+				}
+
+			case "Tm":
+				values, ok := parseNumericOperands(e.logger(), operands, 6)
+				if !ok {
+					continue
+				}
+				copy(state.Tm[:], values)
+				state.Tlm = state.Tm
+
+			case "Td", "TD":
+				values, ok := parseNumericOperands(e.logger(), operands, 2)
+				if !ok {
+					continue
+				}
+				if op.Operator == "TD" {
+					state.Leading = -values[1]
+				}
+				state.moveTextPosition(values[0], values[1])
+
+			case "T*":
+				state.nextLine()
+
+			case "Tj":
+				if len(operands) < 1 {
+					continue
+				}
+				show(operands[len(operands)-1])
+
+			case "'":
+				if len(operands) < 1 {
+					continue
+				}
+				state.nextLine()
+				show(operands[len(operands)-1])
+
+			case "\"":
+				if len(operands) < 3 {
+					continue
+				}
+				values, ok := parseNumericOperands(e.logger(), operands[:2], 2)
+				if !ok {
+					continue
+				}
+				state.WordSpacing = values[0]
+				state.CharSpacing = values[1]
+				state.nextLine()
+				show(operands[2])
+
+			case "TJ":
+				if len(operands) < 1 {
+					continue
+				}
+				for _, item := range content.ParseArrayOperand(operands[len(operands)-1]) {
+					if strings.HasPrefix(item, "(") || strings.HasPrefix(item, "<") {
+						show(item)
+						continue
+					}
+
+					// Numbers adjust the position by thousandths of a text space unit
+					adjustment, err := utils.ParseFloat(item)
+					if err != nil {
+						utils.Warnf(e.logger(), "Invalid TJ adjustment: %v\n", err)
+						continue
+					}
+					state.adjust(adjustment, e.currentFont(state.FontName))
+				}
 			}
+		}
+	}
+	run(page.Contents, page.XObjects, page.HiddenContent)
 
-			ty, err := utils.ParseFloat(string(tdMatch[2]))
-			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid ty value: %v\n", err)
+	// Annotation appearances are drawn after the page content, each fitted into its rectangle
+	if e.Options.IncludeAnnotations {
+		for _, appearance := range page.Appearances {
+			form := appearance.Form
+			if e.Options.ExcludeHiddenLayers && (appearance.Hidden || form.Hidden) {
 				continue
 			}
-
-			// Update text matrix: Tlm = [1 0 0 1 tx ty] × Tlm
-			textState.Tlm[4] += tx
-			textState.Tlm[5] += ty
-
-			// Set Tm = Tlm
-			copy(textState.Tm[:], textState.Tlm[:])
+			gs = graphicsState{CTM: multiplyMatrix(appearanceMatrix(appearance), unitMatrix(page)), Text: *newTextState()}
+			stateStack = nil
+			coverage.markedContent = nil
+			active[form.ObjectNumber] = true
+			run(form.Contents, form.XObjects, form.HiddenContent)
+			delete(active, form.ObjectNumber)
 		}
+	}
 
-		// Find TD (text displacement with leading) operators: tx ty TD
-		tdRegex = regexp.MustCompile(`([-+]?[0-9]*\.?[0-9]+)\s+([-+]?[0-9]*\.?[0-9]+)\s+TD`)
-		tdMatches = tdRegex.FindAllSubmatch(textBlock, -1)
+	page.GlyphCount = coverage.shown
+	page.MappedGlyphCount = coverage.mapped
+	page.FontGlyphs = coverage.fonts
 
-		for _, tdMatch := range tdMatches {
-			tx, err := utils.ParseFloat(string(tdMatch[1]))
+	// The engine runs once per page however often the page is extracted, as it may be slow or
+	// paid for by the call
+	if e.Options.OCR != nil && e.hidden == nil && coverage.shown <= maxScanCharacters {
+		if !page.OCRRecognized {
+			recognized, err := e.Options.OCR.RecognizePage(page)
 			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid tx value: %v\n", err)
-				continue
+				utils.Warnf(e.logger(), "OCR of page %d failed: %v\n", page.PageNumber, err)
 			}
+			page.OCRPositions, page.OCRRecognized = recognized, true
+		}
+		textPositions = append(textPositions, page.OCRPositions...)
+	}
 
-			ty, err := utils.ParseFloat(string(tdMatch[2]))
-			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid ty value: %v\n", err)
-				continue
+	if e.Options.RepairMojibake {
+		RepairMojibake(textPositions)
+	}
+
+	if crop := page.Boxes.CropBox; e.Options.ClipToCropBox && crop[2] > crop[0] && crop[3] > crop[1] {
+		visible := textPositions[:0]
+		for _, pos := range textPositions {
+			if document.BoxContains(crop, pos.X, pos.Y) {
+				visible = append(visible, pos)
 			}
+		}
+		textPositions = visible
+	}
 
-			// Set leading and update text matrix
-			textState.Leading = -ty
+	// Map positions into upright page space, then sort them by reading order
+	width, height, rotation := NormalizeRotation(textPositions, page.Rotation, page.Width, page.Height)
+	SortColumnPositions(textPositions, width, height, e.Options.Columns)
 
-			// Update text matrix: Tlm = [1 0 0 1 tx ty] × Tlm
-			textState.Tlm[4] += tx
-			textState.Tlm[5] += ty
+	page.TextPositions = textPositions
+	page.TextRotation = rotation
+}
 
-			// Set Tm = Tlm
-			copy(textState.Tm[:], textState.Tlm[:])
-		}
+// glyphCoverage counts the glyphs shown on a page, those with authoritative Unicode values and
+// those shown with each font
+type glyphCoverage struct {
+	shown, mapped int
+	fonts         map[string]int
+	markedContent []markedSequence // Open marked-content sequences, innermost last
+}
 
-		// Find T* (next line) operator
-		tStarRegex := regexp.MustCompile(`T\*`)
-		tStarMatches := tStarRegex.FindAll(textBlock, -1)
+// markedSequence is an open marked-content sequence (BMC or BDC ... EMC)
+type markedSequence struct {
+	actualText bool // Has an /ActualText replacement
+	mcid       int  // Marked-content identifier linking it to the structure tree, -1 if none
+	hidden     bool // Optional content hidden in the default configuration
+}
 
-		for range tStarMatches {
-			// Move to next line: Tlm = [1 0 0 1 0 -TL] × Tlm
-			textState.Tlm[5] -= textState.Leading
+// inActualText reports whether the current glyphs are covered by an /ActualText replacement
+func (c *glyphCoverage) inActualText() bool {
+	for _, seq := range c.markedContent {
+		if seq.actualText {
+			return true
+		}
+	}
+	return false
+}
 
-			// Set Tm = Tlm
-			copy(textState.Tm[:], textState.Tlm[:])
+// inHiddenContent reports whether the current glyphs are on a hidden layer
+func (c *glyphCoverage) inHiddenContent() bool {
+	for _, seq := range c.markedContent {
+		if seq.hidden {
+			return true
 		}
+	}
+	return false
+}
+
+// mcid returns the identifier of the innermost marked-content sequence that has one, or -1
+func (c *glyphCoverage) mcid() int {
+	for i := len(c.markedContent) - 1; i >= 0; i-- {
+		if c.markedContent[i].mcid >= 0 {
+			return c.markedContent[i].mcid
+		}
+	}
+	return -1
+}
 
-		// Find font operators: /Font size Tf
-		fontRegex := regexp.MustCompile(`/([A-Za-z0-9]+)\s+([-+]?[0-9]*\.?[0-9]+)\s+Tf`)
-		fontMatches := fontRegex.FindAllSubmatch(textBlock, -1)
+// showText decodes a string operand, records its position and advances the text matrix.
+// Positions are reported in user space, i.e. after applying the text rendering matrix.
+func (e *Extractor) showText(state *textState, ctm [6]float64, coverage *glyphCoverage, operand string) document.TextPosition {
+	raw, err := utils.DecodePDFString(operand)
+	if err != nil {
+		utils.Warnf(e.logger(), "Invalid string operand: %v\n", err)
+	}
 
-		for _, fontMatch := range fontMatches {
-			fontName := string(fontMatch[1])
-			fontSize, err := utils.ParseFloat(string(fontMatch[2]))
-			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid font size: %v\n", err)
-				continue
-			}
+	font := e.currentFont(state.FontName)
+	codes := characterCodes(raw, font)
+	coverage.fonts[e.fontKey(state.FontName)] += len(codes)
+
+	trm := state.renderingMatrix(ctm)
+	pos := document.TextPosition{
+		X:        trm[4],
+		Y:        trm[5],
+		FontSize: math.Hypot(trm[2], trm[3]),
+		FontName: state.FontName,
+		Rise:     state.Rise,
+		Angle:    math.Atan2(trm[1], trm[0]) * 180 / math.Pi,
+		Vertical: font.WritingMode == 1,
+		MCID:     coverage.mcid(),
+	}
 
-			textState.FontName = fontName
-			textState.FontSize = fontSize
+	// Decode and advance glyph by glyph, recording the width of each resulting rune. A glyph
+	// that normalizes to several runes, such as a ligature, shares its width between them.
+	actualText := coverage.inActualText()
+	var text strings.Builder
+	prev := trm
+	for _, code := range codes {
+		coverage.shown++
+		if actualText || font.UnicodeMapped[code] {
+			coverage.mapped++
 		}
 
-		// Process text showing operators
-		tjRegex := regexp.MustCompile(`\((.*?)\)\s+Tj`)
-		tjMatches := tjRegex.FindAllSubmatchIndex(textBlock, -1)
-
-		for _, match := range tjMatches {
-			textBytes := textBlock[match[2]:match[3]]
-
-			// Get current font
-			var currentFont document.PDFFont
-			fontKey := "/" + textState.FontName
-			if font, ok := e.Fonts[fontKey]; ok {
-				currentFont = font
-			} else {
-				// Use default font if not found
-				fontKey = "/DefaultFont"
-				if font, ok := e.Fonts[fontKey]; ok {
-					currentFont = font
-				}
-			}
+		glyph := []rune(NormalizeText(decodeText([]int{code}, font), e.Options.Normalization))
+		state.advanceGlyph(code, font)
 
-			// Decode text
-			text := decodeText(textBytes, currentFont)
+		next := state.renderingMatrix(ctm)
+		advance := math.Hypot(next[4]-prev[4], next[5]-prev[5])
+		prev = next
 
-			// Create text position entry
-			pos := document.TextPosition{
-				X:        textState.Tm[4],
-				Y:        textState.Tm[5],
-				FontSize: textState.FontSize,
-				Text:     text,
-				FontName: textState.FontName,
-			}
+		for _, r := range glyph {
+			text.WriteRune(r)
+			pos.CharWidths = append(pos.CharWidths, advance/float64(len(glyph)))
+		}
+	}
 
-			textPositions = append(textPositions, pos)
+	pos.Text = text.String()
+	pos.Width = math.Hypot(prev[4]-trm[4], prev[5]-trm[5])
 
-			// Advance text position based on the width of the text
-			// This is simplified - in reality, we'd need to calculate
-			// the actual width based on font metrics
-			textState.Tm[4] += float64(len(text)) * textState.FontSize * 0.6
-		}
+	return pos
+}
 
-		// Handle TJ operator
-		tjArrayRegex := regexp.MustCompile(`\[(.*?)\]\s+TJ`)
-		tjArrayMatches := tjArrayRegex.FindAllSubmatch(textBlock, -1)
-
-		for _, tjArrayMatch := range tjArrayMatches {
-			tjArray := tjArrayMatch[1]
-
-			// Extract string parts from the TJ array
-			stringRegex := regexp.MustCompile(`\((.*?)\)`)
-			stringMatches := stringRegex.FindAllSubmatchIndex(tjArray, -1)
-
-			for _, match := range stringMatches {
-				textBytes := tjArray[match[2]:match[3]]
-
-				// Get current font
-				var currentFont document.PDFFont
-				fontKey := "/" + textState.FontName
-				if font, ok := e.Fonts[fontKey]; ok {
-					currentFont = font
-				} else {
-					// Use default font if not found
-					fontKey = "/DefaultFont"
-					if font, ok := e.Fonts[fontKey]; ok {
-						currentFont = font
-					}
-				}
+// currentFont returns the font for a resource name, falling back to the default font
+func (e *Extractor) currentFont(fontName string) document.PDFFont {
+	return e.Fonts[e.fontKey(fontName)]
+}
 
-				// Decode text
-				text := decodeText(textBytes, currentFont)
+// fontKey returns the key in the fonts of the font used for a resource name: the name itself,
+// or the default font if the name is not found
+func (e *Extractor) fontKey(fontName string) string {
+	if _, ok := e.Fonts["/"+fontName]; ok {
+		return "/" + fontName
+	}
+	return "/DefaultFont"
+}
 
-				// Create text position entry
-				pos := document.TextPosition{
-					X:        textState.Tm[4],
-					Y:        textState.Tm[5],
-					FontSize: textState.FontSize,
-					Text:     text,
-					FontName: textState.FontName,
-				}
+// resourceName returns the resource name of a name operand, such as that of Tf or Do, without
+// its slash and with its #xx escapes decoded
+func resourceName(operand string) string {
+	return utils.UnescapeName(strings.TrimPrefix(operand, "/"))
+}
 
-				textPositions = append(textPositions, pos)
+// parseNumericOperands parses the last count operands as numbers, warning about a malformed
+// one on logger unless it is nil
+func parseNumericOperands(logger *slog.Logger, operands []string, count int) ([]float64, bool) {
+	if len(operands) < count {
+		return nil, false
+	}
 
-				// Advance text position
-				textState.Tm[4] += float64(len(text)) * textState.FontSize * 0.6
+	values := make([]float64, count)
+	for i, operand := range operands[len(operands)-count:] {
+		val, err := utils.ParseFloat(operand)
+		if err != nil {
+			if logger != nil {
+				utils.Warnf(logger, "Invalid numeric operand %q: %v", operand, err)
 			}
+			return nil, false
 		}
+		values[i] = val
 	}
 
-	// Sort text positions by reading order
-	SortTextPositions(textPositions, page.Width, page.Height)
+	return values, true
+}
 
-	page.TextPositions = textPositions
+// isCompositeFont reports whether a font is a composite (Type0) font using multi-byte codes
+func isCompositeFont(font document.PDFFont) bool {
+	return font.Subtype == "/Type0"
 }
 
-// decodeText decodes a byte string using font encoding
-func decodeText(textBytes []byte, font document.PDFFont) string {
-	var result strings.Builder
+// characterCodes splits a decoded string operand into character codes. Composite fonts use
+// two-byte codes, as with the Identity-H and Identity-V CMaps; simple fonts use one byte.
+func characterCodes(raw string, font document.PDFFont) []int {
+	if !isCompositeFont(font) {
+		codes := make([]int, len(raw))
+		for i := 0; i < len(raw); i++ {
+			codes[i] = int(raw[i])
+		}
+		return codes
+	}
 
-	// Handle basic PDF escape sequences
-	var i int
-	for i < len(textBytes) {
-		if textBytes[i] == '\\' && i+1 < len(textBytes) {
-			// Handle escape sequence
-			switch textBytes[i+1] {
-			case '\\':
-				result.WriteRune('\\')
-			case '(':
-				result.WriteRune('(')
-			case ')':
-				result.WriteRune(')')
-			case 'n':
-				result.WriteRune('\n')
-			case 'r':
-				result.WriteRune('\r')
-			case 't':
-				result.WriteRune('\t')
-			case 'b':
-				result.WriteRune('\b')
-			case 'f':
-				result.WriteRune('\f')
-			case '0', '1', '2', '3', '4', '5', '6', '7':
-				// Octal code (simplified)
-				if i+3 < len(textBytes) && isOctal(textBytes[i+2]) && isOctal(textBytes[i+3]) {
-					octalStr := string(textBytes[i+1 : i+4])
-					val, err := utils.ParseOctal(octalStr)
-					if err != nil {
-						utils.Logf(utils.LogWarning, "Invalid octal escape: %s\n", octalStr)
-						result.WriteRune(rune(textBytes[i+1]))
-						i += 2
-						continue
-					}
+	codes := make([]int, 0, (len(raw)+1)/2)
+	for i := 0; i < len(raw); i += 2 {
+		if i+1 < len(raw) {
+			codes = append(codes, int(raw[i])<<8|int(raw[i+1]))
+		} else {
+			codes = append(codes, int(raw[i]))
+		}
+	}
+	return codes
+}
 
-					// Map through font encoding if available
-					if char, ok := font.CodeToUnicode[int(val)]; ok {
-						result.WriteRune(char)
-					} else {
-						result.WriteRune(rune(val))
-					}
-					i += 3
-				} else {
-					// Invalid octal, just output the character
-					result.WriteRune(rune(textBytes[i+1]))
-				}
-			default:
-				// Unknown escape, just output the character
-				result.WriteRune(rune(textBytes[i+1]))
-			}
-			i += 2
+// decodeText maps character codes to text using the font encoding
+func decodeText(codes []int, font document.PDFFont) string {
+	var result strings.Builder
+
+	for _, code := range codes {
+		// Map through font encoding if available
+		if char, ok := font.CodeToUnicode[code]; ok {
+			result.WriteRune(char)
 		} else {
-			// Regular character - map through font encoding if available
-			if char, ok := font.CodeToUnicode[int(textBytes[i])]; ok {
-				result.WriteRune(char)
-			} else {
-				result.WriteRune(rune(textBytes[i]))
-			}
-			i++
+			result.WriteRune(rune(code))
 		}
 	}
 
 	return result.String()
 }
 
-// isOctal checks if a byte is an octal digit
-func isOctal(b byte) bool {
-	return b >= '0' && b <= '7'
+// RecordExtractionMetrics records in the document metrics what extracting the text of the whole
+// document found: the script distribution of the decoded text and how it was decoded, the
+// image-only and garbled pages, font usage, extraction quality and running lines
+func RecordExtractionMetrics(doc *document.PDFDocument, extractor *Extractor, pageTexts []string) {
+	m := doc.Metrics()
+	if m == nil {
+		return
+	}
+	m.RecordPageScripts(pageTexts)
+
+	shown := make([]int, len(doc.Pages))
+	mapped := make([]int, len(doc.Pages))
+	for i, page := range doc.Pages {
+		shown[i], mapped[i] = page.GlyphCount, page.MappedGlyphCount
+	}
+	m.RecordMappingCoverage(shown, mapped)
+	m.RecordImageOnlyPages(imageOnlyPages(doc), len(doc.Pages))
+	m.GarbledPages = garbledPages(pageTexts)
+	m.RecordFontUsage(fontUsage(doc))
+	m.RecordExtractionQuality(qualityCounts(pageTexts))
+	m.RunningLines = extractor.RunningLines
 }
 
 // ExtractTextContent extracts all text content from a document
 func ExtractTextContent(doc *document.PDFDocument) (string, error) {
-	extractor := NewExtractor(doc.Pages, doc.Fonts)
+	return ExtractTextContentWithOptions(doc, Options{})
+}
+
+// ExtractTextContentWithOptions extracts all text content from a document with the specified options
+func ExtractTextContentWithOptions(doc *document.PDFDocument, options Options) (string, error) {
+	extractor := NewDocumentExtractor(doc, options)
 	pageTexts := extractor.ExtractText()
 
+	RecordExtractionMetrics(doc, extractor, pageTexts)
+
 	var allText strings.Builder
 	for i, text := range pageTexts {
 		allText.WriteString(text)
 		if i < len(pageTexts)-1 {
-			allText.WriteString("\n\n")
+			allText.WriteString(options.Whitespace.PageSeparator())
 		}
 	}
 
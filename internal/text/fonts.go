@@ -1,14 +1,27 @@
 package text
 
 import (
+	"encoding/hex"
+	"log/slog"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf16"
 
 	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/metrics"
 	"github.com/yourusername/pdfex/internal/utils"
 )
 
+// Regular expressions for CMap programs
+var (
+	wmodeRegex        = regexp.MustCompile(`/WMode\s+1\b`)                        // Vertical writing mode declaration
+	bfcharRegex       = regexp.MustCompile(`(?s)beginbfchar\s+(.*?)\s+endbfchar`) // Sections span lines
+	bfcharEntryRegex  = regexp.MustCompile(`<([0-9A-Fa-f]+)>\s+<([0-9A-Fa-f]+)>`)
+	bfrangeRegex      = regexp.MustCompile(`(?s)beginbfrange\s+(.*?)\s+endbfrange`)
+	bfrangeEntryRegex = regexp.MustCompile(`<([0-9A-Fa-f]+)>\s+<([0-9A-Fa-f]+)>\s+<([0-9A-Fa-f]+)>`)
+)
+
 // FontProcessor handles font processing and character mapping
 type FontProcessor struct {
 	Fonts map[string]document.PDFFont
@@ -31,48 +44,23 @@ func (fp *FontProcessor) ProcessFonts(doc *document.PDFDocument) {
 		}
 	}
 
-	// Scan pages for font resources
+	// Scan pages for font resources, inline or a reference to a font dictionary
+	resolver := doc.Resolver()
 	for _, page := range doc.Pages {
-		if fontsRef, ok := page.ResourcesDict["Font"]; ok {
-			switch fonts := fontsRef.(type) {
-			case string:
-				if strings.HasPrefix(fonts, "<<") {
-					// Inline dictionary
-					fontsDict := make(map[string]interface{})
-					dictBytes := []byte(fonts)[2 : len(fonts)-2]
-					err := utils.ParseDictionary(dictBytes, fontsDict)
-					if err != nil {
-						utils.Logf(utils.LogWarning, "Error parsing fonts dictionary: %v\n", err)
-						continue
-					}
-
-					// Process each font in the dictionary
-					for fontName, fontRefValue := range fontsDict {
-						fp.processNamedFont(fontName, fontRefValue.(string), doc)
-					}
-				} else {
-					// Reference to font dictionary
-					fontsObjNum, err := utils.ExtractReference(fonts)
-					if err != nil {
-						utils.Logf(utils.LogWarning, "Invalid fonts reference: %v\n", err)
-						continue
-					}
-					if fontsObj, ok := doc.Objects[fontsObjNum]; ok {
-						// Process each font in the dictionary
-						for fontName, fontRefValue := range fontsObj.Dictionary {
-							if strings.HasPrefix(fontName, "F") {
-								fp.processNamedFont(fontName, fontRefValue.(string), doc)
-							}
-						}
-					}
-				}
-			case map[string]interface{}:
-				// Direct dictionary
-				for fontName, fontRefValue := range fonts {
-					if refStr, ok := fontRefValue.(string); ok {
-						fp.processNamedFont(fontName, refStr, doc)
-					}
-				}
+		fontsValue := utils.DictFrom(page.ResourcesDict).Get("Font")
+		if fontsValue == nil {
+			continue
+		}
+		fonts, err := resolver.ResolveDict(fontsValue)
+		if err != nil {
+			utils.Warnf(doc.Logger(), "Invalid fonts dictionary: %v\n", err)
+			continue
+		}
+
+		// Process each font in the dictionary
+		for fontName, value := range fonts {
+			if ref, ok := value.(utils.Ref); ok {
+				fp.processNamedFont(fontName, ref, doc)
 			}
 		}
 	}
@@ -89,20 +77,14 @@ func (fp *FontProcessor) ProcessFonts(doc *document.PDFDocument) {
 }
 
 // processNamedFont processes a named font reference
-func (fp *FontProcessor) processNamedFont(fontName string, fontRef string, doc *document.PDFDocument) {
-	fontObjNum, err := utils.ExtractReference(fontRef)
-	if err != nil {
-		utils.Logf(utils.LogWarning, "Invalid font reference %s: %v\n", fontRef, err)
-		return
-	}
-
-	fontObj, ok := doc.Objects[fontObjNum]
+func (fp *FontProcessor) processNamedFont(fontName string, fontRef utils.Ref, doc *document.PDFDocument) {
+	fontObj, ok := doc.Objects[fontRef.Num]
 	if !ok {
-		utils.Logf(utils.LogWarning, "Font object %d not found\n", fontObjNum)
+		utils.Warnf(doc.Logger(), "Font object %d not found\n", fontRef.Num)
 		return
 	}
 
-	font := fp.processFont(fontObjNum, fontObj, doc)
+	font := fp.processFont(fontRef.Num, fontObj, doc)
 
 	// Override the name with the resource name
 	font.Name = fontName
@@ -117,14 +99,19 @@ func (fp *FontProcessor) processFont(objNum int, obj document.PDFObject, doc *do
 	}
 
 	// Extract font properties
-	if subtype, ok := obj.Dictionary["Subtype"]; ok {
-		font.Subtype = subtype.(string)
+	dict := obj.Dict()
+	if subtype, ok := dict.Get("Subtype").(utils.Name); ok {
+		font.Subtype = subtype.String()
+	}
+	if baseFont, ok := obj.Dictionary["BaseFont"].(string); ok {
+		font.BaseFont = baseFont
 	}
 
-	if encoding, ok := obj.Dictionary["Encoding"]; ok {
-		// Use type assertion to convert to string
-		encodingStr, ok := encoding.(string)
-		if ok {
+	if encoding := dict.Get("Encoding"); encoding != nil {
+		// A named encoding or a reference to an encoding dictionary or CMap
+		switch encoding.(type) {
+		case utils.Name, utils.Ref:
+			encodingStr := encoding.String()
 			font.Encoding = encodingStr
 			// Handle standard encodings
 			if encodingStr == "/WinAnsiEncoding" {
@@ -134,26 +121,145 @@ func (fp *FontProcessor) processFont(objNum int, obj document.PDFObject, doc *do
 			} else if strings.HasPrefix(encodingStr, "/Identity") {
 				loadIdentityEncoding(&font)
 			}
-		} else {
-			utils.Logf(utils.LogWarning, "Font encoding is not a string: %v\n", encoding)
+
+			// Vertical CMaps such as Identity-V and UniJIS-UCS2-V end in -V
+			if strings.HasPrefix(encodingStr, "/") && strings.HasSuffix(encodingStr, "-V") {
+				font.WritingMode = 1
+			} else if utils.IsReference(encodingStr) {
+				font.WritingMode = embeddedCMapWritingMode(encodingStr, doc)
+			}
+		default:
+			utils.Warnf(doc.Logger(), "Font encoding is not a name or reference: %v\n", encoding)
 		}
 	}
 
 	// Check for ToUnicode CMap
-	if toUnicodeRef, ok := obj.Dictionary["ToUnicode"]; ok {
-		toUnicodeObjNum, err := utils.ExtractReference(toUnicodeRef.(string))
+	if toUnicodeRef := dict.Get("ToUnicode"); toUnicodeRef != nil {
+		toUnicodeObj, err := doc.Resolver().ResolveStream(toUnicodeRef)
 		if err != nil {
-			utils.Logf(utils.LogWarning, "Invalid ToUnicode reference: %v\n", err)
-		} else if toUnicodeObj, ok := doc.Objects[toUnicodeObjNum]; ok && toUnicodeObj.IsStream {
+			utils.Warnf(doc.Logger(), "Invalid ToUnicode reference: %v\n", err)
+		} else {
 			font.ToUnicode = toUnicodeObj.Stream
 			// Parse the ToUnicode CMap
-			parseCMap(font.ToUnicode, &font)
+			parseCMap(font.ToUnicode, &font, doc.Logger())
 		}
 	}
 
+	loadGlyphWidths(obj, doc, &font)
+	font.Embedded = doc.FontEmbedded(obj)
+
 	return font
 }
 
+// loadGlyphWidths reads the glyph widths of a font: /FirstChar and /Widths for simple fonts,
+// or /DW and /W from the descendant CIDFont of a composite font
+func loadGlyphWidths(obj document.PDFObject, doc *document.PDFDocument, font *document.PDFFont) {
+	if isCompositeFont(*font) {
+		descendant, ok := doc.DescendantFont(obj)
+		if !ok {
+			return
+		}
+
+		font.DefaultWidth = 1000
+		if dw := resolveValue(utils.DictionaryValue(descendant.Content, "DW"), doc); dw != "" {
+			font.DefaultWidth = utils.GetFloat(dw, 1000)
+		}
+		font.CIDWidths = parseCIDWidths(utils.ParseArray(resolveValue(utils.DictionaryValue(descendant.Content, "W"), doc)))
+		return
+	}
+
+	widths := utils.ParseArray(resolveValue(utils.DictionaryValue(obj.Content, "Widths"), doc))
+	if len(widths) == 0 {
+		return
+	}
+
+	font.FirstChar = utils.GetInteger(resolveValue(utils.DictionaryValue(obj.Content, "FirstChar"), doc), 0)
+	font.Widths = make([]float64, len(widths))
+	for i, w := range widths {
+		font.Widths[i] = utils.GetFloat(w, 0)
+	}
+
+	// MissingWidth from the font descriptor applies to codes outside the Widths range
+	if descriptorNum, err := utils.ExtractReference(utils.DictionaryValue(obj.Content, "FontDescriptor")); err == nil {
+		if descriptor, ok := doc.Objects[descriptorNum]; ok {
+			font.DefaultWidth = utils.GetFloat(utils.DictionaryValue(descriptor.Content, "MissingWidth"), 0)
+		}
+	}
+}
+
+// parseCIDWidths parses a CIDFont /W array, whose entries are either "c [w1 w2 ...]" giving
+// widths for consecutive CIDs from c, or "cfirst clast w" giving one width for a range
+func parseCIDWidths(items []string) map[int]float64 {
+	widths := make(map[int]float64)
+
+	for i := 0; i < len(items); {
+		first, err := utils.ParseInt(items[i])
+		if err != nil || i+1 >= len(items) {
+			break
+		}
+
+		if utils.IsArray(items[i+1]) {
+			for j, w := range utils.ParseArray(items[i+1]) {
+				widths[first+j] = utils.GetFloat(w, 0)
+			}
+			i += 2
+			continue
+		}
+
+		if i+2 >= len(items) {
+			break
+		}
+		last, err := utils.ParseInt(items[i+1])
+		if err != nil || last-first > maxCIDRange {
+			break
+		}
+		w := utils.GetFloat(items[i+2], 0)
+		for cid := first; cid <= last; cid++ {
+			widths[cid] = w
+		}
+		i += 3
+	}
+
+	return widths
+}
+
+// Largest CID range accepted from a single /W entry, to bound memory on malformed fonts
+const maxCIDRange = 65536
+
+// resolveValue returns the referenced value in PDF syntax if the value is an indirect
+// reference, and the value itself otherwise
+func resolveValue(value string, doc *document.PDFDocument) string {
+	parsed, err := utils.ParseValue([]byte(value))
+	if err != nil {
+		return value
+	}
+	resolved, err := doc.Resolver().ResolveValue(parsed)
+	if err != nil {
+		return ""
+	}
+	return resolved.String()
+}
+
+// embeddedCMapWritingMode returns the /WMode of an embedded encoding CMap stream
+func embeddedCMapWritingMode(cmapRef string, doc *document.PDFDocument) int {
+	cmapRefValue, err := utils.ParseValue([]byte(cmapRef))
+	if err != nil {
+		return 0
+	}
+	cmapObj, err := doc.Resolver().ResolveStream(cmapRefValue)
+	if err != nil {
+		return 0
+	}
+
+	if wmode, ok := cmapObj.Dictionary["WMode"]; ok {
+		return utils.GetInteger(wmode, 0)
+	}
+	if wmodeRegex.Match(cmapObj.Stream) {
+		return 1
+	}
+	return 0
+}
+
 // createDefaultFont creates a default font mapping
 func (fp *FontProcessor) createDefaultFont() {
 	defaultFont := document.PDFFont{
@@ -174,6 +280,37 @@ func (fp *FontProcessor) createDefaultFont() {
 	fp.Fonts["/DefaultFont"] = defaultFont
 }
 
+// winAnsiOverrides are the codes where WinAnsiEncoding (Windows-1252) differs from ISO-8859-1
+var winAnsiOverrides = map[int]rune{
+	128: '\u20AC', // Euro sign
+	130: '\u201A', // Single low-9 quotation mark
+	131: '\u0192', // Latin small letter f with hook
+	132: '\u201E', // Double low-9 quotation mark
+	133: '\u2026', // Horizontal ellipsis
+	134: '\u2020', // Dagger
+	135: '\u2021', // Double dagger
+	136: '\u02C6', // Modifier letter circumflex accent
+	137: '\u2030', // Per mille sign
+	138: '\u0160', // Latin capital letter S with caron
+	139: '\u2039', // Single left-pointing angle quotation mark
+	140: '\u0152', // Latin capital ligature OE
+	142: '\u017D', // Latin capital letter Z with caron
+	145: '\u2018', // Left single quotation mark
+	146: '\u2019', // Right single quotation mark
+	147: '\u201C', // Left double quotation mark
+	148: '\u201D', // Right double quotation mark
+	149: '\u2022', // Bullet
+	150: '\u2013', // En dash
+	151: '\u2014', // Em dash
+	152: '\u02DC', // Small tilde
+	153: '\u2122', // Trade mark sign
+	154: '\u0161', // Latin small letter s with caron
+	155: '\u203A', // Single right-pointing angle quotation mark
+	156: '\u0153', // Latin small ligature oe
+	158: '\u017E', // Latin small letter z with caron
+	159: '\u0178', // Latin capital letter Y with diaeresis
+}
+
 // loadWinAnsiEncoding loads the WinAnsi encoding into a font
 func loadWinAnsiEncoding(font *document.PDFFont) {
 	// WinAnsiEncoding is similar to ISO-8859-1
@@ -183,36 +320,6 @@ func loadWinAnsiEncoding(font *document.PDFFont) {
 	}
 
 	// Overrides for common characters that differ
-	winAnsiOverrides := map[int]rune{
-		128: '\u20AC', // Euro sign
-		130: '\u201A', // Single low-9 quotation mark
-		131: '\u0192', // Latin small letter f with hook
-		132: '\u201E', // Double low-9 quotation mark
-		133: '\u2026', // Horizontal ellipsis
-		134: '\u2020', // Dagger
-		135: '\u2021', // Double dagger
-		136: '\u02C6', // Modifier letter circumflex accent
-		137: '\u2030', // Per mille sign
-		138: '\u0160', // Latin capital letter S with caron
-		139: '\u2039', // Single left-pointing angle quotation mark
-		140: '\u0152', // Latin capital ligature OE
-		142: '\u017D', // Latin capital letter Z with caron
-		145: '\u2018', // Left single quotation mark
-		146: '\u2019', // Right single quotation mark
-		147: '\u201C', // Left double quotation mark
-		148: '\u201D', // Right double quotation mark
-		149: '\u2022', // Bullet
-		150: '\u2013', // En dash
-		151: '\u2014', // Em dash
-		152: '\u02DC', // Small tilde
-		153: '\u2122', // Trade mark sign
-		154: '\u0161', // Latin small letter s with caron
-		155: '\u203A', // Single right-pointing angle quotation mark
-		156: '\u0153', // Latin small ligature oe
-		158: '\u017E', // Latin small letter z with caron
-		159: '\u0178', // Latin capital letter Y with diaeresis
-	}
-
 	for code, char := range winAnsiOverrides {
 		font.CodeToUnicode[code] = char
 	}
@@ -374,17 +481,50 @@ func loadIdentityEncoding(font *document.PDFFont) {
 	}
 }
 
-// parseCMap parses a CMap to extract character mappings
-func parseCMap(cmapData []byte, font *document.PDFFont) {
+// Presentation forms of the ligatures a ToUnicode CMap maps to several letters
+var ligatureForms = map[string]rune{
+	"ff":  'ﬀ',
+	"fi":  'ﬁ',
+	"fl":  'ﬂ',
+	"ffi": 'ﬃ',
+	"ffl": 'ﬄ',
+	"st":  'ﬆ',
+}
+
+// cmapDestination decodes the UTF-16BE destination of a bfchar mapping to the single rune a
+// code maps to. A destination of several characters, such as the letters of a ligature, maps
+// to the ligature's presentation form when it has one and otherwise to its first character.
+func cmapDestination(destHex string) (rune, bool) {
+	data, err := hex.DecodeString(destHex)
+	if err != nil || len(data) == 0 || len(data)%2 != 0 {
+		return 0, false
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+	}
+	runes := utf16.Decode(units)
+	if len(runes) > 1 {
+		if form, ok := ligatureForms[string(runes)]; ok {
+			return form, true
+		}
+	}
+	return runes[0], true
+}
+
+// parseCMap parses a CMap to extract character mappings, warning about malformed ones on logger
+func parseCMap(cmapData []byte, font *document.PDFFont, logger *slog.Logger) {
+	if font.UnicodeMapped == nil {
+		font.UnicodeMapped = make(map[int]bool)
+	}
+
 	// Look for beginbfchar sections which define character mappings
-	bfcharRegex := regexp.MustCompile(`beginbfchar\s+(.*?)\s+endbfchar`)
 	matches := bfcharRegex.FindAllSubmatch(cmapData, -1)
 
 	for _, match := range matches {
 		mappings := match[1]
 		// Extract character mappings (hex code to Unicode)
-		mapRegex := regexp.MustCompile(`<([0-9A-F]+)>\s+<([0-9A-F]+)>`)
-		mapMatches := mapRegex.FindAllSubmatch(mappings, -1)
+		mapMatches := bfcharEntryRegex.FindAllSubmatch(mappings, -1)
 
 		for _, mapMatch := range mapMatches {
 			srcHex := string(mapMatch[1])
@@ -393,29 +533,28 @@ func parseCMap(cmapData []byte, font *document.PDFFont) {
 			// Convert hex strings to integers
 			src, err := strconv.ParseInt(srcHex, 16, 32)
 			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid source hex in CMap: %s\n", srcHex)
+				utils.Warnf(logger, "Invalid source hex in CMap: %s\n", srcHex)
 				continue
 			}
 
-			dest, err := strconv.ParseInt(destHex, 16, 32)
-			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid destination hex in CMap: %s\n", destHex)
+			dest, ok := cmapDestination(destHex)
+			if !ok {
+				utils.Warnf(logger, "Invalid destination hex in CMap: %s\n", destHex)
 				continue
 			}
 
-			font.CodeToUnicode[int(src)] = rune(dest)
+			font.CodeToUnicode[int(src)] = dest
+			font.UnicodeMapped[int(src)] = true
 		}
 	}
 
 	// Look for beginbfrange sections which define character ranges
-	bfrangeRegex := regexp.MustCompile(`beginbfrange\s+(.*?)\s+endbfrange`)
 	rangeMatches := bfrangeRegex.FindAllSubmatch(cmapData, -1)
 
 	for _, match := range rangeMatches {
 		ranges := match[1]
 		// Extract range mappings
-		rangeRegex := regexp.MustCompile(`<([0-9A-F]+)>\s+<([0-9A-F]+)>\s+<([0-9A-F]+)>`)
-		rangeEntries := rangeRegex.FindAllSubmatch(ranges, -1)
+		rangeEntries := bfrangeEntryRegex.FindAllSubmatch(ranges, -1)
 
 		for _, rangeEntry := range rangeEntries {
 			startHex := string(rangeEntry[1])
@@ -425,19 +564,19 @@ func parseCMap(cmapData []byte, font *document.PDFFont) {
 			// Convert hex strings to integers
 			start, err := strconv.ParseInt(startHex, 16, 32)
 			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid start hex in CMap range: %s\n", startHex)
+				utils.Warnf(logger, "Invalid start hex in CMap range: %s\n", startHex)
 				continue
 			}
 
 			end, err := strconv.ParseInt(endHex, 16, 32)
 			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid end hex in CMap range: %s\n", endHex)
+				utils.Warnf(logger, "Invalid end hex in CMap range: %s\n", endHex)
 				continue
 			}
 
 			destStart, err := strconv.ParseInt(destStartHex, 16, 32)
 			if err != nil {
-				utils.Logf(utils.LogWarning, "Invalid destination start hex in CMap range: %s\n", destStartHex)
+				utils.Warnf(logger, "Invalid destination start hex in CMap range: %s\n", destStartHex)
 				continue
 			}
 
@@ -446,6 +585,7 @@ func parseCMap(cmapData []byte, font *document.PDFFont) {
 				// Convert int64 to int for indexing
 				offset := int(i) - int(start)
 				font.CodeToUnicode[i] = rune(destStart + int64(offset))
+				font.UnicodeMapped[i] = true
 			}
 		}
 	}
@@ -471,6 +611,35 @@ func ProcessFontInDocument(doc *document.PDFDocument) {
 	doc.Fonts = fp.GetFonts()
 }
 
+// fontUsage describes the fonts named in page resources with the glyphs shown with each. The
+// default font is only included when text fell back to it.
+func fontUsage(doc *document.PDFDocument) []metrics.FontUsage {
+	characters := make(map[string]int)
+	for _, page := range doc.Pages {
+		for key, count := range page.FontGlyphs {
+			characters[key] += count
+		}
+	}
+
+	fonts := make([]metrics.FontUsage, 0)
+	for key, font := range doc.Fonts {
+		// Fonts are also stored by object number; those named in resources start with a slash
+		if !strings.HasPrefix(key, "/") || (key == "/DefaultFont" && characters[key] == 0) {
+			continue
+		}
+		fonts = append(fonts, metrics.FontUsage{
+			Name:       font.Name,
+			BaseFont:   font.BaseFont,
+			Subtype:    font.Subtype,
+			Embedded:   font.Embedded,
+			Encoding:   font.Encoding,
+			ToUnicode:  len(font.ToUnicode) > 0,
+			Characters: characters[key],
+		})
+	}
+	return fonts
+}
+
 // GetDefaultFont returns a default font for fallback
 func GetDefaultFont() document.PDFFont {
 	defaultFont := document.PDFFont{
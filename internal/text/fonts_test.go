@@ -0,0 +1,79 @@
+package text
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// benchmarkCMap builds a ToUnicode CMap with the given number of bfchar and bfrange sections
+func benchmarkCMap(sections int) []byte {
+	var cmap strings.Builder
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	cmap.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for i := 0; i < sections; i++ {
+		base := i * 16
+		cmap.WriteString("4 beginbfchar\n")
+		for j := 0; j < 4; j++ {
+			fmt.Fprintf(&cmap, "<%04X> <%04X>\n", base+j, 0x41+j)
+		}
+		cmap.WriteString("endbfchar\n2 beginbfrange\n")
+		fmt.Fprintf(&cmap, "<%04X> <%04X> <%04X>\n", base+4, base+9, 0x61)
+		fmt.Fprintf(&cmap, "<%04X> <%04X> <%04X>\n", base+10, base+15, 0x30)
+		cmap.WriteString("endbfrange\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return []byte(cmap.String())
+}
+
+func TestParseCMap(t *testing.T) {
+	tests := []struct {
+		name string
+		cmap string
+		want map[int]rune
+	}{
+		{
+			name: "sections spanning lines",
+			cmap: "2 beginbfchar\n<0001> <0041>\r\n<0002> <00e9>\nendbfchar\n" +
+				"1 beginbfrange\n<0010> <0012> <0061>\nendbfrange",
+			want: map[int]rune{1: 'A', 2: 'é', 0x10: 'a', 0x11: 'b', 0x12: 'c'},
+		},
+		{
+			name: "sections on one line",
+			cmap: "1 beginbfchar <03> <0020> endbfchar 1 beginbfrange <20> <21> <0030> endbfrange",
+			want: map[int]rune{3: ' ', 0x20: '0', 0x21: '1'},
+		},
+		{
+			name: "several characters and surrogate pairs",
+			cmap: "3 beginbfchar\n<0C> <00660069>\n<0D> <00630068>\n<0E> <D835DC00>\nendbfchar",
+			want: map[int]rune{0x0C: 'ﬁ', 0x0D: 'c', 0x0E: '𝐀'},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			font := document.PDFFont{CodeToUnicode: make(map[int]rune)}
+			parseCMap([]byte(tt.cmap), &font, nil)
+			if len(font.CodeToUnicode) != len(tt.want) {
+				t.Errorf("got %d mappings, want %d", len(font.CodeToUnicode), len(tt.want))
+			}
+			for code, r := range tt.want {
+				if font.CodeToUnicode[code] != r || !font.UnicodeMapped[code] {
+					t.Errorf("code %#x maps to %q, want %q", code, font.CodeToUnicode[code], r)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseCMap(b *testing.B) {
+	cmap := benchmarkCMap(100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		font := document.PDFFont{CodeToUnicode: make(map[int]rune)}
+		parseCMap(cmap, &font, nil)
+	}
+}
@@ -0,0 +1,256 @@
+package text
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Heading detection tuning parameters
+const (
+	headingSizeRatio     = 1.15 // Minimum font size of a heading relative to body text
+	headingMaxChars      = 120  // Longer lines are treated as body text
+	headingRepeatedShare = 0.5  // Lines on more than this share of pages are running headers
+)
+
+// Numbering patterns that commonly start headings
+var (
+	decimalHeadingPattern = regexp.MustCompile(`^(\d+(?:\.\d+)*)\.?\s+\S`)
+	namedHeadingPattern   = regexp.MustCompile(`(?i)^(chapter|section|part|appendix)\s+[\dIVXLC]+\b`)
+	romanHeadingPattern   = regexp.MustCompile(`^[IVXLC]+\.\s+\S`)
+)
+
+// boldFontNames are fragments of font names that indicate a bold weight
+var boldFontNames = []string{"bold", "black", "heavy", "semibold", "demi"}
+
+// headingLine is a line of text with the style information used to recognise headings
+type headingLine struct {
+	text     string
+	page     int
+	y        float64
+	size     float64 // Largest font size on the line
+	bold     bool    // All of the line is set in a bold font
+	numbered int     // Depth of decimal numbering such as 2.1 (2), or 1 for other numbering, 0 for none
+}
+
+// Heading is a line of text recognised as a heading
+type Heading struct {
+	Text       string
+	Level      int // 1 for the most prominent headings
+	PageNumber int
+}
+
+// InferOutline builds an outline from the headings of the pages, for documents without
+// bookmarks
+func InferOutline(pages []document.PDFPage, fonts map[string]document.PDFFont) []document.OutlineItem {
+	return buildOutline(DetectHeadings(pages, fonts))
+}
+
+// DetectHeadings finds the headings of the pages in reading order. Headings are lines set larger
+// than the body text, or in bold or with section numbering; their level comes from their
+// numbering depth or else from their font size. Pages must already have their text positions.
+func DetectHeadings(pages []document.PDFPage, fonts map[string]document.PDFFont) []Heading {
+	var lines []headingLine
+	for _, page := range pages {
+		lines = append(lines, pageHeadingLines(page, fonts)...)
+	}
+
+	bodySize := bodyFontSize(lines)
+	repeated := repeatedLines(lines, len(pages))
+
+	var headings []headingLine
+	for _, line := range lines {
+		if repeated[line.text] || !isHeading(line, bodySize) {
+			continue
+		}
+
+		// A heading that wraps continues on the next line in the same style
+		if n := len(headings); n > 0 {
+			prev := &headings[n-1]
+			if prev.page == line.page && line.numbered == 0 && prev.size == line.size && prev.bold == line.bold &&
+				prev.y-line.y <= line.size*layoutLineSpacing*1.5 {
+				prev.text += " " + line.text
+				prev.y = line.y
+				continue
+			}
+		}
+		headings = append(headings, line)
+	}
+
+	levels := headingLevels(headings)
+	result := make([]Heading, 0, len(headings))
+	for _, h := range headings {
+		level := levels[h.size]
+		if h.numbered > 1 {
+			level = h.numbered
+		}
+		result = append(result, Heading{Text: h.text, Level: level, PageNumber: h.page})
+	}
+	return result
+}
+
+// pageHeadingLines returns the lines of a page with their style
+func pageHeadingLines(page document.PDFPage, fonts map[string]document.PDFFont) []headingLine {
+	var spans []document.TextPosition
+	for _, pos := range page.TextPositions {
+		if strings.TrimSpace(pos.Text) != "" && !pos.Vertical {
+			spans = append(spans, pos)
+		}
+	}
+
+	var lines []headingLine
+	for _, positions := range clusterBaselines(spans) {
+		line := headingLine{
+			text: JoinLines(positions),
+			page: page.PageNumber,
+			y:    positions[0].Y,
+			bold: true,
+		}
+		for _, pos := range positions {
+			line.size = math.Max(line.size, roundFontSize(pos.FontSize))
+			if !isBoldFont(fonts["/"+pos.FontName]) {
+				line.bold = false
+			}
+		}
+
+		if match := decimalHeadingPattern.FindStringSubmatch(line.text); match != nil {
+			line.numbered = strings.Count(match[1], ".") + 1
+		} else if namedHeadingPattern.MatchString(line.text) || romanHeadingPattern.MatchString(line.text) {
+			line.numbered = 1
+		}
+
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// roundFontSize rounds a font size to half a point, so that sizes differing only by rounding
+// noise compare equal
+func roundFontSize(size float64) float64 {
+	return math.Round(size*2) / 2
+}
+
+// isBoldFont reports whether a font's name indicates a bold weight
+func isBoldFont(font document.PDFFont) bool {
+	name := strings.ToLower(font.BaseFont)
+	for _, fragment := range boldFontNames {
+		if strings.Contains(name, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyFontSize returns the font size used by most characters
+func bodyFontSize(lines []headingLine) float64 {
+	chars := make(map[float64]int)
+	for _, line := range lines {
+		chars[line.size] += len(line.text)
+	}
+
+	var body float64
+	for size, count := range chars {
+		if count > chars[body] || (count == chars[body] && size < body) {
+			body = size
+		}
+	}
+	return body
+}
+
+// repeatedLines returns the texts that appear on many pages, such as running headers and footers
+func repeatedLines(lines []headingLine, pageCount int) map[string]bool {
+	repeated := make(map[string]bool)
+	if pageCount < 3 {
+		return repeated
+	}
+
+	pages := make(map[string]map[int]bool)
+	for _, line := range lines {
+		if pages[line.text] == nil {
+			pages[line.text] = make(map[int]bool)
+		}
+		pages[line.text][line.page] = true
+	}
+
+	for text, onPages := range pages {
+		if float64(len(onPages)) > float64(pageCount)*headingRepeatedShare {
+			repeated[text] = true
+		}
+	}
+	return repeated
+}
+
+// isHeading reports whether a line looks like a heading
+func isHeading(line headingLine, bodySize float64) bool {
+	text := strings.TrimSpace(line.text)
+	if text == "" || len(text) > headingMaxChars || strings.HasSuffix(text, ",") {
+		return false
+	}
+
+	// Lines without letters, such as page numbers, are never headings
+	if strings.IndexFunc(text, unicode.IsLetter) < 0 {
+		return false
+	}
+
+	larger := line.size >= bodySize*headingSizeRatio
+	if larger {
+		return true
+	}
+	if line.size < bodySize {
+		return false
+	}
+
+	// Body-sized lines need bold type; numbering alone would also match numbered lists
+	return line.bold && (line.numbered > 0 || !strings.HasSuffix(text, "."))
+}
+
+// headingLevels assigns outline levels to heading font sizes, largest first. Bold headings at the
+// body size come after all larger sizes.
+func headingLevels(headings []headingLine) map[float64]int {
+	seen := make(map[float64]bool)
+	var sizes []float64
+	for _, h := range headings {
+		if !seen[h.size] {
+			seen[h.size] = true
+			sizes = append(sizes, h.size)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(sizes)))
+
+	levels := make(map[float64]int, len(sizes))
+	for i, size := range sizes {
+		levels[size] = i + 1
+	}
+	return levels
+}
+
+// buildOutline nests headings into an outline tree
+func buildOutline(headings []Heading) []document.OutlineItem {
+	var roots []document.OutlineItem
+	var path []*document.OutlineItem // Open item at each level of the tree
+
+	for _, h := range headings {
+		level := h.Level
+		// An item can be at most one level below its parent
+		if level > len(path)+1 {
+			level = len(path) + 1
+		}
+		path = path[:level-1]
+
+		item := document.OutlineItem{Title: h.Text, Level: level, PageNumber: h.PageNumber}
+		if level == 1 {
+			roots = append(roots, item)
+			path = append(path, &roots[len(roots)-1])
+		} else {
+			parent := path[level-2]
+			parent.Children = append(parent.Children, item)
+			path = append(path, &parent.Children[len(parent.Children)-1])
+		}
+	}
+
+	return roots
+}
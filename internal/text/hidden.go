@@ -0,0 +1,175 @@
+package text
+
+import (
+	"math"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Reasons text is hidden from a reader
+const (
+	HiddenCovered        = "covered"         // An opaque rectangle is painted over it, as in a redaction that left the text behind
+	HiddenInvisible      = "invisible"       // Drawn with text rendering mode 3 or 7, which paints nothing
+	HiddenSameColor      = "same-color"      // Filled in the colour of what is behind it, such as white on white
+	HiddenOutsideCropBox = "outside-cropbox" // Placed outside the visible region of the page
+)
+
+// Largest difference of any RGB component between colours that look the same
+const sameColorTolerance = 0.05
+
+// HiddenText is a run of text that a reader of the rendered page can't see
+type HiddenText struct {
+	Reason string
+	Text   string
+	Bounds Box // In points on the unrotated page, from the baseline to the font size above it
+}
+
+// paintedRect is an opaque rectangle painted by the content stream
+type paintedRect struct {
+	box   Box
+	color Color
+}
+
+// hiddenTextScan follows a page as it is extracted, collecting the text a reader can't see
+type hiddenTextScan struct {
+	crop      [4]float64
+	found     []HiddenText
+	visible   []HiddenText  // Text shown so far that a later rectangle could still cover
+	backdrops []paintedRect // Filled rectangles painted so far, in drawing order
+	rects     []Box         // Rectangles of the path under construction
+	curved    bool          // The path under construction has segments other than rectangles
+}
+
+// FindHiddenText extracts a page (1-based) and returns the text on it that a reader can't see:
+// text covered by filled rectangles painted after it, drawn invisibly, filled in the colour of
+// the rectangle or blank page behind it, or placed outside the crop box. Runs are reported in
+// drawing order, except covered ones, which are reported when the covering rectangle is painted.
+// Transparency, clipping, images and shadings are not taken into account.
+func (e *Extractor) FindHiddenText(pageNum int) []HiddenText {
+	if pageNum < 1 || pageNum > len(e.Pages) {
+		return nil
+	}
+	page := &e.Pages[pageNum-1]
+
+	e.hidden = &hiddenTextScan{crop: page.Boxes.CropBox}
+	defer func() { e.hidden = nil }()
+	e.extractTextWithPositioning(page)
+	return e.hidden.found
+}
+
+// text checks a run of text as it is shown, with the text rendering mode and fill colour
+func (s *hiddenTextScan) text(pos document.TextPosition, renderMode int, fill Color) {
+	if strings.TrimSpace(pos.Text) == "" {
+		return
+	}
+
+	run := HiddenText{Text: pos.Text, Bounds: textBounds(pos)}
+	x, y := run.Bounds.center()
+	switch {
+	case renderMode == 3 || renderMode == 7:
+		run.Reason = HiddenInvisible
+	case s.crop[2] > s.crop[0] && s.crop[3] > s.crop[1] && !document.BoxContains(s.crop, x, y):
+		run.Reason = HiddenOutsideCropBox
+	case renderMode != 1 && renderMode != 5 && sameColor(fill, s.backdrop(x, y)):
+		// Modes 1 and 5 only stroke the glyph outlines, in the stroke colour
+		run.Reason = HiddenSameColor
+	default:
+		s.visible = append(s.visible, run)
+		return
+	}
+	s.found = append(s.found, run)
+}
+
+// pathOperator follows the construction and painting of paths, recording filled rectangles and
+// the visible text they cover
+func (s *hiddenTextScan) pathOperator(operator string, operands []string, ctm [6]float64, fill Color) {
+	switch operator {
+	case "re":
+		values, ok := parseNumericOperands(nil, operands, 4)
+		if !ok {
+			return
+		}
+		x, y, w, h := values[0], values[1], values[2], values[3]
+		box := Box{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+		for _, corner := range [][2]float64{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}} {
+			px := ctm[0]*corner[0] + ctm[2]*corner[1] + ctm[4]
+			py := ctm[1]*corner[0] + ctm[3]*corner[1] + ctm[5]
+			box = box.Union(Box{MinX: px, MinY: py, MaxX: px, MaxY: py})
+		}
+		s.rects = append(s.rects, box)
+	case "m", "l", "c", "v", "y":
+		s.curved = true
+	case "f", "F", "f*", "B", "B*", "b", "b*":
+		if !s.curved {
+			for _, box := range s.rects {
+				s.paint(box, fill)
+			}
+		}
+		s.rects, s.curved = nil, false
+	case "S", "s", "n":
+		s.rects, s.curved = nil, false
+	}
+}
+
+// paint records a filled rectangle and reports the visible text under it as covered
+func (s *hiddenTextScan) paint(box Box, fill Color) {
+	s.backdrops = append(s.backdrops, paintedRect{box: box, color: fill})
+
+	remaining := s.visible[:0]
+	for _, run := range s.visible {
+		if box.contains(run.Bounds.center()) {
+			run.Reason = HiddenCovered
+			s.found = append(s.found, run)
+		} else {
+			remaining = append(remaining, run)
+		}
+	}
+	s.visible = remaining
+}
+
+// backdrop returns the colour painted at a point so far: that of the last filled rectangle
+// containing it, or white for the blank page
+func (s *hiddenTextScan) backdrop(x, y float64) Color {
+	for i := len(s.backdrops) - 1; i >= 0; i-- {
+		if s.backdrops[i].box.contains(x, y) {
+			return s.backdrops[i].color
+		}
+	}
+	return Color{1, 1, 1}
+}
+
+// textBounds returns the box of a run of text, from its baseline to the font size above it,
+// following the run's direction
+func textBounds(pos document.TextPosition) Box {
+	angle := pos.Angle * math.Pi / 180
+	dx, dy := math.Cos(angle), math.Sin(angle)
+	x, y := pos.X, pos.Y+pos.Rise
+
+	box := Box{MinX: x, MinY: y, MaxX: x, MaxY: y}
+	for _, corner := range [][2]float64{
+		{x + dx*pos.Width, y + dy*pos.Width},
+		{x - dy*pos.FontSize, y + dx*pos.FontSize},
+		{x + dx*pos.Width - dy*pos.FontSize, y + dy*pos.Width + dx*pos.FontSize},
+	} {
+		box = box.Union(Box{MinX: corner[0], MinY: corner[1], MaxX: corner[0], MaxY: corner[1]})
+	}
+	return box
+}
+
+// center returns the middle of a box
+func (b Box) center() (float64, float64) {
+	return (b.MinX + b.MaxX) / 2, (b.MinY + b.MaxY) / 2
+}
+
+// contains reports whether a point lies inside a box
+func (b Box) contains(x, y float64) bool {
+	return x >= b.MinX && x <= b.MaxX && y >= b.MinY && y <= b.MaxY
+}
+
+// sameColor reports whether two colours look the same
+func sameColor(a, b Color) bool {
+	return math.Abs(a.R-b.R) <= sameColorTolerance &&
+		math.Abs(a.G-b.G) <= sameColorTolerance &&
+		math.Abs(a.B-b.B) <= sameColorTolerance
+}
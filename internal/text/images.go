@@ -0,0 +1,67 @@
+package text
+
+import (
+	"math"
+
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// XObjectPlacement is an XObject other than a form, such as an image, drawn by a page
+type XObjectPlacement struct {
+	Name   string // Resource name without the slash
+	Bounds Box    // Unit square mapped by the CTM, in upright page space like text positions
+}
+
+// DrawnXObjects returns the XObjects other than forms that a page's content stream draws, in
+// drawing order. Images are drawn into the unit square of the current transformation. Pages must
+// already have their text positions; XObjects drawn inside forms are not included.
+func DrawnXObjects(page *document.PDFPage) []XObjectPlacement {
+	return drawnXObjects(page, page.TextRotation)
+}
+
+// drawnXObjects returns the XObjects other than forms that a page draws, with their bounds
+// turned by rotation degrees; 0 leaves them on the unrotated page
+func drawnXObjects(page *document.PDFPage, rotation int) []XObjectPlacement {
+	var placements []XObjectPlacement
+	var stack [][6]float64
+	ctm := unitMatrix(page)
+
+	for _, op := range content.ParseOperations(page.Contents) {
+		switch op.Operator {
+		case "q":
+			stack = append(stack, ctm)
+		case "Q":
+			if n := len(stack); n > 0 {
+				ctm = stack[n-1]
+				stack = stack[:n-1]
+			}
+		case "cm":
+			values, ok := parseNumericOperands(nil, op.Operands, 6)
+			if !ok {
+				continue
+			}
+			var m [6]float64
+			copy(m[:], values)
+			ctm = multiplyMatrix(m, ctm)
+		case "Do":
+			if len(op.Operands) < 1 {
+				continue
+			}
+			name := resourceName(op.Operands[0])
+			if page.XObjects[name] != nil {
+				continue
+			}
+
+			bounds := Box{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+			for _, corner := range [][2]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+				x := ctm[0]*corner[0] + ctm[2]*corner[1] + ctm[4]
+				y := ctm[1]*corner[0] + ctm[3]*corner[1] + ctm[5]
+				x, y = UprightPoint(x, y, rotation, page.Width, page.Height)
+				bounds = bounds.Union(Box{MinX: x, MinY: y, MaxX: x, MaxY: y})
+			}
+			placements = append(placements, XObjectPlacement{Name: name, Bounds: bounds})
+		}
+	}
+	return placements
+}
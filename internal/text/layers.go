@@ -0,0 +1,52 @@
+package text
+
+import (
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// HiddenXObjects returns the names of the XObjects that a page draws only inside marked content
+// of hidden optional content groups (layers). XObjects drawn by form XObjects are not followed.
+func HiddenXObjects(page document.PDFPage) map[string]bool {
+	hidden := make(map[string]bool)
+	if len(page.HiddenContent) == 0 {
+		return hidden
+	}
+
+	shown := make(map[string]bool)
+	var sequences []bool // Open marked-content sequences, true for hidden optional content
+	depth := 0           // Number of open hidden sequences
+	for _, op := range content.ParseOperations(page.Contents) {
+		switch op.Operator {
+		case "BMC", "BDC":
+			h := op.Operator == "BDC" && len(op.Operands) == 2 && op.Operands[0] == "/OC" &&
+				page.HiddenContent[resourceName(op.Operands[1])]
+			sequences = append(sequences, h)
+			if h {
+				depth++
+			}
+		case "EMC":
+			if n := len(sequences); n > 0 {
+				if sequences[n-1] {
+					depth--
+				}
+				sequences = sequences[:n-1]
+			}
+		case "Do":
+			if len(op.Operands) == 0 {
+				continue
+			}
+			name := resourceName(op.Operands[0])
+			if depth > 0 {
+				hidden[name] = true
+			} else {
+				shown[name] = true
+			}
+		}
+	}
+
+	for name := range shown {
+		delete(hidden, name)
+	}
+	return hidden
+}
@@ -0,0 +1,123 @@
+package text
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Layout tuning parameters
+const (
+	layoutLineSpacing   = 1.2 // Line height as a multiple of the typical font size
+	layoutMaxBlankLines = 3   // Most blank lines inserted for a single vertical gap
+)
+
+// LayoutText renders text positions as a fixed-width character grid that preserves column
+// alignment and indentation, similar to pdftotext -layout. Positions must be in upright page
+// space; pages of vertical text fall back to reading-order text.
+func LayoutText(positions []document.TextPosition) string {
+	if isVerticalText(positions) {
+		page := document.PDFPage{TextPositions: positions}
+		return page.ExtractOrderedText()
+	}
+
+	var spans []document.TextPosition
+	for _, pos := range positions {
+		if pos.Text != "" && !pos.Vertical {
+			spans = append(spans, pos)
+		}
+	}
+	if len(spans) == 0 {
+		return ""
+	}
+
+	charWidth := layoutCharWidth(spans)
+	lineHeight := medianFontSize(spans) * layoutLineSpacing
+
+	minX := spans[0].X
+	for _, pos := range spans {
+		minX = math.Min(minX, pos.X)
+	}
+
+	var sb strings.Builder
+	lines := clusterBaselines(spans)
+	for i, line := range lines {
+		if i > 0 {
+			// Reproduce large vertical gaps as blank lines
+			gap := lines[i-1][0].Y - line[0].Y
+			blank := int(math.Round(gap/lineHeight)) - 1
+			if blank > layoutMaxBlankLines {
+				blank = layoutMaxBlankLines
+			}
+			sb.WriteString("\n")
+			for ; blank > 0; blank-- {
+				sb.WriteString("\n")
+			}
+		}
+		sb.WriteString(layoutLine(line, minX, charWidth))
+	}
+
+	return sb.String()
+}
+
+// layoutLine places the positions of a line at their character columns
+func layoutLine(line []document.TextPosition, minX, charWidth float64) string {
+	var row []rune
+	prevEnd := math.Inf(-1)
+
+	for _, pos := range line {
+		col := int(math.Round((pos.X - minX) / charWidth))
+		if col < len(row) {
+			// Overlapping or touching runs are joined, separated by a space if there is a gap
+			col = len(row)
+			if pos.X-prevEnd > charWidth*0.3 {
+				col++
+			}
+		}
+
+		for len(row) < col {
+			row = append(row, ' ')
+		}
+		row = append(row, []rune(pos.Text)...)
+		prevEnd = pos.X + pos.Width
+	}
+
+	return strings.TrimRight(string(row), " ")
+}
+
+// layoutCharWidth returns the typical advance of a character, used as the grid column width
+func layoutCharWidth(spans []document.TextPosition) float64 {
+	var widths []float64
+	for _, pos := range spans {
+		if count := utf8.RuneCountInString(pos.Text); pos.Width > 0 && count > 0 {
+			widths = append(widths, pos.Width/float64(count))
+		}
+	}
+
+	if len(widths) == 0 {
+		return medianFontSize(spans) * defaultGlyphWidth
+	}
+
+	sort.Float64s(widths)
+	return widths[len(widths)/2]
+}
+
+// medianFontSize returns the median font size of the positions
+func medianFontSize(spans []document.TextPosition) float64 {
+	sizes := make([]float64, 0, len(spans))
+	for _, pos := range spans {
+		if pos.FontSize > 0 {
+			sizes = append(sizes, pos.FontSize)
+		}
+	}
+
+	if len(sizes) == 0 {
+		return 12
+	}
+
+	sort.Float64s(sizes)
+	return sizes[len(sizes)/2]
+}
@@ -0,0 +1,242 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Mojibake detection parameters
+const (
+	garbledQuality  = 0.95 // Pages whose text scores below this look garbled
+	minQualityRunes = 20   // Pages with fewer non-space characters are too short to judge
+	repairGain      = 0.02 // Smallest improvement of the score for which a repair is kept
+)
+
+// windows1252 maps the runes of Windows-1252 above 0x7F back to their bytes, those that
+// differ from ISO-8859-1 included
+var windows1252 = func() map[rune]byte {
+	bytes := make(map[rune]byte)
+	for b := 0xA0; b < 0x100; b++ {
+		bytes[rune(b)] = byte(b)
+	}
+	for code, r := range winAnsiOverrides {
+		bytes[r] = byte(code)
+	}
+	return bytes
+}()
+
+// mojibakeRepair rewrites the text of a page's runs, which a decoding may join across runs
+type mojibakeRepair func(texts []string) []string
+
+// mojibakeRepairs are the alternative decodings tried on garbled text
+var mojibakeRepairs = []mojibakeRepair{
+	// UTF-8 bytes that were decoded as Windows-1252, as in "cafÃ©" for "café"
+	func(texts []string) []string { return utf8RoundTrip(texts, windows1252Byte) },
+	// UTF-8 bytes that were decoded as ISO-8859-1, leaving C1 controls between the letters
+	func(texts []string) []string { return utf8RoundTrip(texts, latin1Byte) },
+	// Windows-1252 text decoded as ISO-8859-1, leaving C1 controls for quotes and dashes
+	func(texts []string) []string { return c1ToWindows1252(texts) },
+}
+
+// TextQuality scores how plausible decoded text is, from 0 to 1: the fraction of its
+// non-space characters that are neither control characters, replacement or private-use
+// characters, nor part of UTF-8 sequences decoded as single-byte text
+func TextQuality(text string) float64 {
+	runes := []rune(text)
+	var total, suspect int
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if unicode.IsSpace(r) {
+			continue
+		}
+		total++
+		if n := misdecodedLength(runes[i:], windows1252Byte); n > 0 {
+			total += n - 1
+			suspect += n
+			i += n - 1
+			continue
+		}
+		if r == utf8.RuneError || nonPrintable(r) {
+			suspect++
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return 1 - float64(suspect)/float64(total)
+}
+
+// nonPrintable reports whether a non-space rune is a control, private-use or unassigned
+// character, which glyphs without a Unicode mapping decode to. Format characters such as soft
+// hyphens are invisible but legitimate.
+func nonPrintable(r rune) bool {
+	return unicode.IsControl(r) || unicode.Is(unicode.Co, r) || !unicode.IsPrint(r) && !unicode.Is(unicode.Cf, r)
+}
+
+// Garbled reports whether decoded text looks like mojibake, such as that of a wrong ToUnicode map
+func Garbled(text string) bool {
+	if len([]rune(strings.Join(strings.Fields(text), ""))) < minQualityRunes {
+		return false
+	}
+	return TextQuality(text) < garbledQuality
+}
+
+// RepairMojibake tries alternative decodings on the text positions of a page that looks
+// garbled and keeps the one whose text scores best, if it is clearly better. It reports
+// whether the positions were repaired.
+func RepairMojibake(positions []document.TextPosition) bool {
+	texts := make([]string, len(positions))
+	for i, pos := range positions {
+		texts[i] = pos.Text
+	}
+	joined := strings.Join(texts, "")
+	if !Garbled(joined) {
+		return false
+	}
+
+	best, bestScore := texts, TextQuality(joined)+repairGain
+	repaired := false
+	for _, repair := range mojibakeRepairs {
+		candidate := repair(texts)
+		if score := TextQuality(strings.Join(candidate, "")); score > bestScore {
+			best, bestScore, repaired = candidate, score, true
+		}
+	}
+	if !repaired {
+		return false
+	}
+
+	for i := range positions {
+		if best[i] == positions[i].Text {
+			continue
+		}
+		positions[i].Text = best[i]
+		// Repaired runs share their advance evenly between their new characters
+		n := utf8.RuneCountInString(best[i])
+		positions[i].CharWidths = positions[i].CharWidths[:0]
+		for j := 0; j < n; j++ {
+			positions[i].CharWidths = append(positions[i].CharWidths, positions[i].Width/float64(n))
+		}
+	}
+	return true
+}
+
+// utf8RoundTrip turns runes back into the bytes a single-byte decoding made them from and
+// decodes the valid multi-byte UTF-8 sequences among them, leaving other runes alone. A
+// sequence split across runs is decoded into the first.
+func utf8RoundTrip(texts []string, toByte func(rune) (byte, bool)) []string {
+	type item struct {
+		r   rune
+		run int
+	}
+	var items []item
+	for run, text := range texts {
+		for _, r := range text {
+			items = append(items, item{r, run})
+		}
+	}
+
+	out := make([]strings.Builder, len(texts))
+	runes := make([]rune, len(items))
+	for i, it := range items {
+		runes[i] = it.r
+	}
+	for i := 0; i < len(items); i++ {
+		if n := misdecodedLength(runes[i:], toByte); n > 0 {
+			encoded := make([]byte, n)
+			for j := range encoded {
+				encoded[j], _ = toByte(runes[i+j])
+			}
+			decoded, _ := utf8.DecodeRune(encoded)
+			out[items[i].run].WriteRune(decoded)
+			i += n - 1
+			continue
+		}
+		out[items[i].run].WriteRune(runes[i])
+	}
+
+	result := make([]string, len(texts))
+	for i := range out {
+		result[i] = out[i].String()
+	}
+	return result
+}
+
+// misdecodedLength returns the number of runes at the start of runes that are a multi-byte
+// UTF-8 sequence decoded one byte per rune, or 0
+func misdecodedLength(runes []rune, toByte func(rune) (byte, bool)) int {
+	lead, ok := toByte(runes[0])
+	if !ok {
+		return 0
+	}
+	var n int
+	switch {
+	case lead >= 0xC2 && lead <= 0xDF:
+		n = 2
+	case lead >= 0xE0 && lead <= 0xEF:
+		n = 3
+	case lead >= 0xF0 && lead <= 0xF4:
+		n = 4
+	default:
+		return 0
+	}
+	if len(runes) < n {
+		return 0
+	}
+
+	encoded := []byte{lead}
+	for _, r := range runes[1:n] {
+		b, ok := toByte(r)
+		if !ok || b < 0x80 || b > 0xBF {
+			return 0
+		}
+		encoded = append(encoded, b)
+	}
+	if decoded, size := utf8.DecodeRune(encoded); decoded == utf8.RuneError || size != n {
+		return 0
+	}
+	return n
+}
+
+// c1ToWindows1252 replaces the C1 control characters of text decoded as ISO-8859-1 with the
+// Windows-1252 characters of the same codes
+func c1ToWindows1252(texts []string) []string {
+	result := make([]string, len(texts))
+	for i, text := range texts {
+		result[i] = strings.Map(func(r rune) rune {
+			if replacement, ok := winAnsiOverrides[int(r)]; ok {
+				return replacement
+			}
+			return r
+		}, text)
+	}
+	return result
+}
+
+// windows1252Byte returns the Windows-1252 byte of a rune above 0x7F
+func windows1252Byte(r rune) (byte, bool) {
+	b, ok := windows1252[r]
+	return b, ok
+}
+
+// latin1Byte returns the ISO-8859-1 byte of a rune above 0x7F, C1 controls included
+func latin1Byte(r rune) (byte, bool) {
+	if r >= 0x80 && r <= 0xFF {
+		return byte(r), true
+	}
+	return 0, false
+}
+
+// garbledPages returns the 1-based numbers of the pages whose text looks garbled
+func garbledPages(pageTexts []string) []int {
+	var pages []int
+	for i, text := range pageTexts {
+		if Garbled(text) {
+			pages = append(pages, i+1)
+		}
+	}
+	return pages
+}
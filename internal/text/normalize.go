@@ -0,0 +1,124 @@
+package text
+
+import (
+	"strings"
+)
+
+// NormalizationMode selects the Unicode normalization applied to decoded text
+type NormalizationMode int
+
+// Normalization modes
+const (
+	// NormalizeNone leaves decoded text unchanged
+	NormalizeNone NormalizationMode = iota
+	// NormalizeLigatures decomposes Latin typographic ligatures such as U+FB01 (fi)
+	NormalizeLigatures
+	// NormalizeCompatibility additionally maps Arabic presentation forms to their base letters,
+	// fullwidth ASCII to ASCII and no-break spaces to spaces, a targeted subset of NFKC
+	NormalizeCompatibility
+)
+
+// latinLigatures maps the Alphabetic Presentation Forms ligatures to their letters
+var latinLigatures = map[rune]string{
+	'ﬀ': "ff",
+	'ﬁ': "fi",
+	'ﬂ': "fl",
+	'ﬃ': "ffi",
+	'ﬄ': "ffl",
+	'ﬅ': "st",
+	'ﬆ': "st",
+	'Ĳ': "IJ",
+	'ĳ': "ij",
+}
+
+// arabicFormsB lists the base letters of Arabic Presentation Forms-B (U+FE80 to U+FEF4)
+// in block order, together with the number of contextual forms each letter has
+var arabicFormsB = []struct {
+	base  rune
+	forms int
+}{
+	{'ء', 1}, {'آ', 2}, {'أ', 2}, {'ؤ', 2}, {'إ', 2}, {'ئ', 4},
+	{'ا', 2}, {'ب', 4}, {'ة', 2}, {'ت', 4}, {'ث', 4}, {'ج', 4},
+	{'ح', 4}, {'خ', 4}, {'د', 2}, {'ذ', 2}, {'ر', 2}, {'ز', 2},
+	{'س', 4}, {'ش', 4}, {'ص', 4}, {'ض', 4}, {'ط', 4}, {'ظ', 4},
+	{'ع', 4}, {'غ', 4}, {'ف', 4}, {'ق', 4}, {'ك', 4}, {'ل', 4},
+	{'م', 4}, {'ن', 4}, {'ه', 4}, {'و', 2}, {'ى', 2}, {'ي', 4},
+}
+
+// arabicSpecialForms maps the presentation forms that don't decompose to a single letter
+var arabicSpecialForms = map[rune]string{
+	// Lam-alef ligatures (isolated and final forms)
+	'ﻵ': "لآ", 'ﻶ': "لآ",
+	'ﻷ': "لأ", 'ﻸ': "لأ",
+	'ﻹ': "لإ", 'ﻺ': "لإ",
+	'ﻻ': "لا", 'ﻼ': "لا",
+	// Spacing and tatweel forms of the harakat
+	'ﹰ': " ً", 'ﹱ': "ـً", 'ﹲ': " ٌ", 'ﹴ': " ٍ",
+	'ﹶ': " َ", 'ﹷ': "ـَ", 'ﹸ': " ُ", 'ﹹ': "ـُ",
+	'ﹺ': " ِ", 'ﹻ': "ـِ", 'ﹼ': " ّ", 'ﹽ': "ـّ",
+	'ﹾ': " ْ", 'ﹿ': "ـْ",
+}
+
+// compatibilityForms holds the decompositions used by NormalizeCompatibility, built once
+var compatibilityForms = buildCompatibilityForms()
+
+// buildCompatibilityForms builds the compatibility decomposition map
+func buildCompatibilityForms() map[rune]string {
+	forms := make(map[rune]string)
+
+	for r, s := range latinLigatures {
+		forms[r] = s
+	}
+
+	for r, s := range arabicSpecialForms {
+		forms[r] = s
+	}
+
+	code := rune(0xFE80)
+	for _, letter := range arabicFormsB {
+		for i := 0; i < letter.forms; i++ {
+			forms[code] = string(letter.base)
+			code++
+		}
+	}
+
+	// Fullwidth ASCII variants
+	for r := rune(0xFF01); r <= 0xFF5E; r++ {
+		forms[r] = string(r - 0xFF01 + '!')
+	}
+
+	forms[' '] = " "
+	forms['　'] = " "
+
+	return forms
+}
+
+// NormalizeText applies the given normalization mode to decoded text
+func NormalizeText(text string, mode NormalizationMode) string {
+	var table map[rune]string
+	switch mode {
+	case NormalizeLigatures:
+		table = latinLigatures
+	case NormalizeCompatibility:
+		table = compatibilityForms
+	default:
+		return text
+	}
+
+	// Avoid allocating when there is nothing to replace
+	if strings.IndexFunc(text, func(r rune) bool { _, ok := table[r]; return ok }) == -1 {
+		return text
+	}
+
+	var result strings.Builder
+	result.Grow(len(text))
+	for _, r := range text {
+		if replacement, ok := table[r]; ok {
+			result.WriteString(replacement)
+		} else {
+			result.WriteRune(r)
+		}
+	}
+
+	return result.String()
+}
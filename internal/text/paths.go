@@ -0,0 +1,252 @@
+package text
+
+import (
+	"math"
+
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Path segment operations
+const (
+	SegmentMove  = 'M' // Start a new subpath at Points[0]
+	SegmentLine  = 'L' // Straight line to Points[0]
+	SegmentCurve = 'C' // Cubic Bézier curve through the control points Points[0] and Points[1] to Points[2]
+	SegmentClose = 'Z' // Straight line back to the start of the subpath
+)
+
+// PathSegment is one operation of a path, with its points in user space
+type PathSegment struct {
+	Op     byte
+	Points [][2]float64
+	Rect   bool // Part of a rectangle drawn with re
+}
+
+// Color is an RGB colour with components from 0 to 1
+type Color struct {
+	R, G, B float64
+}
+
+// Path is a path painted by a content stream, in user space
+type Path struct {
+	Segments    []PathSegment
+	Fill        bool
+	EvenOdd     bool // Fill with the even-odd rule rather than nonzero winding
+	Stroke      bool
+	FillColor   Color
+	StrokeColor Color
+	LineWidth   float64   // In user space
+	Dash        []float64 // Dash lengths in user space, empty for solid lines
+}
+
+// paintState is the part of the graphics state that affects painted paths
+type paintState struct {
+	ctm         [6]float64
+	fillColor   Color
+	strokeColor Color
+	lineWidth   float64
+	dash        []float64
+}
+
+// ExtractPaths interprets the path construction and painting operators of a content stream and
+// returns the painted paths in drawing order. Paths used only for clipping are not returned.
+// Colours in DeviceGray, DeviceRGB and DeviceCMYK are converted to RGB; other colour spaces are
+// treated by their number of components. Form XObjects and shading operators are not followed.
+func ExtractPaths(data []byte) []Path {
+	return extractPaths(data, identityMatrix)
+}
+
+// PagePaths returns the painted paths of a page in points, scaled by the page's /UserUnit
+func PagePaths(page *document.PDFPage) []Path {
+	return extractPaths(page.Contents, unitMatrix(page))
+}
+
+// extractPaths returns the painted paths of a content stream, starting from the given CTM
+func extractPaths(data []byte, ctm [6]float64) []Path {
+	var paths []Path
+	var segments []PathSegment
+	var stack []paintState
+	state := paintState{ctm: ctm, lineWidth: 1}
+	var current, start [2]float64
+
+	point := func(x, y float64) [2]float64 {
+		return [2]float64{
+			state.ctm[0]*x + state.ctm[2]*y + state.ctm[4],
+			state.ctm[1]*x + state.ctm[3]*y + state.ctm[5],
+		}
+	}
+	paint := func(fill, evenOdd, stroke bool) {
+		if len(segments) > 0 {
+			scale := matrixScale(state.ctm)
+			path := Path{
+				Segments:    segments,
+				Fill:        fill,
+				EvenOdd:     evenOdd,
+				Stroke:      stroke,
+				FillColor:   state.fillColor,
+				StrokeColor: state.strokeColor,
+				LineWidth:   state.lineWidth * scale,
+			}
+			for _, length := range state.dash {
+				path.Dash = append(path.Dash, length*scale)
+			}
+			paths = append(paths, path)
+		}
+		segments = nil
+	}
+	closePath := func() {
+		segments = append(segments, PathSegment{Op: SegmentClose})
+		current = start
+	}
+
+	for _, op := range content.ParseOperations(data) {
+		switch op.Operator {
+		case "q":
+			stack = append(stack, state)
+		case "Q":
+			if len(stack) > 0 {
+				state = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case "cm":
+			if values, ok := parseNumericOperands(nil, op.Operands, 6); ok {
+				var m [6]float64
+				copy(m[:], values)
+				state.ctm = multiplyMatrix(m, state.ctm)
+			}
+		case "w":
+			if values, ok := parseNumericOperands(nil, op.Operands, 1); ok {
+				state.lineWidth = values[0]
+			}
+		case "d":
+			state.dash = parseDashArray(op.Operands)
+		case "g", "rg", "k", "sc", "scn":
+			state.fillColor = parseColor(op.Operands, state.fillColor)
+		case "G", "RG", "K", "SC", "SCN":
+			state.strokeColor = parseColor(op.Operands, state.strokeColor)
+		case "cs":
+			state.fillColor = Color{}
+		case "CS":
+			state.strokeColor = Color{}
+		case "m":
+			if values, ok := parseNumericOperands(nil, op.Operands, 2); ok {
+				current = point(values[0], values[1])
+				start = current
+				segments = append(segments, PathSegment{Op: SegmentMove, Points: [][2]float64{current}})
+			}
+		case "l":
+			if values, ok := parseNumericOperands(nil, op.Operands, 2); ok {
+				current = point(values[0], values[1])
+				segments = append(segments, PathSegment{Op: SegmentLine, Points: [][2]float64{current}})
+			}
+		case "c":
+			if values, ok := parseNumericOperands(nil, op.Operands, 6); ok {
+				p1, p2, p3 := point(values[0], values[1]), point(values[2], values[3]), point(values[4], values[5])
+				segments = append(segments, PathSegment{Op: SegmentCurve, Points: [][2]float64{p1, p2, p3}})
+				current = p3
+			}
+		case "v":
+			// The first control point is the current point
+			if values, ok := parseNumericOperands(nil, op.Operands, 4); ok {
+				p2, p3 := point(values[0], values[1]), point(values[2], values[3])
+				segments = append(segments, PathSegment{Op: SegmentCurve, Points: [][2]float64{current, p2, p3}})
+				current = p3
+			}
+		case "y":
+			// The second control point is the end point
+			if values, ok := parseNumericOperands(nil, op.Operands, 4); ok {
+				p1, p3 := point(values[0], values[1]), point(values[2], values[3])
+				segments = append(segments, PathSegment{Op: SegmentCurve, Points: [][2]float64{p1, p3, p3}})
+				current = p3
+			}
+		case "h":
+			closePath()
+		case "re":
+			values, ok := parseNumericOperands(nil, op.Operands, 4)
+			if !ok {
+				continue
+			}
+			x, y, w, h := values[0], values[1], values[2], values[3]
+			p1, p2, p3, p4 := point(x, y), point(x+w, y), point(x+w, y+h), point(x, y+h)
+			segments = append(segments,
+				PathSegment{Op: SegmentMove, Points: [][2]float64{p1}, Rect: true},
+				PathSegment{Op: SegmentLine, Points: [][2]float64{p2}, Rect: true},
+				PathSegment{Op: SegmentLine, Points: [][2]float64{p3}, Rect: true},
+				PathSegment{Op: SegmentLine, Points: [][2]float64{p4}, Rect: true},
+				PathSegment{Op: SegmentClose, Rect: true},
+			)
+			current, start = p1, p1
+		case "S":
+			paint(false, false, true)
+		case "s":
+			closePath()
+			paint(false, false, true)
+		case "f", "F":
+			paint(true, false, false)
+		case "f*":
+			paint(true, true, false)
+		case "B":
+			paint(true, false, true)
+		case "B*":
+			paint(true, true, true)
+		case "b":
+			closePath()
+			paint(true, false, true)
+		case "b*":
+			closePath()
+			paint(true, true, true)
+		case "n":
+			segments = nil
+		}
+	}
+
+	return paths
+}
+
+// matrixScale returns the factor by which a transformation matrix scales lengths on average
+func matrixScale(m [6]float64) float64 {
+	return math.Sqrt(math.Abs(m[0]*m[3] - m[1]*m[2]))
+}
+
+// parseColor converts colour operands to RGB, choosing the colour space from the number of
+// components. Operands it cannot interpret leave the colour unchanged.
+func parseColor(operands []string, current Color) Color {
+	// Pattern names given to scn are not colours
+	var numeric []string
+	for _, operand := range operands {
+		if len(operand) > 0 && operand[0] != '/' {
+			numeric = append(numeric, operand)
+		}
+	}
+
+	switch len(numeric) {
+	case 1:
+		if v, ok := parseNumericOperands(nil, numeric, 1); ok {
+			return Color{v[0], v[0], v[0]}
+		}
+	case 3:
+		if v, ok := parseNumericOperands(nil, numeric, 3); ok {
+			return Color{v[0], v[1], v[2]}
+		}
+	case 4:
+		if v, ok := parseNumericOperands(nil, numeric, 4); ok {
+			return Color{(1 - v[0]) * (1 - v[3]), (1 - v[1]) * (1 - v[3]), (1 - v[2]) * (1 - v[3])}
+		}
+	}
+	return current
+}
+
+// parseDashArray returns the dash lengths of a d operator, whose operands are an array and a phase
+func parseDashArray(operands []string) []float64 {
+	if len(operands) < 2 {
+		return nil
+	}
+
+	var dash []float64
+	for _, item := range content.ParseArrayOperand(operands[len(operands)-2]) {
+		if v, ok := parseNumericOperands(nil, []string{item}, 1); ok {
+			dash = append(dash, v[0])
+		}
+	}
+	return dash
+}
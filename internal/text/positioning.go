@@ -9,51 +9,100 @@ import (
 
 // SortTextPositions sorts text positions in reading order
 func SortTextPositions(positions []document.TextPosition, pageWidth, pageHeight float64) {
-	// Simplified approach: sort by rows, then by columns within each row
-	const lineHeightFactor = 1.5 // Multiplier for line height threshold
-
-	// Find average font size to determine line height
-	var totalFontSize float64
-	for _, pos := range positions {
-		totalFontSize += pos.FontSize
+	if isVerticalText(positions) {
+		sortVerticalPositions(positions)
+		return
 	}
 
-	// Default line height if no positions
-	lineHeight := 14.0
-	if len(positions) > 0 {
-		avgFontSize := totalFontSize / float64(len(positions))
-		lineHeight = avgFontSize * lineHeightFactor
+	// Group positions into lines by baseline; each line is ordered left to right
+	var ordered []document.TextPosition
+	for _, line := range clusterBaselines(positions) {
+		ordered = append(ordered, line...)
 	}
 
-	// Group by rows based on Y position
-	rows := make(map[int][]document.TextPosition)
-	for _, pos := range positions {
-		rowKey := int(pos.Y / lineHeight)
-		rows[rowKey] = append(rows[rowKey], pos)
+	copy(positions, ordered)
+}
+
+// Fraction of the smaller font size within which two baselines are considered the same line
+const baselineTolerance = 0.5
+
+// clusterBaselines groups positions into lines, top to bottom, each sorted left to right. A position joins the current line
+// when its baseline is close to the line's mean baseline, measured relative to the font sizes
+// involved, so large headings and small footnotes on the same page each get a suitable tolerance.
+func clusterBaselines(positions []document.TextPosition) [][]document.TextPosition {
+	sorted := make([]document.TextPosition, len(positions))
+	copy(sorted, positions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Y > sorted[j].Y
+	})
+
+	var lines [][]document.TextPosition
+	var baselineSum, lineFontSize float64
+	for _, pos := range sorted {
+		if n := len(lines); n > 0 {
+			baseline := baselineSum / float64(len(lines[n-1]))
+			fontSize := math.Min(pos.FontSize, lineFontSize)
+			if fontSize <= 0 {
+				fontSize = math.Max(pos.FontSize, lineFontSize)
+			}
+			if baseline-pos.Y <= fontSize*baselineTolerance {
+				lines[n-1] = append(lines[n-1], pos)
+				baselineSum += pos.Y
+				lineFontSize = math.Max(lineFontSize, pos.FontSize)
+				continue
+			}
+		}
+
+		lines = append(lines, []document.TextPosition{pos})
+		baselineSum = pos.Y
+		lineFontSize = pos.FontSize
 	}
 
-	// Sort each row by X position
-	for rowKey, rowPositions := range rows {
-		sort.Slice(rowPositions, func(i, j int) bool {
-			return rowPositions[i].X < rowPositions[j].X
+	for _, line := range lines {
+		sort.SliceStable(line, func(i, j int) bool {
+			return line[i].X < line[j].X
 		})
-		rows[rowKey] = rowPositions
 	}
 
-	// Get sorted row keys
-	var rowKeys []int
-	for k := range rows {
-		rowKeys = append(rowKeys, k)
+	return lines
+}
+
+// isVerticalText reports whether most of the text is written in vertical writing mode
+func isVerticalText(positions []document.TextPosition) bool {
+	var vertical, horizontal int
+	for _, pos := range positions {
+		if pos.Vertical {
+			vertical += len(pos.Text)
+		} else {
+			horizontal += len(pos.Text)
+		}
 	}
+	return vertical > horizontal
+}
 
-	// Sort in descending order (assuming Y increases downward)
-	sort.Sort(sort.Reverse(sort.IntSlice(rowKeys)))
+// sortVerticalPositions sorts vertically written text in reading order: columns from
+// right to left, and top to bottom within each column
+func sortVerticalPositions(positions []document.TextPosition) {
+	const columnWidthFactor = 1.5 // Multiplier for column width threshold
 
-	// Rebuild the positions array in reading order
-	positions = positions[:0]
-	for _, rowKey := range rowKeys {
-		positions = append(positions, rows[rowKey]...)
+	var totalFontSize float64
+	for _, pos := range positions {
+		totalFontSize += pos.FontSize
 	}
+
+	columnWidth := 14.0
+	if len(positions) > 0 && totalFontSize > 0 {
+		columnWidth = totalFontSize / float64(len(positions)) * columnWidthFactor
+	}
+
+	sort.SliceStable(positions, func(i, j int) bool {
+		colI := int(math.Floor(positions[i].X / columnWidth))
+		colJ := int(math.Floor(positions[j].X / columnWidth))
+		if colI != colJ {
+			return colI > colJ
+		}
+		return positions[i].Y > positions[j].Y
+	})
 }
 
 // DetectColumns attempts to identify columns in text positions
@@ -188,18 +237,23 @@ func DetectParagraphs(positions []document.TextPosition) [][]document.TextPositi
 	// First position is always start of first paragraph
 	currentParagraph = append(currentParagraph, positions[0])
 	lastY = positions[0].Y
+	lineStart := positions[0].X
 
 	for i := 1; i < len(positions); i++ {
-		// Check if this is a new paragraph
+		// A paragraph ends at a large vertical gap, or before an indented first line
 		yDiff := math.Abs(positions[i].Y - lastY)
+		newLine := yDiff > positions[i].FontSize*baselineTolerance
 
-		if yDiff > paragraphBreak || (yDiff > 0 && positions[i].X < positions[i-1].X) {
+		if newLine && (yDiff > paragraphBreak || positions[i].X > lineStart+avgFontSize) {
 			// End of paragraph, start new one
 			if len(currentParagraph) > 0 {
 				paragraphs = append(paragraphs, currentParagraph)
 				currentParagraph = []document.TextPosition{}
 			}
 		}
+		if newLine {
+			lineStart = positions[i].X
+		}
 
 		currentParagraph = append(currentParagraph, positions[i])
 		lastY = positions[i].Y
@@ -225,8 +279,11 @@ func CalculateTextBounds(positions []document.TextPosition) (minX, minY, maxX, m
 	maxY = positions[0].Y
 
 	for _, pos := range positions {
-		// Estimate text width based on font size
-		textWidth := float64(len(pos.Text)) * pos.FontSize * 0.6
+		// Estimate text width based on font size if the advance is unknown
+		textWidth := pos.Width
+		if textWidth <= 0 {
+			textWidth = float64(len(pos.Text)) * pos.FontSize * 0.6
+		}
 
 		if pos.X < minX {
 			minX = pos.X
@@ -245,6 +302,19 @@ func CalculateTextBounds(positions []document.TextPosition) (minX, minY, maxX, m
 	return minX, minY, maxX, maxY
 }
 
+// TextBounds returns the box enclosing the glyphs of positions, including ascenders and descenders
+func TextBounds(positions []document.TextPosition) Box {
+	if len(positions) == 0 {
+		return Box{}
+	}
+
+	bounds := positionBox(positions[0])
+	for _, pos := range positions[1:] {
+		bounds = bounds.Union(positionBox(pos))
+	}
+	return bounds
+}
+
 // IsLikelyHeader checks if a text position is likely a header
 func IsLikelyHeader(pos document.TextPosition, avgFontSize float64) bool {
 	// Headers are typically larger than surrounding text
@@ -274,16 +344,16 @@ func DetectTextBlocks(positions []document.TextPosition, pageWidth, pageHeight f
 	for i := 1; i < len(paragraphs); i++ {
 		// Calculate bounds of current block and this paragraph
 		// blockMinX, blockMinY, blockMaxX, blockMaxY := CalculateTextBounds(currentBlock)
-		blockMinX, _, blockMaxX, blockMaxY := CalculateTextBounds(currentBlock)
-		paraMinX, paraMinY, paraMaxX, _ := CalculateTextBounds(paragraphs[i])
+		blockMinX, blockMinY, blockMaxX, _ := CalculateTextBounds(currentBlock)
+		paraMinX, _, paraMaxX, paraMaxY := CalculateTextBounds(paragraphs[i])
 
 		// Check if paragraph is part of the same block
 		// 1. Similar horizontal position
 		horizontalOverlap := math.Max(0, math.Min(blockMaxX, paraMaxX)-math.Max(blockMinX, paraMinX))
 		horizontalOverlapRatio := horizontalOverlap / math.Min(blockMaxX-blockMinX, paraMaxX-paraMinX)
 
-		// 2. Vertical distance
-		verticalDistance := math.Abs(blockMaxY - paraMinY)
+		// 2. Vertical distance from the bottom of the block to the top of the paragraph
+		verticalDistance := math.Abs(blockMinY - paraMaxY)
 
 		// Calculate average font size
 		var totalFontSize float64
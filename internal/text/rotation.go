@@ -0,0 +1,96 @@
+package text
+
+import (
+	"math"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// NormalizeRotation maps text positions from user space into upright page space, as the page
+// is displayed. The page's /Rotate value is applied first; if most of the remaining text still
+// runs in another direction (content drawn with a rotated text matrix), the positions are turned
+// so that direction becomes horizontal. It returns the page width and height in the new space
+// and the total clockwise rotation applied.
+func NormalizeRotation(positions []document.TextPosition, rotation int, pageWidth, pageHeight float64) (float64, float64, int) {
+	width, height := rotatePositions(positions, rotation, pageWidth, pageHeight)
+
+	if dominant := dominantDirection(positions); dominant != 0 {
+		// Turning clockwise by the dominant angle makes that direction horizontal
+		width, height = rotatePositions(positions, dominant, width, height)
+		rotation = (rotation + dominant) % 360
+	}
+
+	return width, height, rotation
+}
+
+// UprightPoint turns a point clockwise by a multiple of 90 degrees within a page of the given size
+func UprightPoint(x, y float64, rotation int, pageWidth, pageHeight float64) (float64, float64) {
+	switch rotation {
+	case 90:
+		return y, pageWidth - x
+	case 180:
+		return pageWidth - x, pageHeight - y
+	case 270:
+		return pageHeight - y, x
+	}
+	return x, y
+}
+
+// UserSpacePoint maps a point from upright page space back into the default user space of a
+// page of the given size, undoing a clockwise rotation applied by NormalizeRotation
+func UserSpacePoint(x, y float64, rotation int, pageWidth, pageHeight float64) (float64, float64) {
+	switch rotation {
+	case 90:
+		return pageWidth - y, x
+	case 180:
+		return pageWidth - x, pageHeight - y
+	case 270:
+		return y, pageHeight - x
+	}
+	return x, y
+}
+
+// rotatePositions turns positions clockwise by a multiple of 90 degrees within a page of the
+// given size, and returns the page size after rotation
+func rotatePositions(positions []document.TextPosition, rotation int, pageWidth, pageHeight float64) (float64, float64) {
+	if rotation == 0 {
+		return pageWidth, pageHeight
+	}
+
+	for i := range positions {
+		positions[i].X, positions[i].Y = UprightPoint(positions[i].X, positions[i].Y, rotation, pageWidth, pageHeight)
+		positions[i].Angle = normalizeAngle(positions[i].Angle - float64(rotation))
+	}
+
+	if rotation == 90 || rotation == 270 {
+		return pageHeight, pageWidth
+	}
+	return pageWidth, pageHeight
+}
+
+// dominantDirection returns the quadrant (0, 90, 180 or 270) most text runs in, weighted by length
+func dominantDirection(positions []document.TextPosition) int {
+	var weights [4]int
+	for _, pos := range positions {
+		quadrant := int(math.Round(normalizeAngle(pos.Angle)/90)) % 4
+		weights[quadrant] += len(pos.Text)
+	}
+
+	dominant := 0
+	for quadrant, weight := range weights {
+		if weight > weights[dominant] {
+			dominant = quadrant
+		}
+	}
+
+	return dominant * 90
+}
+
+// normalizeAngle maps an angle in degrees into the range [0, 360)
+func normalizeAngle(angle float64) float64 {
+	angle = math.Mod(angle, 360)
+	if angle < 0 {
+		angle += 360
+	}
+	return angle
+}
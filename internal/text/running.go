@@ -0,0 +1,163 @@
+package text
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Running header and footer detection tuning parameters
+const (
+	runningLineDepth = 3   // Lines at the top and at the bottom of a page that may be running lines
+	runningMinShare  = 0.4 // Share of pages a line must appear on; below half allows for alternating headers
+	runningMinPages  = 2   // Fewest pages a line must appear on
+	runningYBucket   = 4.0 // Vertical distance in points within which lines are at the same place
+)
+
+// Patterns for recognising running lines
+var (
+	digitsPattern     = regexp.MustCompile(`\d+`)
+	pageNumberPattern = regexp.MustCompile(`(?i)^[-–—\s\[(]*(page\s+)?(\d+|m{0,3}(cm|cd|d?c{0,3})(xc|xl|l?x{0,3})(ix|iv|v?i{0,3}))(\s*(of|/)\s*\d+)?[-–—\s\])]*$`)
+)
+
+// RunningLine is a header, footer or page number line found on a page
+type RunningLine struct {
+	Page int // 1-based page number
+	Text string
+
+	positions []document.TextPosition
+	edge      bool // First or last line of the page
+}
+
+// FindRunningLines finds the running headers, footers and bare page numbers of a document.
+// Pages must already have their text positions. A line near the top or bottom of a page is a
+// running line when the same text, with numbers ignored, is at the same height on many other
+// pages, or when it is the first or last line and holds nothing but a page number.
+func FindRunningLines(pages []document.PDFPage) []RunningLine {
+	if len(pages) < runningMinPages {
+		return nil
+	}
+
+	candidates := make([][]RunningLine, len(pages))
+	onPages := make(map[string]map[int]bool)
+	for i, page := range pages {
+		for _, line := range marginLines(page) {
+			candidates[i] = append(candidates[i], line)
+			key := runningKey(line)
+			if onPages[key] == nil {
+				onPages[key] = make(map[int]bool)
+			}
+			onPages[key][i] = true
+		}
+	}
+
+	minPages := int(float64(len(pages))*runningMinShare + 0.5)
+	if minPages < runningMinPages {
+		minPages = runningMinPages
+	}
+
+	var running []RunningLine
+	for _, lines := range candidates {
+		for _, line := range lines {
+			if len(onPages[runningKey(line)]) >= minPages || (line.edge && isPageNumber(line.Text)) {
+				running = append(running, line)
+			}
+		}
+	}
+	return running
+}
+
+// StripRunningLines removes the running headers, footers and page numbers found by
+// FindRunningLines from the text positions of the pages, and returns the distinct texts
+// removed, sorted
+func StripRunningLines(pages []document.PDFPage) []string {
+	removed := make(map[string]bool)
+	strip := make(map[int]map[positionID]bool)
+	for _, line := range FindRunningLines(pages) {
+		removed[line.Text] = true
+		if strip[line.Page] == nil {
+			strip[line.Page] = make(map[positionID]bool)
+		}
+		for _, pos := range line.positions {
+			strip[line.Page][positionKey(pos)] = true
+		}
+	}
+
+	for i := range pages {
+		page := &pages[i]
+		if strip[page.PageNumber] == nil {
+			continue
+		}
+		kept := page.TextPositions[:0]
+		for _, pos := range page.TextPositions {
+			if !strip[page.PageNumber][positionKey(pos)] {
+				kept = append(kept, pos)
+			}
+		}
+		page.TextPositions = kept
+	}
+
+	texts := make([]string, 0, len(removed))
+	for text := range removed {
+		texts = append(texts, text)
+	}
+	sort.Strings(texts)
+	return texts
+}
+
+// marginLines returns the lines at the top and at the bottom of a page
+func marginLines(page document.PDFPage) []RunningLine {
+	var spans []document.TextPosition
+	for _, pos := range page.TextPositions {
+		if hasVisibleText(pos) && !pos.Vertical {
+			spans = append(spans, pos)
+		}
+	}
+
+	lines := clusterBaselines(spans)
+	var margin []RunningLine
+	for i, positions := range lines {
+		if i >= runningLineDepth && i < len(lines)-runningLineDepth {
+			continue
+		}
+		text := strings.TrimSpace(JoinLines(positions))
+		if text != "" {
+			margin = append(margin, RunningLine{
+				Page:      page.PageNumber,
+				Text:      text,
+				positions: positions,
+				edge:      i == 0 || i == len(lines)-1,
+			})
+		}
+	}
+	return margin
+}
+
+// runningKey identifies a line for comparison across pages by its height on the page and its
+// text, ignoring numbers and spacing
+func runningKey(line RunningLine) string {
+	text := strings.Join(strings.Fields(digitsPattern.ReplaceAllString(strings.ToLower(line.Text), "#")), " ")
+	return fmt.Sprintf("%.0f:%s", math.Round(line.positions[0].Y/runningYBucket), text)
+}
+
+// isPageNumber reports whether a line holds nothing but a page number, such as "12", "- 12 -",
+// "Page 3 of 10" or "xiv"
+func isPageNumber(text string) bool {
+	match := pageNumberPattern.FindStringSubmatch(text)
+	return match != nil && match[2] != ""
+}
+
+// positionID identifies a text position within a page
+type positionID struct {
+	x, y float64
+	text string
+}
+
+// positionKey returns the identity of a text position
+func positionKey(pos document.TextPosition) positionID {
+	return positionID{x: pos.X, y: pos.Y, text: pos.Text}
+}
@@ -0,0 +1,98 @@
+package text
+
+import (
+	"math"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Scanned page detection parameters
+const (
+	maxScanCharacters = 20  // Pages showing more glyphs than this have a text layer
+	minScanCoverage   = 0.5 // Fraction of the page that scan images must cover
+)
+
+// Filters of the images that scanners and scanning software produce
+var scanFilters = []string{"/DCTDecode", "/CCITTFaxDecode", "/JBIG2Decode", "/JPXDecode"}
+
+// PageScan describes how much of a page is a scanned image and how much is text
+type PageScan struct {
+	Characters    int     // Glyphs shown by text operators, including invisible OCR text layers
+	ImageCoverage float64 // Fraction of the page covered by DCT, CCITT fax, JBIG2 or JPX images, at most 1
+}
+
+// ImageOnly reports whether the page is a scanned image without a text layer, which needs OCR
+// for its text to be extracted
+func (s PageScan) ImageOnly() bool {
+	return s.Characters <= maxScanCharacters && s.ImageCoverage >= minScanCoverage
+}
+
+// ScanImage is an image compressed with a filter used for scans, drawn by a page
+type ScanImage struct {
+	Object document.PDFObject
+	Bounds Box // Area the image is drawn into, in points on the unrotated page
+}
+
+// PageRecognizer recognizes the text of a scanned page, returning text positions in points on
+// the unrotated page like those of the content stream
+type PageRecognizer interface {
+	RecognizePage(page *document.PDFPage) ([]document.TextPosition, error)
+}
+
+// ClassifyScan measures the scanned images and the text of a page whose glyphs have been
+// counted by extraction. Images drawn inside forms are not counted.
+func ClassifyScan(doc *document.PDFDocument, page *document.PDFPage) PageScan {
+	scan := PageScan{Characters: page.GlyphCount}
+
+	pageArea := page.Width * page.Height
+	if pageArea <= 0 {
+		return scan
+	}
+
+	var covered float64
+	for _, image := range ScanImages(doc, page) {
+		covered += (image.Bounds.MaxX - image.Bounds.MinX) * (image.Bounds.MaxY - image.Bounds.MinY)
+	}
+	scan.ImageCoverage = math.Min(covered/pageArea, 1)
+
+	return scan
+}
+
+// ScanImages returns the images with scan filters that a page draws directly, in drawing order
+func ScanImages(doc *document.PDFDocument, page *document.PDFPage) []ScanImage {
+	xobjects := doc.PageResources(*page, "XObject")
+	var images []ScanImage
+	for _, placement := range drawnXObjects(page, 0) {
+		obj, ok := doc.GetObject(xobjects[placement.Name])
+		if ok && obj.Dictionary["Subtype"] == "/Image" && scanImage(obj) {
+			images = append(images, ScanImage{Object: obj, Bounds: placement.Bounds})
+		}
+	}
+	return images
+}
+
+// scanImage reports whether an image is compressed with a filter used for scans
+func scanImage(obj document.PDFObject) bool {
+	filter := utils.GetString(obj.Dictionary["Filter"], "")
+	for _, name := range scanFilters {
+		if strings.Contains(filter, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// imageOnlyPages returns the 1-based numbers of the extracted pages that are scanned images
+// without a text layer. Only pages showing few glyphs are measured.
+func imageOnlyPages(doc *document.PDFDocument) []int {
+	var pages []int
+	for i := range doc.Pages {
+		page := &doc.Pages[i]
+		if page.GlyphCount <= maxScanCharacters && ClassifyScan(doc, page).ImageOnly() {
+			pages = append(pages, page.PageNumber)
+		}
+	}
+	return pages
+}
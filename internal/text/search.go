@@ -0,0 +1,258 @@
+package text
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Box is an axis-aligned rectangle in upright page space
+type Box struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// Union returns the smallest box containing both boxes
+func (b Box) Union(other Box) Box {
+	return Box{
+		MinX: math.Min(b.MinX, other.MinX),
+		MinY: math.Min(b.MinY, other.MinY),
+		MaxX: math.Max(b.MaxX, other.MaxX),
+		MaxY: math.Max(b.MaxY, other.MaxY),
+	}
+}
+
+// Glyph extents relative to the baseline, as fractions of the font size
+const (
+	glyphAscent  = 0.8
+	glyphDescent = 0.2
+)
+
+// PageIndex maps the text of a page to the boxes of its characters, so that matches found in
+// the text can be located on the page
+type PageIndex struct {
+	Text  string
+	chars []rune // Runes of Text
+	boxes []Box  // Box of each rune of Text
+	shown []bool // Whether the rune was shown on the page, rather than inserted as a separator
+	runes []int  // Rune index of each byte offset of Text
+	spans []int  // Index of the position each rune came from, -1 for separators
+
+	positions []document.TextPosition
+}
+
+// NewPageIndex builds an index from text positions in reading order, inserting spaces between
+// separated runs and newlines between lines
+func NewPageIndex(positions []document.TextPosition) *PageIndex {
+	idx := &PageIndex{positions: positions}
+	var sb strings.Builder
+	span := -1
+
+	add := func(r rune, box Box, shown bool) {
+		for i := 0; i < utf8.RuneLen(r); i++ {
+			idx.runes = append(idx.runes, len(idx.boxes))
+		}
+		sb.WriteRune(r)
+		idx.chars = append(idx.chars, r)
+		idx.boxes = append(idx.boxes, box)
+		idx.shown = append(idx.shown, shown)
+		if shown {
+			idx.spans = append(idx.spans, span)
+		} else {
+			idx.spans = append(idx.spans, -1)
+		}
+	}
+
+	var prev *document.TextPosition
+	var prevEnd float64
+	for i := range positions {
+		pos := &positions[i]
+		if pos.Text == "" {
+			continue
+		}
+
+		if prev != nil {
+			if sameLine(prev, pos) {
+				if pos.X-prevEnd > pos.FontSize*0.2 {
+					add(' ', Box{}, false)
+				}
+			} else {
+				add('\n', Box{}, false)
+			}
+		}
+
+		span = i
+		offset, n := 0.0, 0
+		count := utf8.RuneCountInString(pos.Text)
+		for _, r := range pos.Text {
+			advance := runeAdvance(pos, n, count)
+			add(r, runeBox(pos, offset, advance), true)
+			offset += advance
+			n++
+		}
+
+		prev = pos
+		prevEnd = pos.X + pos.Width
+	}
+
+	idx.Text = sb.String()
+	// Sentinel so that an end offset of len(Text) maps to the rune count
+	idx.runes = append(idx.runes, len(idx.boxes))
+
+	return idx
+}
+
+// sameLine reports whether two consecutive positions belong to the same line
+func sameLine(a, b *document.TextPosition) bool {
+	if a.Vertical || b.Vertical {
+		return math.Abs(a.X-b.X) < math.Max(a.FontSize, b.FontSize)*0.5
+	}
+	return math.Abs(a.Y-b.Y) < math.Max(a.FontSize, b.FontSize)*0.5
+}
+
+// runeAdvance returns the advance of the nth of count runes of a position, from the recorded
+// glyph widths when they line up with the text, or an even share of the total otherwise
+func runeAdvance(pos *document.TextPosition, n, count int) float64 {
+	if len(pos.CharWidths) == count {
+		return pos.CharWidths[n]
+	}
+	return pos.Width / float64(count)
+}
+
+// runeBox returns the box of a rune that starts offset units along the position's direction
+func runeBox(pos *document.TextPosition, offset, advance float64) Box {
+	if pos.Vertical {
+		// Vertical text advances downwards, centred on X
+		top := pos.Y - offset
+		return Box{
+			MinX: pos.X - pos.FontSize/2,
+			MinY: top - advance,
+			MaxX: pos.X + pos.FontSize/2,
+			MaxY: top,
+		}
+	}
+
+	left := pos.X + offset
+	return Box{
+		MinX: left,
+		MinY: pos.Y - pos.FontSize*glyphDescent,
+		MaxX: left + advance,
+		MaxY: pos.Y + pos.FontSize*glyphAscent,
+	}
+}
+
+// MatchBoxes returns the boxes covering the text between two byte offsets, one per line
+func (idx *PageIndex) MatchBoxes(start, end int) []Box {
+	if start < 0 || end > len(idx.Text) || start >= end {
+		return nil
+	}
+
+	var boxes []Box
+	var current Box
+	open := false
+	for i := idx.runes[start]; i < idx.runes[end]; i++ {
+		if !idx.shown[i] {
+			if idx.chars[i] == '\n' && open {
+				boxes = append(boxes, current)
+				open = false
+			}
+			continue
+		}
+
+		if open {
+			current = current.Union(idx.boxes[i])
+		} else {
+			current = idx.boxes[i]
+			open = true
+		}
+	}
+
+	if open {
+		boxes = append(boxes, current)
+	}
+
+	return boxes
+}
+
+// IndexedWord is a run of non-space text in a page index, with its box and the position its
+// first rune came from
+type IndexedWord struct {
+	Text     string
+	Box      Box
+	Position document.TextPosition
+}
+
+// Words splits the indexed text into words at whitespace and inserted separators
+func (idx *PageIndex) Words() []IndexedWord {
+	var words []IndexedWord
+	var current *IndexedWord
+	var text strings.Builder
+
+	flush := func() {
+		if current != nil {
+			current.Text = text.String()
+			words = append(words, *current)
+			current = nil
+			text.Reset()
+		}
+	}
+
+	for i, r := range idx.chars {
+		if !idx.shown[i] || unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+
+		if current == nil {
+			current = &IndexedWord{Box: idx.boxes[i], Position: idx.positions[idx.spans[i]]}
+		} else {
+			current.Box = current.Box.Union(idx.boxes[i])
+		}
+		text.WriteRune(r)
+	}
+	flush()
+
+	return words
+}
+
+// TextInBox returns the text of the runes whose centre lies inside a box, with lines joined by
+// spaces. Runs are clipped to the box, so a box over part of a line yields only those words.
+func TextInBox(positions []document.TextPosition, box Box) string {
+	var clipped []document.TextPosition
+	for i := range positions {
+		pos := &positions[i]
+		count := utf8.RuneCountInString(pos.Text)
+		var sb strings.Builder
+		var first Box
+		offset, n := 0.0, 0
+		for _, r := range pos.Text {
+			advance := runeAdvance(pos, n, count)
+			rb := runeBox(pos, offset, advance)
+			x, y := (rb.MinX+rb.MaxX)/2, (rb.MinY+rb.MaxY)/2
+			if x >= box.MinX && x <= box.MaxX && y >= box.MinY && y <= box.MaxY {
+				if sb.Len() == 0 {
+					first = rb
+				}
+				sb.WriteRune(r)
+			}
+			offset += advance
+			n++
+		}
+		if sb.Len() == 0 {
+			continue
+		}
+
+		part := *pos
+		part.Text = sb.String()
+		part.CharWidths = nil
+		if pos.Vertical {
+			part.Y = first.MaxY
+		} else {
+			part.X = first.MinX
+		}
+		clipped = append(clipped, part)
+	}
+	return JoinLines(clipped)
+}
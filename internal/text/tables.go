@@ -0,0 +1,408 @@
+package text
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Table detection parameters
+const (
+	rulingTolerance   = 2.0 // Distance within which ruling lines are merged or considered touching
+	minRulingLength   = 5.0 // Shorter segments are ignored
+	thinRectThickness = 3.0 // Rectangles thinner than this are treated as lines
+	columnGapFactor   = 1.0 // Gap between cells of an unruled table, as a multiple of the font size
+	minUnruledRows    = 3   // Fewest aligned lines that form an unruled table
+	rowGapFactor      = 2.5 // Largest gap between rows of an unruled table, in line heights
+)
+
+// Segment is an axis-aligned line segment in upright page space
+type Segment struct {
+	X1, Y1, X2, Y2 float64
+}
+
+// horizontal reports whether the segment runs along the X axis
+func (s Segment) horizontal() bool {
+	return math.Abs(s.Y2-s.Y1) <= math.Abs(s.X2-s.X1)
+}
+
+// TableCell is a cell of a detected table
+type TableCell struct {
+	Text   string
+	Bounds Box
+}
+
+// Table is a detected table with its cells in row-major order
+type Table struct {
+	Rows   [][]TableCell
+	Bounds Box
+	Ruled  bool // Detected from ruling lines rather than text alignment
+}
+
+// DetectTables finds tables on a page whose text positions have been extracted. Ruled tables are
+// detected from the lines and rectangles drawn by the content stream; the remaining text is then
+// searched for blocks of lines whose cells line up in columns.
+func DetectTables(page *document.PDFPage) []Table {
+	segments := rulings(PagePaths(page))
+	for i, s := range segments {
+		segments[i] = uprightSegment(s, page)
+	}
+
+	var tables []Table
+	remaining := page.TextPositions
+	for _, group := range groupRulings(segments) {
+		table, ok := ruledTable(group, page.TextPositions)
+		if !ok {
+			continue
+		}
+		tables = append(tables, table)
+		remaining = outsideBox(remaining, table.Bounds)
+	}
+
+	tables = append(tables, alignedTables(remaining)...)
+
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].Bounds.MaxY > tables[j].Bounds.MaxY
+	})
+
+	return tables
+}
+
+// ExtractRulings returns the axis-aligned lines stroked or filled by a content stream, in user space
+func ExtractRulings(data []byte) []Segment {
+	return rulings(ExtractPaths(data))
+}
+
+// rulings returns the axis-aligned lines of painted paths
+func rulings(paths []Path) []Segment {
+	var segments []Segment
+	add := func(a, b [2]float64) {
+		if s := (Segment{X1: a[0], Y1: a[1], X2: b[0], Y2: b[1]}); axisAligned(s) {
+			segments = append(segments, s)
+		}
+	}
+
+	for _, path := range paths {
+		var current, start [2]float64
+		for i := 0; i < len(path.Segments); i++ {
+			seg := path.Segments[i]
+			if seg.Rect && seg.Op == SegmentMove && i+4 < len(path.Segments) {
+				// A rectangle is a move, three lines and a close
+				p1, p2, p3, p4 := seg.Points[0], path.Segments[i+1].Points[0], path.Segments[i+2].Points[0], path.Segments[i+3].Points[0]
+				w, h := math.Hypot(p2[0]-p1[0], p2[1]-p1[1]), math.Hypot(p4[0]-p1[0], p4[1]-p1[1])
+				switch {
+				case h < thinRectThickness && w >= h:
+					// A thin rectangle is a ruling line along its centre
+					add(midpoint(p1, p4), midpoint(p2, p3))
+				case w < thinRectThickness:
+					add(midpoint(p1, p2), midpoint(p4, p3))
+				default:
+					add(p1, p2)
+					add(p2, p3)
+					add(p3, p4)
+					add(p4, p1)
+				}
+				current, start = p1, p1
+				i += 4
+				continue
+			}
+
+			switch seg.Op {
+			case SegmentMove:
+				current = seg.Points[0]
+				start = current
+			case SegmentLine:
+				add(current, seg.Points[0])
+				current = seg.Points[0]
+			case SegmentCurve:
+				current = seg.Points[2]
+			case SegmentClose:
+				add(current, start)
+				current = start
+			}
+		}
+	}
+
+	return segments
+}
+
+// midpoint returns the point halfway between two points
+func midpoint(a, b [2]float64) [2]float64 {
+	return [2]float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+}
+
+// axisAligned reports whether a segment is a long enough horizontal or vertical line
+func axisAligned(s Segment) bool {
+	dx, dy := math.Abs(s.X2-s.X1), math.Abs(s.Y2-s.Y1)
+	return (dy <= 1 && dx >= minRulingLength) || (dx <= 1 && dy >= minRulingLength)
+}
+
+// uprightSegment maps a segment into the upright page space of the page's text positions,
+// with its end points ordered left to right or bottom to top
+func uprightSegment(s Segment, page *document.PDFPage) Segment {
+	x1, y1 := UprightPoint(s.X1, s.Y1, page.TextRotation, page.Width, page.Height)
+	x2, y2 := UprightPoint(s.X2, s.Y2, page.TextRotation, page.Width, page.Height)
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	return Segment{X1: x1, Y1: y1, X2: x2, Y2: y2}
+}
+
+// groupRulings splits segments into groups of lines that touch or cross each other
+func groupRulings(segments []Segment) [][]Segment {
+	parent := make([]int, len(segments))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range segments {
+		for j := i + 1; j < len(segments); j++ {
+			if touches(segments[i], segments[j]) {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	groups := make(map[int][]Segment)
+	var roots []int
+	for i, s := range segments {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], s)
+	}
+
+	result := make([][]Segment, 0, len(roots))
+	for _, root := range roots {
+		result = append(result, groups[root])
+	}
+	return result
+}
+
+// touches reports whether the bounding boxes of two segments overlap, within the tolerance
+func touches(a, b Segment) bool {
+	return a.X1-rulingTolerance <= b.X2 && b.X1-rulingTolerance <= a.X2 &&
+		a.Y1-rulingTolerance <= b.Y2 && b.Y1-rulingTolerance <= a.Y2
+}
+
+// ruledTable builds a grid table from a group of ruling lines and assigns text to its cells
+func ruledTable(group []Segment, positions []document.TextPosition) (Table, bool) {
+	var ys, xs []float64
+	for _, s := range group {
+		if s.horizontal() {
+			ys = append(ys, s.Y1)
+		} else {
+			xs = append(xs, s.X1)
+		}
+	}
+
+	ys = mergeCoordinates(ys)
+	xs = mergeCoordinates(xs)
+	// A single framed box is not a table
+	if len(ys) < 2 || len(xs) < 2 || (len(ys)-1)*(len(xs)-1) < 2 {
+		return Table{}, false
+	}
+
+	// Rows run top to bottom
+	sort.Sort(sort.Reverse(sort.Float64Slice(ys)))
+
+	table := Table{
+		Bounds: Box{MinX: xs[0], MinY: ys[len(ys)-1], MaxX: xs[len(xs)-1], MaxY: ys[0]},
+		Ruled:  true,
+	}
+
+	hasText := false
+	for r := 0; r+1 < len(ys); r++ {
+		row := make([]TableCell, 0, len(xs)-1)
+		for c := 0; c+1 < len(xs); c++ {
+			bounds := Box{MinX: xs[c], MinY: ys[r+1], MaxX: xs[c+1], MaxY: ys[r]}
+			cell := TableCell{Text: JoinLines(insideBox(positions, bounds)), Bounds: bounds}
+			hasText = hasText || cell.Text != ""
+			row = append(row, cell)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, hasText
+}
+
+// mergeCoordinates sorts coordinates and merges those within the ruling tolerance
+func mergeCoordinates(values []float64) []float64 {
+	sort.Float64s(values)
+	var merged []float64
+	for _, v := range values {
+		if n := len(merged); n > 0 && v-merged[n-1] <= rulingTolerance {
+			continue
+		}
+		merged = append(merged, v)
+	}
+	return merged
+}
+
+// positionCentre returns the centre of a text position's box
+func positionCentre(pos document.TextPosition) (float64, float64) {
+	box := positionBox(pos)
+	return (box.MinX + box.MaxX) / 2, (box.MinY + box.MaxY) / 2
+}
+
+// insideBox returns the positions whose centre lies inside the box
+func insideBox(positions []document.TextPosition, box Box) []document.TextPosition {
+	var result []document.TextPosition
+	for _, pos := range positions {
+		x, y := positionCentre(pos)
+		if x >= box.MinX && x <= box.MaxX && y >= box.MinY && y <= box.MaxY {
+			result = append(result, pos)
+		}
+	}
+	return result
+}
+
+// outsideBox returns the positions whose centre lies outside the box
+func outsideBox(positions []document.TextPosition, box Box) []document.TextPosition {
+	var result []document.TextPosition
+	for _, pos := range positions {
+		x, y := positionCentre(pos)
+		if x < box.MinX || x > box.MaxX || y < box.MinY || y > box.MaxY {
+			result = append(result, pos)
+		}
+	}
+	return result
+}
+
+// JoinLines joins the text of positions in reading order into a single line
+func JoinLines(positions []document.TextPosition) string {
+	return strings.TrimSpace(strings.Join(TextLines(positions), " "))
+}
+
+// GroupLines groups positions into lines by baseline, top to bottom, each sorted left to right
+func GroupLines(positions []document.TextPosition) [][]document.TextPosition {
+	return clusterBaselines(positions)
+}
+
+// TextLines returns the text of positions line by line, top to bottom
+func TextLines(positions []document.TextPosition) []string {
+	var lines []string
+	for _, line := range clusterBaselines(positions) {
+		parts := make([]string, 0, len(line))
+		for _, pos := range line {
+			parts = append(parts, strings.TrimSpace(pos.Text))
+		}
+		lines = append(lines, strings.Join(parts, " "))
+	}
+	return lines
+}
+
+// alignedTables finds unruled tables: runs of consecutive lines that split into the same
+// number of cells, with each column overlapping the same horizontal span
+func alignedTables(positions []document.TextPosition) []Table {
+	var tables []Table
+	var block [][]TableCell
+	var columns []Box
+	var lastY, lineHeight float64
+
+	flush := func() {
+		if len(block) >= minUnruledRows {
+			table := Table{Rows: block, Bounds: block[0][0].Bounds}
+			for _, row := range block {
+				for _, cell := range row {
+					table.Bounds = table.Bounds.Union(cell.Bounds)
+				}
+			}
+			tables = append(tables, table)
+		}
+		block, columns = nil, nil
+	}
+
+	for _, line := range clusterBaselines(positions) {
+		cells := lineCells(line)
+		y := line[0].Y
+
+		continues := len(block) > 0 && len(cells) == len(columns) && lastY-y <= lineHeight*rowGapFactor
+		if continues {
+			for i, cell := range cells {
+				if cell.Bounds.MaxX < columns[i].MinX || cell.Bounds.MinX > columns[i].MaxX {
+					continues = false
+					break
+				}
+			}
+		}
+
+		if !continues {
+			flush()
+			if len(cells) < 2 {
+				continue
+			}
+			columns = make([]Box, len(cells))
+			for i, cell := range cells {
+				columns[i] = cell.Bounds
+			}
+			lineHeight = medianFontSize(line) * layoutLineSpacing
+		} else {
+			for i, cell := range cells {
+				columns[i] = columns[i].Union(cell.Bounds)
+			}
+		}
+
+		block = append(block, cells)
+		lastY = y
+	}
+	flush()
+
+	return tables
+}
+
+// lineCells splits a line into cells at gaps wider than the column gap
+func lineCells(line []document.TextPosition) []TableCell {
+	var cells []TableCell
+	var group []document.TextPosition
+	var groupEnd float64
+
+	emit := func() {
+		if len(group) == 0 {
+			return
+		}
+		bounds := positionBox(group[0])
+		for _, pos := range group[1:] {
+			bounds = bounds.Union(positionBox(pos))
+		}
+		cells = append(cells, TableCell{Text: JoinLines(group), Bounds: bounds})
+		group = nil
+	}
+
+	for _, pos := range line {
+		if strings.TrimSpace(pos.Text) == "" {
+			continue
+		}
+		if len(group) > 0 && pos.X-groupEnd > pos.FontSize*columnGapFactor {
+			emit()
+		}
+		group = append(group, pos)
+		groupEnd = pos.X + pos.Width
+	}
+	emit()
+
+	return cells
+}
+
+// positionBox returns the box covered by a text position
+func positionBox(pos document.TextPosition) Box {
+	return Box{
+		MinX: pos.X,
+		MinY: pos.Y - pos.FontSize*glyphDescent,
+		MaxX: pos.X + pos.Width,
+		MaxY: pos.Y + pos.FontSize*glyphAscent,
+	}
+}
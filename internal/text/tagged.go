@@ -0,0 +1,146 @@
+package text
+
+import (
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Standard structure types whose content forms a block of its own. Elements nested in a block,
+// such as a paragraph in a list item or a span in a paragraph, are part of that block's text.
+var blockStructTypes = map[string]bool{
+	"P": true, "H": true, "H1": true, "H2": true, "H3": true, "H4": true, "H5": true, "H6": true,
+	"LI": true, "TH": true, "TD": true, "Caption": true, "Figure": true, "Formula": true,
+	"TOCI": true, "Note": true, "Title": true, "Quote": true, "Code": true, "BibEntry": true,
+}
+
+// TaggedBlock is a block of text in logical order from the structure tree of a tagged PDF
+type TaggedBlock struct {
+	Type       string // Standard structure type, e.g. P, H1, TD or Figure
+	Role       string // Type as written in the document, when it is role-mapped
+	PageNumber int    // Page of the block's first content, 0 for figures known only by /Alt
+	Lang       string
+	Text       string // Content text; the alternate description for figures and formulas
+}
+
+// markedContentKey identifies a marked-content sequence within a document
+type markedContentKey struct {
+	page, mcid int
+}
+
+// TaggedBlocks returns the blocks of a tagged document in the logical order of its structure
+// tree. Pages must already have their text positions. Marked content directly inside grouping
+// elements such as Sect or Div forms a block of the grouping element's type.
+func TaggedBlocks(elements []*document.StructElement, pages []document.PDFPage) []TaggedBlock {
+	texts := markedContentTexts(pages)
+
+	var blocks []TaggedBlock
+	var walk func(elem *document.StructElement)
+	walk = func(elem *document.StructElement) {
+		if blockStructTypes[elem.Type] {
+			if block := newTaggedBlock(elem, texts); block.Text != "" {
+				blocks = append(blocks, block)
+			}
+			return
+		}
+
+		// Group runs of content between the child elements into blocks of their own
+		var run []document.StructKid
+		flush := func() {
+			if len(run) > 0 {
+				loose := &document.StructElement{Type: elem.Type, Role: elem.Role, Lang: elem.Lang, Kids: run}
+				if block := newTaggedBlock(loose, texts); block.Text != "" {
+					blocks = append(blocks, block)
+				}
+				run = nil
+			}
+		}
+		for _, kid := range elem.Kids {
+			if kid.Element == nil {
+				run = append(run, kid)
+				continue
+			}
+			flush()
+			walk(kid.Element)
+		}
+		flush()
+	}
+
+	for _, elem := range elements {
+		walk(elem)
+	}
+	return blocks
+}
+
+// newTaggedBlock builds the block of an element from the text of its content and of the
+// elements nested in it
+func newTaggedBlock(elem *document.StructElement, texts map[markedContentKey]string) TaggedBlock {
+	block := TaggedBlock{Type: elem.Type, Role: elem.Role, Lang: elem.Lang}
+
+	var parts []string
+	var collect func(e *document.StructElement)
+	collect = func(e *document.StructElement) {
+		if e.ActualText != "" {
+			parts = append(parts, e.ActualText)
+			block.PageNumber = firstPage(e, block.PageNumber)
+			return
+		}
+		if (e.Type == "Figure" || e.Type == "Formula") && e.Alt != "" {
+			parts = append(parts, e.Alt)
+			block.PageNumber = firstPage(e, block.PageNumber)
+			return
+		}
+		for _, kid := range e.Kids {
+			if kid.Element != nil {
+				collect(kid.Element)
+				continue
+			}
+			if text := texts[markedContentKey{kid.PageNumber, kid.MCID}]; text != "" {
+				parts = append(parts, text)
+				if block.PageNumber == 0 {
+					block.PageNumber = kid.PageNumber
+				}
+			}
+		}
+	}
+	collect(elem)
+
+	block.Text = strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+	return block
+}
+
+// firstPage returns page if it is already known, or the page of an element's first content
+func firstPage(elem *document.StructElement, page int) int {
+	if page != 0 {
+		return page
+	}
+	for _, kid := range elem.Kids {
+		if kid.Element == nil {
+			return kid.PageNumber
+		}
+		if p := firstPage(kid.Element, 0); p != 0 {
+			return p
+		}
+	}
+	return 0
+}
+
+// markedContentTexts returns the text of each marked-content sequence with an MCID, from the
+// text positions of the pages. Positions keep their reading order within a sequence.
+func markedContentTexts(pages []document.PDFPage) map[markedContentKey]string {
+	grouped := make(map[markedContentKey][]document.TextPosition)
+	for _, page := range pages {
+		for _, pos := range page.TextPositions {
+			if pos.MCID >= 0 {
+				key := markedContentKey{page.PageNumber, pos.MCID}
+				grouped[key] = append(grouped[key], pos)
+			}
+		}
+	}
+
+	texts := make(map[markedContentKey]string, len(grouped))
+	for key, positions := range grouped {
+		texts[key] = NewPageIndex(positions).Text
+	}
+	return texts
+}
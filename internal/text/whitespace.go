@@ -0,0 +1,109 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// NewlinePolicy selects how the line breaks of extracted text are kept
+type NewlinePolicy int
+
+// Newline policies
+const (
+	NewlinesPreserve   NewlinePolicy = iota // Keep a line break for each line of the layout
+	NewlinesParagraphs                      // Join the lines of each paragraph, one paragraph per line
+	NewlinesSpaces                          // Replace every line break with a space, one line per document
+)
+
+// WhitespaceOptions controls the whitespace of extracted text. The zero value leaves the text
+// as laid out.
+type WhitespaceOptions struct {
+	Newlines          NewlinePolicy
+	CollapseSpaces    bool // Replace runs of spaces and tabs with one space and trim the ends of lines
+	StripControlChars bool // Remove control characters other than tabs and line breaks
+}
+
+// PageSeparator returns the text placed between the texts of consecutive pages: a blank line,
+// or a line break or space when line breaks are joined
+func (o WhitespaceOptions) PageSeparator() string {
+	switch o.Newlines {
+	case NewlinesParagraphs:
+		return "\n"
+	case NewlinesSpaces:
+		return " "
+	}
+	return "\n\n"
+}
+
+// NormalizeWhitespace applies whitespace options to text. Without text positions to tell them
+// apart, paragraphs are taken to be separated by blank lines.
+func NormalizeWhitespace(text string, options WhitespaceOptions) string {
+	if options == (WhitespaceOptions{}) {
+		return text
+	}
+
+	if options.StripControlChars {
+		text = strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+				return -1
+			}
+			return r
+		}, text)
+	}
+
+	switch options.Newlines {
+	case NewlinesSpaces:
+		return strings.Join(strings.Fields(text), " ")
+	case NewlinesParagraphs:
+		var paragraphs, lines []string
+		for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, strings.TrimSpace(line))
+				continue
+			}
+			if len(lines) > 0 {
+				paragraphs = append(paragraphs, strings.Join(lines, " "))
+				lines = nil
+			}
+		}
+		if len(lines) > 0 {
+			paragraphs = append(paragraphs, strings.Join(lines, " "))
+		}
+		text = strings.Join(paragraphs, "\n")
+	}
+
+	if options.CollapseSpaces {
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			lines[i] = strings.Join(strings.FieldsFunc(line, func(r rune) bool {
+				return r == ' ' || r == '\t' || r == '\r' || r == '\u00a0'
+			}), " ")
+		}
+		text = strings.Join(lines, "\n")
+	}
+
+	return text
+}
+
+// pageText assembles the text of an extracted page with the extractor's whitespace options.
+// Paragraphs are found from the text positions, so that those not separated by blank lines
+// still get a line each.
+func (e *Extractor) pageText(page *document.PDFPage) string {
+	options := e.Options.Whitespace
+	if options.Newlines != NewlinesParagraphs {
+		return NormalizeWhitespace(page.ExtractOrderedText(), options)
+	}
+
+	var paragraphs []string
+	for _, positions := range DetectParagraphs(page.TextPositions) {
+		paragraph := document.PDFPage{TextPositions: positions}
+		if text := strings.TrimSpace(strings.ReplaceAll(paragraph.ExtractOrderedText(), "\n", " ")); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	}
+	// The paragraphs are on lines of their own now
+	options.Newlines = NewlinesPreserve
+	return NormalizeWhitespace(strings.Join(paragraphs, "\n"), options)
+}
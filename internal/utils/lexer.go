@@ -0,0 +1,184 @@
+package utils
+
+// tokenKind is the kind of a token read by the lexer
+type tokenKind int
+
+const (
+	tokenEOF        tokenKind = iota
+	tokenName                 // /Name
+	tokenNumber               // 12, -3.5, .5
+	tokenString               // (literal string)
+	tokenHexString            // <48656C6C6F>
+	tokenDictOpen             // <<
+	tokenDictClose            // >>
+	tokenArrayOpen            // [
+	tokenArrayClose           // ]
+	tokenKeyword              // true, false, null, R, or any other bare word or stray delimiter
+)
+
+// token is a token of PDF source, located by its offsets in the data
+type token struct {
+	kind  tokenKind
+	start int
+	end   int
+
+	// A literal or hex string that runs to the end of the data without being closed
+	unterminated bool
+}
+
+// lexer splits PDF source into tokens, skipping whitespace and comments. Literal strings are
+// read whole, with their nested parentheses and escapes, so delimiters inside them don't end
+// the structures around them.
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+// text returns the source of a token
+func (l *lexer) text(tok token) string {
+	return string(l.data[tok.start:tok.end])
+}
+
+// next reads the token at the current position
+func (l *lexer) next() token {
+	l.skipSpace()
+	start := l.pos
+	if start >= len(l.data) {
+		return token{kind: tokenEOF, start: start, end: start}
+	}
+
+	kind, unterminated := tokenKeyword, false
+	switch c := l.data[start]; {
+	case c == '/':
+		kind = tokenName
+		l.pos = tokenEnd(l.data, start)
+	case c == '(':
+		kind = tokenString
+		l.pos, unterminated = stringEnd(l.data, start)
+	case c == '<' && start+1 < len(l.data) && l.data[start+1] == '<':
+		kind = tokenDictOpen
+		l.pos = start + 2
+	case c == '<':
+		kind = tokenHexString
+		l.pos = start + 1
+		for l.pos < len(l.data) && l.data[l.pos] != '>' {
+			l.pos++
+		}
+		unterminated = l.pos == len(l.data)
+		l.pos = min(l.pos+1, len(l.data))
+	case c == '>' && start+1 < len(l.data) && l.data[start+1] == '>':
+		kind = tokenDictClose
+		l.pos = start + 2
+	case c == '[':
+		kind = tokenArrayOpen
+		l.pos = start + 1
+	case c == ']':
+		kind = tokenArrayClose
+		l.pos = start + 1
+	case isPDFDelimiter(c):
+		// A stray delimiter such as ")" or "{"
+		l.pos = start + 1
+	default:
+		l.pos = tokenEnd(l.data, start)
+		if isNumberToken(l.data[start:l.pos]) {
+			kind = tokenNumber
+		}
+	}
+	return token{kind: kind, start: start, end: l.pos, unterminated: unterminated}
+}
+
+// stringEnd returns the offset just past the ) that closes the literal string starting at
+// start, past its balanced parentheses and escapes, or the end of the data and true if the
+// string is unterminated
+func stringEnd(data []byte, start int) (int, bool) {
+	depth := 0
+	for i := start; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i + 1, false
+			}
+		}
+	}
+	return len(data), true
+}
+
+// skipSpace skips whitespace and comments
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		switch c := l.data[l.pos]; {
+		case isPDFWhitespace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// skipDictionary moves past the >> that closes a dictionary whose << has been read, reporting
+// whether there was one
+func (l *lexer) skipDictionary() bool {
+	for depth := 1; ; {
+		switch l.next().kind {
+		case tokenEOF:
+			return false
+		case tokenDictOpen:
+			depth++
+		case tokenDictClose:
+			if depth--; depth == 0 {
+				return true
+			}
+		}
+	}
+}
+
+// isNumberToken reports whether a token is a PDF number: an optional sign, then digits with
+// at most one decimal point
+func isNumberToken(text []byte) bool {
+	if len(text) > 0 && (text[0] == '+' || text[0] == '-') {
+		text = text[1:]
+	}
+	digits, points := 0, 0
+	for _, c := range text {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '.':
+			points++
+		default:
+			return false
+		}
+	}
+	return digits > 0 && points <= 1
+}
+
+// DictionaryBody returns the entries of the dictionary at the start of data, between its <<
+// and the >> that closes it, as ParseDictionary takes them. Nested dictionaries and strings
+// holding ">>" don't end it early. It reports false if data doesn't start with a complete
+// dictionary.
+func DictionaryBody(data []byte) ([]byte, bool) {
+	l := &lexer{data: data}
+	open := l.next()
+	if open.kind != tokenDictOpen || !l.skipDictionary() {
+		return nil, false
+	}
+	return data[open.end : l.pos-2], true
+}
+
+// DictionaryEnd returns the offset just past the >> that closes the dictionary at the start
+// of data, reporting false if data doesn't start with a complete dictionary
+func DictionaryEnd(data []byte) (int, bool) {
+	l := &lexer{data: data}
+	if l.next().kind != tokenDictOpen || !l.skipDictionary() {
+		return 0, false
+	}
+	return l.pos, true
+}
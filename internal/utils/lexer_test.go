@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+// lexed is a token as the tests expect it: its kind and source
+type lexed struct {
+	kind tokenKind
+	text string
+}
+
+// lexAll reads the tokens of data up to the end
+func lexAll(data string) []lexed {
+	l := &lexer{data: []byte(data)}
+	var tokens []lexed
+	for tok := l.next(); tok.kind != tokenEOF; tok = l.next() {
+		tokens = append(tokens, lexed{tok.kind, l.text(tok)})
+	}
+	return tokens
+}
+
+func TestLexer(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []lexed
+	}{
+		{
+			name: "dictionary",
+			data: "<</Type/Page/Count 3>>",
+			want: []lexed{
+				{tokenDictOpen, "<<"}, {tokenName, "/Type"}, {tokenName, "/Page"},
+				{tokenName, "/Count"}, {tokenNumber, "3"}, {tokenDictClose, ">>"},
+			},
+		},
+		{
+			name: "nested arrays",
+			data: "[1[2[-3.5 .5]]]",
+			want: []lexed{
+				{tokenArrayOpen, "["}, {tokenNumber, "1"}, {tokenArrayOpen, "["}, {tokenNumber, "2"},
+				{tokenArrayOpen, "["}, {tokenNumber, "-3.5"}, {tokenNumber, ".5"},
+				{tokenArrayClose, "]"}, {tokenArrayClose, "]"}, {tokenArrayClose, "]"},
+			},
+		},
+		{
+			name: "whitespace and comments across lines",
+			data: "/A % comment >> ]\r\n  12\n\t0 R\f\x00true",
+			want: []lexed{
+				{tokenName, "/A"}, {tokenNumber, "12"}, {tokenNumber, "0"},
+				{tokenKeyword, "R"}, {tokenKeyword, "true"},
+			},
+		},
+		{
+			name: "balanced parentheses",
+			data: "(a (b (c)) d) /X",
+			want: []lexed{{tokenString, "(a (b (c)) d)"}, {tokenName, "/X"}},
+		},
+		{
+			name: "escaped parentheses",
+			data: `(a \) b \( c \\) /X`,
+			want: []lexed{{tokenString, `(a \) b \( c \\)`}, {tokenName, "/X"}},
+		},
+		{
+			name: "delimiters inside a string",
+			data: "(>> ] /Not a name <<) >>",
+			want: []lexed{{tokenString, "(>> ] /Not a name <<)"}, {tokenDictClose, ">>"}},
+		},
+		{
+			name: "hex string",
+			data: "<48 65\n6C6C6F><>",
+			want: []lexed{{tokenHexString, "<48 65\n6C6C6F>"}, {tokenHexString, "<>"}},
+		},
+		{
+			name: "name escapes",
+			data: "/A#20B/#2Fslash/C#",
+			want: []lexed{{tokenName, "/A#20B"}, {tokenName, "/#2Fslash"}, {tokenName, "/C#"}},
+		},
+		{
+			name: "empty name",
+			data: "/ /A",
+			want: []lexed{{tokenName, "/"}, {tokenName, "/A"}},
+		},
+		{
+			name: "not numbers",
+			data: "1.2.3 +-4 - . null",
+			want: []lexed{
+				{tokenKeyword, "1.2.3"}, {tokenKeyword, "+-4"}, {tokenKeyword, "-"},
+				{tokenKeyword, "."}, {tokenKeyword, "null"},
+			},
+		},
+		{
+			name: "stray delimiters",
+			data: "){}>",
+			want: []lexed{{tokenKeyword, ")"}, {tokenKeyword, "{"}, {tokenKeyword, "}"}, {tokenKeyword, ">"}},
+		},
+		{
+			name: "only whitespace and a comment",
+			data: " \r\n% nothing",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lexAll(tt.data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lexAll(%q) = %v, want %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLexerUnterminated(t *testing.T) {
+	tests := []struct {
+		data         string
+		unterminated bool
+	}{
+		{"(abc)", false},
+		{"(abc", true},
+		{"(a (b) c", true},
+		{`(abc\)`, true},
+		{`(abc\`, true},
+		{"<4142>", false},
+		{"<4142", true},
+		{"<", true},
+	}
+
+	for _, tt := range tests {
+		l := &lexer{data: []byte(tt.data)}
+		tok := l.next()
+		if tok.unterminated != tt.unterminated {
+			t.Errorf("%q: unterminated = %v, want %v", tt.data, tok.unterminated, tt.unterminated)
+		}
+		if tok.end != len(tt.data) {
+			t.Errorf("%q: token ends at %d, want %d", tt.data, tok.end, len(tt.data))
+		}
+	}
+}
+
+func TestDictionaryBody(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		body string
+		end  int
+		ok   bool
+	}{
+		{"simple", "<< /A 1 >> stream", " /A 1 ", 10, true},
+		{"nested", "<</A<</B<</C 1>>>>>>rest", "/A<</B<</C 1>>>>", 20, true},
+		{"string holding >>", "<< /S (>>) >>", " /S (>>) ", 13, true},
+		{"escaped parenthesis before >>", `<< /S (\) >>) >>`, ` /S (\) >>) `, 16, true},
+		{"leading whitespace", "\r\n<< >>", " ", 7, true},
+		{"unterminated", "<< /A << /B 1 >>", "", 0, false},
+		{"string left open", "<< /S (>> >>", "", 0, false},
+		{"not a dictionary", "[1 2]", "", 0, false},
+		{"empty", "", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, ok := DictionaryBody([]byte(tt.data))
+			if ok != tt.ok || string(body) != tt.body {
+				t.Errorf("DictionaryBody(%q) = %q, %v, want %q, %v", tt.data, body, ok, tt.body, tt.ok)
+			}
+			end, ok := DictionaryEnd([]byte(tt.data))
+			if ok != tt.ok || end != tt.end {
+				t.Errorf("DictionaryEnd(%q) = %d, %v, want %d, %v", tt.data, end, ok, tt.end, tt.ok)
+			}
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package utils
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"strings"
 	"sync"
-	"time"
 )
 
 // LogLevel represents logging levels
@@ -19,19 +22,46 @@ const (
 	LogDebug
 )
 
-// Global log level and writer with mutex for thread safety
+// SlogLevel returns the slog level corresponding to the log level
+func (level LogLevel) SlogLevel() slog.Level {
+	switch level {
+	case LogError:
+		return slog.LevelError
+	case LogWarning:
+		return slog.LevelWarn
+	case LogInfo:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// Global log level and writer with mutex for thread safety. They configure the process-wide
+// logger of applications such as the command-line tool; library code logs to the logger it is
+// given instead.
 var (
 	logLevel               = LogWarning // Default to warnings
 	logWriter    io.Writer = os.Stdout
 	loggerMutex  sync.Mutex
 	logTimestamp = true
+
+	globalLevel  slog.LevelVar
+	globalLogger = slog.New(&Handler{
+		shared: &handlerOutput{w: globalWriter{}, timestamp: globalTimestamp},
+		level:  &globalLevel,
+	})
 )
 
+func init() {
+	globalLevel.Set(logLevel.SlogLevel())
+}
+
 // SetLogLevel sets the global logging level
 func SetLogLevel(level LogLevel) {
 	loggerMutex.Lock()
 	defer loggerMutex.Unlock()
 	logLevel = level
+	globalLevel.Set(level.SlogLevel())
 }
 
 // GetLogLevel returns the current global logging level
@@ -55,31 +85,174 @@ func EnableTimestamp(enable bool) {
 	logTimestamp = enable
 }
 
-// Logf logs a message at the specified level
-func Logf(level LogLevel, format string, args ...interface{}) {
+// globalWriter writes to the global log writer
+type globalWriter struct{}
+
+func (globalWriter) Write(p []byte) (int, error) {
 	loggerMutex.Lock()
 	defer loggerMutex.Unlock()
+	return logWriter.Write(p)
+}
 
-	if level <= logLevel {
-		prefix := ""
-		switch level {
-		case LogError:
-			prefix = "ERROR: "
-		case LogWarning:
-			prefix = "WARNING: "
-		case LogInfo:
-			prefix = "INFO: "
-		case LogDebug:
-			prefix = "DEBUG: "
-		}
+// globalTimestamp reports whether the global logger prints timestamps
+func globalTimestamp() bool {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	return logTimestamp
+}
+
+// DefaultLogger returns the logger writing to the global log writer at the global log level
+func DefaultLogger() *slog.Logger {
+	return globalLogger
+}
+
+// NewLevelLogger returns a logger writing to the global log writer at the given level, without
+// changing the global level
+func NewLevelLogger(level LogLevel) *slog.Logger {
+	return slog.New(&Handler{
+		shared: &handlerOutput{w: globalWriter{}, timestamp: globalTimestamp},
+		level:  level.SlogLevel(),
+	})
+}
+
+// Handler is a slog.Handler writing one line per record in the pdfex log format:
+// an optional timestamp, the level, the message and the attributes as key=value pairs
+type Handler struct {
+	shared *handlerOutput
+	level  slog.Leveler
+	attrs  string // Preformatted attributes added by WithAttrs
+	group  string // Prefix of attribute keys added by WithGroup
+}
+
+// handlerOutput is the destination shared by a handler and those derived from it
+type handlerOutput struct {
+	mu        sync.Mutex
+	w         io.Writer
+	timestamp func() bool
+}
+
+// NewHandler returns a handler writing records at or above level to w
+func NewHandler(w io.Writer, level slog.Leveler, timestamp bool) *Handler {
+	return &Handler{
+		shared: &handlerOutput{w: w, timestamp: func() bool { return timestamp }},
+		level:  level,
+	}
+}
+
+// Enabled reports whether records at level are written
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle writes a record
+func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+	var buf bytes.Buffer
+	if h.shared.timestamp() {
+		buf.WriteString(record.Time.Format("2006-01-02 15:04:05.000"))
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(levelPrefix(record.Level))
+	buf.WriteString(strings.TrimRight(record.Message, "\n"))
+	buf.WriteString(h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		appendAttr(&buf, h.group, attr)
+		return true
+	})
+	buf.WriteByte('\n')
+
+	h.shared.mu.Lock()
+	defer h.shared.mu.Unlock()
+	_, err := h.shared.w.Write(buf.Bytes())
+	return err
+}
+
+// WithAttrs returns a handler that writes attrs with every record
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var buf bytes.Buffer
+	for _, attr := range attrs {
+		appendAttr(&buf, h.group, attr)
+	}
+	derived := *h
+	derived.attrs += buf.String()
+	return &derived
+}
+
+// WithGroup returns a handler that qualifies the keys of later attributes with name
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	derived := *h
+	derived.group += name + "."
+	return &derived
+}
 
-		if logTimestamp {
-			timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-			prefix = timestamp + " " + prefix
+// levelPrefix returns the prefix of a log line at level
+func levelPrefix(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "ERROR: "
+	case level >= slog.LevelWarn:
+		return "WARNING: "
+	case level >= slog.LevelInfo:
+		return "INFO: "
+	default:
+		return "DEBUG: "
+	}
+}
+
+// appendAttr writes an attribute as " key=value", flattening groups into dotted keys
+func appendAttr(buf *bytes.Buffer, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			appendAttr(buf, prefix, member)
 		}
+		return
+	}
 
-		fmt.Fprintf(logWriter, prefix+format+"\n", args...)
+	value := attr.Value.String()
+	if strings.ContainsAny(value, " \t\n\"=") || value == "" {
+		value = fmt.Sprintf("%q", value)
 	}
+	fmt.Fprintf(buf, " %s%s=%s", prefix, attr.Key, value)
+}
+
+// Debugf logs a printf-style message to logger at debug level, formatting it only when the
+// level is enabled
+func Debugf(logger *slog.Logger, format string, args ...interface{}) {
+	logAt(logger, slog.LevelDebug, format, args...)
+}
+
+// Infof logs a printf-style message to logger at info level
+func Infof(logger *slog.Logger, format string, args ...interface{}) {
+	logAt(logger, slog.LevelInfo, format, args...)
+}
+
+// Warnf logs a printf-style message to logger at warning level
+func Warnf(logger *slog.Logger, format string, args ...interface{}) {
+	logAt(logger, slog.LevelWarn, format, args...)
+}
+
+// logAt logs a printf-style message to logger at level
+func logAt(logger *slog.Logger, level slog.Level, format string, args ...interface{}) {
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	message := strings.TrimSuffix(fmt.Sprintf(format, args...), "\n")
+	logger.Log(ctx, level, message)
+}
+
+// Logf logs a message at the specified level to the global logger
+func Logf(level LogLevel, format string, args ...interface{}) {
+	logAt(globalLogger, level.SlogLevel(), format, args...)
 }
 
 // LogErrorf logs an error message
@@ -112,53 +285,6 @@ func LogIfError(err error, format string, args ...interface{}) bool {
 	return false
 }
 
-// NewLogger creates a new logger with a specific prefix
-type Logger struct {
-	prefix string
-	level  LogLevel
-}
-
-// NewLogger creates a new logger with a specific prefix
-func NewLogger(prefix string) *Logger {
-	return &Logger{
-		prefix: prefix,
-		level:  logLevel,
-	}
-}
-
-// SetLevel sets the log level for this logger
-func (l *Logger) SetLevel(level LogLevel) {
-	l.level = level
-}
-
-// Error logs an error message
-func (l *Logger) Error(format string, args ...interface{}) {
-	if l.level >= LogError {
-		Logf(LogError, l.prefix+": "+format, args...)
-	}
-}
-
-// Warning logs a warning message
-func (l *Logger) Warning(format string, args ...interface{}) {
-	if l.level >= LogWarning {
-		Logf(LogWarning, l.prefix+": "+format, args...)
-	}
-}
-
-// Info logs an info message
-func (l *Logger) Info(format string, args ...interface{}) {
-	if l.level >= LogInfo {
-		Logf(LogInfo, l.prefix+": "+format, args...)
-	}
-}
-
-// Debug logs a debug message
-func (l *Logger) Debug(format string, args ...interface{}) {
-	if l.level >= LogDebug {
-		Logf(LogDebug, l.prefix+": "+format, args...)
-	}
-}
-
 // LogToFile sets up logging to a file
 func LogToFile(filename string) error {
 	file, err := os.Create(filename)
@@ -0,0 +1,284 @@
+package utils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ParquetType is the type of a Parquet column
+type ParquetType int
+
+// Column types, stored as the INT64, DOUBLE and UTF-8 BYTE_ARRAY physical types
+const (
+	ParquetInt64 ParquetType = iota
+	ParquetDouble
+	ParquetString
+)
+
+// ParquetColumn describes a required column of a Parquet file
+type ParquetColumn struct {
+	Name string
+	Type ParquetType
+}
+
+// Rows of each row group; every column of a row group is a single uncompressed data page
+const parquetRowGroupSize = 50000
+
+// Parquet physical types, encodings and Thrift compact protocol field types
+const (
+	parquetPhysicalInt64     = 2
+	parquetPhysicalDouble    = 5
+	parquetPhysicalByteArray = 6
+	parquetConvertedUTF8     = 0
+	parquetRequired          = 0
+	parquetEncodingPlain     = 0
+	parquetEncodingRLE       = 3
+	parquetDataPage          = 0
+
+	thriftTypeI32    = 5
+	thriftTypeI64    = 6
+	thriftTypeBinary = 8
+	thriftTypeList   = 9
+	thriftTypeStruct = 12
+)
+
+// WriteParquet writes rows as a Parquet file with the given columns. Each row holds a value per
+// column: an int or int64 for ParquetInt64, a float64 for ParquetDouble and a string for
+// ParquetString. The file is written in one pass, with the metadata footer last.
+func WriteParquet(w io.Writer, columns []ParquetColumn, rows [][]interface{}) error {
+	out := &countingWriter{w: w}
+	if _, err := io.WriteString(out, "PAR1"); err != nil {
+		return err
+	}
+
+	var rowGroups []*thriftStruct
+	for start := 0; start < len(rows); start += parquetRowGroupSize {
+		end := start + parquetRowGroupSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		rowGroup, err := writeParquetRowGroup(out, columns, rows[start:end])
+		if err != nil {
+			return err
+		}
+		rowGroups = append(rowGroups, rowGroup)
+	}
+
+	// The schema is a root group followed by its columns
+	schema := []*thriftStruct{new(thriftStruct).binary(4, "schema").i32(5, int32(len(columns)))}
+	for _, column := range columns {
+		element := new(thriftStruct).i32(1, parquetPhysicalType(column.Type)).i32(3, parquetRequired).binary(4, column.Name)
+		if column.Type == ParquetString {
+			element.i32(6, parquetConvertedUTF8)
+		}
+		schema = append(schema, element)
+	}
+
+	metadata := new(thriftStruct).
+		i32(1, 1).
+		structs(2, schema).
+		i64(3, int64(len(rows))).
+		structs(4, rowGroups).
+		binary(6, "pdfex")
+	footer := metadata.bytes()
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	footer = append(footer, "PAR1"...)
+	_, err := out.Write(footer)
+	return err
+}
+
+// writeParquetRowGroup writes a column chunk of one data page for each column, returning the
+// row group metadata
+func writeParquetRowGroup(out *countingWriter, columns []ParquetColumn, rows [][]interface{}) (*thriftStruct, error) {
+	var chunks []*thriftStruct
+	var totalSize int64
+	for c, column := range columns {
+		var data []byte
+		for r, row := range rows {
+			if len(row) != len(columns) {
+				return nil, fmt.Errorf("row %d has %d values for %d columns", r, len(row), len(columns))
+			}
+			var err error
+			data, err = appendParquetValue(data, column.Type, row[c])
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %v", column.Name, err)
+			}
+		}
+		if len(data) > math.MaxInt32 {
+			return nil, fmt.Errorf("column %s is too large for one page", column.Name)
+		}
+
+		// Required columns have no definition or repetition levels, so the page is the values
+		pageHeader := new(thriftStruct).
+			i32(1, parquetDataPage).
+			i32(2, int32(len(data))).
+			i32(3, int32(len(data))).
+			structField(5, new(thriftStruct).
+				i32(1, int32(len(rows))).
+				i32(2, parquetEncodingPlain).
+				i32(3, parquetEncodingRLE).
+				i32(4, parquetEncodingRLE)).
+			bytes()
+
+		offset := out.n
+		if _, err := out.Write(pageHeader); err != nil {
+			return nil, err
+		}
+		if _, err := out.Write(data); err != nil {
+			return nil, err
+		}
+		size := int64(len(pageHeader) + len(data))
+		totalSize += size
+
+		metadata := new(thriftStruct).
+			i32(1, parquetPhysicalType(column.Type)).
+			i32s(2, []int32{parquetEncodingPlain, parquetEncodingRLE}).
+			strings(3, []string{column.Name}).
+			i32(4, 0). // Uncompressed
+			i64(5, int64(len(rows))).
+			i64(6, size).
+			i64(7, size).
+			i64(9, offset)
+		chunks = append(chunks, new(thriftStruct).i64(2, offset).structField(3, metadata))
+	}
+
+	return new(thriftStruct).structs(1, chunks).i64(2, totalSize).i64(3, int64(len(rows))), nil
+}
+
+// parquetPhysicalType returns the physical type that stores a column type
+func parquetPhysicalType(t ParquetType) int32 {
+	switch t {
+	case ParquetDouble:
+		return parquetPhysicalDouble
+	case ParquetString:
+		return parquetPhysicalByteArray
+	}
+	return parquetPhysicalInt64
+}
+
+// appendParquetValue appends a value in the plain encoding of its column type
+func appendParquetValue(data []byte, t ParquetType, value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case int:
+		if t == ParquetInt64 {
+			return binary.LittleEndian.AppendUint64(data, uint64(v)), nil
+		}
+	case int64:
+		if t == ParquetInt64 {
+			return binary.LittleEndian.AppendUint64(data, uint64(v)), nil
+		}
+	case float64:
+		if t == ParquetDouble {
+			return binary.LittleEndian.AppendUint64(data, math.Float64bits(v)), nil
+		}
+	case string:
+		if t == ParquetString {
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(v)))
+			return append(data, v...), nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected value %T", value)
+}
+
+// countingWriter tracks the offset in the file being written
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// thriftStruct encodes a struct in the Thrift compact protocol, in which Parquet metadata is
+// written. Fields must be added in increasing id order.
+type thriftStruct struct {
+	buf    []byte
+	lastID int16
+}
+
+// field writes the header of a field, with its id as a delta from the previous one when it fits
+func (s *thriftStruct) field(id int16, fieldType byte) {
+	if delta := id - s.lastID; delta > 0 && delta <= 15 {
+		s.buf = append(s.buf, byte(delta)<<4|fieldType)
+	} else {
+		s.buf = append(s.buf, fieldType)
+		s.buf = binary.AppendUvarint(s.buf, zigzag(int64(id)))
+	}
+	s.lastID = id
+}
+
+func (s *thriftStruct) i32(id int16, v int32) *thriftStruct {
+	s.field(id, thriftTypeI32)
+	s.buf = binary.AppendUvarint(s.buf, zigzag(int64(v)))
+	return s
+}
+
+func (s *thriftStruct) i64(id int16, v int64) *thriftStruct {
+	s.field(id, thriftTypeI64)
+	s.buf = binary.AppendUvarint(s.buf, zigzag(v))
+	return s
+}
+
+func (s *thriftStruct) binary(id int16, v string) *thriftStruct {
+	s.field(id, thriftTypeBinary)
+	s.buf = binary.AppendUvarint(s.buf, uint64(len(v)))
+	s.buf = append(s.buf, v...)
+	return s
+}
+
+func (s *thriftStruct) structField(id int16, v *thriftStruct) *thriftStruct {
+	s.field(id, thriftTypeStruct)
+	s.buf = append(s.buf, v.bytes()...)
+	return s
+}
+
+func (s *thriftStruct) i32s(id int16, values []int32) *thriftStruct {
+	s.listHeader(id, thriftTypeI32, len(values))
+	for _, v := range values {
+		s.buf = binary.AppendUvarint(s.buf, zigzag(int64(v)))
+	}
+	return s
+}
+
+func (s *thriftStruct) strings(id int16, values []string) *thriftStruct {
+	s.listHeader(id, thriftTypeBinary, len(values))
+	for _, v := range values {
+		s.buf = binary.AppendUvarint(s.buf, uint64(len(v)))
+		s.buf = append(s.buf, v...)
+	}
+	return s
+}
+
+func (s *thriftStruct) structs(id int16, values []*thriftStruct) *thriftStruct {
+	s.listHeader(id, thriftTypeStruct, len(values))
+	for _, v := range values {
+		s.buf = append(s.buf, v.bytes()...)
+	}
+	return s
+}
+
+// listHeader writes the header of a list field: its size and element type
+func (s *thriftStruct) listHeader(id int16, elemType byte, size int) {
+	s.field(id, thriftTypeList)
+	if size < 15 {
+		s.buf = append(s.buf, byte(size)<<4|elemType)
+		return
+	}
+	s.buf = append(s.buf, 0xf0|elemType)
+	s.buf = binary.AppendUvarint(s.buf, uint64(size))
+}
+
+// bytes returns the encoded struct, terminated by a stop field
+func (s *thriftStruct) bytes() []byte {
+	return append(append([]byte(nil), s.buf...), 0)
+}
+
+// zigzag maps signed integers to unsigned ones so that small magnitudes encode briefly
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
@@ -1,56 +1,59 @@
 package utils
 
 import (
+	"bytes"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf16"
 )
 
 var (
 	// Regular expressions for parsing PDF objects
-	keyRegex        = regexp.MustCompile(`/([A-Za-z0-9]+)[\s]+([\S]+|<<.*?>>|\[.*?\])`)
-	nestedDictRegex = regexp.MustCompile(`/([A-Za-z0-9]+)\s+<<(.*?)>>`)
-	refPattern      = regexp.MustCompile(`(\d+)\s+\d+\s+R`)
+	refPattern = regexp.MustCompile(`(\d+)\s+\d+\s+R`)
 )
 
-// ParseDictionary parses a PDF dictionary with improved handling for nested dictionaries
+// ParseDictionary parses the entries of a PDF dictionary, given without its enclosing << and
+// >>. Nested dictionaries become nested maps; other values are kept in PDF syntax, such as
+// "[1 2 3]", "(some text)", "12 0 R" or "null". Keys and name values have their #xx escapes
+// decoded. The entries before a malformed one are kept.
 func ParseDictionary(data []byte, dict map[string]interface{}) error {
-	// Handle nested dictionaries
-	matches := nestedDictRegex.FindAllSubmatch(data, -1)
-
-	for _, match := range matches {
-		key := string(match[1])
-		dictData := match[2]
+	p := &valueParser{lex: lexer{data: data}}
+	for {
+		keyTok := p.lex.next()
+		switch keyTok.kind {
+		case tokenEOF:
+			return nil
+		case tokenName:
+		default:
+			return fmt.Errorf("dictionary key is not a name: %s", truncate(p.lex.text(keyTok), 40))
+		}
+		key := UnescapeName(p.lex.text(keyTok)[1:])
 
-		// Create nested dictionary
-		nestedDict := make(map[string]interface{})
-		err := ParseDictionary(dictData, nestedDict)
+		valueTok := p.lex.next()
+		value, err := p.parse(valueTok, 1)
 		if err != nil {
-			return fmt.Errorf("error parsing nested dictionary for key %s: %v", key, err)
+			return fmt.Errorf("error parsing value of %s: %v", key, err)
 		}
 
-		dict[key] = nestedDict
-	}
-
-	// Regular key-value extraction
-	matches = keyRegex.FindAllSubmatch(data, -1)
-
-	for _, match := range matches {
-		key := string(match[1])
-		value := string(match[2])
-
-		// Skip keys that are already processed as nested dictionaries
-		if _, exists := dict[key]; exists {
-			if _, ok := dict[key].(map[string]interface{}); ok {
-				continue
+		switch v := value.(type) {
+		case Dict:
+			// Nested dictionary, without the << and >> that enclose it
+			nestedDict := make(map[string]interface{})
+			if err := ParseDictionary(data[valueTok.end:p.lex.pos-2], nestedDict); err != nil {
+				return fmt.Errorf("error parsing nested dictionary for key %s: %v", key, err)
 			}
+			dict[key] = nestedDict
+		case Name:
+			dict[key] = "/" + string(v)
+		case Ref:
+			dict[key] = v.String()
+		default:
+			dict[key] = string(data[valueTok.start:p.lex.pos])
 		}
-
-		dict[key] = value
 	}
-
-	return nil
 }
 
 // ParseArray parses a PDF array
@@ -117,6 +120,160 @@ func ExtractReference(ref string) (int, error) {
 	return 0, fmt.Errorf("invalid reference format: %s", ref)
 }
 
+// FindReferences returns the object numbers of all indirect references (e.g., "12 0 R") in data
+func FindReferences(data []byte) []int {
+	var refs []int
+	for _, match := range refPattern.FindAllSubmatch(data, -1) {
+		if objNum, err := strconv.Atoi(string(match[1])); err == nil {
+			refs = append(refs, objNum)
+		}
+	}
+	return refs
+}
+
+// DictionaryValue returns the raw value of a key in dictionary source, e.g. "[1 2 3]" or
+// "12 0 R". Unlike ParseDictionary it keeps arrays and references whole. Keys written with #xx
+// escapes match, and a name value has its escapes decoded. It returns an empty string if the
+// key is not present.
+func DictionaryValue(data []byte, key string) string {
+	if value := dictionaryValue(data, []byte("/"+key)); value != "" || bytes.IndexByte(data, '#') == -1 {
+		return value
+	}
+
+	// Look for the key among the escaped names
+	for offset := 0; ; {
+		idx := bytes.IndexByte(data[offset:], '#')
+		if idx == -1 {
+			return ""
+		}
+		start := bytes.LastIndexByte(data[:offset+idx], '/')
+		offset += idx + 1
+		if start == -1 {
+			continue
+		}
+		end := tokenEnd(data, start)
+		if end > offset && UnescapeName(string(data[start+1:end])) == key {
+			if value := dictionaryValue(data[start:], data[start:end]); value != "" {
+				return value
+			}
+		}
+	}
+}
+
+// dictionaryValue returns the value following the first occurrence of the name in data
+func dictionaryValue(data []byte, name []byte) string {
+	for offset := 0; ; {
+		idx := bytes.Index(data[offset:], name)
+		if idx == -1 {
+			return ""
+		}
+		start := offset + idx + len(name)
+		offset = start
+
+		// The name must end here, not continue as e.g. /WidthsX
+		if start < len(data) && !isPDFDelimiter(data[start]) && !isPDFWhitespace(data[start]) {
+			continue
+		}
+
+		for start < len(data) && isPDFWhitespace(data[start]) {
+			start++
+		}
+		if start >= len(data) {
+			return ""
+		}
+
+		switch {
+		case data[start] == '[':
+			return string(data[start:balancedEnd(data, start, '[', ']')])
+		case data[start] == '(':
+			return string(data[start:balancedEnd(data, start, '(', ')')])
+		case bytes.HasPrefix(data[start:], []byte("<<")):
+			return string(data[start:balancedEnd(data, start, '<', '>')])
+		}
+
+		// A single token, or an indirect reference "num gen R"
+		end := tokenEnd(data, start)
+		if ref := refPattern.Find(data[start:]); ref != nil && bytes.Index(data[start:], ref) == 0 {
+			return string(ref)
+		}
+		return UnescapeNameValue(string(data[start:end]))
+	}
+}
+
+// UnescapeName decodes the #xx escapes of a name given without its slash, such as
+// "Font#20Name" for "Font Name". Invalid escapes are kept as they are.
+func UnescapeName(name string) string {
+	if strings.IndexByte(name, '#') == -1 {
+		return name
+	}
+
+	var result strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '#' && i+2 < len(name) && isHexDigit(name[i+1]) && isHexDigit(name[i+2]) {
+			b, _ := strconv.ParseUint(name[i+1:i+3], 16, 8)
+			result.WriteByte(byte(b))
+			i += 2
+			continue
+		}
+		result.WriteByte(name[i])
+	}
+	return result.String()
+}
+
+// UnescapeNameValue decodes the #xx escapes of a value if it is a name, leaving other values alone
+func UnescapeNameValue(value string) string {
+	if !strings.HasPrefix(value, "/") {
+		return value
+	}
+	return "/" + UnescapeName(value[1:])
+}
+
+// isHexDigit reports whether a byte is a hexadecimal digit
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// balancedEnd returns the offset just past the delimiter that closes the one at start.
+// Dictionaries are handled by counting each angle bracket of "<<" and ">>".
+func balancedEnd(data []byte, start int, open, close byte) int {
+	depth := 0
+	for i := start; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			if open == '(' {
+				i++
+			}
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(data)
+}
+
+// tokenEnd returns the offset just past the token starting at start
+func tokenEnd(data []byte, start int) int {
+	end := start + 1
+	for end < len(data) && !isPDFWhitespace(data[end]) && !isPDFDelimiter(data[end]) {
+		end++
+	}
+	return end
+}
+
+// isPDFWhitespace reports whether a byte is PDF whitespace
+func isPDFWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+// isPDFDelimiter reports whether a byte is a PDF delimiter
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) != -1
+}
+
 // ParseFloat parses a float from a string
 func ParseFloat(str string) (float64, error) {
 	return strconv.ParseFloat(str, 64)
@@ -238,12 +395,13 @@ func GetName(val interface{}, defaultValue string) string {
 	return s
 }
 
-// DecodePDFString decodes a PDF string (handles hex strings and escapes)
+// DecodePDFString decodes a PDF string (handles hex strings and escapes) into its raw bytes.
+// Text strings meant for display need DecodeTextString, which also decodes their encoding.
 func DecodePDFString(str string) (string, error) {
 	// Check if this is a hex string
 	if strings.HasPrefix(str, "<") && strings.HasSuffix(str, ">") {
-		// Hex string
-		hexStr := str[1 : len(str)-1]
+		// Hex string (whitespace between digits is ignored)
+		hexStr := strings.Join(strings.Fields(str[1:len(str)-1]), "")
 		bytes := make([]byte, 0, len(hexStr)/2)
 
 		for i := 0; i < len(hexStr); i += 2 {
@@ -337,3 +495,130 @@ func DecodePDFString(str string) (string, error) {
 func isOctalDigit(c byte) bool {
 	return c >= '0' && c <= '7'
 }
+
+// pdfDocEncoding maps the codes of PDFDocEncoding that differ from ISO-8859-1 to Unicode
+// (PDF 32000-1:2008, Annex D.2). Codes 0x7F, 0x9F and 0xAD are undefined and kept as they are.
+var pdfDocEncoding = map[byte]rune{
+	0x18: '\u02D8', 0x19: '\u02C7', 0x1A: '\u02C6', 0x1B: '\u02D9',
+	0x1C: '\u02DD', 0x1D: '\u02DB', 0x1E: '\u02DA', 0x1F: '\u02DC',
+	0x80: '\u2022', 0x81: '\u2020', 0x82: '\u2021', 0x83: '\u2026',
+	0x84: '\u2014', 0x85: '\u2013', 0x86: '\u0192', 0x87: '\u2044',
+	0x88: '\u2039', 0x89: '\u203A', 0x8A: '\u2212', 0x8B: '\u2030',
+	0x8C: '\u201E', 0x8D: '\u201C', 0x8E: '\u201D', 0x8F: '\u2018',
+	0x90: '\u2019', 0x91: '\u201A', 0x92: '\u2122', 0x93: '\uFB01',
+	0x94: '\uFB02', 0x95: '\u0141', 0x96: '\u0152', 0x97: '\u0160',
+	0x98: '\u0178', 0x99: '\u017D', 0x9A: '\u0131', 0x9B: '\u0142',
+	0x9C: '\u0153', 0x9D: '\u0161', 0x9E: '\u017E', 0xA0: '\u20AC',
+}
+
+// DecodeTextString decodes a PDF text string, such as a bookmark title or an Info entry, into
+// UTF-8. See DecodeTextBytes for the encodings.
+func DecodeTextString(str string) string {
+	raw, err := DecodePDFString(str)
+	if err != nil {
+		return str
+	}
+	return DecodeTextBytes(raw)
+}
+
+// DecodeTextBytes decodes the bytes of a PDF text string into UTF-8. Strings starting with a
+// UTF-16BE or UTF-8 byte order mark are decoded accordingly, dropping the language escape
+// sequences that UTF-16 strings may hold; others are in PDFDocEncoding.
+func DecodeTextBytes(raw string) string {
+	switch {
+	case strings.HasPrefix(raw, "\xFE\xFF"):
+		units := make([]uint16, 0, len(raw)/2)
+		for i := 2; i+1 < len(raw); i += 2 {
+			units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+		}
+		return stripLanguageEscapes(string(utf16.Decode(units)))
+	case strings.HasPrefix(raw, "\xEF\xBB\xBF"):
+		return stripLanguageEscapes(raw[3:])
+	}
+
+	runes := make([]rune, len(raw))
+	for i := 0; i < len(raw); i++ {
+		if r, ok := pdfDocEncoding[raw[i]]; ok {
+			runes[i] = r
+		} else {
+			runes[i] = rune(raw[i])
+		}
+	}
+	return string(runes)
+}
+
+// stripLanguageEscapes removes the language and country codes that Unicode text strings may
+// enclose between ESC (U+001B) characters
+func stripLanguageEscapes(text string) string {
+	for {
+		start := strings.IndexRune(text, '\x1B')
+		if start < 0 {
+			return text
+		}
+		end := strings.IndexRune(text[start+1:], '\x1B')
+		if end < 0 {
+			return text
+		}
+		text = text[:start] + text[start+1+end+1:]
+	}
+}
+
+// ParseDate parses a PDF date string such as "D:20240131143000+01'00'", in which everything
+// after the year is optional, or an ISO 8601 date as used in XMP. Dates without a time zone are
+// taken to be in UTC.
+func ParseDate(str string) (time.Time, error) {
+	str = strings.TrimSpace(str)
+	if strings.Contains(str, "-") && !strings.HasPrefix(str, "D:") && len(str) >= 7 && str[4] == '-' {
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04Z07:00", "2006-01-02T15:04:05", "2006-01-02", "2006-01"} {
+			if t, err := time.Parse(layout, str); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid date: %q", str)
+	}
+
+	digits := strings.TrimPrefix(str, "D:")
+	end := 0
+	for end < len(digits) && end < 14 && digits[end] >= '0' && digits[end] <= '9' {
+		end++
+	}
+	if end < 4 || end%2 != 0 {
+		return time.Time{}, fmt.Errorf("invalid date: %q", str)
+	}
+
+	// Year, then month, day, hour, minute and second, which default to the start of the period
+	fields := []int{0, 1, 1, 0, 0, 0}
+	fields[0], _ = strconv.Atoi(digits[:4])
+	for i := 4; i < end; i += 2 {
+		fields[(i-2)/2], _ = strconv.Atoi(digits[i : i+2])
+	}
+	if fields[1] < 1 || fields[1] > 12 || fields[2] < 1 || fields[2] > 31 || fields[3] > 23 || fields[4] > 59 || fields[5] > 59 {
+		return time.Time{}, fmt.Errorf("invalid date: %q", str)
+	}
+
+	location := time.UTC
+	if zone := strings.TrimRight(digits[end:], "'"); zone != "" && zone[0] != 'Z' {
+		sign := 1
+		switch zone[0] {
+		case '+':
+		case '-':
+			sign = -1
+		default:
+			return time.Time{}, fmt.Errorf("invalid date: %q", str)
+		}
+		parts := strings.SplitN(zone[1:], "'", 2)
+		hours, err := strconv.Atoi(parts[0])
+		if err != nil || hours > 23 {
+			return time.Time{}, fmt.Errorf("invalid date: %q", str)
+		}
+		var minutes int
+		if len(parts) == 2 && parts[1] != "" {
+			if minutes, err = strconv.Atoi(parts[1]); err != nil || minutes > 59 {
+				return time.Time{}, fmt.Errorf("invalid date: %q", str)
+			}
+		}
+		location = time.FixedZone("", sign*(hours*3600+minutes*60))
+	}
+
+	return time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], fields[5], 0, location), nil
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ScratchPolicy controls where and how temporary files are written
+type ScratchPolicy struct {
+	Dir          string // Directory for temporary files; empty means os.TempDir()
+	MaxBytes     int64  // Maximum total size of live scratch files; 0 means no limit
+	SecureDelete bool   // Overwrite file contents with zeros before removing them
+}
+
+// scratchFile is a live scratch file: its size and whether it is overwritten on removal
+type scratchFile struct {
+	size         int64
+	secureDelete bool
+}
+
+// Usage accounting of live scratch files, shared by every caller, with mutex for thread safety
+var (
+	scratchFiles = make(map[string]scratchFile)
+	scratchUsed  int64
+	scratchMutex sync.Mutex
+)
+
+// CreateScratchFile writes data to a new temporary file according to policy and returns its
+// path. MaxBytes caps the size of every live scratch file of the process, this one included.
+// The file must be released with RemoveScratchFile, which honours the policy's SecureDelete.
+func CreateScratchFile(policy ScratchPolicy, pattern string, data []byte) (string, error) {
+	size := int64(len(data))
+
+	scratchMutex.Lock()
+	if policy.MaxBytes > 0 && scratchUsed+size > policy.MaxBytes {
+		used := scratchUsed
+		scratchMutex.Unlock()
+		return "", fmt.Errorf("scratch space limit exceeded: %d bytes in use, %d requested, limit %d",
+			used, size, policy.MaxBytes)
+	}
+	// Reserve the space before writing so concurrent callers can't overshoot the cap
+	scratchUsed += size
+	scratchMutex.Unlock()
+
+	release := func() {
+		scratchMutex.Lock()
+		scratchUsed -= size
+		scratchMutex.Unlock()
+	}
+
+	file, err := os.CreateTemp(policy.Dir, pattern)
+	if err != nil {
+		release()
+		return "", fmt.Errorf("failed to create scratch file: %v", err)
+	}
+	path := file.Name()
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		removeFile(path, size, policy.SecureDelete)
+		release()
+		return "", fmt.Errorf("failed to write scratch file: %v", err)
+	}
+
+	if err := file.Close(); err != nil {
+		removeFile(path, size, policy.SecureDelete)
+		release()
+		return "", fmt.Errorf("failed to close scratch file: %v", err)
+	}
+
+	scratchMutex.Lock()
+	scratchFiles[path] = scratchFile{size: size, secureDelete: policy.SecureDelete}
+	scratchMutex.Unlock()
+
+	return path, nil
+}
+
+// RemoveScratchFile removes a file created by CreateScratchFile, overwriting it first
+// when the policy it was created with requires secure deletion
+func RemoveScratchFile(path string) error {
+	scratchMutex.Lock()
+	file, ok := scratchFiles[path]
+	delete(scratchFiles, path)
+	if ok {
+		scratchUsed -= file.size
+	}
+	scratchMutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("not a scratch file: %s", path)
+	}
+
+	return removeFile(path, file.size, file.secureDelete)
+}
+
+// ScratchBytesInUse returns the total size of live scratch files
+func ScratchBytesInUse() int64 {
+	scratchMutex.Lock()
+	defer scratchMutex.Unlock()
+	return scratchUsed
+}
+
+// removeFile deletes a file, overwriting its contents with zeros first if requested
+func removeFile(path string, size int64, secure bool) error {
+	if secure {
+		if err := overwriteFile(path, size); err != nil {
+			LogWarningf("Failed to overwrite scratch file %s: %v", path, err)
+		}
+	}
+	return os.Remove(path)
+}
+
+// overwriteFile overwrites the first size bytes of a file with zeros and syncs it to disk
+func overwriteFile(path string, size int64) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zeros := make([]byte, 32*1024)
+	for remaining := size; remaining > 0; {
+		n := int64(len(zeros))
+		if remaining < n {
+			n = remaining
+		}
+		if _, err := file.Write(zeros[:n]); err != nil {
+			return err
+		}
+		remaining -= n
+	}
+
+	return file.Sync()
+}
@@ -0,0 +1,354 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Value is a PDF object value: a Name, Number, String, Bool, Null, Ref, Array or Dict. String
+// returns it in PDF syntax, so that it can be handed to code that works on the source.
+type Value interface {
+	String() string
+}
+
+// Name is a PDF name, without its slash and with its #xx escapes decoded
+type Name string
+
+// Number is a PDF integer or real number
+type Number float64
+
+// String is a PDF literal or hexadecimal string, holding its bytes with escapes decoded
+type String string
+
+// Bool is a PDF boolean
+type Bool bool
+
+// Null is the PDF null object
+type Null struct{}
+
+// Ref is an indirect reference to an object
+type Ref struct {
+	Num int
+	Gen int
+}
+
+// Array is a PDF array
+type Array []Value
+
+// Dict is a PDF dictionary, keyed by name without the slash
+type Dict map[string]Value
+
+// String returns the name with its slash, escaping the characters a name can't hold as is
+func (n Name) String() string {
+	var sb strings.Builder
+	sb.WriteByte('/')
+	for i := 0; i < len(n); i++ {
+		c := n[i]
+		if c < '!' || c > '~' || c == '#' || isPDFDelimiter(c) {
+			fmt.Fprintf(&sb, "#%02X", c)
+		} else {
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// String returns the number as an integer when it has no fractional part
+func (n Number) String() string {
+	return strconv.FormatFloat(float64(n), 'f', -1, 64)
+}
+
+// String returns the string as a hexadecimal string, which holds any bytes
+func (s String) String() string {
+	return fmt.Sprintf("<%X>", string(s))
+}
+
+// Text decodes the string as a text string, from UTF-16BE or PDFDocEncoding
+func (s String) Text() string {
+	return DecodeTextBytes(string(s))
+}
+
+// String returns true or false
+func (b Bool) String() string {
+	return strconv.FormatBool(bool(b))
+}
+
+// String returns null
+func (Null) String() string {
+	return "null"
+}
+
+// String returns the reference as "num gen R"
+func (r Ref) String() string {
+	return fmt.Sprintf("%d %d R", r.Num, r.Gen)
+}
+
+// String returns the items of the array between brackets
+func (a Array) String() string {
+	items := make([]string, len(a))
+	for i, item := range a {
+		items[i] = item.String()
+	}
+	return "[" + strings.Join(items, " ") + "]"
+}
+
+// String returns the entries of the dictionary, sorted by key
+func (d Dict) String() string {
+	keys := make([]string, 0, len(d))
+	for key := range d {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString("<<")
+	for _, key := range keys {
+		sb.WriteString(Name(key).String())
+		sb.WriteByte(' ')
+		sb.WriteString(d[key].String())
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(">>")
+	return sb.String()
+}
+
+// Get returns the value of a key, or nil if the dictionary doesn't have it
+func (d Dict) Get(key string) Value {
+	return d[key]
+}
+
+// Name returns the value of a key if it is a name, without its slash
+func (d Dict) Name(key string) (string, bool) {
+	name, ok := d[key].(Name)
+	return string(name), ok
+}
+
+// Number returns the value of a key if it is a number
+func (d Dict) Number(key string) (float64, bool) {
+	number, ok := d[key].(Number)
+	return float64(number), ok
+}
+
+// Int returns the value of a key if it is a number, truncated to an integer
+func (d Dict) Int(key string) (int, bool) {
+	number, ok := d[key].(Number)
+	return int(number), ok
+}
+
+// Bool returns the value of a key if it is a boolean
+func (d Dict) Bool(key string) (bool, bool) {
+	b, ok := d[key].(Bool)
+	return bool(b), ok
+}
+
+// Text returns the value of a key decoded as a text string, if it is a string
+func (d Dict) Text(key string) (string, bool) {
+	s, ok := d[key].(String)
+	return s.Text(), ok
+}
+
+// Ref returns the value of a key if it is an indirect reference
+func (d Dict) Ref(key string) (Ref, bool) {
+	ref, ok := d[key].(Ref)
+	return ref, ok
+}
+
+// Array returns the value of a key if it is an array
+func (d Dict) Array(key string) (Array, bool) {
+	array, ok := d[key].(Array)
+	return array, ok
+}
+
+// Dict returns the value of a key if it is a dictionary
+func (d Dict) Dict(key string) (Dict, bool) {
+	dict, ok := d[key].(Dict)
+	return dict, ok
+}
+
+// Map returns the dictionary in the form of ParseDictionary: values in PDF syntax, except
+// dictionaries, which are nested maps
+func (d Dict) Map() map[string]interface{} {
+	m := make(map[string]interface{}, len(d))
+	for key, value := range d {
+		if dict, ok := value.(Dict); ok {
+			m[key] = dict.Map()
+		} else {
+			m[key] = value.String()
+		}
+	}
+	return m
+}
+
+// Numbers returns the items of the array if they are all numbers
+func (a Array) Numbers() ([]float64, bool) {
+	numbers := make([]float64, len(a))
+	for i, item := range a {
+		number, ok := item.(Number)
+		if !ok {
+			return nil, false
+		}
+		numbers[i] = float64(number)
+	}
+	return numbers, true
+}
+
+// DictFrom converts a dictionary parsed by ParseDictionary to the typed model. Values that
+// can't be parsed are left out.
+func DictFrom(m map[string]interface{}) Dict {
+	dict := make(Dict, len(m))
+	for key, value := range m {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			dict[key] = DictFrom(v)
+		case string:
+			if parsed, err := ParseValue([]byte(v)); err == nil {
+				dict[key] = parsed
+			}
+		}
+	}
+	return dict
+}
+
+// ParseDict parses the source of a dictionary, such as that of an object before its stream
+func ParseDict(data []byte) (Dict, error) {
+	value, err := ParseValue(data)
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := value.(Dict)
+	if !ok {
+		return nil, fmt.Errorf("not a dictionary: %s", truncate(string(data), 40))
+	}
+	return dict, nil
+}
+
+// ParseValue parses the first value in data. Anything after it is ignored.
+func ParseValue(data []byte) (Value, error) {
+	p := &valueParser{lex: lexer{data: data}}
+	return p.value(0)
+}
+
+// Maximum nesting of arrays and dictionaries parsed by ParseValue
+const maxValueDepth = 64
+
+// valueParser reads values from PDF source
+type valueParser struct {
+	lex lexer
+}
+
+// value parses the value at the current position
+func (p *valueParser) value(depth int) (Value, error) {
+	return p.parse(p.lex.next(), depth)
+}
+
+// parse parses the value starting with a token that has been read
+func (p *valueParser) parse(tok token, depth int) (Value, error) {
+	if depth > maxValueDepth {
+		return nil, fmt.Errorf("values nested more than %d deep", maxValueDepth)
+	}
+
+	text := p.lex.text(tok)
+	switch tok.kind {
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of data")
+	case tokenName:
+		return Name(UnescapeName(text[1:])), nil
+	case tokenString, tokenHexString:
+		if tok.unterminated {
+			return nil, fmt.Errorf("unterminated string: %s", truncate(text, 40))
+		}
+		decoded, err := DecodePDFString(text)
+		return String(decoded), err
+	case tokenDictOpen:
+		return p.dict(depth)
+	case tokenArrayOpen:
+		return p.array(depth)
+	case tokenNumber:
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number: %s", truncate(text, 40))
+		}
+		return p.reference(Number(number)), nil
+	case tokenKeyword:
+		switch text {
+		case "true":
+			return Bool(true), nil
+		case "false":
+			return Bool(false), nil
+		case "null":
+			return Null{}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid value: %s", truncate(text, 40))
+}
+
+// array parses the items of an array whose [ has been read
+func (p *valueParser) array(depth int) (Value, error) {
+	array := Array{}
+	for {
+		tok := p.lex.next()
+		switch tok.kind {
+		case tokenArrayClose:
+			return array, nil
+		case tokenEOF:
+			return nil, fmt.Errorf("unterminated array")
+		}
+		item, err := p.parse(tok, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, item)
+	}
+}
+
+// dict parses the entries of a dictionary whose << has been read
+func (p *valueParser) dict(depth int) (Value, error) {
+	dict := make(Dict)
+	for {
+		tok := p.lex.next()
+		switch tok.kind {
+		case tokenDictClose:
+			return dict, nil
+		case tokenEOF:
+			return nil, fmt.Errorf("unterminated dictionary")
+		case tokenName:
+		default:
+			return nil, fmt.Errorf("dictionary key is not a name: %s", truncate(p.lex.text(tok), 40))
+		}
+
+		value, err := p.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		dict[UnescapeName(p.lex.text(tok)[1:])] = value
+	}
+}
+
+// reference turns a number followed by a generation number and R into a reference
+func (p *valueParser) reference(value Value) Value {
+	num, ok := value.(Number)
+	if !ok || float64(num) != float64(int(num)) || num < 0 {
+		return value
+	}
+
+	saved := p.lex.pos
+	if gen := p.lex.next(); gen.kind == tokenNumber {
+		if genNum, err := strconv.Atoi(p.lex.text(gen)); err == nil {
+			if r := p.lex.next(); r.kind == tokenKeyword && p.lex.text(r) == "R" {
+				return Ref{Num: int(num), Gen: genNum}
+			}
+		}
+	}
+	p.lex.pos = saved
+	return value
+}
+
+// truncate shortens text for an error message
+func truncate(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	return text[:n] + "..."
+}
@@ -0,0 +1,277 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Value
+	}{
+		{"integer", "42", Number(42)},
+		{"real", "-.5", Number(-0.5)},
+		{"true", "true", Bool(true)},
+		{"false", "false", Bool(false)},
+		{"null", "null", Null{}},
+		{"name", "/Type", Name("Type")},
+		{"reference", "12 0 R", Ref{Num: 12, Gen: 0}},
+		{"number before another number", "12 0 obj", Number(12)},
+		{"negative number is no reference", "-1 0 R", Number(-1)},
+		{"real is no reference", "1.5 0 R", Number(1.5)},
+		{"literal string", "(Hello World)", String("Hello World")},
+		{"balanced parentheses", "(a (b (c)) d)", String("a (b (c)) d")},
+		{"escaped parentheses", `(a \) b \( c)`, String("a ) b ( c")},
+		{"escaped backslash before )", `(a\\)`, String(`a\`)},
+		{"escapes", `(\n\t\101\0533)`, String("\n\tA+3")},
+		{"line continuation", "(one \\\r\ntwo)", String("one two")},
+		{"string spanning lines", "(one\ntwo)", String("one\ntwo")},
+		{"hex string", "<48 65\n6C 6C 6F>", String("Hello")},
+		{"odd hex string", "<414>", String("A@")},
+		{"empty hex string", "<>", String("")},
+		{"name escape", "/A#20B", Name("A B")},
+		{"escaped slash and delimiter", "/#2F#28x#29", Name("/(x)")},
+		{"invalid name escapes kept", "/C#zz#4", Name("C#zz#4")},
+		{"empty array", "[]", Array{}},
+		{
+			name: "nested arrays",
+			data: "[1 [2 [3 /Four] ] [] (five)]",
+			want: Array{Number(1), Array{Number(2), Array{Number(3), Name("Four")}}, Array{}, String("five")},
+		},
+		{
+			name: "references in an array",
+			data: "[1 0 R 2 0 R 3 4]",
+			want: Array{Ref{Num: 1}, Ref{Num: 2}, Number(3), Number(4)},
+		},
+		{
+			name: "nested dictionaries",
+			data: "<< /A << /B << /C 1 >> >> /D [<< /E null >>] >>",
+			want: Dict{
+				"A": Dict{"B": Dict{"C": Number(1)}},
+				"D": Array{Dict{"E": Null{}}},
+			},
+		},
+		{
+			name: "values spanning lines",
+			data: "<<\n/Type\n/Page\r\n/MediaBox [0 0\n612\r792]\r\n/Parent\n3\n0\nR\n>>",
+			want: Dict{
+				"Type":     Name("Page"),
+				"MediaBox": Array{Number(0), Number(0), Number(612), Number(792)},
+				"Parent":   Ref{Num: 3},
+			},
+		},
+		{
+			name: "no whitespace between tokens",
+			data: "<</A/B/C[1 2]/D(x)/E<41>/F<</G 1>>>>",
+			want: Dict{
+				"A": Name("B"),
+				"C": Array{Number(1), Number(2)},
+				"D": String("x"),
+				"E": String("A"),
+				"F": Dict{"G": Number(1)},
+			},
+		},
+		{
+			name: "comments",
+			data: "<< /A 1 % /B 2 >>\n/C (% not a comment) >>",
+			want: Dict{"A": Number(1), "C": String("% not a comment")},
+		},
+		{
+			name: "delimiters inside strings",
+			data: "<< /S (>> ] /X) /T [(a]b)] >>",
+			want: Dict{"S": String(">> ] /X"), "T": Array{String("a]b")}},
+		},
+		{
+			name: "escaped keys",
+			data: "<< /Key#20One 1 /Key#23 2 >>",
+			want: Dict{"Key One": Number(1), "Key#": Number(2)},
+		},
+		{
+			name: "null values",
+			data: "<< /A null /B [null null] >>",
+			want: Dict{"A": Null{}, "B": Array{Null{}, Null{}}},
+		},
+		{
+			name: "later duplicate key wins",
+			data: "<< /A 1 /A 2 >>",
+			want: Dict{"A": Number(2)},
+		},
+		{"trailing data ignored", "<< /A 1 >> stream\nxxx", Dict{"A": Number(1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseValue([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseValue(%q) failed: %v", tt.data, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseValue(%q) = %#v, want %#v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseValueMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"only whitespace", " \r\n\t"},
+		{"only a comment", "% nothing"},
+		{"unterminated dictionary", "<< /A 1"},
+		{"unterminated nested dictionary", "<< /A << /B 1 >>"},
+		{"unterminated array", "[1 2"},
+		{"unterminated nested array", "[1 [2]"},
+		{"unterminated string", "(abc"},
+		{"unterminated nested string", "(a (b) c"},
+		{"escaped closing parenthesis", `(abc\)`},
+		{"unterminated hex string", "<414"},
+		{"invalid hex string", "<4G>"},
+		{"key without a value", "<< /A >>"},
+		{"key that isn't a name", "<< 1 2 >>"},
+		{"string key", "<< (A) 1 >>"},
+		{"stray closing bracket", "]"},
+		{"stray closing dictionary", ">>"},
+		{"stray parenthesis", ")"},
+		{"mismatched brackets", "[1 >>"},
+		{"mismatched dictionary close", "<< /A 1 ]"},
+		{"unknown keyword", "nil"},
+		{"malformed number", "1.2.3"},
+		{"double sign", "--5"},
+		{"too deeply nested", strings.Repeat("[", 100) + strings.Repeat("]", 100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ParseValue([]byte(tt.data)); err == nil {
+				t.Errorf("ParseValue(%q) = %#v, want an error", tt.data, got)
+			}
+		})
+	}
+}
+
+// Every proper prefix of a dictionary is incomplete, so parsing it must fail without panicking
+func TestParseDictTruncated(t *testing.T) {
+	source := "<< /Type /Annot /Rect [10 20.5 -30 .4] /Contents (A (nested) \\) string)" +
+		" /AP << /N 12 0 R /D [<< /X null >>] >> /Name /A#20B /H <4142> /F true >>"
+	if _, err := ParseDict([]byte(source)); err != nil {
+		t.Fatalf("ParseDict of the whole dictionary failed: %v", err)
+	}
+
+	for n := 0; n < len(source); n++ {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("ParseDict(%q) panicked: %v", source[:n], r)
+				}
+			}()
+			if dict, err := ParseDict([]byte(source[:n])); err == nil {
+				t.Errorf("ParseDict(%q) = %v, want an error", source[:n], dict)
+			}
+		}()
+	}
+}
+
+func TestParseDictNotADictionary(t *testing.T) {
+	for _, data := range []string{"[1 2]", "/Name", "(<< /A 1 >>)", "12 0 R"} {
+		if dict, err := ParseDict([]byte(data)); err == nil {
+			t.Errorf("ParseDict(%q) = %v, want an error", data, dict)
+		}
+	}
+}
+
+func TestParseDictionary(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want map[string]interface{}
+	}{
+		{
+			name: "values kept in PDF syntax",
+			data: "/Type /Page /Kids [3 0 R 4 0 R] /Count 2 /Parent 1 0 R /Title (A (nested) title) /N null",
+			want: map[string]interface{}{
+				"Type":   "/Page",
+				"Kids":   "[3 0 R 4 0 R]",
+				"Count":  "2",
+				"Parent": "1 0 R",
+				"Title":  "(A (nested) title)",
+				"N":      "null",
+			},
+		},
+		{
+			name: "nested dictionaries become maps",
+			data: "/Resources << /Font << /F1 5 0 R >> /ProcSet [/PDF /Text] >>",
+			want: map[string]interface{}{
+				"Resources": map[string]interface{}{
+					"Font":    map[string]interface{}{"F1": "5 0 R"},
+					"ProcSet": "[/PDF /Text]",
+				},
+			},
+		},
+		{
+			name: "arrays holding dictionaries",
+			data: "/Annots [<< /A 1 >> << /B [2] >>] /X 1",
+			want: map[string]interface{}{"Annots": "[<< /A 1 >> << /B [2] >>]", "X": "1"},
+		},
+		{
+			name: "values spanning lines",
+			data: "/MediaBox [0 0\r\n612 792]\n/Contents\n8\n0\nR",
+			want: map[string]interface{}{"MediaBox": "[0 0\r\n612 792]", "Contents": "8 0 R"},
+		},
+		{
+			name: "escaped names",
+			data: "/Font#20Name /Helvetica#2DBold",
+			want: map[string]interface{}{"Font Name": "/Helvetica-Bold"},
+		},
+		{
+			name: "empty",
+			data: " \n",
+			want: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]interface{})
+			if err := ParseDictionary([]byte(tt.data), got); err != nil {
+				t.Fatalf("ParseDictionary(%q) failed: %v", tt.data, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDictionary(%q) = %v, want %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+// The entries before a malformed one are kept, and the error reports it
+func TestParseDictionaryMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		kept map[string]interface{}
+	}{
+		{"key without a value", "/A 1 /B", map[string]interface{}{"A": "1"}},
+		{"unterminated array", "/A 1 /B [1 2", map[string]interface{}{"A": "1"}},
+		{"unterminated string", "/A (x) /B (y", map[string]interface{}{"A": "(x)"}},
+		{"unterminated nested dictionary", "/A /N /B << /C 1", map[string]interface{}{"A": "/N"}},
+		{"key that isn't a name", "/A 1 2 3", map[string]interface{}{"A": "1"}},
+		{"stray closing dictionary", "/A 1 >>", map[string]interface{}{"A": "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]interface{})
+			if err := ParseDictionary([]byte(tt.data), got); err == nil {
+				t.Errorf("ParseDictionary(%q) succeeded, want an error", tt.data)
+			}
+			if !reflect.DeepEqual(got, tt.kept) {
+				t.Errorf("ParseDictionary(%q) kept %v, want %v", tt.data, got, tt.kept)
+			}
+		})
+	}
+}
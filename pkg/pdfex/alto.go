@@ -0,0 +1,213 @@
+package pdfex
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// ALTO (Analyzed Layout and Text Object) version 4 elements. Measurements are in 1/1200 inch
+// from the top left of the upright page.
+type altoDocument struct {
+	XMLName     xml.Name        `xml:"alto"`
+	Namespace   string          `xml:"xmlns,attr"`
+	Description altoDescription `xml:"Description"`
+	Styles      []altoTextStyle `xml:"Styles>TextStyle,omitempty"`
+	Pages       []altoPage      `xml:"Layout>Page"`
+}
+
+type altoDescription struct {
+	MeasurementUnit string `xml:"MeasurementUnit"`
+	FileName        string `xml:"sourceImageInformation>fileName"`
+}
+
+type altoTextStyle struct {
+	ID         string  `xml:"ID,attr"`
+	FontFamily string  `xml:"FONTFAMILY,attr,omitempty"`
+	FontSize   float64 `xml:"FONTSIZE,attr"` // In points
+}
+
+type altoPage struct {
+	ID         string         `xml:"ID,attr"`
+	Number     int            `xml:"PHYSICAL_IMG_NR,attr"`
+	Width      int            `xml:"WIDTH,attr"`
+	Height     int            `xml:"HEIGHT,attr"`
+	PrintSpace altoPrintSpace `xml:"PrintSpace"`
+}
+
+type altoPrintSpace struct {
+	altoBox
+	Blocks []altoTextBlock `xml:"TextBlock"`
+}
+
+type altoBox struct {
+	HPos   int `xml:"HPOS,attr"`
+	VPos   int `xml:"VPOS,attr"`
+	Width  int `xml:"WIDTH,attr"`
+	Height int `xml:"HEIGHT,attr"`
+}
+
+type altoTextBlock struct {
+	ID string `xml:"ID,attr"`
+	altoBox
+	Lines []altoTextLine `xml:"TextLine"`
+}
+
+type altoTextLine struct {
+	ID string `xml:"ID,attr"`
+	altoBox
+	Items []altoLineItem
+}
+
+// altoLineItem is a String, or an SP between two strings
+type altoLineItem struct {
+	XMLName   xml.Name
+	ID        string `xml:"ID,attr,omitempty"`
+	Content   string `xml:"CONTENT,attr,omitempty"`
+	HPos      int    `xml:"HPOS,attr"`
+	VPos      int    `xml:"VPOS,attr"`
+	Width     int    `xml:"WIDTH,attr"`
+	Height    int    `xml:"HEIGHT,attr,omitempty"`
+	StyleRefs string `xml:"STYLEREFS,attr,omitempty"`
+}
+
+// altoUnits converts points to the 1/1200 inch units of ALTO measurements
+func altoUnits(v float64) int {
+	return int(math.Round(v * 1200 / 72))
+}
+
+// ExportALTO writes the text of the document as ALTO XML, version 4, for archive and
+// digitisation workflows. Each page has a TextBlock per detected text block, a TextLine per
+// line and a String per word, positioned from the glyph widths of the fonts and measured in
+// 1/1200 inch from the top left of the upright page. Vertical text is left out.
+func (p *PDFDocument) ExportALTO(w io.Writer) error {
+	p.ensureExtracted()
+
+	doc := altoDocument{
+		Namespace:   "http://www.loc.gov/standards/alto/ns-v4#",
+		Description: altoDescription{MeasurementUnit: "inch1200", FileName: filepath.Base(p.source)},
+	}
+	styles := make(map[string]string)
+
+	// style returns the ID of the text style of a word's font and size, adding it if needed
+	style := func(pos document.TextPosition) string {
+		family := strings.TrimPrefix(p.doc.Fonts["/"+pos.FontName].BaseFont, "/")
+		size := math.Round(pos.FontSize*10) / 10
+		key := fmt.Sprintf("%s %g", family, size)
+		if id, ok := styles[key]; ok {
+			return id
+		}
+		id := fmt.Sprintf("TS%d", len(styles)+1)
+		styles[key] = id
+		doc.Styles = append(doc.Styles, altoTextStyle{ID: id, FontFamily: family, FontSize: size})
+		return id
+	}
+
+	for i := range p.doc.Pages {
+		page := &p.doc.Pages[i]
+		if !p.doc.PageSelected(page.PageNumber) {
+			continue
+		}
+
+		width, height := page.Width, page.Height
+		if page.TextRotation == 90 || page.TextRotation == 270 {
+			width, height = height, width
+		}
+		// box converts a box in upright page space to ALTO measurements
+		box := func(b text.Box) altoBox {
+			return altoBox{
+				HPos:   altoUnits(b.MinX),
+				VPos:   altoUnits(height - b.MaxY),
+				Width:  altoUnits(b.MaxX - b.MinX),
+				Height: altoUnits(b.MaxY - b.MinY),
+			}
+		}
+
+		pageID := fmt.Sprintf("P%d", page.PageNumber)
+		entry := altoPage{
+			ID:         pageID,
+			Number:     page.PageNumber,
+			Width:      altoUnits(width),
+			Height:     altoUnits(height),
+			PrintSpace: altoPrintSpace{altoBox: box(text.Box{MaxX: width, MaxY: height})},
+		}
+
+		var spans []document.TextPosition
+		for _, pos := range page.TextPositions {
+			if strings.TrimSpace(pos.Text) != "" && !pos.Vertical {
+				spans = append(spans, pos)
+			}
+		}
+
+		for _, positions := range text.DetectTextBlocks(spans, page.Width, page.Height) {
+			block := altoTextBlock{ID: fmt.Sprintf("%s_B%d", pageID, len(entry.PrintSpace.Blocks)+1)}
+			var blockBounds text.Box
+
+			for _, linePositions := range text.GroupLines(positions) {
+				words := text.NewPageIndex(linePositions).Words()
+				if len(words) == 0 {
+					continue
+				}
+				line := altoTextLine{ID: fmt.Sprintf("%s_L%d", block.ID, len(block.Lines)+1)}
+				lineBounds := words[0].Box
+
+				for n, word := range words {
+					b := box(word.Box)
+					if n > 0 {
+						// SP spans the gap after the previous word
+						previous := words[n-1].Box
+						line.Items = append(line.Items, altoLineItem{
+							XMLName: xml.Name{Local: "SP"},
+							HPos:    altoUnits(previous.MaxX),
+							VPos:    b.VPos,
+							Width:   altoUnits(math.Max(word.Box.MinX-previous.MaxX, 0)),
+						})
+					}
+					line.Items = append(line.Items, altoLineItem{
+						XMLName:   xml.Name{Local: "String"},
+						ID:        fmt.Sprintf("%s_S%d", line.ID, n+1),
+						Content:   word.Text,
+						HPos:      b.HPos,
+						VPos:      b.VPos,
+						Width:     b.Width,
+						Height:    b.Height,
+						StyleRefs: style(word.Position),
+					})
+					lineBounds = lineBounds.Union(word.Box)
+				}
+
+				line.altoBox = box(lineBounds)
+				if len(block.Lines) == 0 {
+					blockBounds = lineBounds
+				} else {
+					blockBounds = blockBounds.Union(lineBounds)
+				}
+				block.Lines = append(block.Lines, line)
+			}
+
+			if len(block.Lines) > 0 {
+				block.altoBox = box(blockBounds)
+				entry.PrintSpace.Blocks = append(entry.PrintSpace.Blocks, block)
+			}
+		}
+
+		doc.Pages = append(doc.Pages, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write ALTO: %v", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,28 @@
+package pdfex
+
+// Annotation is an annotation on a page, such as a comment, a highlight or a link
+type Annotation struct {
+	Page     int    `json:"page"`    // 1-based page the annotation is on
+	Subtype  string `json:"subtype"` // Annotation type, e.g. Text, Highlight or Link
+	Rect     Rect   `json:"rect"`    // Area on the unrotated page
+	Contents string `json:"contents,omitempty"`
+	Author   string `json:"author,omitempty"`
+	Modified string `json:"modified,omitempty"` // Modification date as written in the file
+}
+
+// GetAnnotations returns the annotations of the document in page order
+func (p *PDFDocument) GetAnnotations() []Annotation {
+	internal := p.doc.GetAnnotations()
+	annotations := make([]Annotation, 0, len(internal))
+	for _, annot := range internal {
+		annotations = append(annotations, Annotation{
+			Page:     annot.PageNumber,
+			Subtype:  annot.Subtype,
+			Rect:     Rect{X1: annot.Rect[0], Y1: annot.Rect[1], X2: annot.Rect[2], Y2: annot.Rect[3]},
+			Contents: annot.Contents,
+			Author:   annot.Author,
+			Modified: annot.Modified,
+		})
+	}
+	return annotations
+}
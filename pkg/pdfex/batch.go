@@ -0,0 +1,121 @@
+package pdfex
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// BatchOptions control how ProcessBatch works through a list of files
+type BatchOptions struct {
+	Jobs         int           // Files parsed at the same time; 0 or less uses one per CPU
+	ParseOptions *ParseOptions // Options for every file; nil uses DefaultParseOptions
+}
+
+// BatchResult is the outcome of processing one file of a batch
+type BatchResult struct {
+	File      string        `json:"file"`
+	Pages     int           `json:"pages"`
+	Duration  time.Duration `json:"duration"`  // Time spent parsing and processing the file
+	Recovered bool          `json:"recovered"` // The document was damaged and needed recovery to parse
+	Err       error         `json:"-"`
+	Error     string        `json:"error,omitempty"`      // Err as text, for JSON reports
+	ErrorKind ErrorKind     `json:"error_kind,omitempty"` // Classification of Err
+}
+
+// BatchSummary aggregates the results of a batch, which are in the order the files were given
+type BatchSummary struct {
+	Files     int           `json:"files"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Recovered int           `json:"recovered"` // Files that succeeded after recovery
+	Pages     int           `json:"pages"`     // Pages of the files that succeeded
+	Duration  time.Duration `json:"duration"`  // Wall-clock time of the whole batch
+	Results   []BatchResult `json:"results"`
+}
+
+// ProcessBatch parses files concurrently with a bounded number of workers and calls process on
+// each document that parses, from the worker that parsed it, so process must be safe to call
+// concurrently. A file fails if it doesn't parse, is encrypted (with ErrEncrypted) or process
+// returns an error. Log output from different files may interleave.
+func ProcessBatch(files []string, options *BatchOptions, process func(filename string, doc *PDFDocument) error) *BatchSummary {
+	if options == nil {
+		options = &BatchOptions{}
+	}
+	jobs := options.Jobs
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+	if jobs > len(files) {
+		jobs = len(files)
+	}
+	parseOptions := options.ParseOptions
+	if parseOptions == nil {
+		parseOptions = DefaultParseOptions()
+	}
+
+	start := time.Now()
+	results := make([]BatchResult, len(files))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] = processBatchFile(files[i], parseOptions, process)
+			}
+		}()
+	}
+	for i := range files {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	summary := &BatchSummary{Files: len(files), Duration: time.Since(start), Results: results}
+	for _, result := range results {
+		if result.Err != nil {
+			summary.Failed++
+			continue
+		}
+		summary.Succeeded++
+		summary.Pages += result.Pages
+		if result.Recovered {
+			summary.Recovered++
+		}
+	}
+	return summary
+}
+
+// processBatchFile parses and processes one file of a batch
+func processBatchFile(filename string, options *ParseOptions, process func(string, *PDFDocument) error) BatchResult {
+	start := time.Now()
+	result := BatchResult{File: filename}
+
+	doc, err := ParsePDFWithOptions(filename, options)
+	if err == nil {
+		result.Pages = doc.PageCount()
+		result.Recovered = doc.DegradationReport().Degraded()
+		if doc.Encrypted() {
+			err = ErrEncrypted
+		} else if process != nil {
+			err = process(filename, doc)
+		}
+		doc.Close()
+	}
+
+	result.Duration = time.Since(start)
+	result.SetErr(err)
+	return result
+}
+
+// SetErr records the error of a result with its text and kind
+func (r *BatchResult) SetErr(err error) {
+	r.Err = err
+	r.Error = ""
+	r.ErrorKind = KindOf(err)
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
@@ -0,0 +1,62 @@
+package pdfex
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// Paragraph is a paragraph of a text block, with its lines joined into a single line
+type Paragraph struct {
+	Text   string `json:"text"`
+	Bounds Rect   `json:"bounds"`
+}
+
+// Block is a group of paragraphs laid out together, such as a column of body text, a heading
+// or a caption. Coordinates are on the unrotated page, in points like any Rect.
+type Block struct {
+	Text       string      `json:"text"` // Paragraphs separated by blank lines
+	Bounds     Rect        `json:"bounds"`
+	Paragraphs []Paragraph `json:"paragraphs"`
+}
+
+// GetPageBlocks segments the text of a page (1-based) into blocks and paragraphs, in reading order
+func (p *PDFDocument) GetPageBlocks(pageNum int) ([]Block, error) {
+	if pageNum < 1 || pageNum > len(p.doc.Pages) {
+		return nil, fmt.Errorf("page number out of range: %d", pageNum)
+	}
+
+	return pageBlocks(p.extractPage(pageNum)), nil
+}
+
+// pageBlocks segments the text of a page whose positions have been extracted into blocks
+func pageBlocks(page *document.PDFPage) []Block {
+	var spans []document.TextPosition
+	for _, pos := range page.TextPositions {
+		if strings.TrimSpace(pos.Text) != "" {
+			spans = append(spans, pos)
+		}
+	}
+
+	detected := text.DetectTextBlocks(spans, page.Width, page.Height)
+	blocks := make([]Block, 0, len(detected))
+	for _, positions := range detected {
+		block := Block{Bounds: userSpaceRect(text.TextBounds(positions), page)}
+
+		var texts []string
+		for _, paragraph := range text.DetectParagraphs(positions) {
+			paragraphText := text.JoinLines(paragraph)
+			texts = append(texts, paragraphText)
+			block.Paragraphs = append(block.Paragraphs, Paragraph{
+				Text:   paragraphText,
+				Bounds: userSpaceRect(text.TextBounds(paragraph), page),
+			})
+		}
+		block.Text = strings.Join(texts, "\n\n")
+
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
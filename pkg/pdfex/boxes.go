@@ -0,0 +1,33 @@
+package pdfex
+
+import "fmt"
+
+// PageBoxes are the boundaries of a page, in points like any Rect. Boxes a page doesn't define take
+// their default: the crop box defaults to the media box, and the others to the crop box.
+type PageBoxes struct {
+	MediaBox Rect `json:"media_box"` // Extent of the physical medium
+	CropBox  Rect `json:"crop_box"`  // Region shown by viewers and printers
+	BleedBox Rect `json:"bleed_box"` // Region to clip to in production, including bleed
+	TrimBox  Rect `json:"trim_box"`  // Intended size of the finished page after trimming
+	ArtBox   Rect `json:"art_box"`   // Extent of the page's meaningful content
+}
+
+// GetPageBoxes returns the media, crop, bleed, trim and art boxes of a page (1-based), with
+// inherited values and defaults applied
+func (p *PDFDocument) GetPageBoxes(pageNum int) (PageBoxes, error) {
+	if pageNum < 1 || pageNum > len(p.doc.Pages) {
+		return PageBoxes{}, fmt.Errorf("page number out of range: %d", pageNum)
+	}
+
+	boxes := p.doc.Pages[pageNum-1].Boxes
+	rect := func(box [4]float64) Rect {
+		return Rect{X1: box[0], Y1: box[1], X2: box[2], Y2: box[3]}
+	}
+	return PageBoxes{
+		MediaBox: rect(boxes.MediaBox),
+		CropBox:  rect(boxes.CropBox),
+		BleedBox: rect(boxes.BleedBox),
+		TrimBox:  rect(boxes.TrimBox),
+		ArtBox:   rect(boxes.ArtBox),
+	}, nil
+}
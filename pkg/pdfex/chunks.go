@@ -0,0 +1,410 @@
+package pdfex
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yourusername/pdfex/internal/text"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// ChunkSplit selects where chunks preferably end
+type ChunkSplit int
+
+const (
+	// SplitParagraph ends chunks at blank lines, or at line ends within long paragraphs
+	SplitParagraph ChunkSplit = iota
+	// SplitSentence ends chunks at sentence ends
+	SplitSentence
+	// SplitPage starts a new chunk on every page, splitting long pages like SplitParagraph
+	SplitPage
+	// SplitHeading starts a new chunk at every detected heading, splitting long sections like
+	// SplitParagraph
+	SplitHeading
+)
+
+// chunkSplitNames are the names of the split modes accepted by ParseChunkSplit
+var chunkSplitNames = map[string]ChunkSplit{
+	"paragraph": SplitParagraph,
+	"sentence":  SplitSentence,
+	"page":      SplitPage,
+	"heading":   SplitHeading,
+}
+
+// ParseChunkSplit returns the split mode with the given name: paragraph, sentence, page or heading
+func ParseChunkSplit(name string) (ChunkSplit, error) {
+	split, ok := chunkSplitNames[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown chunk split mode: %s", name)
+	}
+	return split, nil
+}
+
+// ChunkOptions control how the text of a document is cut into chunks, for example to index it
+// for retrieval
+type ChunkOptions struct {
+	MaxSize int        // Maximum chunk length in characters
+	Overlap int        // Characters at the end of a chunk that are repeated at the start of the next
+	SplitOn ChunkSplit // Where chunks preferably end
+
+	// Add the source file name and the document title and author to each chunk's Metadata
+	IncludeMetadata bool
+}
+
+// DefaultChunkOptions returns options for chunks of up to 1000 characters ending at paragraphs,
+// without overlap, the size of the chunks made while parsing
+func DefaultChunkOptions() *ChunkOptions {
+	return &ChunkOptions{MaxSize: 1000}
+}
+
+// Chunk is a piece of the document text. Offsets locate it in the text of all pages as returned
+// by ExtractPageTexts, joined by blank lines; Text is that range with surrounding whitespace
+// removed.
+type Chunk struct {
+	Index       int               `json:"index"` // 0-based position in the document
+	Text        string            `json:"text"`
+	StartPage   int               `json:"start_page"`
+	EndPage     int               `json:"end_page"`
+	StartOffset int               `json:"start_offset"` // In bytes
+	EndOffset   int               `json:"end_offset"`
+	StartChar   int               `json:"start_char"` // In characters
+	EndChar     int               `json:"end_char"`
+	HeadingPath []string          `json:"heading_path,omitempty"` // Detected headings the chunk falls under, outermost first
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Patterns of the places where chunks may end, from the end of a match
+var (
+	blankLinePattern   = regexp.MustCompile(`\n[ \t]*\n\s*`)
+	lineEndPattern     = regexp.MustCompile(`\n\s*`)
+	sentenceEndPattern = regexp.MustCompile(`[.!?…][)\]"'”’]*\s+`)
+	wordEndPattern     = regexp.MustCompile(`\s+`)
+)
+
+// Chunks extracts the text of the document and cuts it into chunks of at most MaxSize
+// characters. Each chunk ends at the most suitable place within its last half for the split
+// mode, falling back to line ends, sentence ends, spaces and finally a hard cut. Page and
+// heading boundaries in those modes always end a chunk, and overlap doesn't cross them.
+func (p *PDFDocument) Chunks(options *ChunkOptions) ([]Chunk, error) {
+	if options == nil {
+		options = DefaultChunkOptions()
+	}
+	if options.MaxSize <= 0 {
+		return nil, fmt.Errorf("invalid chunk size: %d", options.MaxSize)
+	}
+	if options.Overlap < 0 || options.Overlap >= options.MaxSize {
+		return nil, fmt.Errorf("invalid chunk overlap: %d", options.Overlap)
+	}
+
+	texts := p.ExtractPageTexts()
+	source, pageStarts := documentText(texts)
+	pageAt := func(offset int) int {
+		return pageAtOffset(pageStarts, offset)
+	}
+
+	headings := locateHeadings(text.DetectHeadings(p.doc.Pages, p.doc.Fonts), texts, pageStarts)
+
+	// Hard boundaries always start a new chunk
+	var hard []int
+	switch options.SplitOn {
+	case SplitPage:
+		hard = append(hard, pageStarts[1:]...)
+	case SplitHeading:
+		for _, h := range headings {
+			hard = append(hard, h.offset)
+		}
+	}
+	hard = append(hard, len(source))
+	sort.Ints(hard)
+
+	preferred := []*regexp.Regexp{blankLinePattern, lineEndPattern, sentenceEndPattern, wordEndPattern}
+	if options.SplitOn == SplitSentence {
+		preferred = []*regexp.Regexp{sentenceEndPattern, blankLinePattern, lineEndPattern, wordEndPattern}
+	}
+
+	var metadata map[string]string
+	if options.IncludeMetadata {
+		metadata = map[string]string{"source": filepath.Base(p.source)}
+		info := p.Metadata()
+		if info.Title != "" {
+			metadata["title"] = info.Title
+		}
+		if info.Author != "" {
+			metadata["author"] = info.Author
+		}
+	}
+
+	// Chunks start in increasing order, so headings and character counts are tracked as they go
+	var path []chunkHeading
+	nextHeading := 0
+	counted, chars := 0, 0
+
+	var chunks []Chunk
+	start := 0
+	for start < len(source) {
+		limit := hard[sort.SearchInts(hard, start+1)]
+		end := limit
+		if utf8.RuneCountInString(source[start:limit]) > options.MaxSize {
+			end = chunkEnd(source, start, runeOffset(source, start, options.MaxSize), preferred)
+		}
+
+		first := start + len(source[start:end]) - len(strings.TrimLeft(source[start:end], " \t\r\n"))
+		last := start + len(strings.TrimRight(source[start:end], " \t\r\n"))
+		if first < last {
+			if first < counted {
+				counted, chars = 0, 0
+			}
+			chars += utf8.RuneCountInString(source[counted:first])
+			counted = first
+
+			for ; nextHeading < len(headings) && headings[nextHeading].offset <= first; nextHeading++ {
+				h := headings[nextHeading]
+				for len(path) > 0 && path[len(path)-1].level >= h.level {
+					path = path[:len(path)-1]
+				}
+				path = append(path, h)
+			}
+
+			chunk := Chunk{
+				Index:       len(chunks),
+				Text:        source[first:last],
+				StartPage:   pageAt(first),
+				EndPage:     pageAt(last - 1),
+				StartOffset: first,
+				EndOffset:   last,
+				StartChar:   chars,
+				EndChar:     chars + utf8.RuneCountInString(source[first:last]),
+			}
+			for _, h := range path {
+				chunk.HeadingPath = append(chunk.HeadingPath, h.text)
+			}
+			if metadata != nil {
+				chunk.Metadata = make(map[string]string, len(metadata))
+				for k, v := range metadata {
+					chunk.Metadata[k] = v
+				}
+			}
+			chunks = append(chunks, chunk)
+		}
+
+		if end == limit || options.Overlap == 0 {
+			start = end
+			continue
+		}
+		// The next chunk starts at a word within the overlap, or mid-word if there is none
+		next := runeOffset(source, start, utf8.RuneCountInString(source[start:end])-options.Overlap)
+		if loc := wordEndPattern.FindStringIndex(source[next:end]); loc != nil && next+loc[1] < end {
+			next += loc[1]
+		}
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+
+	return chunks, nil
+}
+
+// documentText joins page texts with blank lines, returning the text and the offset at which
+// each page starts
+func documentText(texts []string) (string, []int) {
+	pageStarts := make([]int, len(texts))
+	offset := 0
+	for i, t := range texts {
+		pageStarts[i] = offset
+		offset += len(t) + len("\n\n")
+	}
+	return strings.Join(texts, "\n\n"), pageStarts
+}
+
+// pageAtOffset returns the 1-based page whose text contains an offset of the document text
+func pageAtOffset(pageStarts []int, offset int) int {
+	return sort.Search(len(pageStarts), func(i int) bool { return pageStarts[i] > offset })
+}
+
+// chunkHeading is a detected heading located in the document text
+type chunkHeading struct {
+	text   string
+	level  int
+	offset int
+}
+
+// locateHeadings finds detected headings in the page texts, in order. Lines of a wrapped heading
+// are joined by spaces when detected, so any whitespace matches between words.
+func locateHeadings(headings []text.Heading, texts []string, pageStarts []int) []chunkHeading {
+	var located []chunkHeading
+	from := make([]int, len(texts))
+	for _, h := range headings {
+		i := h.PageNumber - 1
+		if i < 0 || i >= len(texts) {
+			continue
+		}
+		words := strings.Fields(h.Text)
+		for n, word := range words {
+			words[n] = regexp.QuoteMeta(word)
+		}
+		pattern, err := regexp.Compile(strings.Join(words, `\s+`))
+		if err != nil || len(words) == 0 {
+			continue
+		}
+		if loc := pattern.FindStringIndex(texts[i][from[i]:]); loc != nil {
+			located = append(located, chunkHeading{text: h.Text, level: h.Level, offset: pageStarts[i] + from[i] + loc[0]})
+			from[i] += loc[1]
+		}
+	}
+	return located
+}
+
+// chunkEnd returns where a chunk starting at start and cut at cut should end: after the last
+// match of the first pattern that matches within the second half of the chunk, otherwise after
+// the last match of any pattern, or at the cut
+func chunkEnd(source string, start, cut int, preferred []*regexp.Regexp) int {
+	half := start + (cut-start)/2
+	best := start
+	for _, pattern := range preferred {
+		last := start
+		for _, loc := range pattern.FindAllStringIndex(source[start:cut], -1) {
+			last = start + loc[1]
+		}
+		if last >= half && last > start {
+			return last
+		}
+		if last > best {
+			best = last
+		}
+	}
+	if best > start {
+		return best
+	}
+	return cut
+}
+
+// runeOffset returns the byte offset n runes after start in s
+func runeOffset(s string, start, n int) int {
+	offset := start
+	for i := 0; i < n && offset < len(s); i++ {
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// ChunkRecord is a line of a JSONL chunk export: a chunk with the provenance of its source
+type ChunkRecord struct {
+	Source       string `json:"source"`        // File name of the document
+	SourceSHA256 string `json:"source_sha256"` // Hash of the document file
+	Chunk
+}
+
+// ChunkRecords cuts the document text into chunks and returns them with their provenance
+func (p *PDFDocument) ChunkRecords(options *ChunkOptions) ([]ChunkRecord, error) {
+	chunks, err := p.Chunks(options)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]ChunkRecord, len(chunks))
+	for i, chunk := range chunks {
+		records[i] = ChunkRecord{Source: filepath.Base(p.source), SourceSHA256: p.sourceSHA256, Chunk: chunk}
+	}
+	return records, nil
+}
+
+// WriteChunksJSONL cuts the document text into chunks and writes them as JSON Lines, one
+// ChunkRecord per line, for loading into retrieval and indexing pipelines
+func (p *PDFDocument) WriteChunksJSONL(w io.Writer, options *ChunkOptions) error {
+	records, err := p.ChunkRecords(options)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteChunksParquet cuts the document text into chunks and writes them as a Parquet file, for
+// loading into Spark, DuckDB and other data-engineering tools
+func (p *PDFDocument) WriteChunksParquet(w io.Writer, options *ChunkOptions) error {
+	records, err := p.ChunkRecords(options)
+	if err != nil {
+		return err
+	}
+	return WriteChunkRecordsParquet(w, records)
+}
+
+// Columns of chunk Parquet files: the fields of ChunkRecord, with the heading path and metadata
+// as JSON text
+var chunkParquetColumns = []utils.ParquetColumn{
+	{Name: "source", Type: utils.ParquetString},
+	{Name: "source_sha256", Type: utils.ParquetString},
+	{Name: "index", Type: utils.ParquetInt64},
+	{Name: "text", Type: utils.ParquetString},
+	{Name: "start_page", Type: utils.ParquetInt64},
+	{Name: "end_page", Type: utils.ParquetInt64},
+	{Name: "start_offset", Type: utils.ParquetInt64},
+	{Name: "end_offset", Type: utils.ParquetInt64},
+	{Name: "start_char", Type: utils.ParquetInt64},
+	{Name: "end_char", Type: utils.ParquetInt64},
+	{Name: "heading_path", Type: utils.ParquetString},
+	{Name: "metadata", Type: utils.ParquetString},
+}
+
+// WriteChunkRecordsParquet writes chunk records, which may come from several documents, as a
+// Parquet file with a row per chunk
+func WriteChunkRecordsParquet(w io.Writer, records []ChunkRecord) error {
+	rows := make([][]interface{}, len(records))
+	for i, record := range records {
+		// Empty rather than null, so that JSON functions see the same shape in every row
+		if record.HeadingPath == nil {
+			record.HeadingPath = []string{}
+		}
+		if record.Metadata == nil {
+			record.Metadata = map[string]string{}
+		}
+		headingPath, err := json.Marshal(record.HeadingPath)
+		if err != nil {
+			return err
+		}
+		metadata, err := json.Marshal(record.Metadata)
+		if err != nil {
+			return err
+		}
+		rows[i] = []interface{}{
+			record.Source, record.SourceSHA256, record.Index, record.Text,
+			record.StartPage, record.EndPage, record.StartOffset, record.EndOffset,
+			record.StartChar, record.EndChar, string(headingPath), string(metadata),
+		}
+	}
+	return utils.WriteParquet(w, chunkParquetColumns, rows)
+}
+
+// SaveChunksJSONL writes the chunks made with the default options to a JSON Lines file
+func (p *PDFDocument) SaveChunksJSONL(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(file)
+	if err := p.WriteChunksJSONL(bw, DefaultChunkOptions()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
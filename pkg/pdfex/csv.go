@@ -0,0 +1,90 @@
+package pdfex
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// CSVOptions control how the text of a document is exported as delimited rows
+type CSVOptions struct {
+	Spans     bool // One row per text span instead of per word
+	Delimiter rune // Field separator; defaults to a comma, use '\t' for TSV
+}
+
+// csvHeader is the header row of text exports
+var csvHeader = []string{"page", "x", "y", "width", "height", "font", "size", "text"}
+
+// ExportCSV writes the text of the document as delimited rows with a header, one row per word
+// or span in reading order: the page, the box in points from the bottom left of the upright page,
+// the font and its size, and the text. Fonts are given by their base font name where known.
+func (p *PDFDocument) ExportCSV(w io.Writer, options *CSVOptions) error {
+	if options == nil {
+		options = &CSVOptions{}
+	}
+
+	p.ensureExtracted()
+
+	writer := csv.NewWriter(w)
+	if options.Delimiter != 0 {
+		writer.Comma = options.Delimiter
+	}
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for i := range p.doc.Pages {
+		page := &p.doc.Pages[i]
+		if !p.doc.PageSelected(page.PageNumber) {
+			continue
+		}
+		pageNumber := strconv.Itoa(page.PageNumber)
+
+		if options.Spans {
+			for _, pos := range page.TextPositions {
+				if strings.TrimSpace(pos.Text) == "" {
+					continue
+				}
+				row := p.csvRow(pageNumber, text.TextBounds([]document.TextPosition{pos}), pos, pos.Text)
+				if err := writer.Write(row); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		for _, word := range text.NewPageIndex(page.TextPositions).Words() {
+			if err := writer.Write(p.csvRow(pageNumber, word.Box, word.Position, word.Text)); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvRow formats a word or span as a row of a text export
+func (p *PDFDocument) csvRow(pageNumber string, box text.Box, pos document.TextPosition, s string) []string {
+	font := strings.TrimPrefix(p.doc.Fonts["/"+pos.FontName].BaseFont, "/")
+	if font == "" {
+		font = pos.FontName
+	}
+	number := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+	return []string{
+		pageNumber,
+		number(box.MinX),
+		number(box.MinY),
+		number(box.MaxX - box.MinX),
+		number(box.MaxY - box.MinY),
+		font,
+		number(pos.FontSize),
+		s,
+	}
+}
@@ -0,0 +1,18 @@
+package pdfex
+
+import "github.com/yourusername/pdfex/internal/document"
+
+// DegradationReport records which parse path produced the document and, in verification
+// mode, how the cross-reference table compares with a full scan of the file
+type DegradationReport = document.DegradationReport
+
+// ParsePath identifies how the object table of a document was obtained
+type ParsePath = document.ParsePath
+
+// Parse paths, from most to least trustworthy
+const (
+	ParsePathXRef         = document.ParsePathXRef         // Cross-reference table at the startxref offset
+	ParsePathAdjustedXRef = document.ParsePathAdjustedXRef // Cross-reference table found near a wrong startxref offset
+	ParsePathRebuild      = document.ParsePathRebuild      // Table rebuilt by scanning the file for objects
+	ParsePathLinear       = document.ParsePathLinear       // No usable xref; objects parsed linearly
+)
@@ -0,0 +1,120 @@
+package pdfex
+
+import (
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// DiffOp is the kind of a line in a page diff
+type DiffOp = text.DiffOp
+
+// Page diff line operations
+const (
+	DiffEqual  = text.DiffEqual
+	DiffDelete = text.DiffDelete
+	DiffInsert = text.DiffInsert
+)
+
+// DiffLine is one line of a page diff
+type DiffLine struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// PageStatus says how a page changed between two documents
+type PageStatus string
+
+// Page statuses
+const (
+	PageUnchanged PageStatus = "unchanged"
+	PageChanged   PageStatus = "changed"
+	PageAdded     PageStatus = "added"   // Only in the new document
+	PageRemoved   PageStatus = "removed" // Only in the old document
+)
+
+// PageDiff is the difference between a page of the old document and the page of the new
+// document aligned with it
+type PageDiff struct {
+	OldPage int        `json:"old_page,omitempty"` // 1-based, 0 for an added page
+	NewPage int        `json:"new_page,omitempty"` // 1-based, 0 for a removed page
+	Status  PageStatus `json:"status"`
+	Lines   []DiffLine `json:"lines,omitempty"` // Edit script from the old text to the new, empty if unchanged
+}
+
+// DocumentDiff is the page-aligned text difference between two documents
+type DocumentDiff struct {
+	Pages     []PageDiff `json:"pages"`
+	Added     int        `json:"added"`
+	Removed   int        `json:"removed"`
+	Changed   int        `json:"changed"`
+	Unchanged int        `json:"unchanged"`
+}
+
+// Identical reports whether the documents have the same text on every page
+func (d *DocumentDiff) Identical() bool {
+	return d.Added == 0 && d.Removed == 0 && d.Changed == 0
+}
+
+// Diff compares the text of two documents page by page, for reviewing revisions. Pages are
+// aligned so that an inserted or deleted page doesn't show every later page as changed, and
+// each changed page gets a line diff. Trailing spaces and blank lines are ignored.
+func Diff(oldDoc, newDoc *PDFDocument) *DocumentDiff {
+	oldPages := diffPageTexts(oldDoc)
+	newPages := diffPageTexts(newDoc)
+
+	diff := &DocumentDiff{Pages: []PageDiff{}}
+	for _, pair := range text.AlignPages(oldPages, newPages) {
+		page := PageDiff{OldPage: pair[0] + 1, NewPage: pair[1] + 1}
+
+		var oldLines, newLines []string
+		switch {
+		case pair[0] < 0:
+			page.Status = PageAdded
+			diff.Added++
+			newLines = diffLines(newPages[pair[1]])
+		case pair[1] < 0:
+			page.Status = PageRemoved
+			diff.Removed++
+			oldLines = diffLines(oldPages[pair[0]])
+		case oldPages[pair[0]] == newPages[pair[1]]:
+			page.Status = PageUnchanged
+			diff.Unchanged++
+			diff.Pages = append(diff.Pages, page)
+			continue
+		default:
+			page.Status = PageChanged
+			diff.Changed++
+			oldLines = diffLines(oldPages[pair[0]])
+			newLines = diffLines(newPages[pair[1]])
+		}
+
+		for _, line := range text.DiffLines(oldLines, newLines) {
+			page.Lines = append(page.Lines, DiffLine{Op: line.Op, Text: line.Text})
+		}
+		diff.Pages = append(diff.Pages, page)
+	}
+	return diff
+}
+
+// diffPageTexts returns the text of each page normalized for comparison, so that pages
+// differing only in trailing spaces and blank lines compare equal
+func diffPageTexts(doc *PDFDocument) []string {
+	pages := doc.ExtractPageTexts()
+	for i, pageText := range pages {
+		pages[i] = strings.Join(diffLines(pageText), "\n")
+	}
+	return pages
+}
+
+// diffLines splits page text into lines without trailing spaces, dropping blank lines
+func diffLines(pageText string) []string {
+	var lines []string
+	for _, line := range strings.Split(pageText, "\n") {
+		line = strings.TrimRight(line, " \t\r\f")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
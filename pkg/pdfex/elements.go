@@ -0,0 +1,282 @@
+package pdfex
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Kinds of page elements recognised for document exports
+const (
+	elementHeading = iota
+	elementParagraph
+	elementList
+	elementTable
+	elementImage
+)
+
+// listMarkerPattern matches the bullet or number that starts a list item; the group is set for
+// numbered items
+var listMarkerPattern = regexp.MustCompile(`^(?:[•◦▪▫‣⁃∙·*–-]|(\(?(?:\d{1,3}|[a-zA-Z])[.)]))\s+`)
+
+// pageElement is a unit of page content, such as a heading or a table, in reading order
+type pageElement struct {
+	kind    int
+	level   int         // Heading level, 1 for the most prominent
+	text    string      // Heading or paragraph text
+	items   []string    // List items without their markers
+	ordered bool        // The list is numbered
+	table   *text.Table // Table cells
+	image   *pageImage
+	bounds  text.Box // In upright page space
+}
+
+// pageImage is an image drawn on a page, encoded for embedding
+type pageImage struct {
+	name string // Resource name on the page
+	mime string // image/jpeg or image/png
+	data []byte
+}
+
+// pageElements segments the text of a page whose positions have been extracted into headings,
+// paragraphs, lists and tables in reading order, with its images placed before the first
+// element below their top edge. Headings are those detected for the document.
+func (p *PDFDocument) pageElements(page *document.PDFPage, headings []text.Heading, includeImages bool) []pageElement {
+	var pageHeadings []text.Heading
+	for _, h := range headings {
+		if h.PageNumber == page.PageNumber {
+			pageHeadings = append(pageHeadings, h)
+		}
+	}
+	// heading returns the heading that a paragraph starts with, if any, and consumes it
+	heading := func(s string) (text.Heading, bool) {
+		for i, h := range pageHeadings {
+			if s == h.Text || strings.HasPrefix(s, h.Text+" ") {
+				pageHeadings = append(pageHeadings[:i], pageHeadings[i+1:]...)
+				return h, true
+			}
+		}
+		return text.Heading{}, false
+	}
+
+	tables := text.DetectTables(page)
+	emitted := make([]bool, len(tables))
+	tableAt := func(box text.Box) int {
+		x, y := (box.MinX+box.MaxX)/2, (box.MinY+box.MaxY)/2
+		for i, t := range tables {
+			if x >= t.Bounds.MinX && x <= t.Bounds.MaxX && y >= t.Bounds.MinY && y <= t.Bounds.MaxY {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var spans []document.TextPosition
+	for _, pos := range page.TextPositions {
+		if strings.TrimSpace(pos.Text) != "" {
+			spans = append(spans, pos)
+		}
+	}
+
+	var elements []pageElement
+	for _, block := range text.DetectTextBlocks(spans, page.Width, page.Height) {
+		for _, paragraph := range text.DetectParagraphs(block) {
+			bounds := text.TextBounds(paragraph)
+			if i := tableAt(bounds); i >= 0 {
+				if !emitted[i] {
+					emitted[i] = true
+					elements = append(elements, pageElement{kind: elementTable, table: &tables[i], bounds: tables[i].Bounds})
+				}
+				continue
+			}
+
+			content := text.JoinLines(paragraph)
+			if h, ok := heading(content); ok {
+				elements = append(elements, pageElement{kind: elementHeading, level: h.Level, text: h.Text, bounds: bounds})
+				if content = strings.TrimSpace(content[len(h.Text):]); content == "" {
+					continue
+				}
+			}
+
+			if items, ordered := listItems(text.TextLines(paragraph)); items != nil {
+				// Consecutive lists of the same kind are one list split across paragraphs
+				if n := len(elements); n > 0 && elements[n-1].kind == elementList && elements[n-1].ordered == ordered {
+					elements[n-1].items = append(elements[n-1].items, items...)
+					continue
+				}
+				elements = append(elements, pageElement{kind: elementList, items: items, ordered: ordered, bounds: bounds})
+				continue
+			}
+			elements = append(elements, pageElement{kind: elementParagraph, text: content, bounds: bounds})
+		}
+	}
+	for i := range tables {
+		if !emitted[i] {
+			elements = append(elements, pageElement{kind: elementTable, table: &tables[i], bounds: tables[i].Bounds})
+		}
+	}
+
+	if includeImages {
+		for _, img := range p.pageImages(page) {
+			at := len(elements)
+			for i, element := range elements {
+				if element.bounds.MaxY <= img.bounds.MaxY {
+					at = i
+					break
+				}
+			}
+			elements = append(elements[:at], append([]pageElement{img}, elements[at:]...)...)
+		}
+	}
+
+	return elements
+}
+
+// listItems splits the lines of a paragraph into list items if it starts with a list marker,
+// joining lines without a marker to the item before them
+func listItems(lines []string) ([]string, bool) {
+	if len(lines) == 0 {
+		return nil, false
+	}
+	match := listMarkerPattern.FindStringSubmatch(lines[0])
+	if match == nil {
+		return nil, false
+	}
+	ordered := match[1] != ""
+
+	var items []string
+	for _, line := range lines {
+		if m := listMarkerPattern.FindStringSubmatch(line); m != nil && (m[1] != "") == ordered {
+			items = append(items, strings.TrimSpace(line[len(m[0]):]))
+			continue
+		}
+		items[len(items)-1] += " " + strings.TrimSpace(line)
+	}
+	return items, ordered
+}
+
+// pageImages returns the images a page draws directly, as elements placed where they are drawn.
+// Images that can't be embedded, and with ExcludeHiddenLayers those on hidden layers, are left
+// out.
+func (p *PDFDocument) pageImages(page *document.PDFPage) []pageElement {
+	var images []pageElement
+	for _, drawn := range p.drawnImages(page) {
+		mime, data, ok := encodeImage(drawn.obj)
+		if !ok {
+			utils.Infof(p.doc.Logger(), "Leaving out image %s on page %d: unsupported encoding\n", drawn.name, page.PageNumber)
+			continue
+		}
+		images = append(images, pageElement{
+			kind:   elementImage,
+			image:  &pageImage{name: drawn.name, mime: mime, data: data},
+			bounds: drawn.bounds,
+		})
+	}
+	return images
+}
+
+// drawnImage is an image XObject drawn directly by a page's content stream
+type drawnImage struct {
+	name   string // Resource name on the page
+	obj    document.PDFObject
+	bounds text.Box // In upright page space
+}
+
+// drawnImages returns the image XObjects a page draws directly, in drawing order. With
+// ExcludeHiddenLayers, images on hidden layers are left out.
+func (p *PDFDocument) drawnImages(page *document.PDFPage) []drawnImage {
+	xobjects := p.doc.PageResources(*page, "XObject")
+	var hidden map[string]bool
+	if p.textOptions.ExcludeHiddenLayers {
+		hidden = text.HiddenXObjects(*page)
+	}
+
+	var images []drawnImage
+	for _, placement := range text.DrawnXObjects(page) {
+		obj, ok := p.doc.GetObject(xobjects[placement.Name])
+		if !ok || obj.Dictionary["Subtype"] != "/Image" || hidden[placement.Name] {
+			continue
+		}
+		if p.textOptions.ExcludeHiddenLayers && p.doc.XObjectHidden(obj.ObjectNumber) {
+			continue
+		}
+		images = append(images, drawnImage{name: placement.Name, obj: obj, bounds: placement.Bounds})
+	}
+	return images
+}
+
+// encodeImage returns an image XObject in a format browsers display: JPEG data as stored, or
+// 8-bit grey, RGB and CMYK samples as PNG
+func encodeImage(obj document.PDFObject) (string, []byte, bool) {
+	if strings.Contains(utils.GetString(obj.Dictionary["Filter"], ""), "/DCTDecode") {
+		return "image/jpeg", obj.Stream, true
+	}
+	img, ok := decodeImage(obj, nil)
+	if !ok {
+		return "", nil, false
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", nil, false
+	}
+	return "image/png", buf.Bytes(), true
+}
+
+// decodeImage decodes JPEG data or 8-bit grey, RGB and CMYK samples. With a palette of RGB
+// triplets, the samples are indexes into it instead.
+func decodeImage(obj document.PDFObject, palette []byte) (image.Image, bool) {
+	filter := utils.GetString(obj.Dictionary["Filter"], "")
+	if strings.Contains(filter, "/DCTDecode") {
+		img, _, err := image.Decode(bytes.NewReader(obj.Stream))
+		return img, err == nil
+	}
+	if filter != "" && filter != "/FlateDecode" {
+		return nil, false
+	}
+
+	width := utils.GetInteger(obj.Dictionary["Width"], 0)
+	height := utils.GetInteger(obj.Dictionary["Height"], 0)
+	if width <= 0 || height <= 0 || utils.GetInteger(obj.Dictionary["BitsPerComponent"], 8) != 8 {
+		return nil, false
+	}
+	components := 1
+	if palette == nil {
+		components = map[string]int{"/DeviceGray": 1, "/DeviceRGB": 3, "/DeviceCMYK": 4}[utils.GetString(obj.Dictionary["ColorSpace"], "")]
+	}
+	if components == 0 || len(obj.Stream) < width*height*components {
+		return nil, false
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	samples := obj.Stream
+	for i := 0; i < width*height; i++ {
+		s := samples[i*components : (i+1)*components]
+		var c color.NRGBA
+		switch {
+		case palette != nil:
+			// Indexes beyond the palette are black
+			if idx := int(s[0]) * 3; idx+3 <= len(palette) {
+				c = color.NRGBA{palette[idx], palette[idx+1], palette[idx+2], 255}
+			} else {
+				c = color.NRGBA{0, 0, 0, 255}
+			}
+		case components == 1:
+			c = color.NRGBA{s[0], s[0], s[0], 255}
+		case components == 3:
+			c = color.NRGBA{s[0], s[1], s[2], 255}
+		case components == 4:
+			r, g, b := color.CMYKToRGB(s[0], s[1], s[2], s[3])
+			c = color.NRGBA{r, g, b, 255}
+		}
+		img.SetNRGBA(i%width, i/width, c)
+	}
+	return img, true
+}
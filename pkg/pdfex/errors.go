@@ -0,0 +1,71 @@
+package pdfex
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// ErrorKind classifies why a document couldn't be processed, for scripts and monitoring
+type ErrorKind string
+
+// Error kinds
+const (
+	ErrorKindNotPDF    ErrorKind = "not-pdf"   // The file has no %PDF- header
+	ErrorKindEncrypted ErrorKind = "encrypted" // The document is encrypted, so its text can't be read
+	ErrorKindIO        ErrorKind = "io"        // A file couldn't be read or written
+	ErrorKindLimit     ErrorKind = "limit"     // The document exceeds a resource limit of the parse options
+	ErrorKindAborted   ErrorKind = "aborted"   // A hook of the parse options aborted the parse
+	ErrorKindCorrupt   ErrorKind = "corrupt"   // The document is damaged beyond recovery, or any other failure
+)
+
+var (
+	// ErrNotPDF is returned when parsing a file without a %PDF- header
+	ErrNotPDF = document.ErrNotPDF
+	// ErrEncrypted is returned by ProcessBatch for encrypted documents; see Encrypted
+	ErrEncrypted = errors.New("document is encrypted")
+	// ErrWarnings is returned when ParseOptions.TreatWarningsAsErrors is set and the parse
+	// found problems; see PDFDocument.Warnings
+	ErrWarnings = errors.New("warnings treated as errors")
+	// ErrNonConformant is returned when ParseOptions.StrictMode is set and the document breaks
+	// the specification in a way the parse would otherwise recover from
+	ErrNonConformant = errors.New("document does not conform to the PDF specification")
+	// ErrAborted is returned when a hook of ParseOptions.Hooks aborts the parse; the error also
+	// wraps the one returned by the hook
+	ErrAborted = document.ErrAborted
+)
+
+// LimitError is returned when a document exceeds a resource limit of the parse options; Limit
+// names the option, e.g. "MaxDecompressedStreamSize"
+type LimitError = content.LimitError
+
+// KindOf classifies an error returned while parsing or processing a document. It returns an
+// empty kind for a nil error.
+func KindOf(err error) ErrorKind {
+	var pathErr *fs.PathError
+	var limitErr *LimitError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrAborted):
+		return ErrorKindAborted
+	case errors.Is(err, ErrNotPDF):
+		return ErrorKindNotPDF
+	case errors.Is(err, ErrEncrypted):
+		return ErrorKindEncrypted
+	case errors.As(err, &pathErr):
+		return ErrorKindIO
+	case errors.As(err, &limitErr):
+		return ErrorKindLimit
+	}
+	return ErrorKindCorrupt
+}
+
+// Encrypted reports whether the document is encrypted. Encrypted documents parse, but their
+// strings and streams can't be decoded, so the extracted text is meaningless.
+func (p *PDFDocument) Encrypted() bool {
+	_, ok := p.doc.Trailer["Encrypt"]
+	return ok
+}
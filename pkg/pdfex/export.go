@@ -0,0 +1,97 @@
+package pdfex
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ExportVersion identifies the layout of structured document exports
+const ExportVersion = 1
+
+// DocumentExport is a structured dump of a document: its metadata and, for each page, the
+// dimensions, text blocks, positioned spans, images, links and annotations. Rectangles are on
+// the unrotated page like any Rect, while span coordinates are on the upright page like those
+// of GetPageTextPositions.
+type DocumentExport struct {
+	ExportVersion int          `json:"export_version"`
+	Generator     string       `json:"generator"` // pdfex library version
+	Source        string       `json:"source"`
+	PDFVersion    string       `json:"pdf_version"`
+	PageCount     int          `json:"page_count"`
+	Metadata      *Metadata    `json:"metadata"`
+	Fonts         []Font       `json:"fonts"`
+	Pages         []PageExport `json:"pages"`
+}
+
+// PageExport is the content of a page in a structured export
+type PageExport struct {
+	Page
+	Text        string           `json:"text"`
+	Blocks      []Block          `json:"blocks"`
+	Spans       []TextSpan       `json:"spans"`
+	Images      []ImagePlacement `json:"images"`
+	Links       []Link           `json:"links"`
+	Annotations []Annotation     `json:"annotations"`
+}
+
+// StructuredExport extracts the text of every page and collects the structured dump of the
+// document. Only images the pages draw directly are listed. With a PageRange, only the selected
+// pages are included.
+func (p *PDFDocument) StructuredExport() *DocumentExport {
+	texts := p.ExtractPageTexts()
+
+	export := &DocumentExport{
+		ExportVersion: ExportVersion,
+		Generator:     "pdfex " + Version(),
+		Source:        p.source,
+		PDFVersion:    p.Version(),
+		PageCount:     p.PageCount(),
+		Metadata:      p.Metadata(),
+		Fonts:         p.Fonts(),
+		Pages:         make([]PageExport, 0, len(p.doc.Pages)),
+	}
+
+	links := make(map[int][]Link)
+	for _, link := range p.GetLinks() {
+		links[link.Page] = append(links[link.Page], link)
+	}
+	annotations := make(map[int][]Annotation)
+	for _, annot := range p.GetAnnotations() {
+		annotations[annot.Page] = append(annotations[annot.Page], annot)
+	}
+
+	for i := range p.doc.Pages {
+		page := &p.doc.Pages[i]
+		if !p.doc.PageSelected(page.PageNumber) {
+			continue
+		}
+		entry := PageExport{
+			Page:        newPage(*page, p.generationOf(page.ObjectNumber)),
+			Text:        texts[i],
+			Blocks:      []Block{},
+			Spans:       []TextSpan{},
+			Images:      []ImagePlacement{},
+			Links:       append([]Link{}, links[page.PageNumber]...),
+			Annotations: append([]Annotation{}, annotations[page.PageNumber]...),
+		}
+
+		entry.Blocks = append(entry.Blocks, pageBlocks(page)...)
+		for _, pos := range page.TextPositions {
+			entry.Spans = append(entry.Spans, newTextSpan(pos))
+		}
+		for _, drawn := range p.drawnImages(page) {
+			entry.Images = append(entry.Images, newImagePlacement(page, drawn))
+		}
+
+		export.Pages = append(export.Pages, entry)
+	}
+
+	return export
+}
+
+// ExportJSON writes the structured dump of the document as indented JSON
+func (p *PDFDocument) ExportJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p.StructuredExport())
+}
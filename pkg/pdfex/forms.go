@@ -0,0 +1,82 @@
+package pdfex
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// FormField is a field of an interactive (AcroForm) form with its current value
+type FormField struct {
+	Name     string        `json:"name"`              // Fully qualified name, e.g. "address.city"
+	Type     string        `json:"type"`              // text, checkbox, radio, pushbutton, choice, signature or unknown
+	Value    string        `json:"value"`             // Export value of the selected check box or radio button, "Off" if cleared
+	Default  string        `json:"default,omitempty"` // Value the field resets to
+	Options  []string      `json:"options,omitempty"` // Choices of a choice field
+	Flags    int           `json:"flags"`             // Field flags (/Ff)
+	ReadOnly bool          `json:"read_only"`
+	Required bool          `json:"required"`
+	Widgets  []FieldWidget `json:"widgets"`
+}
+
+// FieldWidget is the place of a field on a page
+type FieldWidget struct {
+	Page int  `json:"page"` // 1-based page number, 0 if it could not be resolved
+	Rect Rect `json:"rect"` // Area on the unrotated page
+}
+
+// GetFormFields returns the fields of the document's interactive form, or nil if it has none.
+// Radio button groups are one field with a widget per button. XFA forms are not read.
+func (p *PDFDocument) GetFormFields() []FormField {
+	items := p.doc.GetFormFields()
+	if len(items) == 0 {
+		return nil
+	}
+
+	fields := make([]FormField, 0, len(items))
+	for _, item := range items {
+		field := FormField{
+			Name:     item.Name,
+			Type:     item.Type,
+			Value:    item.Value,
+			Default:  item.Default,
+			Options:  item.Options,
+			Flags:    item.Flags,
+			ReadOnly: item.Flags&document.FieldFlagReadOnly != 0,
+			Required: item.Flags&document.FieldFlagRequired != 0,
+			Widgets:  make([]FieldWidget, 0, len(item.Widgets)),
+		}
+		for _, w := range item.Widgets {
+			field.Widgets = append(field.Widgets, FieldWidget{
+				Page: w.PageNumber,
+				Rect: Rect{X1: w.Rect[0], Y1: w.Rect[1], X2: w.Rect[2], Y2: w.Rect[3]},
+			})
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// FormValues returns the values of the form fields keyed by fully qualified name, the filled-in
+// data of the form without its layout. Pushbuttons, which hold no value, are left out.
+func (p *PDFDocument) FormValues() map[string]string {
+	values := make(map[string]string)
+	for _, field := range p.GetFormFields() {
+		if field.Type != "pushbutton" {
+			values[field.Name] = field.Value
+		}
+	}
+	return values
+}
+
+// WriteFormFields writes the form fields as indented JSON
+func (p *PDFDocument) WriteFormFields(w io.Writer) error {
+	fields := p.GetFormFields()
+	if fields == nil {
+		fields = []FormField{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(fields)
+}
@@ -0,0 +1,46 @@
+package pdfex
+
+import "github.com/yourusername/pdfex/internal/text"
+
+// Reasons text is hidden from a reader
+const (
+	HiddenCovered        = text.HiddenCovered        // An opaque rectangle is painted over it, as in a redaction that left the text behind
+	HiddenInvisible      = text.HiddenInvisible      // Drawn with text rendering mode 3 or 7, which paints nothing
+	HiddenSameColor      = text.HiddenSameColor      // Filled in the colour of what is behind it, such as white on white
+	HiddenOutsideCropBox = text.HiddenOutsideCropBox // Placed outside the visible region of the page
+)
+
+// HiddenText is a run of text that a reader of the rendered page can't see, though extraction
+// and copying still reach it. Bounds are on the unrotated page, in points like any Rect.
+type HiddenText struct {
+	Page   int    `json:"page"`   // 1-based page number
+	Reason string `json:"reason"` // One of the Hidden constants
+	Text   string `json:"text"`
+	Bounds Rect   `json:"bounds"`
+}
+
+// FindHiddenText finds the text of the selected pages that a reader can't see, to catch failed
+// redactions before a document is published: text under filled rectangles painted after it,
+// text drawn with the invisible rendering mode, text in the colour of the rectangle or blank
+// page behind it, and text outside the crop box. Transparency, clipping paths and images are
+// not taken into account, so findings are suspicions to review rather than proof.
+func (p *PDFDocument) FindHiddenText() []HiddenText {
+	extractor := text.NewDocumentExtractor(p.doc, p.textOptions)
+	defer p.resetExtraction()
+
+	found := []HiddenText{}
+	for pageNum := 1; pageNum <= len(p.doc.Pages); pageNum++ {
+		if !p.doc.PageSelected(pageNum) {
+			continue
+		}
+		for _, run := range extractor.FindHiddenText(pageNum) {
+			found = append(found, HiddenText{
+				Page:   pageNum,
+				Reason: run.Reason,
+				Text:   run.Text,
+				Bounds: Rect{X1: run.Bounds.MinX, Y1: run.Bounds.MinY, X2: run.Bounds.MaxX, Y2: run.Bounds.MaxY},
+			})
+		}
+	}
+	return found
+}
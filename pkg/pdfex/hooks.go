@@ -0,0 +1,50 @@
+package pdfex
+
+import "github.com/yourusername/pdfex/internal/document"
+
+// Hooks observes a parse as it happens, so that an application can react to a document
+// without a second pass over it, for example to abort on suspicious content or to build an
+// index. Set ParseOptions.Hooks to use them. An error returned by a hook aborts the parse with
+// an error wrapping both ErrAborted and the hook's error. Embed NoHooks to implement only some
+// of the methods.
+type Hooks interface {
+	// OnObjectLoaded is called for each object read through the xref table, in no particular
+	// order, before its stream is decoded
+	OnObjectLoaded(obj ObjectInfo) error
+	// OnStreamDecoded is called with the decoded data of each stream that has filters
+	OnStreamDecoded(obj ObjectInfo, data []byte) error
+	// OnPageProcessed is called for each selected page, in page order, once its content
+	// streams, boxes and form XObjects are loaded
+	OnPageProcessed(page Page) error
+	// OnWarning is called for each problem the parse recovers from, as it is found
+	OnWarning(warning Warning)
+}
+
+// NoHooks implements Hooks with methods that do nothing
+type NoHooks struct{}
+
+func (NoHooks) OnObjectLoaded(obj ObjectInfo) error               { return nil }
+func (NoHooks) OnStreamDecoded(obj ObjectInfo, data []byte) error { return nil }
+func (NoHooks) OnPageProcessed(page Page) error                   { return nil }
+func (NoHooks) OnWarning(warning Warning)                         {}
+
+// documentHooks passes the events of the internal parse to public hooks
+type documentHooks struct {
+	hooks Hooks
+}
+
+func (h documentHooks) OnObjectLoaded(doc *document.PDFDocument, obj document.PDFObject) error {
+	return h.hooks.OnObjectLoaded(newObjectInfo(doc, obj.ObjectNumber, obj))
+}
+
+func (h documentHooks) OnStreamDecoded(doc *document.PDFDocument, obj document.PDFObject) error {
+	return h.hooks.OnStreamDecoded(newObjectInfo(doc, obj.ObjectNumber, obj), obj.Stream)
+}
+
+func (h documentHooks) OnPageProcessed(doc *document.PDFDocument, page *document.PDFPage) error {
+	return h.hooks.OnPageProcessed(newPage(*page, doc.Objects[page.ObjectNumber].Generation))
+}
+
+func (h documentHooks) OnWarning(doc *document.PDFDocument, warning document.Warning) {
+	h.hooks.OnWarning(newWarning(warning))
+}
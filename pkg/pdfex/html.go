@@ -0,0 +1,151 @@
+package pdfex
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// HTMLOptions control how a document is exported as HTML
+type HTMLOptions struct {
+	Positioned bool   // Place text spans and images where they are on the page instead of flowing them
+	Title      string // Document title; defaults to the metadata title, then the source file name
+	OmitImages bool   // Leave out images, which are otherwise embedded as data URIs
+}
+
+// htmlStyle is the style sheet of exported documents
+const htmlStyle = `body { font-family: sans-serif; margin: 2em; }
+.page { margin-bottom: 2em; }
+.page.positioned { position: relative; overflow: hidden; border: 1px solid #ccc; }
+.page.positioned span, .page.positioned img { position: absolute; white-space: pre; line-height: 1; }
+table { border-collapse: collapse; }
+td { border: 1px solid #ccc; padding: 0.2em 0.4em; vertical-align: top; }
+img { max-width: 100%; }
+`
+
+// ExportHTML writes the document as a standalone HTML page with a div per page. By default the
+// text flows as headings, paragraphs, lists and tables in reading order, with images placed
+// between them; with Positioned set, each text span and image is placed absolutely as on the
+// page. Images are embedded as data URIs when they are JPEG data or plain 8-bit samples.
+func (p *PDFDocument) ExportHTML(w io.Writer, options *HTMLOptions) error {
+	if options == nil {
+		options = &HTMLOptions{}
+	}
+
+	p.ensureExtracted()
+	var headings []text.Heading
+	if !options.Positioned {
+		headings = text.DetectHeadings(p.doc.Pages, p.doc.Fonts)
+	}
+
+	title := options.Title
+	if title == "" {
+		title = p.Metadata().Title
+	}
+	if title == "" {
+		title = filepath.Base(p.source)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n%s</style>\n</head>\n<body>\n",
+		html.EscapeString(title), htmlStyle)
+
+	for i := range p.doc.Pages {
+		page := &p.doc.Pages[i]
+		if !p.doc.PageSelected(page.PageNumber) {
+			continue
+		}
+		if options.Positioned {
+			p.writePositionedPage(bw, page, !options.OmitImages)
+		} else {
+			writeFlowedPage(bw, page, p.pageElements(page, headings, !options.OmitImages))
+		}
+	}
+
+	bw.WriteString("</body>\n</html>\n")
+	return bw.Flush()
+}
+
+// writeFlowedPage writes the elements of a page as HTML in reading order
+func writeFlowedPage(w *bufio.Writer, page *document.PDFPage, elements []pageElement) {
+	fmt.Fprintf(w, "<div class=\"page\" id=\"page-%d\">\n", page.PageNumber)
+	for _, element := range elements {
+		switch element.kind {
+		case elementHeading:
+			level := element.level
+			if level > 6 {
+				level = 6
+			}
+			fmt.Fprintf(w, "<h%d>%s</h%d>\n", level, html.EscapeString(element.text), level)
+		case elementParagraph:
+			fmt.Fprintf(w, "<p>%s</p>\n", html.EscapeString(element.text))
+		case elementList:
+			tag := "ul"
+			if element.ordered {
+				tag = "ol"
+			}
+			fmt.Fprintf(w, "<%s>\n", tag)
+			for _, item := range element.items {
+				fmt.Fprintf(w, "<li>%s</li>\n", html.EscapeString(item))
+			}
+			fmt.Fprintf(w, "</%s>\n", tag)
+		case elementTable:
+			w.WriteString("<table>\n")
+			for _, row := range element.table.Rows {
+				w.WriteString("<tr>")
+				for _, cell := range row {
+					fmt.Fprintf(w, "<td>%s</td>", html.EscapeString(cell.Text))
+				}
+				w.WriteString("</tr>\n")
+			}
+			w.WriteString("</table>\n")
+		case elementImage:
+			fmt.Fprintf(w, "<img src=\"%s\" alt=\"\" style=\"width: %spt\">\n",
+				imageDataURI(element.image), formatNumbers(element.bounds.MaxX-element.bounds.MinX))
+		}
+	}
+	w.WriteString("</div>\n")
+}
+
+// writePositionedPage writes a page as HTML with its text spans and images placed absolutely,
+// in points from the top left of the upright page
+func (p *PDFDocument) writePositionedPage(w *bufio.Writer, page *document.PDFPage, includeImages bool) {
+	width, height := page.Width, page.Height
+	if page.TextRotation == 90 || page.TextRotation == 270 {
+		width, height = height, width
+	}
+	fmt.Fprintf(w, "<div class=\"page positioned\" id=\"page-%d\" style=\"width: %spt; height: %spt\">\n",
+		page.PageNumber, formatNumbers(width), formatNumbers(height))
+
+	if includeImages {
+		for _, element := range p.pageImages(page) {
+			b := element.bounds
+			fmt.Fprintf(w, "<img src=\"%s\" alt=\"\" style=\"left: %spt; top: %spt; width: %spt; height: %spt\">\n",
+				imageDataURI(element.image), formatNumbers(b.MinX), formatNumbers(height-b.MaxY),
+				formatNumbers(b.MaxX-b.MinX), formatNumbers(b.MaxY-b.MinY))
+		}
+	}
+
+	for _, pos := range page.TextPositions {
+		if strings.TrimSpace(pos.Text) == "" || pos.Vertical {
+			continue
+		}
+		// Spans are placed by their top edge, one font size above the baseline
+		fmt.Fprintf(w, "<span style=\"left: %spt; top: %spt; font-size: %spt\">%s</span>\n",
+			formatNumbers(pos.X), formatNumbers(height-pos.Y-pos.FontSize), formatNumbers(pos.FontSize),
+			html.EscapeString(pos.Text))
+	}
+	w.WriteString("</div>\n")
+}
+
+// imageDataURI returns an image as a data URI
+func imageDataURI(img *pageImage) string {
+	return "data:" + img.mime + ";base64," + base64.StdEncoding.EncodeToString(img.data)
+}
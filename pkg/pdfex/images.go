@@ -0,0 +1,70 @@
+package pdfex
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// ImagePlacement is an image XObject drawn on a page
+type ImagePlacement struct {
+	Page             int    `json:"page"`   // 1-based page the image is drawn on
+	Name             string `json:"name"`   // Resource name on the page
+	Object           int    `json:"object"` // Object number
+	Bounds           Rect   `json:"bounds"` // Area the image is drawn into
+	Width            int    `json:"width"`  // In samples
+	Height           int    `json:"height"` // In samples
+	ColorSpace       string `json:"color_space,omitempty"`
+	BitsPerComponent int    `json:"bits_per_component,omitempty"`
+	Filter           string `json:"filter,omitempty"`
+}
+
+// GetImages returns the images the selected pages draw directly, in page and drawing order.
+// With ExcludeHiddenLayers, images on hidden layers are left out.
+func (p *PDFDocument) GetImages() []ImagePlacement {
+	p.ensureExtracted()
+
+	var images []ImagePlacement
+	for i := range p.doc.Pages {
+		page := &p.doc.Pages[i]
+		if !p.doc.PageSelected(page.PageNumber) {
+			continue
+		}
+		for _, drawn := range p.drawnImages(page) {
+			images = append(images, newImagePlacement(page, drawn))
+		}
+	}
+	return images
+}
+
+// ImageData returns the data of an image in a format image viewers display, with its MIME type:
+// JPEG data as stored, or 8-bit grey, RGB and CMYK samples encoded as PNG
+func (p *PDFDocument) ImageData(image ImagePlacement) (string, []byte, error) {
+	obj, ok := p.doc.GetObject(image.Object)
+	if !ok || obj.Dictionary["Subtype"] != "/Image" {
+		return "", nil, fmt.Errorf("object %d is not an image", image.Object)
+	}
+	mime, data, ok := encodeImage(obj)
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported encoding of image %s on page %d", image.Name, image.Page)
+	}
+	return mime, data, nil
+}
+
+// newImagePlacement converts an image drawn by a page into its public representation
+func newImagePlacement(page *document.PDFPage, drawn drawnImage) ImagePlacement {
+	dict := drawn.obj.Dictionary
+	return ImagePlacement{
+		Page:             page.PageNumber,
+		Name:             drawn.name,
+		Object:           drawn.obj.ObjectNumber,
+		Bounds:           userSpaceRect(drawn.bounds, page),
+		Width:            utils.GetInteger(dict["Width"], 0),
+		Height:           utils.GetInteger(dict["Height"], 0),
+		ColorSpace:       strings.TrimPrefix(utils.GetString(dict["ColorSpace"], ""), "/"),
+		BitsPerComponent: utils.GetInteger(dict["BitsPerComponent"], 0),
+		Filter:           strings.TrimPrefix(utils.GetString(dict["Filter"], ""), "/"),
+	}
+}
@@ -0,0 +1,26 @@
+package pdfex
+
+import (
+	"os"
+	"testing"
+)
+
+func TestGetPDFInfoFromReader(t *testing.T) {
+	want, err := GetPDFInfo(scannedFixture)
+	if err != nil {
+		t.Fatalf("GetPDFInfo: %v", err)
+	}
+	file, err := os.Open(scannedFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	got, err := GetPDFInfoFromReader(file, "stdin")
+	if err != nil {
+		t.Fatalf("GetPDFInfoFromReader: %v", err)
+	}
+	if got.PageCount != 2 || got.PageCount != want.PageCount || got.Version != want.Version || got.FileSize != want.FileSize {
+		t.Errorf("got %d pages of version %s in %d bytes, want %d of %s in %d",
+			got.PageCount, got.Version, got.FileSize, want.PageCount, want.Version, want.FileSize)
+	}
+}
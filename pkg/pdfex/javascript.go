@@ -0,0 +1,20 @@
+package pdfex
+
+// Script is a piece of JavaScript the document runs, with the object holding its source
+type Script struct {
+	Name         string `json:"name"`              // Name in the /JavaScript name tree, or the holder and trigger such as "field total /C"
+	Trigger      string `json:"trigger,omitempty"` // OpenAction, A, or an /AA key such as K or WC; empty for document-level scripts
+	ObjectNumber int    `json:"object_number"`     // The /JS stream, else the action, else the object the action is attached to
+	Source       string `json:"source"`
+}
+
+// JavaScripts extracts the JavaScript of the document for review: the document-level scripts
+// of the /JavaScript name tree, then those run by the open action and by the /A and /AA
+// triggers of pages, form fields and annotations. Metrics().JavaScriptCount counts them.
+func (p *PDFDocument) JavaScripts() []Script {
+	scripts := []Script{}
+	for _, script := range p.doc.JavaScripts() {
+		scripts = append(scripts, Script{Name: script.Name, Trigger: script.Trigger, ObjectNumber: script.ObjectNumber, Source: script.Source})
+	}
+	return scripts
+}
@@ -0,0 +1,17 @@
+package pdfex
+
+// Layer is an optional content group, shown as a layer in viewers
+type Layer struct {
+	Name    string `json:"name"`
+	Visible bool   `json:"visible"` // Visible when the document is opened
+}
+
+// Layers returns the optional content groups (layers) of the document, or nil if it has none.
+// Set ParseOptions.ExcludeHiddenLayers to leave the text of hidden layers out of extraction.
+func (p *PDFDocument) Layers() []Layer {
+	var layers []Layer
+	for _, layer := range p.doc.GetLayers() {
+		layers = append(layers, Layer{Name: layer.Name, Visible: layer.Visible})
+	}
+	return layers
+}
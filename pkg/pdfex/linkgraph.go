@@ -0,0 +1,103 @@
+package pdfex
+
+// Kinds of link graph edges
+const (
+	EdgeLink    = "link"    // A link annotation on a page
+	EdgeOutline = "outline" // A bookmark of the document outline
+)
+
+// LinkGraph is the graph of references within a document: links between pages and bookmarks
+// into pages. Web links and links to other files are not part of it.
+type LinkGraph struct {
+	PageCount int        `json:"page_count"`
+	Edges     []LinkEdge `json:"edges"`
+}
+
+// LinkEdge is a reference to a place in the document. Edges whose target could not be resolved,
+// such as named destinations that don't exist, have To set to 0.
+type LinkEdge struct {
+	Kind        string      `json:"kind"`           // EdgeLink or EdgeOutline
+	From        int         `json:"from"`           // Page of the link, 0 for bookmarks
+	To          int         `json:"to"`             // Target page, 0 if unresolved
+	Text        string      `json:"text"`           // Anchor text of a link, or title of a bookmark
+	Rect        *Rect       `json:"rect,omitempty"` // Clickable area of a link
+	Destination Destination `json:"destination"`
+}
+
+// NamedDestinations returns the named destinations of the document, from the catalog's /Dests
+// dictionary and the /Dests name tree, resolved to pages and views
+func (p *PDFDocument) NamedDestinations() map[string]Destination {
+	named := make(map[string]Destination)
+	for name, dest := range p.doc.NamedDestinations() {
+		if d := newDestination(dest); d != nil {
+			named[name] = *d
+		}
+	}
+	return named
+}
+
+// LinkGraph builds the graph of links and bookmarks within the document, with links in page
+// order followed by bookmarks in outline order
+func (p *PDFDocument) LinkGraph() *LinkGraph {
+	graph := &LinkGraph{PageCount: p.PageCount(), Edges: []LinkEdge{}}
+
+	for _, link := range p.GetLinks() {
+		if link.Destination == nil {
+			continue
+		}
+		rect := link.Rect
+		graph.Edges = append(graph.Edges, LinkEdge{
+			Kind:        EdgeLink,
+			From:        link.Page,
+			To:          link.Destination.Page,
+			Text:        link.Text,
+			Rect:        &rect,
+			Destination: *link.Destination,
+		})
+	}
+
+	var addOutline func(entries []OutlineEntry)
+	addOutline = func(entries []OutlineEntry) {
+		for _, entry := range entries {
+			if entry.Destination != nil && entry.Destination.URI == "" {
+				graph.Edges = append(graph.Edges, LinkEdge{
+					Kind:        EdgeOutline,
+					To:          entry.Destination.Page,
+					Text:        entry.Title,
+					Destination: *entry.Destination,
+				})
+			}
+			addOutline(entry.Children)
+		}
+	}
+	addOutline(p.GetOutline())
+
+	return graph
+}
+
+// Unresolved returns the edges whose target page could not be resolved
+func (g *LinkGraph) Unresolved() []LinkEdge {
+	var edges []LinkEdge
+	for _, edge := range g.Edges {
+		if edge.To == 0 {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// Unreferenced returns the pages, in ascending order, that no link or bookmark leads to
+func (g *LinkGraph) Unreferenced() []int {
+	referenced := make(map[int]bool)
+	for _, edge := range g.Edges {
+		referenced[edge.To] = true
+	}
+
+	var pages []int
+	for page := 1; page <= g.PageCount; page++ {
+		if !referenced[page] {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
@@ -0,0 +1,54 @@
+package pdfex
+
+import (
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// Link is a hyperlink on a page: the clickable area, its target and the text it covers
+type Link struct {
+	Page        int          `json:"page"`                  // 1-based page the link is on
+	URI         string       `json:"uri,omitempty"`         // Target of web links
+	Destination *Destination `json:"destination,omitempty"` // Target of links within the document
+	Rect        Rect         `json:"rect"`                  // Clickable area on the unrotated page
+	Text        string       `json:"text"`                  // Anchor text under the rectangle
+}
+
+// GetLinks returns the Link annotations of the document in page order, with their URI or
+// in-document destination and the text under each link's rectangle. Links to other files and
+// links that launch applications have neither a URI nor a destination.
+func (p *PDFDocument) GetLinks() []Link {
+	annotations := p.doc.GetLinks()
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	p.ensureExtracted()
+
+	links := make([]Link, 0, len(annotations))
+	for _, annot := range annotations {
+		page := &p.doc.Pages[annot.PageNumber-1]
+		link := Link{
+			Page: annot.PageNumber,
+			URI:  annot.Destination.URI,
+			Rect: Rect{X1: annot.Rect[0], Y1: annot.Rect[1], X2: annot.Rect[2], Y2: annot.Rect[3]},
+		}
+		if link.URI == "" {
+			link.Destination = newDestination(annot.Destination)
+		}
+
+		// Text positions are upright, so map the rectangle into the same space
+		x1, y1 := text.UprightPoint(annot.Rect[0], annot.Rect[1], page.TextRotation, page.Width, page.Height)
+		x2, y2 := text.UprightPoint(annot.Rect[2], annot.Rect[3], page.TextRotation, page.Width, page.Height)
+		box := text.Box{MinX: x1, MinY: y1, MaxX: x2, MaxY: y2}
+		if box.MinX > box.MaxX {
+			box.MinX, box.MaxX = box.MaxX, box.MinX
+		}
+		if box.MinY > box.MaxY {
+			box.MinY, box.MaxY = box.MaxY, box.MinY
+		}
+		link.Text = text.TextInBox(page.TextPositions, box)
+
+		links = append(links, link)
+	}
+	return links
+}
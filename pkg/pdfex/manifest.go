@@ -0,0 +1,231 @@
+package pdfex
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// ManifestVersion identifies the layout of checksum manifests
+const ManifestVersion = 1
+
+// Manifest records checksums of a document and of each of its pages, so that stored extraction
+// artifacts can later be verified against the exact source they were produced from. All lists
+// are in a fixed order and the manifest holds no timestamps, so the same document and settings
+// always produce byte-identical JSON that can be signed.
+type Manifest struct {
+	ManifestVersion int                `json:"manifest_version"`
+	Generator       string             `json:"generator"` // pdfex library version
+	Source          ManifestSource     `json:"source"`
+	Settings        ExtractionSettings `json:"settings"`
+	Fonts           []string           `json:"fonts"` // Base font names used on any page, sorted
+	Pages           []PageManifest     `json:"pages"`
+}
+
+// ManifestSource identifies the source document
+type ManifestSource struct {
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	SHA256     string `json:"sha256"`
+	PDFVersion string `json:"pdf_version"`
+	PageCount  int    `json:"page_count"`
+}
+
+// ExtractionSettings are the parse options that affect extracted text
+type ExtractionSettings struct {
+	Normalization string `json:"normalization"` // none, ligatures or compatibility
+	Columns       string `json:"columns"`       // off, auto or a forced column count
+
+	ExcludeHiddenLayers bool   `json:"exclude_hidden_layers,omitempty"`
+	PageRange           string `json:"page_range,omitempty"` // Pages the manifest covers, if not all
+	IncludeAnnotations  bool   `json:"include_annotations,omitempty"`
+	Newlines            string `json:"newlines,omitempty"` // paragraphs or spaces, if line breaks aren't preserved
+	CollapseSpaces      bool   `json:"collapse_spaces,omitempty"`
+	StripControlChars   bool   `json:"strip_control_chars,omitempty"`
+}
+
+// PageManifest records the checksums of a page. ContentSHA256 covers the decoded content
+// streams, so it doesn't change when a file is recompressed; TextSHA256 covers the text
+// extracted with the manifest's settings.
+type PageManifest struct {
+	Page          int           `json:"page"` // 1-based page number
+	Width         float64       `json:"width"`
+	Height        float64       `json:"height"`
+	Rotation      int           `json:"rotation"`
+	ContentSHA256 string        `json:"content_sha256"`
+	TextSHA256    string        `json:"text_sha256"`
+	Images        []ImageDigest `json:"images"`
+	Fonts         []string      `json:"fonts"` // Base font names in the page resources, sorted
+}
+
+// ImageDigest is the checksum of an image XObject drawn on a page
+type ImageDigest struct {
+	Name   string `json:"name"`   // Resource name on the page
+	Object int    `json:"object"` // Object number
+	SHA256 string `json:"sha256"` // Hash of the image stream data as stored in the file
+}
+
+// Manifest builds the checksum manifest of the document, extracting the text of every page.
+// Only images listed in the page resources are included; images inside form XObjects and
+// inline images are covered by the content hash of the page. With ExcludeHiddenLayers, images
+// on hidden layers are left out. With a PageRange, only the selected pages are listed.
+func (p *PDFDocument) Manifest() *Manifest {
+	texts := p.ExtractPageTexts()
+
+	manifest := &Manifest{
+		ManifestVersion: ManifestVersion,
+		Generator:       "pdfex " + Version(),
+		Source: ManifestSource{
+			Name:       p.source,
+			Size:       p.sourceSize,
+			SHA256:     p.sourceSHA256,
+			PDFVersion: p.Version(),
+			PageCount:  p.PageCount(),
+		},
+		Settings: ExtractionSettings{
+			Normalization: normalizationName(p.textOptions.Normalization),
+			Columns:       columnsName(p.textOptions.Columns),
+
+			ExcludeHiddenLayers: p.textOptions.ExcludeHiddenLayers,
+			PageRange:           p.doc.PageRange().String(),
+			IncludeAnnotations:  p.textOptions.IncludeAnnotations,
+			Newlines:            newlinesName(p.textOptions.Whitespace.Newlines),
+			CollapseSpaces:      p.textOptions.Whitespace.CollapseSpaces,
+			StripControlChars:   p.textOptions.Whitespace.StripControlChars,
+		},
+		Fonts: []string{},
+		Pages: make([]PageManifest, 0, len(p.doc.Pages)),
+	}
+
+	documentFonts := make(map[string]bool)
+	for i, page := range p.doc.Pages {
+		if !p.doc.PageSelected(page.PageNumber) {
+			continue
+		}
+		entry := PageManifest{
+			Page:          page.PageNumber,
+			Width:         page.Width,
+			Height:        page.Height,
+			Rotation:      page.Rotation,
+			ContentSHA256: sha256Hex(page.Contents),
+			TextSHA256:    sha256Hex([]byte(texts[i])),
+			Images:        []ImageDigest{},
+			Fonts:         []string{},
+		}
+
+		var hidden map[string]bool
+		if p.textOptions.ExcludeHiddenLayers {
+			hidden = text.HiddenXObjects(page)
+		}
+
+		xobjects := p.doc.PageResources(page, "XObject")
+		names := make([]string, 0, len(xobjects))
+		for name := range xobjects {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			obj, ok := p.doc.GetObject(xobjects[name])
+			if !ok || obj.Dictionary["Subtype"] != "/Image" {
+				continue
+			}
+			if hidden[name] || p.textOptions.ExcludeHiddenLayers && p.doc.XObjectHidden(obj.ObjectNumber) {
+				continue
+			}
+			entry.Images = append(entry.Images, ImageDigest{Name: name, Object: obj.ObjectNumber, SHA256: sha256Hex(obj.Stream)})
+		}
+
+		pageFonts := make(map[string]bool)
+		for _, objNum := range p.doc.PageResources(page, "Font") {
+			obj, ok := p.doc.GetObject(objNum)
+			if !ok {
+				continue
+			}
+			baseFont, _ := obj.Dictionary["BaseFont"].(string)
+			if baseFont = strings.TrimPrefix(baseFont, "/"); baseFont != "" {
+				pageFonts[baseFont] = true
+				documentFonts[baseFont] = true
+			}
+		}
+		entry.Fonts = append(entry.Fonts, sortedKeys(pageFonts)...)
+
+		manifest.Pages = append(manifest.Pages, entry)
+	}
+	manifest.Fonts = append(manifest.Fonts, sortedKeys(documentFonts)...)
+
+	return manifest
+}
+
+// WriteManifest writes the document's checksum manifest as indented JSON
+func (p *PDFDocument) WriteManifest(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p.Manifest())
+}
+
+// hashFile returns the hex-encoded SHA-256 of a file's contents and the file's size
+func hashFile(filename string) (string, int64, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// normalizationName returns the manifest name of a normalization mode
+func normalizationName(mode NormalizationMode) string {
+	switch mode {
+	case text.NormalizeLigatures:
+		return "ligatures"
+	case text.NormalizeCompatibility:
+		return "compatibility"
+	}
+	return "none"
+}
+
+// newlinesName returns the manifest name of a newline policy, empty when line breaks are preserved
+func newlinesName(policy NewlinePolicy) string {
+	switch policy {
+	case NewlinesParagraphs:
+		return "paragraphs"
+	case NewlinesSpaces:
+		return "spaces"
+	}
+	return ""
+}
+
+// columnsName returns the manifest name of a column layout
+func columnsName(columns int) string {
+	switch columns {
+	case ColumnsOff:
+		return "off"
+	case ColumnsAuto:
+		return "auto"
+	}
+	return strconv.Itoa(columns)
+}
+
+// sortedKeys returns the members of a set in sorted order
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,136 @@
+package pdfex
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// markdownEscaper escapes the characters that Markdown would read as inline formatting
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `<`, `\<`, `>`, `\>`, `|`, `\|`,
+)
+
+// markdownBlockPattern matches text at the start of a line that Markdown would read as a
+// heading, list item, quote or rule
+var markdownBlockPattern = regexp.MustCompile(`^(?:[#+=-]|\d+([.)]))`)
+
+// ExportMarkdown converts the document to GitHub-flavored Markdown: detected headings,
+// paragraphs, bulleted and numbered lists and tables in reading order, with web links on the
+// text they cover. The first row of each table is used as its header. Images are left out.
+func (p *PDFDocument) ExportMarkdown() (string, error) {
+	p.ensureExtracted()
+	headings := text.DetectHeadings(p.doc.Pages, p.doc.Fonts)
+
+	pageLinks := make(map[int][]Link)
+	for _, link := range p.GetLinks() {
+		if link.URI != "" && strings.TrimSpace(link.Text) != "" {
+			pageLinks[link.Page] = append(pageLinks[link.Page], link)
+		}
+	}
+
+	var blocks []string
+	for i := range p.doc.Pages {
+		page := &p.doc.Pages[i]
+		if !p.doc.PageSelected(page.PageNumber) {
+			continue
+		}
+		links := pageLinks[page.PageNumber]
+
+		for _, element := range p.pageElements(page, headings, false) {
+			switch element.kind {
+			case elementHeading:
+				level := element.level
+				if level > 6 {
+					level = 6
+				}
+				blocks = append(blocks, strings.Repeat("#", level)+" "+markdownText(element.text, &links))
+			case elementParagraph:
+				blocks = append(blocks, markdownBlock(markdownText(element.text, &links)))
+			case elementList:
+				items := make([]string, 0, len(element.items))
+				for n, item := range element.items {
+					marker := "-"
+					if element.ordered {
+						marker = strconv.Itoa(n+1) + "."
+					}
+					items = append(items, marker+" "+markdownText(item, &links))
+				}
+				blocks = append(blocks, strings.Join(items, "\n"))
+			case elementTable:
+				blocks = append(blocks, markdownTable(element.table, &links))
+			}
+		}
+	}
+
+	if len(blocks) == 0 {
+		return "", nil
+	}
+	return strings.Join(blocks, "\n\n") + "\n", nil
+}
+
+// markdownText escapes text for Markdown and turns the first occurrence of each link's anchor
+// text into a link, consuming the links it uses
+func markdownText(s string, links *[]Link) string {
+	s = markdownEscaper.Replace(s)
+
+	remaining := (*links)[:0]
+	for _, link := range *links {
+		anchor := markdownEscaper.Replace(strings.TrimSpace(link.Text))
+		if i := strings.Index(s, anchor); i >= 0 {
+			s = s[:i] + "[" + anchor + "](" + markdownURL(link.URI) + ")" + s[i+len(anchor):]
+			continue
+		}
+		remaining = append(remaining, link)
+	}
+	*links = remaining
+	return s
+}
+
+// markdownBlock escapes the start of a paragraph that Markdown would otherwise read as another
+// kind of block
+func markdownBlock(s string) string {
+	match := markdownBlockPattern.FindStringSubmatchIndex(s)
+	switch {
+	case match == nil:
+		return s
+	case match[2] >= 0:
+		// A number is escaped by the period or parenthesis after it
+		return s[:match[2]] + `\` + s[match[2]:]
+	default:
+		return `\` + s
+	}
+}
+
+// markdownURL escapes the characters of a URL that would end a Markdown link
+func markdownURL(uri string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(uri)
+}
+
+// markdownTable formats a table as a GitHub-flavored Markdown table, padding short rows
+func markdownTable(table *text.Table, links *[]Link) string {
+	columns := 0
+	for _, row := range table.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(table.Rows)+1)
+	for r, row := range table.Rows {
+		cells := make([]string, columns)
+		for c, cell := range row {
+			cells[c] = markdownText(strings.Join(strings.Fields(cell.Text), " "), links)
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if r == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", columns))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
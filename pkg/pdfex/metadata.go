@@ -0,0 +1,253 @@
+package pdfex
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Namespaces of the XMP schemas mapped onto Metadata fields
+const (
+	NamespaceDublinCore = "http://purl.org/dc/elements/1.1/"
+	NamespaceXMP        = "http://ns.adobe.com/xap/1.0/"
+	NamespacePDF        = "http://ns.adobe.com/pdf/1.3/"
+	NamespacePDFAID     = "http://www.aiim.org/pdfa/ns/id/"
+)
+
+// Metadata is the document metadata from the information dictionary and the XMP packet. The
+// top-level fields merge both sources, preferring XMP, which PDF 2.0 makes the primary source;
+// dates keep the format of the source they came from.
+type Metadata struct {
+	Title        string `json:"title,omitempty"`
+	Author       string `json:"author,omitempty"` // XMP creators joined by "; "
+	Subject      string `json:"subject,omitempty"`
+	Keywords     string `json:"keywords,omitempty"`
+	Creator      string `json:"creator,omitempty"` // Application that created the original document
+	Producer     string `json:"producer,omitempty"`
+	CreationDate string `json:"creation_date,omitempty"`
+	ModDate      string `json:"mod_date,omitempty"`
+
+	DublinCore   DublinCore          `json:"dublin_core"`
+	MetadataDate string              `json:"metadata_date,omitempty"` // xmp:MetadataDate
+	PDFVersion   string              `json:"pdf_version,omitempty"`   // pdf:PDFVersion
+	PDFA         *PDFAIdentification `json:"pdfa,omitempty"`
+
+	Info   map[string]string              `json:"info"`   // Information dictionary entries, decoded
+	Custom map[string]map[string][]string `json:"custom"` // Other XMP properties by namespace URI and name
+	XMP    []byte                         `json:"-"`      // Raw XMP packet, nil if there is none
+}
+
+// DublinCore holds the Dublin Core (dc) properties of the XMP packet
+type DublinCore struct {
+	Title        string   `json:"title,omitempty"`
+	Creators     []string `json:"creators,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Subjects     []string `json:"subjects,omitempty"`
+	Publishers   []string `json:"publishers,omitempty"`
+	Contributors []string `json:"contributors,omitempty"`
+	Dates        []string `json:"dates,omitempty"`
+	Types        []string `json:"types,omitempty"`
+	Format       string   `json:"format,omitempty"`
+	Identifier   string   `json:"identifier,omitempty"`
+	Source       string   `json:"source,omitempty"`
+	Languages    []string `json:"languages,omitempty"`
+	Relations    []string `json:"relations,omitempty"`
+	Coverage     string   `json:"coverage,omitempty"`
+	Rights       string   `json:"rights,omitempty"`
+}
+
+// PDFAIdentification is the PDF/A conformance the document claims, e.g. part 2, level "B"
+// for PDF/A-2b. A claim is not a validation.
+type PDFAIdentification struct {
+	Part        int    `json:"part"`
+	Conformance string `json:"conformance,omitempty"`
+	Amendment   string `json:"amendment,omitempty"`
+	Revision    string `json:"revision,omitempty"`
+}
+
+// Metadata returns the document metadata, merging the information dictionary with the XMP
+// packet of the catalog. An XMP packet that can't be parsed is ignored with a warning.
+func (p *PDFDocument) Metadata() *Metadata {
+	meta := &Metadata{
+		Info:   p.doc.InfoDictionary(),
+		Custom: make(map[string]map[string][]string),
+		XMP:    p.doc.XMPPacket(),
+	}
+
+	xmp := make(map[string][]string)
+	if meta.XMP != nil {
+		properties, err := document.ParseXMP(meta.XMP)
+		if err != nil {
+			utils.Warnf(p.doc.Logger(), "Ignoring XMP metadata: %v\n", err)
+		}
+		for _, prop := range properties {
+			if !meta.setXMPProperty(prop) {
+				if meta.Custom[prop.Namespace] == nil {
+					meta.Custom[prop.Namespace] = make(map[string][]string)
+				}
+				meta.Custom[prop.Namespace][prop.Name] = prop.Values
+			}
+			xmp[prop.Namespace+prop.Name] = prop.Values
+		}
+	}
+
+	// first returns the first XMP value of a property, or the information dictionary entry
+	first := func(namespace, name, infoKey string) string {
+		if values := xmp[namespace+name]; len(values) > 0 {
+			return values[0]
+		}
+		return meta.Info[infoKey]
+	}
+
+	meta.Title = first(NamespaceDublinCore, "title", "Title")
+	meta.Author = meta.Info["Author"]
+	if len(meta.DublinCore.Creators) > 0 {
+		meta.Author = strings.Join(meta.DublinCore.Creators, "; ")
+	}
+	meta.Subject = first(NamespaceDublinCore, "description", "Subject")
+	meta.Keywords = first(NamespacePDF, "Keywords", "Keywords")
+	meta.Creator = first(NamespaceXMP, "CreatorTool", "Creator")
+	meta.Producer = first(NamespacePDF, "Producer", "Producer")
+	meta.CreationDate = first(NamespaceXMP, "CreateDate", "CreationDate")
+	meta.ModDate = first(NamespaceXMP, "ModifyDate", "ModDate")
+
+	return meta
+}
+
+// setXMPProperty stores an XMP property in its typed field, and reports whether it has one.
+// Properties that only feed the merged fields also count as typed.
+func (meta *Metadata) setXMPProperty(prop document.XMPProperty) bool {
+	value := prop.Values[0]
+	dc := &meta.DublinCore
+
+	switch prop.Namespace {
+	case NamespaceDublinCore:
+		switch prop.Name {
+		case "title":
+			dc.Title = value
+		case "creator":
+			dc.Creators = prop.Values
+		case "description":
+			dc.Description = value
+		case "subject":
+			dc.Subjects = prop.Values
+		case "publisher":
+			dc.Publishers = prop.Values
+		case "contributor":
+			dc.Contributors = prop.Values
+		case "date":
+			dc.Dates = prop.Values
+		case "type":
+			dc.Types = prop.Values
+		case "format":
+			dc.Format = value
+		case "identifier":
+			dc.Identifier = value
+		case "source":
+			dc.Source = value
+		case "language":
+			dc.Languages = prop.Values
+		case "relation":
+			dc.Relations = prop.Values
+		case "coverage":
+			dc.Coverage = value
+		case "rights":
+			dc.Rights = value
+		default:
+			return false
+		}
+	case NamespaceXMP:
+		switch prop.Name {
+		case "MetadataDate":
+			meta.MetadataDate = value
+		case "CreatorTool", "CreateDate", "ModifyDate":
+		default:
+			return false
+		}
+	case NamespacePDF:
+		switch prop.Name {
+		case "PDFVersion":
+			meta.PDFVersion = value
+		case "Keywords", "Producer":
+		default:
+			return false
+		}
+	case NamespacePDFAID:
+		if meta.PDFA == nil {
+			meta.PDFA = &PDFAIdentification{}
+		}
+		switch prop.Name {
+		case "part":
+			meta.PDFA.Part, _ = strconv.Atoi(value)
+		case "conformance":
+			meta.PDFA.Conformance = value
+		case "amd":
+			meta.PDFA.Amendment = value
+		case "rev":
+			meta.PDFA.Revision = value
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// DocumentInfo is the document information with typed values: the common fields of Metadata,
+// with the dates parsed, and the other information dictionary entries
+type DocumentInfo struct {
+	Title        string            `json:"title,omitempty"`
+	Author       string            `json:"author,omitempty"`
+	Subject      string            `json:"subject,omitempty"`
+	Keywords     string            `json:"keywords,omitempty"`
+	Creator      string            `json:"creator,omitempty"`
+	Producer     string            `json:"producer,omitempty"`
+	CreationDate time.Time         `json:"creation_date"` // Zero if missing or invalid
+	ModDate      time.Time         `json:"mod_date"`      // Zero if missing or invalid
+	Custom       map[string]string `json:"custom"`        // Other information dictionary entries, such as Trapped
+}
+
+// DocumentInfo returns the document information, merged from the information dictionary and
+// the XMP packet as in Metadata. Dates in either the PDF "D:YYYYMMDDHHmmSSOHH'mm'" format or
+// ISO 8601 are parsed; invalid ones are left zero with a warning. Non-string entries are
+// returned in their PDF syntax.
+func (p *PDFDocument) DocumentInfo() *DocumentInfo {
+	meta := p.Metadata()
+
+	info := &DocumentInfo{
+		Title:    meta.Title,
+		Author:   meta.Author,
+		Subject:  meta.Subject,
+		Keywords: meta.Keywords,
+		Creator:  meta.Creator,
+		Producer: meta.Producer,
+		Custom:   make(map[string]string),
+	}
+	info.CreationDate = p.infoDate("CreationDate", meta.CreationDate)
+	info.ModDate = p.infoDate("ModDate", meta.ModDate)
+
+	for key, value := range meta.Info {
+		switch key {
+		case "Title", "Author", "Subject", "Keywords", "Creator", "Producer", "CreationDate", "ModDate":
+		default:
+			info.Custom[key] = value
+		}
+	}
+	return info
+}
+
+// infoDate parses a date of the document information, warning about invalid ones
+func (p *PDFDocument) infoDate(key, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	date, err := utils.ParseDate(value)
+	if err != nil {
+		utils.Warnf(p.doc.Logger(), "Ignoring %s: %v\n", key, err)
+	}
+	return date
+}
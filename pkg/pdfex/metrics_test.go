@@ -0,0 +1,63 @@
+package pdfex
+
+import (
+	"testing"
+
+	"github.com/yourusername/pdfex/internal/metrics"
+)
+
+// scannedFixture is a document whose first page has text in Helvetica and whose second page is
+// a scanned image without a text layer
+const scannedFixture = "testdata/scanned.pdf"
+
+// freshMetrics returns the metrics of the scanned fixture, parsed without asking for its text
+func freshMetrics(t *testing.T) *metrics.PDFMetrics {
+	t.Helper()
+	p, err := ParsePDF(scannedFixture)
+	if err != nil {
+		t.Fatalf("ParsePDF: %v", err)
+	}
+	return p.Metrics()
+}
+
+func TestMetricsExtractionQuality(t *testing.T) {
+	m := freshMetrics(t)
+	if m.ExtractionQuality <= 0 || m.DictionaryWordRatio <= 0 {
+		t.Errorf("extraction quality %v with dictionary word ratio %v, want both above 0",
+			m.ExtractionQuality, m.DictionaryWordRatio)
+	}
+	if m.ScriptCounts["Latin"] == 0 || len(m.PageScriptCounts) != 2 {
+		t.Errorf("script counts %v for %d pages, want Latin characters and 2 pages", m.ScriptCounts, len(m.PageScriptCounts))
+	}
+	if m.TextChunkCount != 1 {
+		t.Errorf("got %d text chunks, want 1", m.TextChunkCount)
+	}
+}
+
+func TestMetricsImageOnlyPages(t *testing.T) {
+	m := freshMetrics(t)
+	if len(m.ImageOnlyPages) != 1 || m.ImageOnlyPages[0] != 2 || m.ImageOnlyRatio != 0.5 {
+		t.Errorf("image-only pages %v with ratio %v, want [2] with 0.5", m.ImageOnlyPages, m.ImageOnlyRatio)
+	}
+}
+
+func TestMetricsFontUsage(t *testing.T) {
+	m := freshMetrics(t)
+	if len(m.Fonts) != 1 {
+		t.Fatalf("got %d fonts, want 1", len(m.Fonts))
+	}
+	if font := m.Fonts[0]; font.Characters == 0 || font.BaseFont != "/Helvetica" {
+		t.Errorf("font usage %+v, want Helvetica showing characters", font)
+	}
+}
+
+func TestMetricsMappingCoverage(t *testing.T) {
+	// The fixture's text is shown with Helvetica, which has no ToUnicode map
+	m := freshMetrics(t)
+	if m.ShownGlyphs == 0 || len(m.PageCoverage) != 2 {
+		t.Errorf("%d glyphs shown with the coverage of %d pages, want glyphs and 2 pages", m.ShownGlyphs, len(m.PageCoverage))
+	}
+	if m.MappedGlyphs != 0 || m.MappingCoverage != 0 {
+		t.Errorf("%d glyphs mapped with coverage %v, want none", m.MappedGlyphs, m.MappingCoverage)
+	}
+}
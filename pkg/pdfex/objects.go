@@ -0,0 +1,175 @@
+package pdfex
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/content"
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// Value is a PDF object value: a Name, Number, String, Bool, Null, Ref, Array or Dict. Use a
+// type switch to tell them apart; the String method of each gives it in PDF syntax.
+type Value = utils.Value
+
+// Typed PDF object values, as returned by ObjectValue and ObjectDict
+type (
+	Name   = utils.Name   // A name, without its slash and with its #xx escapes decoded
+	Number = utils.Number // An integer or real number
+	String = utils.String // The bytes of a literal or hexadecimal string; Text decodes them
+	Bool   = utils.Bool
+	Null   = utils.Null
+	Ref    = utils.Ref // An indirect reference; Resolve follows it
+	Array  = utils.Array
+	Dict   = utils.Dict // A dictionary keyed by name without the slash, with typed accessors
+)
+
+// ObjectInfo describes an indirect object of the document, for inspecting its raw structure
+type ObjectInfo struct {
+	Number     int      `json:"number"`
+	Generation int      `json:"generation"`
+	Offset     int64    `json:"offset"`            // Byte offset in the file, -1 if the xref table has none
+	Type       string   `json:"type,omitempty"`    // Value of /Type, without the slash
+	Subtype    string   `json:"subtype,omitempty"` // Value of /Subtype, without the slash
+	Keys       []string `json:"keys"`              // Dictionary keys, sorted
+	Stream     bool     `json:"stream"`
+	Filters    []string `json:"filters,omitempty"` // Stream filters in the order they are applied when decoding
+	Length     int      `json:"length,omitempty"`  // Stream data length as parsed
+}
+
+// Objects lists the indirect objects of the document in object number order
+func (p *PDFDocument) Objects() []ObjectInfo {
+	numbers := objectNumbers(p.doc)
+	objects := make([]ObjectInfo, 0, len(numbers))
+	for _, num := range numbers {
+		objects = append(objects, newObjectInfo(p.doc, num, p.doc.Objects[num]))
+	}
+	return objects
+}
+
+// objectNumbers returns the numbers of the loaded objects of a document in ascending order
+func objectNumbers(doc *document.PDFDocument) []int {
+	numbers := make([]int, 0, len(doc.Objects))
+	for num := range doc.Objects {
+		numbers = append(numbers, num)
+	}
+	sort.Ints(numbers)
+	return numbers
+}
+
+// newObjectInfo describes object num of a document
+func newObjectInfo(doc *document.PDFDocument, num int, obj document.PDFObject) ObjectInfo {
+	info := ObjectInfo{
+		Number:     num,
+		Generation: obj.Generation,
+		Offset:     -1,
+		Type:       strings.TrimPrefix(utils.GetString(obj.Dictionary["Type"], ""), "/"),
+		Subtype:    strings.TrimPrefix(utils.GetString(obj.Dictionary["Subtype"], ""), "/"),
+		Keys:       make([]string, 0, len(obj.Dictionary)),
+		Stream:     obj.IsStream,
+		Filters:    streamFilters(utils.GetString(obj.Dictionary["Filter"], "")),
+	}
+	if entry, ok := doc.XRefTable[num]; ok && entry.InUse {
+		info.Offset = entry.Offset
+	}
+	for key := range obj.Dictionary {
+		info.Keys = append(info.Keys, key)
+	}
+	sort.Strings(info.Keys)
+	if obj.IsStream {
+		info.Length = len(obj.Stream)
+	}
+	return info
+}
+
+// streamFilters splits a /Filter value, a name or an array of names, into filter names
+func streamFilters(spec string) []string {
+	var filters []string
+	for _, name := range strings.Fields(strings.Trim(spec, "[]")) {
+		filters = append(filters, strings.TrimPrefix(name, "/"))
+	}
+	return filters
+}
+
+// ObjectData returns the stream data of an object as stored in the file, or decoded through its
+// filters when decode is set. Raw data is read again from the source file, so it is not
+// available for documents parsed from memory. Objects without a stream return their content.
+func (p *PDFDocument) ObjectData(num int, decode bool) ([]byte, error) {
+	obj, ok := p.doc.GetObject(num)
+	if !ok {
+		return nil, fmt.Errorf("object %d not found", num)
+	}
+	if !obj.IsStream {
+		return obj.Content, nil
+	}
+
+	raw, err := p.rawStream(num)
+	if err != nil {
+		return nil, err
+	}
+	filter := utils.GetString(obj.Dictionary["Filter"], "")
+	if !decode || filter == "" {
+		return raw, nil
+	}
+
+	data, err := content.DecompressStream(raw, filter, obj.DecodeParms())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode object %d: %v", num, err)
+	}
+	return data, nil
+}
+
+// rawStream reads the stream data of an object from the data the document was parsed from, at
+// its xref offset
+func (p *PDFDocument) rawStream(num int) ([]byte, error) {
+	var data []byte
+	err := p.withSource(func(src io.ReaderAt, size int64) error {
+		var err error
+		data, err = p.doc.RawStream(src, num)
+		return err
+	})
+	return data, err
+}
+
+// EachObject calls fn with each indirect object of the document in object number order. It
+// stops at the first error fn returns and returns it.
+func (p *PDFDocument) EachObject(fn func(ObjectInfo) error) error {
+	for _, num := range objectNumbers(p.doc) {
+		if err := fn(newObjectInfo(p.doc, num, p.doc.Objects[num])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ObjectValue returns the value of an indirect object in the typed model. The value of a
+// stream is its dictionary; ObjectData returns its data.
+func (p *PDFDocument) ObjectValue(num int) (Value, error) {
+	obj, ok := p.doc.GetObject(num)
+	if !ok {
+		return nil, fmt.Errorf("object %d not found", num)
+	}
+	return p.doc.Resolver().ResolveValue(utils.Ref{Num: num, Gen: obj.Generation})
+}
+
+// ObjectDict returns the dictionary of an object that is a dictionary or a stream
+func (p *PDFDocument) ObjectDict(num int) (Dict, error) {
+	value, err := p.ObjectValue(num)
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := value.(Dict)
+	if !ok {
+		return nil, fmt.Errorf("object %d is not a dictionary", num)
+	}
+	return dict, nil
+}
+
+// Resolve follows indirect references from a value, such as an entry of a dictionary from
+// ObjectDict, until it is a direct object. A reference to a stream resolves to its dictionary.
+func (p *PDFDocument) Resolve(value Value) (Value, error) {
+	return p.doc.Resolver().ResolveValue(value)
+}
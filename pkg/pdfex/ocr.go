@@ -0,0 +1,96 @@
+package pdfex
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Scanned pages are mostly DCT-encoded
+	"unicode/utf8"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// OCRWord is a word recognized in a page image
+type OCRWord struct {
+	Text   string
+	Bounds image.Rectangle // In pixels of the image, from its top-left corner
+}
+
+// OCREngine recognizes the words of a page image, such as a wrapper around Tesseract or a
+// cloud OCR service. Set ParseOptions.OCR to have the text of scanned pages extracted with it.
+type OCREngine interface {
+	Recognize(img image.Image) ([]OCRWord, error)
+}
+
+// ocrFontName is the font name of text positions recognized by OCR
+const ocrFontName = "OCR"
+
+// ocrRecognizer runs an OCR engine on the image-only pages of a document
+type ocrRecognizer struct {
+	doc    *document.PDFDocument
+	engine OCREngine
+}
+
+// RecognizePage recognizes the largest scan image of an image-only page and maps its words
+// onto the page. Pages that aren't image-only are left alone.
+func (r ocrRecognizer) RecognizePage(page *document.PDFPage) ([]document.TextPosition, error) {
+	if !text.ClassifyScan(r.doc, page).ImageOnly() {
+		return nil, nil
+	}
+
+	var scan text.ScanImage
+	var largest float64
+	for _, image := range text.ScanImages(r.doc, page) {
+		if area := (image.Bounds.MaxX - image.Bounds.MinX) * (image.Bounds.MaxY - image.Bounds.MinY); area > largest {
+			scan, largest = image, area
+		}
+	}
+
+	_, data, ok := encodeImage(scan.Object)
+	if !ok {
+		return nil, fmt.Errorf("unsupported encoding of image %d", scan.Object.ObjectNumber)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding image %d: %v", scan.Object.ObjectNumber, err)
+	}
+	words, err := r.engine.Recognize(img)
+	if err != nil {
+		return nil, err
+	}
+
+	// The image fills its bounds, so pixels scale linearly onto the page with the Y axis flipped
+	size := img.Bounds().Size()
+	if size.X == 0 || size.Y == 0 {
+		return nil, nil
+	}
+	scaleX := (scan.Bounds.MaxX - scan.Bounds.MinX) / float64(size.X)
+	scaleY := (scan.Bounds.MaxY - scan.Bounds.MinY) / float64(size.Y)
+
+	positions := make([]document.TextPosition, 0, len(words))
+	for _, word := range words {
+		if word.Text == "" {
+			continue
+		}
+		// Words carry a trailing space, since gaps between them are far narrower than the
+		// gaps that separate runs of text shown without spaces
+		box := word.Bounds.Sub(img.Bounds().Min)
+		pos := document.TextPosition{
+			X:        scan.Bounds.MinX + float64(box.Min.X)*scaleX,
+			Y:        scan.Bounds.MaxY - float64(box.Max.Y)*scaleY,
+			FontSize: float64(box.Dy()) * scaleY,
+			Text:     word.Text + " ",
+			FontName: ocrFontName,
+			Width:    float64(box.Dx()) * scaleX,
+			MCID:     -1,
+		}
+		runes := utf8.RuneCountInString(word.Text)
+		for i := 0; i < runes; i++ {
+			pos.CharWidths = append(pos.CharWidths, pos.Width/float64(runes))
+		}
+		pos.CharWidths = append(pos.CharWidths, 0)
+		positions = append(positions, pos)
+	}
+	return positions, nil
+}
@@ -0,0 +1,82 @@
+package pdfex
+
+import (
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// OutlineEntry is an entry of the document outline, either a bookmark or an inferred heading
+type OutlineEntry struct {
+	Title       string         `json:"title"`
+	Level       int            `json:"level"` // 1 for top-level entries
+	Page        int            `json:"page"`  // 1-based destination page, 0 if it could not be resolved
+	Destination *Destination   `json:"destination,omitempty"`
+	Children    []OutlineEntry `json:"children,omitempty"`
+}
+
+// Destination is the view a bookmark opens: a position on a page, or a URI for web links.
+// Coordinates are in the default user space of the page; nil values keep the current view.
+type Destination struct {
+	Page   int      `json:"page,omitempty"` // 1-based page number, 0 if it could not be resolved
+	Name   string   `json:"name,omitempty"` // Named destination the bookmark refers to
+	Fit    string   `json:"fit,omitempty"`  // XYZ, Fit, FitH, FitV, FitR, FitB, FitBH or FitBV
+	Left   *float64 `json:"left,omitempty"`
+	Top    *float64 `json:"top,omitempty"`
+	Right  *float64 `json:"right,omitempty"`
+	Bottom *float64 `json:"bottom,omitempty"`
+	Zoom   *float64 `json:"zoom,omitempty"`
+	URI    string   `json:"uri,omitempty"`
+}
+
+// GetOutline returns the bookmarks of the document as a tree, with their destinations and
+// resolved page numbers, or nil if it has none
+func (p *PDFDocument) GetOutline() []OutlineEntry {
+	return newOutlineEntries(p.doc.GetOutline())
+}
+
+// InferOutline builds an outline from the headings found in the text, for documents without
+// bookmarks. Headings are recognised by a font size larger than the body text, bold fonts and
+// section numbering such as "2.1" or "Chapter 3".
+func (p *PDFDocument) InferOutline() []OutlineEntry {
+	p.ensureExtracted()
+
+	return newOutlineEntries(text.InferOutline(p.doc.Pages, p.doc.Fonts))
+}
+
+// newOutlineEntries converts internal outline items into their public representation
+func newOutlineEntries(items []document.OutlineItem) []OutlineEntry {
+	if len(items) == 0 {
+		return nil
+	}
+
+	entries := make([]OutlineEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, OutlineEntry{
+			Title:       item.Title,
+			Level:       item.Level,
+			Page:        item.PageNumber,
+			Destination: newDestination(item.Destination),
+			Children:    newOutlineEntries(item.Children),
+		})
+	}
+	return entries
+}
+
+// newDestination converts an internal destination into its public representation, or nil if
+// the entry has none, as for inferred headings
+func newDestination(dest document.Destination) *Destination {
+	if dest == (document.Destination{}) {
+		return nil
+	}
+	return &Destination{
+		Page:   dest.PageNumber,
+		Name:   dest.Name,
+		Fit:    dest.Fit,
+		Left:   dest.Left,
+		Top:    dest.Top,
+		Right:  dest.Right,
+		Bottom: dest.Bottom,
+		Zoom:   dest.Zoom,
+		URI:    dest.URI,
+	}
+}
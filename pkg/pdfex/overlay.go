@@ -0,0 +1,165 @@
+package pdfex
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// Rect is a rectangle on the unrotated page, in points: default user space scaled by the page's
+// /UserUnit, which is 1 for nearly all documents
+type Rect struct {
+	X1 float64 `json:"x1"` // Lower-left corner
+	Y1 float64 `json:"y1"`
+	X2 float64 `json:"x2"` // Upper-right corner
+	Y2 float64 `json:"y2"`
+}
+
+// Highlight marks a matched run of text on a page. A match that wraps across lines has one
+// rectangle per line.
+type Highlight struct {
+	Page  int    `json:"page"` // 1-based page number
+	Text  string `json:"text"`
+	Rects []Rect `json:"rects"`
+}
+
+// HighlightMatches searches the positioned text of each page for a regular expression and
+// returns the location of every match
+func (p *PDFDocument) HighlightMatches(pattern string) ([]Highlight, error) {
+	matches, err := p.FindText(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	highlights := make([]Highlight, 0, len(matches))
+	for _, match := range matches {
+		highlights = append(highlights, Highlight{Page: match.Page, Text: match.Text, Rects: match.Rects})
+	}
+	return highlights, nil
+}
+
+// userSpaceRect maps a box from upright page space back into the page's default user space
+func userSpaceRect(box text.Box, page *document.PDFPage) Rect {
+	x1, y1 := text.UserSpacePoint(box.MinX, box.MinY, page.TextRotation, page.Width, page.Height)
+	x2, y2 := text.UserSpacePoint(box.MaxX, box.MaxY, page.TextRotation, page.Width, page.Height)
+
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+
+	return Rect{X1: x1, Y1: y1, X2: x2, Y2: y2}
+}
+
+// annotationRect maps a box from upright page space into the unscaled default user space that
+// annotations are placed in
+func annotationRect(box text.Box, page *document.PDFPage) Rect {
+	r := userSpaceRect(box, page)
+	unit := page.Unit()
+	return Rect{X1: r.X1 / unit, Y1: r.Y1 / unit, X2: r.X2 / unit, Y2: r.Y2 / unit}
+}
+
+// XFDF elements for a document containing highlight annotations
+type xfdfDocument struct {
+	XMLName     xml.Name        `xml:"xfdf"`
+	Namespace   string          `xml:"xmlns,attr"`
+	Space       string          `xml:"xml:space,attr"`
+	File        xfdfFile        `xml:"f"`
+	Annotations []xfdfHighlight `xml:"annots>highlight"`
+}
+
+type xfdfFile struct {
+	Href string `xml:"href,attr"`
+}
+
+type xfdfHighlight struct {
+	Page     int    `xml:"page,attr"` // 0-based in XFDF
+	Rect     string `xml:"rect,attr"`
+	Coords   string `xml:"coords,attr"`
+	Color    string `xml:"color,attr"`
+	Title    string `xml:"title,attr"`
+	Name     string `xml:"name,attr"`
+	Contents string `xml:"contents"`
+}
+
+// WriteXFDF writes highlights as an XFDF annotation file for the given PDF, which viewers can
+// import on top of the original document
+func WriteXFDF(w io.Writer, pdfFile string, highlights []Highlight) error {
+	doc := xfdfDocument{
+		Namespace: "http://ns.adobe.com/xfdf/",
+		Space:     "preserve",
+		File:      xfdfFile{Href: filepath.Base(pdfFile)},
+	}
+
+	for i, h := range highlights {
+		bounds := h.Rects[0]
+		var coords []string
+		for _, r := range h.Rects {
+			bounds = Rect{
+				X1: math.Min(bounds.X1, r.X1),
+				Y1: math.Min(bounds.Y1, r.Y1),
+				X2: math.Max(bounds.X2, r.X2),
+				Y2: math.Max(bounds.Y2, r.Y2),
+			}
+			// QuadPoints order: upper-left, upper-right, lower-left, lower-right
+			coords = append(coords, formatNumbers(r.X1, r.Y2, r.X2, r.Y2, r.X1, r.Y1, r.X2, r.Y1))
+		}
+
+		doc.Annotations = append(doc.Annotations, xfdfHighlight{
+			Page:     h.Page - 1,
+			Rect:     formatNumbers(bounds.X1, bounds.Y1, bounds.X2, bounds.Y2),
+			Coords:   strings.Join(coords, ","),
+			Color:    "#FFFF00",
+			Title:    "pdfex",
+			Name:     fmt.Sprintf("pdfex-%d", i+1),
+			Contents: h.Text,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write XFDF: %v", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteHighlightsJSON writes highlights as a JSON overlay
+func WriteHighlightsJSON(w io.Writer, pdfFile string, highlights []Highlight) error {
+	overlay := struct {
+		File       string      `json:"file"`
+		Highlights []Highlight `json:"highlights"`
+	}{
+		File:       filepath.Base(pdfFile),
+		Highlights: highlights,
+	}
+	if overlay.Highlights == nil {
+		overlay.Highlights = []Highlight{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(overlay)
+}
+
+// formatNumbers formats coordinates as a comma-separated list
+func formatNumbers(values ...float64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%.2f", v)
+	}
+	return strings.Join(parts, ",")
+}
@@ -1,9 +1,14 @@
 package pdfex
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,22 +20,120 @@ import (
 
 // PDFDocument represents a parsed PDF document with a public API
 type PDFDocument struct {
-	doc *document.PDFDocument
+	doc          *document.PDFDocument
+	textOptions  text.Options
+	source       string // Name of the source document, a label for output
+	sourcePath   string // File the document was parsed from, empty if parsed from memory
+	sourceData   []byte // Data the document was parsed from, nil if parsed from a file
+	sourceSHA256 string // Hex-encoded SHA-256 of the source file
+	sourceSize   int64
+
+	// Text of each page from extracting the whole document with textOptions, nil until then
+	// or after the pages were extracted again with other options
+	pageTexts []string
 }
 
+// NormalizationMode selects the Unicode normalization applied to extracted text
+type NormalizationMode = text.NormalizationMode
+
+// Unicode normalization modes
+const (
+	NormalizeNone          = text.NormalizeNone          // Leave decoded text unchanged
+	NormalizeLigatures     = text.NormalizeLigatures     // Decompose Latin ligatures such as "ﬁ"
+	NormalizeCompatibility = text.NormalizeCompatibility // Also map presentation forms, fullwidth ASCII and NBSP
+)
+
+// TextOptions controls the whitespace of extracted text; the zero value keeps the layout's
+type TextOptions = text.WhitespaceOptions
+
+// NewlinePolicy selects how the line breaks of extracted text are kept
+type NewlinePolicy = text.NewlinePolicy
+
+// Newline policies for TextOptions.Newlines
+const (
+	NewlinesPreserve   = text.NewlinesPreserve   // Keep a line break for each line of the layout
+	NewlinesParagraphs = text.NewlinesParagraphs // One paragraph per line
+	NewlinesSpaces     = text.NewlinesSpaces     // Replace line breaks with spaces
+)
+
+// Column layouts for ParseOptions.Columns
+const (
+	ColumnsOff  = text.ColumnsOff  // Read each line across the full width of the page
+	ColumnsAuto = text.ColumnsAuto // Detect columns from the gutters between them
+)
+
 // ParseOptions contains options for parsing PDFs
 type ParseOptions struct {
-	LogLevel              utils.LogLevel
+	Logger                *slog.Logger   // Receives the log of the parse, with the file name attached; nil to log at LogLevel
+	LogLevel              utils.LogLevel // Level of the default logger, used when Logger is nil
 	ExtractText           bool
 	ExtractFonts          bool
 	ExtractImages         bool
 	MaxObjectsToScan      int
 	SkipPageTree          bool
 	FollowReferences      bool
-	StrictMode            bool
+	StrictMode            bool // Reject documents that needed recovery or break the specification; see ErrNonConformant
 	OutputChunks          bool
 	ChunkOutputPath       string
 	TreatWarningsAsErrors bool
+	VerifyXRef            bool // Rebuild the xref table by scanning and compare it with the parsed one
+
+	// Scratch-space policy for temporary files
+	ScratchDir      string // Directory for temporary files (default: the system temp directory)
+	ScratchMaxBytes int64  // Maximum total size of live temporary files, 0 for no limit
+	SecureDelete    bool   // Overwrite temporary files with zeros before deleting them
+
+	// Unicode normalization applied to extracted text after decoding
+	Normalization NormalizationMode
+
+	// Column layout used for reading order: ColumnsOff, ColumnsAuto, or 2 or more to force
+	// that many equal-width columns
+	Columns int
+
+	// Remove running headers, footers and bare page numbers from the document text; the
+	// removed lines are listed in the metrics
+	StripRunningLines bool
+
+	// Leave out the text of optional content groups (layers) that are hidden when the document
+	// is opened, and their images from checksum manifests. Layer names are in the metrics.
+	ExcludeHiddenLayers bool
+
+	// Leave out text outside each page's crop box, which viewers and printers don't show
+	ClipToCropBox bool
+
+	// Also extract the text of annotation appearance streams, such as free-text comments and
+	// filled-in form fields, merged into the page text where viewers draw it
+	IncludeAnnotations bool
+
+	// Re-decode the text of pages that look garbled (see TextQuality) when a Windows-1252 or
+	// ISO-8859-1 round trip to UTF-8 makes it clearly more plausible
+	RepairMojibake bool
+
+	// Newline policy, space collapsing and control-character stripping of extracted text,
+	// applied alike by GetText, ExtractTextContent and the other plain-text methods
+	Text TextOptions
+
+	// Pages to process, e.g. "1-5,12" or "10-" for page 10 to the end; empty for all. Other
+	// pages are counted and keep their size, but their resources and content streams are not
+	// loaded and they have no text.
+	PageRange string
+
+	// Tracer receives a span around the parse and each of its phases; nil for no tracing
+	Tracer Tracer
+
+	// Hooks observe the parse as it happens and may abort it; nil for none
+	Hooks Hooks
+
+	// OCR recognizes the text of image-only pages (see ClassifyPages), which is merged into
+	// their text positions as words in the "OCR" font; nil to leave such pages without text
+	OCR OCREngine
+
+	// Resource limits against hostile documents such as decompression bombs, 0 for no limit.
+	// Exceeding one fails the parse with a *LimitError.
+	MaxDecompressedStreamSize int64 // Decoded bytes of any one stream
+	MaxTotalDecompressedBytes int64 // Decoded bytes of all streams together
+	MaxObjects                int   // Objects in the document
+	MaxRecursionDepth         int   // Nesting of page tree nodes and of form XObjects
 }
 
 // DefaultParseOptions returns default parsing options
@@ -46,6 +149,8 @@ func DefaultParseOptions() *ParseOptions {
 		StrictMode:            false,
 		OutputChunks:          false,
 		TreatWarningsAsErrors: false,
+		VerifyXRef:            false,
+		Normalization:         NormalizeNone,
 	}
 }
 
@@ -56,39 +161,174 @@ func ParsePDF(filename string) (*PDFDocument, error) {
 
 // ParsePDFWithOptions parses a PDF file with the specified options
 func ParsePDFWithOptions(filename string, options *ParseOptions) (*PDFDocument, error) {
-	// Set up logging
-	utils.SetLogLevel(options.LogLevel)
+	start := time.Now()
+	doc, err := parsePDFWithOptions(filename, options)
+	if err != nil {
+		metrics.Service.RecordParse(time.Since(start), 0, true)
+		return nil, err
+	}
+	metrics.Service.RecordParse(time.Since(start), doc.PageCount(), false)
+	return doc, nil
+}
+
+// parsePDFWithOptions parses a PDF file, without recording it in the service metrics
+func parsePDFWithOptions(filename string, options *ParseOptions) (*PDFDocument, error) {
+	pageRange, err := document.ParsePageRange(options.PageRange)
+	if err != nil {
+		return nil, err
+	}
 
 	// Parse the PDF
-	doc, err := document.ParsePDF(filename)
+	doc, err := document.ParsePDFWithConfig(filename, document.ParseConfig{
+		VerifyXRef: options.VerifyXRef,
+		PageRange:  pageRange,
+		Tracer:     options.Tracer,
+		Limits:     options.limits(),
+		Logger:     options.logger(),
+		Hooks:      options.hooks(),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse PDF: %v", err)
+		return nil, fmt.Errorf("failed to parse PDF: %w", err)
+	}
+
+	// Read the encodings and ToUnicode maps of the fonts the pages name, which text extraction
+	// decodes with and the font usage metrics describe
+	text.ProcessFontInDocument(doc)
+	doc.Metrics().FontCount = doc.FontCount()
+
+	// The hash identifies the exact source state in checksum manifests
+	sourceSHA256, sourceSize, err := hashFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PDFDocument{
+		doc:          doc,
+		textOptions:  options.textOptions(doc),
+		source:       filename,
+		sourcePath:   filename,
+		sourceSHA256: sourceSHA256,
+		sourceSize:   sourceSize,
+	}
+	if warnings := result.Warnings(); options.TreatWarningsAsErrors && len(warnings) > 0 {
+		problems := make([]fmt.Stringer, len(warnings))
+		for i, warning := range warnings {
+			problems[i] = warning
+		}
+		return nil, problemsError(ErrWarnings, problems)
+	}
+	if options.StrictMode {
+		violations, err := result.strictViolations()
+		if err != nil {
+			return nil, err
+		}
+		if len(violations) > 0 {
+			return nil, problemsError(ErrNonConformant, violations)
+		}
 	}
+	return result, nil
+}
 
-	return &PDFDocument{doc}, nil
+// textOptions returns the text extraction options described by the options, for a document
+func (options *ParseOptions) textOptions(doc *document.PDFDocument) text.Options {
+	textOptions := text.Options{
+		Normalization:       options.Normalization,
+		Columns:             options.Columns,
+		StripRunningLines:   options.StripRunningLines,
+		ExcludeHiddenLayers: options.ExcludeHiddenLayers,
+		ClipToCropBox:       options.ClipToCropBox,
+		IncludeAnnotations:  options.IncludeAnnotations,
+		RepairMojibake:      options.RepairMojibake,
+		Whitespace:          options.Text,
+	}
+	if options.OCR != nil {
+		textOptions.OCR = ocrRecognizer{doc: doc, engine: options.OCR}
+	}
+	return textOptions
+}
+
+// logger returns the logger of the parse: ParseOptions.Logger, or one writing to the global
+// log writer at ParseOptions.LogLevel
+func (options *ParseOptions) logger() *slog.Logger {
+	if options.Logger != nil {
+		return options.Logger
+	}
+	return utils.NewLevelLogger(options.LogLevel)
+}
+
+// hooks returns the hooks of the parse, or nil if there are none
+func (options *ParseOptions) hooks() document.Hooks {
+	if options.Hooks == nil {
+		return nil
+	}
+	return documentHooks{hooks: options.Hooks}
+}
+
+// limits returns the resource limits described by the options
+func (options *ParseOptions) limits() document.Limits {
+	return document.Limits{
+		MaxDecompressedStreamSize: options.MaxDecompressedStreamSize,
+		MaxTotalDecompressedBytes: options.MaxTotalDecompressedBytes,
+		MaxObjects:                options.MaxObjects,
+		MaxRecursionDepth:         options.MaxRecursionDepth,
+	}
+}
+
+// scratchPolicy returns the scratch-space policy described by the options
+func (options *ParseOptions) scratchPolicy() utils.ScratchPolicy {
+	return utils.ScratchPolicy{
+		Dir:          options.ScratchDir,
+		MaxBytes:     options.ScratchMaxBytes,
+		SecureDelete: options.SecureDelete,
+	}
 }
 
 // ParsePDFFromBytes parses a PDF from a byte slice
 func ParsePDFFromBytes(data []byte, name string) (*PDFDocument, error) {
+	return ParsePDFFromBytesWithOptions(data, name, DefaultParseOptions())
+}
+
+// ParsePDFFromBytesWithOptions parses a PDF from a byte slice with the specified options.
+// The data is staged in a temporary file that honours the scratch-space options. The document
+// keeps a copy of the data for the methods that read it again, such as Validate; name only
+// labels the document.
+func ParsePDFFromBytesWithOptions(data []byte, name string, options *ParseOptions) (*PDFDocument, error) {
+	return parseOwnedBytes(bytes.Clone(data), name, options)
+}
+
+// parseOwnedBytes parses a PDF from data that the document may keep, as nothing else modifies it
+func parseOwnedBytes(data []byte, name string, options *ParseOptions) (*PDFDocument, error) {
 	// Write data to a temporary file
-	tmpFile, err := os.CreateTemp("", "pdfex-*.pdf")
+	tempName, err := utils.CreateScratchFile(options.scratchPolicy(), "pdfex-*.pdf", data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temporary file: %v", err)
+		return nil, err
 	}
+	defer utils.RemoveScratchFile(tempName) // Clean up
 
-	tempName := tmpFile.Name()
-	defer os.Remove(tempName) // Clean up
-
-	_, err = tmpFile.Write(data)
+	// Parse the temporary file
+	doc, err := ParsePDFWithOptions(tempName, options)
 	if err != nil {
-		tmpFile.Close()
-		return nil, fmt.Errorf("failed to write data to temporary file: %v", err)
+		return nil, err
 	}
+	doc.source = name
+	doc.sourcePath = ""
+	doc.sourceData = data
+	return doc, nil
+}
 
-	tmpFile.Close()
+// ParsePDFFromReader parses a PDF read from r, such as standard input
+func ParsePDFFromReader(r io.Reader, name string) (*PDFDocument, error) {
+	return ParsePDFFromReaderWithOptions(r, name, DefaultParseOptions())
+}
 
-	// Parse the temporary file
-	return ParsePDF(tempName)
+// ParsePDFFromReaderWithOptions parses a PDF read from r with the specified options. Objects are
+// located by their offsets, so the whole document is read before parsing starts.
+func ParsePDFFromReaderWithOptions(r io.Reader, name string, options *ParseOptions) (*PDFDocument, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	return parseOwnedBytes(data, name, options)
 }
 
 // Version returns the PDF version
@@ -96,11 +336,22 @@ func (p *PDFDocument) Version() string {
 	return p.doc.Version
 }
 
+// SourceSHA256 returns the hex-encoded SHA-256 hash of the parsed file
+func (p *PDFDocument) SourceSHA256() string {
+	return p.sourceSHA256
+}
+
 // PageCount returns the number of pages in the document
 func (p *PDFDocument) PageCount() int {
 	return len(p.doc.Pages)
 }
 
+// PageSelected reports whether a page is in ParseOptions.PageRange, so that its content was
+// processed
+func (p *PDFDocument) PageSelected(pageNum int) bool {
+	return p.doc.PageSelected(pageNum)
+}
+
 // ObjectCount returns the number of objects in the document
 func (p *PDFDocument) ObjectCount() int {
 	return len(p.doc.Objects)
@@ -108,50 +359,184 @@ func (p *PDFDocument) ObjectCount() int {
 
 // FontCount returns the number of fonts in the document
 func (p *PDFDocument) FontCount() int {
-	return len(p.doc.Fonts)
+	return p.doc.FontCount()
 }
 
-// TextChunkCount returns the number of text chunks in the document
+// TextChunkCount returns the number of chunks Chunks cuts the text into with
+// DefaultChunkOptions
 func (p *PDFDocument) TextChunkCount() int {
-	return len(p.doc.TextChunks)
+	return len(p.GetTextChunks())
 }
 
-// GetText returns the text content of the document
+// GetText returns the extracted text of the document, pages separated as in ExtractTextContent
 func (p *PDFDocument) GetText() string {
-	var allText strings.Builder
-	for _, page := range p.doc.Pages {
-		allText.WriteString(page.Text)
-		allText.WriteString("\n\n")
-	}
-	return allText.String()
+	return strings.Join(p.ensureExtracted(), p.textOptions.Whitespace.PageSeparator())
 }
 
-// GetPageText returns the text content of a specific page
+// GetPageText returns the extracted text of a specific page
 func (p *PDFDocument) GetPageText(pageNum int) (string, error) {
 	if pageNum < 1 || pageNum > len(p.doc.Pages) {
 		return "", fmt.Errorf("page number out of range: %d", pageNum)
 	}
-	return p.doc.Pages[pageNum-1].Text, nil
+	return p.pageText(pageNum), nil
 }
 
-// GetTextChunks returns the text chunks of the document
+// GetTextChunks returns the text of the chunks Chunks makes with DefaultChunkOptions
 func (p *PDFDocument) GetTextChunks() []string {
-	return p.doc.TextChunks
+	chunks, err := p.Chunks(DefaultChunkOptions())
+	if err != nil {
+		return nil
+	}
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Text
+	}
+	return texts
 }
 
-// SaveChunksToFile saves the text chunks to a file
+// SaveChunksToFile saves the text of GetTextChunks to a file, each chunk after a
+// "--- Chunk N ---" line.
+//
+// Deprecated: SaveChunksJSONL writes the chunks with their page numbers, offsets and source.
 func (p *PDFDocument) SaveChunksToFile(filename string) error {
-	return p.doc.SaveChunksToFile(filename)
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for i, chunk := range p.GetTextChunks() {
+		if _, err := fmt.Fprintf(file, "--- Chunk %d ---\n%s\n\n", i+1, chunk); err != nil {
+			return err
+		}
+	}
+	return file.Close()
 }
 
-// Metrics returns the document metrics
+// Metrics returns the document metrics, extracting the text to count its chunks and to record
+// what the extraction found, such as its quality and the image-only pages
 func (p *PDFDocument) Metrics() *metrics.PDFMetrics {
-	return p.doc.Metrics()
+	p.ensureExtracted()
+	m := p.doc.Metrics()
+	m.TextChunkCount = p.TextChunkCount()
+	return m
+}
+
+// DegradationReport returns how the document was parsed, including recovery paths taken
+// and, when ParseOptions.VerifyXRef is set, the comparison between the xref table and a full scan
+func (p *PDFDocument) DegradationReport() *DegradationReport {
+	return p.doc.DegradationReport()
+}
+
+// RecoveryReport returns what the parse repaired: the recovery heuristics that fired, the
+// objects only loaded thanks to them and the objects that were lost
+func (p *PDFDocument) RecoveryReport() *RecoveryReport {
+	return p.doc.RecoveryReport()
 }
 
 // ExtractTextContent extracts text from the document
 func (p *PDFDocument) ExtractTextContent() (string, error) {
-	return text.ExtractTextContent(p.doc)
+	return text.ExtractTextContentWithOptions(p.doc, p.textOptions)
+}
+
+// EachPageText extracts the text of the selected pages one at a time, in page order, passing
+// each to fn before moving on. Text positions are released after each page, so long documents
+// can be processed without holding those of every page. It returns the first error from fn.
+func (p *PDFDocument) EachPageText(fn func(pageNum int, text string) error) error {
+	extractor := text.NewDocumentExtractor(p.doc, p.textOptions)
+	defer p.resetExtraction()
+	return extractor.EachPage(func(pageNum int, text string) error {
+		if !p.doc.PageSelected(pageNum) {
+			return nil
+		}
+		return fn(pageNum, text)
+	})
+}
+
+// ExtractTextTo writes the text of the selected pages to w as each is extracted, separated as
+// in ExtractTextContent. Running lines are kept, as recognising them needs every
+// page at once.
+func (p *PDFDocument) ExtractTextTo(w io.Writer) error {
+	first := true
+	return p.EachPageText(func(pageNum int, text string) error {
+		if !first {
+			if _, err := io.WriteString(w, p.textOptions.Whitespace.PageSeparator()); err != nil {
+				return err
+			}
+		}
+		first = false
+		_, err := io.WriteString(w, text)
+		return err
+	})
+}
+
+// ExtractPageTexts extracts the text of each page, in page order
+func (p *PDFDocument) ExtractPageTexts() []string {
+	return slices.Clone(p.ensureExtracted())
+}
+
+// ensureExtracted extracts the whole document once, filling in the text positions of each
+// page, and returns the text of each page. Methods that extract the pages with other options
+// call resetExtraction after.
+func (p *PDFDocument) ensureExtracted() []string {
+	if p.pageTexts == nil {
+		extractor := text.NewDocumentExtractor(p.doc, p.textOptions)
+		p.pageTexts = extractor.ExtractText()
+		text.RecordExtractionMetrics(p.doc, extractor, p.pageTexts)
+		if p.pageTexts == nil {
+			p.pageTexts = []string{}
+		}
+	}
+	return p.pageTexts
+}
+
+// pageText returns the extracted text of a page (1-based, already validated), formatted by the
+// text options. Running lines are only recognisable by comparing pages, so stripping them
+// extracts the whole document.
+func (p *PDFDocument) pageText(pageNum int) string {
+	if p.pageTexts == nil && !p.textOptions.StripRunningLines {
+		return text.NewDocumentExtractor(p.doc, p.textOptions).ExtractPage(pageNum)
+	}
+	return p.ensureExtracted()[pageNum-1]
+}
+
+// resetExtraction records that the text positions of the pages no longer come from extraction
+// with the document's options
+func (p *PDFDocument) resetExtraction() {
+	p.pageTexts = nil
+}
+
+// extractPage extracts the text of a single page (1-based, already validated) and returns the
+// page with its text positions filled in
+func (p *PDFDocument) extractPage(pageNum int) *document.PDFPage {
+	if p.pageTexts == nil {
+		extractor := text.NewDocumentExtractor(p.doc, p.textOptions)
+		extractor.ExtractPage(pageNum)
+	}
+	return &p.doc.Pages[pageNum-1]
+}
+
+// ExtractTextLayout extracts the text of the document as fixed-width pages that preserve
+// column alignment and indentation. Pages are separated by form feeds.
+func (p *PDFDocument) ExtractTextLayout() (string, error) {
+	p.ensureExtracted()
+
+	pages := make([]string, 0, len(p.doc.Pages))
+	for _, page := range p.doc.Pages {
+		pages = append(pages, text.LayoutText(page.TextPositions))
+	}
+
+	return strings.Join(pages, "\f"), nil
+}
+
+// ExtractPageTextLayout extracts the text of a specific page as a fixed-width layout
+func (p *PDFDocument) ExtractPageTextLayout(pageNum int) (string, error) {
+	if pageNum < 1 || pageNum > len(p.doc.Pages) {
+		return "", fmt.Errorf("page number out of range: %d", pageNum)
+	}
+
+	page := p.extractPage(pageNum)
+	return text.LayoutText(page.TextPositions), nil
 }
 
 // GetTextByPattern searches for text matching a pattern
@@ -191,45 +576,36 @@ func (p *PDFDocument) GetPageDimensions(pageNum int) (width, height float64, err
 	return page.Width, page.Height, nil
 }
 
-// GetMetadata returns the document metadata
+// GetMetadata returns the entries of the information dictionary, with the common fields
+// (Title, Author, Subject, Keywords, Creator, Producer, CreationDate and ModDate) taken from the
+// XMP metadata where it has them. Use Metadata for the typed XMP properties.
+//
+// Deprecated: DocumentInfo returns the same fields typed, with the dates parsed.
 func (p *PDFDocument) GetMetadata() map[string]string {
-	metadata := make(map[string]string)
-
-	// Try to find the info dictionary
-	if infoRef, ok := p.doc.Trailer["Info"]; ok {
-		objNum, err := utils.ExtractReference(infoRef.(string))
-		if err == nil {
-			if infoObj, ok := p.doc.Objects[objNum]; ok {
-				// Extract common metadata fields
-				commonFields := []string{"Title", "Author", "Subject", "Keywords", "Creator", "Producer", "CreationDate", "ModDate"}
-
-				for _, field := range commonFields {
-					if value, ok := infoObj.Dictionary[field]; ok {
-						metadata[field] = value.(string)
-					}
-				}
-
-				// Process any other fields in the info dictionary
-				for key, value := range infoObj.Dictionary {
-					if !contains(commonFields, key) {
-						metadata[key] = value.(string)
-					}
-				}
-			}
-		}
-	}
+	meta := p.Metadata()
 
-	return metadata
-}
+	metadata := make(map[string]string, len(meta.Info))
+	for key, value := range meta.Info {
+		metadata[key] = value
+	}
 
-// contains checks if a string is in a slice
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
+	common := map[string]string{
+		"Title":        meta.Title,
+		"Author":       meta.Author,
+		"Subject":      meta.Subject,
+		"Keywords":     meta.Keywords,
+		"Creator":      meta.Creator,
+		"Producer":     meta.Producer,
+		"CreationDate": meta.CreationDate,
+		"ModDate":      meta.ModDate,
+	}
+	for key, value := range common {
+		if value != "" {
+			metadata[key] = value
 		}
 	}
-	return false
+
+	return metadata
 }
 
 // Close releases any resources associated with the document
@@ -242,61 +618,79 @@ func (p *PDFDocument) Close() error {
 type PDFInfo struct {
 	Filename  string
 	FileSize  int64
-	PageCount int
+	PageCount int // -1 if unknown
 	Version   string
+	Encrypted bool
+	Producer  string // Empty for encrypted documents, whose strings can't be read
 	ParseTime time.Duration
 }
 
-// GetPDFInfo returns basic information about a PDF file without fully parsing it
+// GetPDFInfo returns basic information about a PDF file without fully parsing it: only the
+// header, the cross-reference sections, the trailer, the catalog, the page tree root and the
+// information dictionary are read, so it takes milliseconds even on huge files. Files whose
+// cross-reference table can't be followed are parsed in full instead.
 func GetPDFInfo(filename string) (*PDFInfo, error) {
 	startTime := time.Now()
 
-	// Open the file
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %v", err)
+		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
-
-	// Get file size
 	fileInfo, err := file.Stat()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get file info: %v", err)
+		return nil, fmt.Errorf("failed to get file info: %w", err)
 	}
-	fileSize := fileInfo.Size()
 
-	// Check PDF header
-	header := make([]byte, 8)
-	_, err = file.Read(header)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read header: %v", err)
-	}
+	return readPDFInfo(file, fileInfo.Size(), filename, startTime, func() (*PDFDocument, error) {
+		return ParsePDF(filename)
+	})
+}
 
-	if !strings.HasPrefix(string(header), "%PDF-") {
-		return nil, fmt.Errorf("not a PDF file")
-	}
+// GetPDFInfoFromReader returns what GetPDFInfo does for a PDF read from r, such as standard
+// input. Objects are located by their offsets, so the whole document is read first.
+func GetPDFInfoFromReader(r io.Reader, name string) (*PDFInfo, error) {
+	startTime := time.Now()
 
-	// Extract PDF version
-	version := string(header[5:])
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
 
-	// Try to get page count without fully parsing
-	pageCount := -1 // -1 means unknown
+	return readPDFInfo(bytes.NewReader(data), int64(len(data)), name, startTime, func() (*PDFDocument, error) {
+		return parseOwnedBytes(data, name, DefaultParseOptions())
+	})
+}
 
-	// First try to get the root catalog
-	doc, err := ParsePDF(filename)
+// readPDFInfo reads the information GetPDFInfo returns from the size bytes of file, calling
+// parse for the document parsed in full if the cross-reference table can't be followed
+func readPDFInfo(file io.ReaderAt, size int64, name string, startTime time.Time, parse func() (*PDFDocument, error)) (*PDFInfo, error) {
+	info := &PDFInfo{Filename: name, FileSize: size, PageCount: -1}
+	quick, err := document.ReadQuickInfoFrom(file, size, name, nil)
+	if errors.Is(err, document.ErrNotPDF) {
+		return nil, err
+	}
 	if err == nil {
-		pageCount = doc.PageCount()
+		info.PageCount = quick.PageCount
+		info.Version = quick.Version
+		info.Encrypted = quick.Encrypted
+		info.Producer = quick.Producer
+	} else {
+		utils.LogInfof("Fast info failed, parsing %s in full: %v", name, err)
+		doc, parseErr := parse()
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to read PDF info: %w", parseErr)
+		}
+		info.PageCount = doc.PageCount()
+		info.Version = doc.Version()
+		info.Encrypted = doc.Encrypted()
+		if !info.Encrypted {
+			info.Producer = doc.doc.InfoDictionary()["Producer"]
+		}
 	}
 
-	parseTime := time.Since(startTime)
-
-	return &PDFInfo{
-		Filename:  filename,
-		FileSize:  fileSize,
-		PageCount: pageCount,
-		Version:   version,
-		ParseTime: parseTime,
-	}, nil
+	info.ParseTime = time.Since(startTime)
+	return info, nil
 }
 
 // CreatePDFMetricsCollection creates a metrics collection from multiple PDF files
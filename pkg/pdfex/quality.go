@@ -0,0 +1,27 @@
+package pdfex
+
+import "github.com/yourusername/pdfex/internal/text"
+
+// PageQuality scores how plausible the extracted text of a page is
+type PageQuality struct {
+	Page    int     `json:"page"`    // 1-based page number
+	Score   float64 `json:"score"`   // From 0 to 1, the fraction of characters that aren't suspect
+	Garbled bool    `json:"garbled"` // The page has enough text to judge and scores below 0.95
+}
+
+// TextQuality scores the extracted text of the selected pages, flagging those that look like
+// mojibake: control, replacement and private-use characters, or UTF-8 sequences shown as
+// single-byte text, which wrong ToUnicode maps produce. Set ParseOptions.RepairMojibake to
+// re-decode such pages when another decoding is clearly more plausible.
+func (p *PDFDocument) TextQuality() []PageQuality {
+	texts := p.ExtractPageTexts()
+
+	quality := []PageQuality{}
+	for i, pageText := range texts {
+		if !p.doc.PageSelected(i + 1) {
+			continue
+		}
+		quality = append(quality, PageQuality{Page: i + 1, Score: text.TextQuality(pageText), Garbled: text.Garbled(pageText)})
+	}
+	return quality
+}
@@ -0,0 +1,20 @@
+package pdfex
+
+import "github.com/yourusername/pdfex/internal/document"
+
+// RecoveryReport describes what the parse repaired: the heuristics that fired, in order, the
+// objects that were only loaded thanks to them and the objects that could not be loaded
+type RecoveryReport = document.RecoveryReport
+
+// RecoveryStep is a recovery heuristic that fired while parsing
+type RecoveryStep = document.RecoveryStep
+
+// Recovery heuristics the parser can fall back to, as named by RecoveryStep.Heuristic
+const (
+	HeuristicNearbyXRef   = document.HeuristicNearbyXRef   // The xref table was searched for around a wrong startxref offset
+	HeuristicRebuildXRef  = document.HeuristicRebuildXRef  // The xref table was rebuilt by scanning the file for objects
+	HeuristicTrailerScan  = document.HeuristicTrailerScan  // The trailer was found by scanning back from the end of the file
+	HeuristicLinearParse  = document.HeuristicLinearParse  // No xref table was found; objects were parsed linearly
+	HeuristicStreamLength = document.HeuristicStreamLength // A stream's /Length didn't match its data; endstream delimited it
+	HeuristicCatalogScan  = document.HeuristicCatalogScan  // The trailer's /Root couldn't be loaded; an object of /Type /Catalog was used
+)
@@ -0,0 +1,40 @@
+package pdfex
+
+import "github.com/yourusername/pdfex/internal/text"
+
+// Reordering issues reported by CheckReordering
+const (
+	IssueBidi          = text.IssueBidi
+	IssueCombiningMark = text.IssueCombiningMark
+	IssuePreBaseVowel  = text.IssuePreBaseVowel
+)
+
+// ReorderIssue is a line whose extracted text is not in logical order, as happens with
+// right-to-left scripts, detached combining marks and Indic pre-base vowel signs
+type ReorderIssue struct {
+	Page   int      `json:"page"` // 1-based page number
+	Line   int      `json:"line"` // 1-based line number on the page
+	Before string   `json:"before"`
+	After  string   `json:"after"`
+	Issues []string `json:"issues"`
+}
+
+// CheckReordering audits the extracted text of each page and returns the lines that need
+// logical reordering, with the text as extracted and as it reads in logical order
+func (p *PDFDocument) CheckReordering() []ReorderIssue {
+	p.ensureExtracted()
+
+	var issues []ReorderIssue
+	for _, page := range p.doc.Pages {
+		for _, d := range text.DiagnoseReordering(page.TextPositions) {
+			issues = append(issues, ReorderIssue{
+				Page:   page.PageNumber,
+				Line:   d.Line,
+				Before: d.Before,
+				After:  d.After,
+				Issues: d.Issues,
+			})
+		}
+	}
+	return issues
+}
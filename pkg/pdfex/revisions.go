@@ -0,0 +1,75 @@
+package pdfex
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Revision is one version of an incrementally updated document. Revision N is the file up to
+// End; the bytes from Start to End are the update that revision N made to revision N-1.
+type Revision struct {
+	Number     int   `json:"number"` // 1 for the original document
+	Start      int64 `json:"start"`
+	End        int64 `json:"end"`
+	XRefOffset int64 `json:"xref_offset"` // Offset given by the revision's startxref, -1 if it has none
+	Objects    []int `json:"objects"`     // Objects the revision defines or redefines
+}
+
+// GetRevisions returns the revisions of the document, oldest first. A document that was never
+// updated has a single revision covering the whole file.
+func (p *PDFDocument) GetRevisions() ([]Revision, error) {
+	var found []document.Revision
+	err := p.withSource(func(src io.ReaderAt, size int64) error {
+		var err error
+		found, err = document.FindRevisions(src, size)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find revisions: %w", err)
+	}
+
+	revisions := make([]Revision, len(found))
+	for i, r := range found {
+		revisions[i] = Revision{
+			Number:     i + 1,
+			Start:      r.Start,
+			End:        r.End,
+			XRefOffset: r.XRefOffset,
+			Objects:    r.Objects,
+		}
+	}
+	return revisions, nil
+}
+
+// WriteRevision writes the file as it was at the given revision, numbered from 1, to w
+func (p *PDFDocument) WriteRevision(w io.Writer, number int) error {
+	revisions, err := p.GetRevisions()
+	if err != nil {
+		return err
+	}
+	if number < 1 || number > len(revisions) {
+		return fmt.Errorf("invalid revision: %d (document has %d)", number, len(revisions))
+	}
+
+	return p.withSource(func(src io.ReaderAt, size int64) error {
+		_, err := io.Copy(w, io.NewSectionReader(src, 0, revisions[number-1].End))
+		return err
+	})
+}
+
+// OpenRevision parses the document as it was at the given revision, numbered from 1, with the
+// specified options, or the defaults if nil
+func (p *PDFDocument) OpenRevision(number int, options *ParseOptions) (*PDFDocument, error) {
+	if options == nil {
+		options = DefaultParseOptions()
+	}
+
+	var data bytes.Buffer
+	if err := p.WriteRevision(&data, number); err != nil {
+		return nil, err
+	}
+	return parseOwnedBytes(data.Bytes(), fmt.Sprintf("%s#revision%d", p.source, number), options)
+}
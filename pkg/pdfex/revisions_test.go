@@ -0,0 +1,55 @@
+package pdfex
+
+import (
+	"strings"
+	"testing"
+)
+
+// incrementalFixture is a document with two revisions, its xref entries ended by space-LF as
+// most writers do. The update rewrites the first page's content and adds a second page.
+const incrementalFixture = "testdata/incremental.pdf"
+
+func TestOpenRevision(t *testing.T) {
+	p, err := ParsePDF(incrementalFixture)
+	if err != nil {
+		t.Fatalf("ParsePDF: %v", err)
+	}
+	revisions, err := p.GetRevisions()
+	if err != nil {
+		t.Fatalf("GetRevisions: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(revisions))
+	}
+
+	tests := []struct {
+		revision int
+		pages    []string // Text expected on each page
+	}{
+		{1, []string{"Original first page"}},
+		{2, []string{"Updated first page", "Added second page"}},
+	}
+	for _, tt := range tests {
+		revision, err := p.OpenRevision(tt.revision, nil)
+		if err != nil {
+			t.Fatalf("OpenRevision(%d): %v", tt.revision, err)
+		}
+		if got := revision.PageCount(); got != len(tt.pages) {
+			t.Fatalf("revision %d: got %d pages, want %d", tt.revision, got, len(tt.pages))
+		}
+		for i, want := range tt.pages {
+			text, err := revision.GetPageText(i + 1)
+			if err != nil {
+				t.Fatalf("revision %d: GetPageText(%d): %v", tt.revision, i+1, err)
+			}
+			if !strings.Contains(text, want) {
+				t.Errorf("revision %d: page %d text %q doesn't contain %q", tt.revision, i+1, text, want)
+			}
+		}
+	}
+
+	// The latest revision is the document itself
+	if got := p.PageCount(); got != 2 {
+		t.Errorf("document: got %d pages, want 2", got)
+	}
+}
@@ -0,0 +1,25 @@
+package pdfex
+
+import "github.com/yourusername/pdfex/internal/text"
+
+// RunningLine is a running header, footer or bare page number found on a page
+type RunningLine struct {
+	Page int    `json:"page"` // 1-based page number
+	Text string `json:"text"`
+}
+
+// RunningLines finds the headers, footers and page numbers repeated across the pages of the
+// document, in page order. Set ParseOptions.StripRunningLines to remove them from the text.
+func (p *PDFDocument) RunningLines() []RunningLine {
+	// Analyze every line, including any that extraction would strip
+	options := p.textOptions
+	options.StripRunningLines = false
+	text.NewDocumentExtractor(p.doc, options).ExtractText()
+	p.resetExtraction()
+
+	var lines []RunningLine
+	for _, line := range text.FindRunningLines(p.doc.Pages) {
+		lines = append(lines, RunningLine{Page: line.Page, Text: line.Text})
+	}
+	return lines
+}
@@ -0,0 +1,43 @@
+package pdfex
+
+import "github.com/yourusername/pdfex/internal/document"
+
+// UnsafeContent describes an object holding JavaScript, an embedded file or an external action
+type UnsafeContent struct {
+	ObjectNumber int    `json:"object_number"`
+	Kind         string `json:"kind"`             // JavaScript, EmbeddedFile, or an action type such as Launch or URI
+	Detail       string `json:"detail,omitempty"` // Target of the action, if known
+}
+
+// SanitizeReport lists what sanitizing the document would remove
+type SanitizeReport struct {
+	Unsafe       []UnsafeContent `json:"unsafe"`
+	Unreferenced []int           `json:"unreferenced"` // Objects not reachable from the trailer
+}
+
+// Clean reports whether there is nothing to remove
+func (r *SanitizeReport) Clean() bool {
+	return len(r.Unsafe) == 0 && len(r.Unreferenced) == 0
+}
+
+// SanitizeReport finds the active content, embedded files, external actions and unreferenced
+// objects that a sanitized copy of the document should drop
+func (p *PDFDocument) SanitizeReport() *SanitizeReport {
+	report := &SanitizeReport{
+		Unsafe:       []UnsafeContent{},
+		Unreferenced: p.doc.UnreferencedObjects(),
+	}
+	if report.Unreferenced == nil {
+		report.Unreferenced = []int{}
+	}
+
+	for _, item := range p.doc.FindUnsafeContent() {
+		report.Unsafe = append(report.Unsafe, newUnsafeContent(item))
+	}
+	return report
+}
+
+// newUnsafeContent converts a finding into its public representation
+func newUnsafeContent(item document.UnsafeContent) UnsafeContent {
+	return UnsafeContent{ObjectNumber: item.ObjectNumber, Kind: item.Kind, Detail: item.Detail}
+}
@@ -0,0 +1,49 @@
+package pdfex
+
+import "github.com/yourusername/pdfex/internal/text"
+
+// PageClassification tells whether a page is a scanned image without a text layer
+type PageClassification struct {
+	Page          int     `json:"page"`           // 1-based page number
+	Characters    int     `json:"characters"`     // Glyphs shown by text operators, including invisible OCR text layers
+	ImageCoverage float64 `json:"image_coverage"` // Fraction of the page covered by DCT, CCITT fax, JBIG2 or JPX images
+	ImageOnly     bool    `json:"image_only"`     // A scanned image with almost no extractable text, which needs OCR
+}
+
+// ClassifyPages classifies the selected pages as scanned images or text pages, so that
+// pipelines can route the image-only ones to OCR. A page is image-only when scan-compressed
+// images cover at least half of it and it shows next to no glyphs.
+func (p *PDFDocument) ClassifyPages() []PageClassification {
+	// Extraction fills in the glyph counts of each page; OCR isn't needed for them
+	options := p.textOptions
+	options.OCR = nil
+	text.NewDocumentExtractor(p.doc, options).ExtractText()
+	p.resetExtraction()
+
+	classes := []PageClassification{}
+	for i := range p.doc.Pages {
+		page := &p.doc.Pages[i]
+		if !p.doc.PageSelected(page.PageNumber) {
+			continue
+		}
+		scan := text.ClassifyScan(p.doc, page)
+		classes = append(classes, PageClassification{
+			Page:          page.PageNumber,
+			Characters:    scan.Characters,
+			ImageCoverage: scan.ImageCoverage,
+			ImageOnly:     scan.ImageOnly(),
+		})
+	}
+	return classes
+}
+
+// NeedsOCR reports whether any selected page is a scanned image without a text layer. The
+// pages are listed by ClassifyPages and, once the text is extracted, in Metrics().ImageOnlyPages.
+func (p *PDFDocument) NeedsOCR() bool {
+	for _, class := range p.ClassifyPages() {
+		if class.ImageOnly {
+			return true
+		}
+	}
+	return false
+}
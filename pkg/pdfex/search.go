@@ -0,0 +1,130 @@
+package pdfex
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// matchContextChars is the number of characters of context kept on each side of a match
+const matchContextChars = 40
+
+// TextMatch is a match of a search pattern, located on its page
+type TextMatch struct {
+	Page   int    `json:"page"`   // 1-based page number
+	Offset int    `json:"offset"` // Character offset of the match within the page text
+	Line   int    `json:"line"`   // 1-based line of the page text the match starts on
+	Text   string `json:"text"`
+	Before string `json:"before"` // Text preceding the match on the same page
+	After  string `json:"after"`  // Text following the match on the same page
+	Rects  []Rect `json:"rects"`  // One rectangle per line, in annotation (unscaled user space) units
+}
+
+// PageText is the text of a page as FindText searches it, with a line for each line of the page
+type PageText struct {
+	Page int    `json:"page"` // 1-based page number
+	Text string `json:"text"`
+}
+
+// SearchText returns the positioned text of each page that FindText searches, so that it can be
+// stored and searched again with FindTextIn without parsing the document
+func (p *PDFDocument) SearchText() []PageText {
+	p.ensureExtracted()
+
+	pages := make([]PageText, 0, len(p.doc.Pages))
+	for _, page := range p.doc.Pages {
+		pages = append(pages, PageText{Page: page.PageNumber, Text: text.NewPageIndex(page.TextPositions).Text})
+	}
+	return pages
+}
+
+// FindText searches the positioned text of each page for a regular expression and returns every
+// match with its page, offset, surrounding context and coordinates
+func (p *PDFDocument) FindText(pattern string) ([]TextMatch, error) {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %v", err)
+	}
+
+	p.ensureExtracted()
+
+	var matches []TextMatch
+	for _, page := range p.doc.Pages {
+		index := text.NewPageIndex(page.TextPositions)
+		for _, match := range regex.FindAllStringIndex(index.Text, -1) {
+			start, end := match[0], match[1]
+			boxes := index.MatchBoxes(start, end)
+			if len(boxes) == 0 {
+				continue
+			}
+
+			rects := make([]Rect, 0, len(boxes))
+			for _, box := range boxes {
+				rects = append(rects, annotationRect(box, &page))
+			}
+
+			textMatch := newTextMatch(page.PageNumber, index.Text, start, end)
+			textMatch.Rects = rects
+			matches = append(matches, textMatch)
+		}
+	}
+
+	return matches, nil
+}
+
+// FindTextIn searches page text returned by SearchText like FindText searches the document.
+// The matches have no coordinates, as the text keeps no positions.
+func FindTextIn(pages []PageText, pattern string) ([]TextMatch, error) {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %v", err)
+	}
+
+	var matches []TextMatch
+	for _, page := range pages {
+		for _, match := range regex.FindAllStringIndex(page.Text, -1) {
+			if match[0] == match[1] {
+				// FindText skips empty matches, which cover no characters
+				continue
+			}
+			matches = append(matches, newTextMatch(page.Page, page.Text, match[0], match[1]))
+		}
+	}
+
+	return matches, nil
+}
+
+// newTextMatch returns the match between two byte offsets of a page's text, without coordinates
+func newTextMatch(pageNum int, pageText string, start, end int) TextMatch {
+	return TextMatch{
+		Page:   pageNum,
+		Offset: utf8.RuneCountInString(pageText[:start]),
+		Line:   strings.Count(pageText[:start], "\n") + 1,
+		Text:   pageText[start:end],
+		Before: lastChars(pageText[:start], matchContextChars),
+		After:  firstChars(pageText[end:], matchContextChars),
+	}
+}
+
+// lastChars returns the last n characters of s
+func lastChars(s string, n int) string {
+	i := len(s)
+	for ; n > 0 && i > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(s[:i])
+		i -= size
+	}
+	return s[i:]
+}
+
+// firstChars returns the first n characters of s
+func firstChars(s string, n int) string {
+	i := 0
+	for ; n > 0 && i < len(s); n-- {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return s[:i]
+}
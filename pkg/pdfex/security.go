@@ -0,0 +1,60 @@
+package pdfex
+
+import "github.com/yourusername/pdfex/internal/document"
+
+// Kinds of security indicators
+const (
+	IndicatorJavaScript         = document.IndicatorJavaScript         // /JavaScript or /JS entries
+	IndicatorLaunch             = document.IndicatorLaunch             // /Launch action running a program or opening a file
+	IndicatorSubmitForm         = document.IndicatorSubmitForm         // /SubmitForm action sending form data out
+	IndicatorOpenAction         = document.IndicatorOpenAction         // Action run when the document is opened
+	IndicatorAdditionalActions  = document.IndicatorAdditionalActions  // /AA actions triggered by events such as page open
+	IndicatorEmbeddedExecutable = document.IndicatorEmbeddedExecutable // Embedded file that is a program or script
+	IndicatorSuspiciousURI      = document.IndicatorSuspiciousURI      // URI action to a scheme other than web, mail or phone
+)
+
+// Risk levels of security indicators
+const (
+	RiskHigh   = document.RiskHigh   // Runs code or reaches outside the document without the user's intent
+	RiskMedium = document.RiskMedium // Triggers automatically or sends data out, but is common in benign files
+)
+
+// SecurityIndicator is a risky construct found in an object; dump the object for follow-up
+type SecurityIndicator struct {
+	ObjectNumber int    `json:"object_number"`
+	Kind         string `json:"kind"`             // One of the Indicator constants
+	Risk         string `json:"risk"`             // RiskHigh or RiskMedium
+	Detail       string `json:"detail,omitempty"` // Target, trigger or file name, if known
+}
+
+// SecurityReport lists the risky constructs of a document
+type SecurityReport struct {
+	Indicators []SecurityIndicator `json:"indicators"`
+}
+
+// Clean reports whether no indicator was found
+func (r *SecurityReport) Clean() bool {
+	return len(r.Indicators) == 0
+}
+
+// HighRisk reports whether any indicator is of high risk
+func (r *SecurityReport) HighRisk() bool {
+	for _, indicator := range r.Indicators {
+		if indicator.Risk == RiskHigh {
+			return true
+		}
+	}
+	return false
+}
+
+// SecurityReport flags the risky constructs of the document, for triage of untrusted files:
+// JavaScript, launch and submit-form actions, the open action, additional actions, embedded
+// executables and URI actions to suspicious schemes such as file: or javascript:. Indicators
+// are ordered by object number, which ObjectData and "pdfex dump -obj" take for follow-up.
+func (p *PDFDocument) SecurityReport() *SecurityReport {
+	report := &SecurityReport{Indicators: []SecurityIndicator{}}
+	for _, item := range p.doc.SecurityReport() {
+		report.Indicators = append(report.Indicators, SecurityIndicator{ObjectNumber: item.ObjectNumber, Kind: item.Kind, Risk: item.Risk, Detail: item.Detail})
+	}
+	return report
+}
@@ -0,0 +1,32 @@
+package pdfex
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/yourusername/pdfex/internal/metrics"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// WriteServiceMetrics writes the metrics accumulated over the life of the process in the
+// Prometheus text exposition format: documents parsed by result, the parse duration histogram,
+// pages processed, filter failures by filter and bytes decompressed
+func WriteServiceMetrics(w io.Writer) error {
+	return metrics.Service.WritePrometheus(w)
+}
+
+// ReadServiceMetrics returns a copy of the metrics accumulated over the life of the process,
+// for exporting them through a metrics library such as the Prometheus client
+func ReadServiceMetrics() metrics.ServiceSnapshot {
+	return metrics.Service.Snapshot()
+}
+
+// ServiceMetricsHandler returns an HTTP handler that serves the service metrics to Prometheus
+func ServiceMetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := WriteServiceMetrics(w); err != nil {
+			utils.Logf(utils.LogWarning, "Failed to write service metrics: %v\n", err)
+		}
+	})
+}
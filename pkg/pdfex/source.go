@@ -0,0 +1,41 @@
+package pdfex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrNoSource is returned by the methods that read the document's data again, such as
+// Validate, GetRevisions and raw stream access, when the data it was parsed from is no longer
+// available: its file was removed or changed, or the document wasn't parsed by this package
+var ErrNoSource = errors.New("source data of the document is not available")
+
+// withSource calls fn with the data the document was parsed from and its size: the bytes kept
+// by ParsePDFFromBytes and ParsePDFFromReader, or the file given to ParsePDF, which must not
+// have changed size since. The source name is only a label and is never opened.
+func (p *PDFDocument) withSource(fn func(src io.ReaderAt, size int64) error) error {
+	if p.sourceData != nil {
+		return fn(bytes.NewReader(p.sourceData), int64(len(p.sourceData)))
+	}
+	if p.sourcePath == "" {
+		return ErrNoSource
+	}
+
+	file, err := os.Open(p.sourcePath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNoSource, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNoSource, err)
+	}
+	if info.Size() != p.sourceSize {
+		return fmt.Errorf("%w: %s has changed since it was parsed", ErrNoSource, p.sourcePath)
+	}
+	return fn(file, info.Size())
+}
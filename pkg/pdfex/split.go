@@ -0,0 +1,98 @@
+package pdfex
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// Section is a part of the document delimited by outline (bookmark) entries, typically a chapter
+type Section struct {
+	Title     string `json:"title"`
+	Level     int    `json:"level"`
+	StartPage int    `json:"start_page"`
+	EndPage   int    `json:"end_page"`
+	Text      string `json:"text"`
+}
+
+// SplitByOutline cuts the document into sections at the outline entries of the given level
+// (1 for top-level bookmarks). Pages before the first entry form an untitled leading section.
+// Documents without bookmarks are cut at the detected headings of that level or above instead,
+// where the heading starts rather than at page boundaries.
+func (p *PDFDocument) SplitByOutline(level int) ([]Section, error) {
+	if level < 1 {
+		return nil, fmt.Errorf("invalid outline level: %d", level)
+	}
+
+	outlineSections := p.doc.OutlineSections(level)
+	if len(outlineSections) == 0 {
+		return p.splitByHeadings(level)
+	}
+
+	pageTexts := p.ExtractPageTexts()
+
+	sections := make([]Section, 0, len(outlineSections))
+	for _, s := range outlineSections {
+		var sectionText strings.Builder
+		for pageNum := s.StartPage; pageNum <= s.EndPage; pageNum++ {
+			if sectionText.Len() > 0 {
+				sectionText.WriteString("\n\n")
+			}
+			sectionText.WriteString(pageTexts[pageNum-1])
+		}
+
+		sections = append(sections, Section{
+			Title:     s.Title,
+			Level:     s.Level,
+			StartPage: s.StartPage,
+			EndPage:   s.EndPage,
+			Text:      sectionText.String(),
+		})
+	}
+
+	return sections, nil
+}
+
+// splitByHeadings cuts the document text into sections at the detected headings of the given
+// level or above. Text before the first heading forms an untitled leading section.
+func (p *PDFDocument) splitByHeadings(level int) ([]Section, error) {
+	texts := p.ExtractPageTexts()
+	source, pageStarts := documentText(texts)
+
+	var headings []chunkHeading
+	for _, h := range locateHeadings(text.DetectHeadings(p.doc.Pages, p.doc.Fonts), texts, pageStarts) {
+		if h.level <= level {
+			headings = append(headings, h)
+		}
+	}
+	if len(headings) == 0 {
+		return nil, fmt.Errorf("document has no outline entries with resolvable pages or detected headings")
+	}
+	if headings[0].offset > 0 {
+		headings = append([]chunkHeading{{level: level}}, headings...)
+	}
+
+	var sections []Section
+	for i, h := range headings {
+		end := len(source)
+		if i+1 < len(headings) {
+			end = headings[i+1].offset
+		}
+		raw := source[h.offset:end]
+		first := h.offset + len(raw) - len(strings.TrimLeft(raw, " \t\r\n"))
+		last := h.offset + len(strings.TrimRight(raw, " \t\r\n"))
+		if first >= last {
+			continue
+		}
+
+		sections = append(sections, Section{
+			Title:     h.text,
+			Level:     h.level,
+			StartPage: pageAtOffset(pageStarts, first),
+			EndPage:   pageAtOffset(pageStarts, last-1),
+			Text:      source[first:last],
+		})
+	}
+	return sections, nil
+}
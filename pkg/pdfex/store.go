@@ -0,0 +1,339 @@
+package pdfex
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OutputStore is a destination for extraction artifacts, such as a local directory or an
+// object storage bucket. Names are slash-separated paths relative to the store's root.
+type OutputStore interface {
+	// Put writes the contents of r under name, replacing any existing artifact
+	Put(name string, r io.Reader) error
+	// Exists reports whether an artifact with the given name is already stored
+	Exists(name string) (bool, error)
+	// Location returns a human-readable location for name, for messages
+	Location(name string) string
+}
+
+// PutIfAbsent writes the artifact unless one with the same name already exists, so interrupted
+// batch runs can be resumed. It reports whether the artifact was written.
+func PutIfAbsent(store OutputStore, name string, r io.Reader) (bool, error) {
+	exists, err := store.Exists(name)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+	return true, store.Put(name, r)
+}
+
+// OpenOutputStore returns the store for an output location. Locations of the form
+// s3://bucket/prefix use an S3Store configured from the environment; anything else is
+// a local directory.
+func OpenOutputStore(location string) (OutputStore, error) {
+	if strings.HasPrefix(location, "s3://") {
+		return NewS3StoreFromEnv(location)
+	}
+	return NewLocalStore(location)
+}
+
+// LocalStore writes artifacts to a directory on the local file system
+type LocalStore struct {
+	Dir string
+}
+
+// NewLocalStore creates a store that writes to dir, creating the directory if needed
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %v", err)
+	}
+	return &LocalStore{Dir: dir}, nil
+}
+
+// Put writes the artifact to a file, creating intermediate directories. The data is written to
+// a temporary file first so that readers never see a partial artifact.
+func (s *LocalStore) Put(name string, r io.Reader) error {
+	path := s.path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %v", name, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".pdfex-*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", path, err)
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// Exists reports whether the artifact's file exists
+func (s *LocalStore) Exists(name string) (bool, error) {
+	_, err := os.Stat(s.path(name))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// Location returns the file path of the artifact
+func (s *LocalStore) Location(name string) string {
+	return s.path(name)
+}
+
+// path returns the file path of an artifact name
+func (s *LocalStore) path(name string) string {
+	return filepath.Join(s.Dir, filepath.FromSlash(name))
+}
+
+// S3Store writes artifacts to an S3-compatible object storage bucket, signing requests with
+// AWS Signature Version 4
+type S3Store struct {
+	Endpoint     string // Service URL; empty means AWS S3 in Region
+	Region       string
+	Bucket       string
+	Prefix       string // Key prefix for all artifacts, without a trailing slash
+	AccessKey    string
+	SecretKey    string
+	SessionToken string // Optional, for temporary credentials
+	PathStyle    bool   // Address the bucket in the path rather than the host name
+	Client       *http.Client
+}
+
+// NewS3StoreFromEnv creates a store for an s3://bucket/prefix location using the standard
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN and AWS_REGION variables.
+// AWS_ENDPOINT_URL selects an S3-compatible service, which is addressed path-style.
+func NewS3StoreFromEnv(location string) (*S3Store, error) {
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(location, "s3://"), "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("invalid S3 location %q, expected s3://bucket/prefix", location)
+	}
+
+	store := &S3Store{
+		Endpoint:     os.Getenv("AWS_ENDPOINT_URL"),
+		Region:       os.Getenv("AWS_REGION"),
+		Bucket:       bucket,
+		Prefix:       strings.Trim(prefix, "/"),
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if store.Region == "" {
+		store.Region = "us-east-1"
+	}
+	if store.AccessKey == "" || store.SecretKey == "" {
+		return nil, fmt.Errorf("S3 credentials not set: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required")
+	}
+	store.PathStyle = store.Endpoint != ""
+
+	return store, nil
+}
+
+// Put uploads the artifact as an object
+func (s *S3Store) Put(name string, r io.Reader) error {
+	// The payload is buffered because the signature covers its hash
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", name, err)
+	}
+
+	resp, err := s.do(http.MethodPut, name, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to upload %s: %s: %s", s.Location(name), resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Exists reports whether the object exists, using a HEAD request
+func (s *S3Store) Exists(name string) (bool, error) {
+	resp, err := s.do(http.MethodHead, name, nil)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to check %s: %s", s.Location(name), resp.Status)
+	}
+}
+
+// Location returns the s3:// URL of the artifact
+func (s *S3Store) Location(name string) string {
+	return "s3://" + s.Bucket + "/" + s.key(name)
+}
+
+// key returns the object key of an artifact name
+func (s *S3Store) key(name string) string {
+	if s.Prefix == "" {
+		return name
+	}
+	return s.Prefix + "/" + name
+}
+
+// objectURL returns the URL of the object holding an artifact
+func (s *S3Store) objectURL(name string) (*url.URL, error) {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + s.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint %q: %v", endpoint, err)
+	}
+
+	path := "/" + s.key(name)
+	if s.PathStyle {
+		path = "/" + s.Bucket + path
+	} else {
+		u.Host = s.Bucket + "." + u.Host
+	}
+	u.Path = path
+	u.RawPath = s3EscapePath(path)
+
+	return u, nil
+}
+
+// do sends a signed request for an object
+func (s *S3Store) do(method, name string, payload []byte) (*http.Response, error) {
+	u, err := s.objectURL(name)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if payload != nil {
+		req.ContentLength = int64(len(payload))
+	}
+	s.sign(req, payload, time.Now().UTC())
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("S3 request failed: %v", err)
+	}
+	return resp, nil
+}
+
+// sign adds an AWS Signature Version 4 authorization header to the request
+func (s *S3Store) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	// Signed headers must be lower case and sorted
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := []string{req.URL.Host, payloadHash, amzDate}
+	if s.SessionToken != "" {
+		headers = append(headers, "x-amz-security-token")
+		values = append(values, s.SessionToken)
+	}
+
+	var canonicalHeaders strings.Builder
+	for i, header := range headers {
+		canonicalHeaders.WriteString(header + ":" + values[i] + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signedHeaders, signature))
+}
+
+// s3EscapePath percent-encodes a path the way Signature Version 4 expects, leaving only
+// unreserved characters and slashes as they are
+func s3EscapePath(path string) string {
+	var sb strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			sb.WriteByte(c)
+		} else {
+			sb.WriteString(fmt.Sprintf("%%%02X", c))
+		}
+	}
+	return sb.String()
+}
+
+// sha256Hex returns the hex-encoded SHA-256 hash of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data using key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
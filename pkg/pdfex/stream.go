@@ -0,0 +1,46 @@
+package pdfex
+
+import (
+	"context"
+)
+
+// PageResult is the extraction result of one page, as delivered by StreamPages
+type PageResult struct {
+	Page
+	Text   string     `json:"text"`
+	Blocks []Block    `json:"blocks"`
+	Spans  []TextSpan `json:"spans"`
+}
+
+// StreamPages extracts the selected pages one at a time, in order, calling fn with each page as
+// soon as it is extracted, so that callers can start on the first pages of a long document
+// while the rest are still being extracted. It stops at the first error returned by fn or when
+// ctx is done. With StripRunningLines every page is extracted before the first is delivered,
+// since running lines are only recognisable by comparing pages.
+func (p *PDFDocument) StreamPages(ctx context.Context, fn func(PageResult) error) error {
+	for i := range p.doc.Pages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page := &p.doc.Pages[i]
+		if !p.doc.PageSelected(page.PageNumber) {
+			continue
+		}
+
+		result := PageResult{
+			Page:   newPage(*page, p.generationOf(page.ObjectNumber)),
+			Blocks: []Block{},
+			Spans:  []TextSpan{},
+		}
+		result.Text = p.pageText(page.PageNumber)
+		result.Blocks = append(result.Blocks, pageBlocks(page)...)
+		for _, pos := range page.TextPositions {
+			result.Spans = append(result.Spans, newTextSpan(pos))
+		}
+
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package pdfex
+
+import "fmt"
+
+// Findings of Validate that fail strict mode although they are only warnings there
+var strictFindings = map[string]bool{
+	"length-mismatch": true,
+}
+
+// strictViolations returns why the document fails strict mode: the validation findings that
+// are errors, such as a missing /Root, a page without /Type /Page or a missing /Length, streams
+// whose /Length is wrong, the warnings from reading the xref table and object headers, and
+// those of SeverityError, such as pages that couldn't be found
+func (p *PDFDocument) strictViolations() ([]fmt.Stringer, error) {
+	findings, err := p.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []fmt.Stringer
+	for _, warning := range p.Warnings() {
+		if warning.Stage == "xref" || warning.Stage == "objects" || warning.Severity == SeverityError {
+			violations = append(violations, warning)
+		}
+	}
+	for _, finding := range findings {
+		if finding.Severity == SeverityError || strictFindings[finding.Code] {
+			violations = append(violations, finding)
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,116 @@
+package pdfex
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// StructElement is an element of the logical structure tree of a tagged PDF
+type StructElement struct {
+	Type       string          `json:"type"`           // Standard structure type, e.g. P, H1, Table, TD or Figure
+	Role       string          `json:"role,omitempty"` // Custom type as written, when role-mapped to Type
+	Page       int             `json:"page,omitempty"` // 1-based page of the element's first content
+	Title      string          `json:"title,omitempty"`
+	Lang       string          `json:"lang,omitempty"`
+	Alt        string          `json:"alt,omitempty"` // Alternate description, e.g. of a figure
+	ActualText string          `json:"actual_text,omitempty"`
+	Children   []StructElement `json:"children,omitempty"`
+}
+
+// TaggedBlock is a block of text in logical order, with the structure type it is tagged as
+type TaggedBlock struct {
+	Type string `json:"type"`           // Standard structure type, e.g. P, H1, LI, TD or Figure
+	Role string `json:"role,omitempty"` // Custom type as written, when role-mapped to Type
+	Page int    `json:"page"`           // 1-based page of the block's first content
+	Lang string `json:"lang,omitempty"`
+	Text string `json:"text"` // Content text; the alternate description for figures and formulas
+}
+
+// GetStructureTree returns the structure tree of a tagged PDF, or nil if the document is not
+// tagged
+func (p *PDFDocument) GetStructureTree() []StructElement {
+	return newStructElements(p.doc.GetStructureTree())
+}
+
+// TaggedBlocks returns the text of a tagged PDF as blocks in the logical order of its structure
+// tree, which is the reading order the author intended, rather than one inferred from the page
+// layout. It fails if the document is not tagged. Text drawn by form XObjects belongs to the
+// marked content that draws the form; marked content inside forms is not read.
+func (p *PDFDocument) TaggedBlocks() ([]TaggedBlock, error) {
+	elements := p.doc.GetStructureTree()
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("document is not tagged")
+	}
+
+	p.ensureExtracted()
+
+	var blocks []TaggedBlock
+	for _, block := range text.TaggedBlocks(elements, p.doc.Pages) {
+		blocks = append(blocks, TaggedBlock{
+			Type: block.Type,
+			Role: block.Role,
+			Page: block.PageNumber,
+			Lang: block.Lang,
+			Text: block.Text,
+		})
+	}
+	return blocks, nil
+}
+
+// ExtractTaggedText extracts the text of a tagged PDF in logical order, one block per line
+func (p *PDFDocument) ExtractTaggedText() (string, error) {
+	blocks, err := p.TaggedBlocks()
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		lines = append(lines, block.Text)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// newStructElements converts internal structure elements into their public representation
+func newStructElements(elements []*document.StructElement) []StructElement {
+	if len(elements) == 0 {
+		return nil
+	}
+
+	result := make([]StructElement, 0, len(elements))
+	for _, elem := range elements {
+		var children []*document.StructElement
+		page := 0
+		for _, kid := range elem.Kids {
+			if kid.Element != nil {
+				children = append(children, kid.Element)
+			} else if page == 0 {
+				page = kid.PageNumber
+			}
+		}
+
+		entry := StructElement{
+			Type:       elem.Type,
+			Role:       elem.Role,
+			Page:       page,
+			Title:      elem.Title,
+			Lang:       elem.Lang,
+			Alt:        elem.Alt,
+			ActualText: elem.ActualText,
+			Children:   newStructElements(children),
+		}
+		if entry.Page == 0 {
+			for _, child := range entry.Children {
+				if child.Page != 0 {
+					entry.Page = child.Page
+					break
+				}
+			}
+		}
+		result = append(result, entry)
+	}
+	return result
+}
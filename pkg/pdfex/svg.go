@@ -0,0 +1,134 @@
+package pdfex
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// SVGOptions control how a page is exported as SVG
+type SVGOptions struct {
+	OmitText bool // Export only the vector paths, without the text spans
+}
+
+// WritePageSVG writes the vector content of a page (1-based) as an SVG document: the paths it
+// strokes and fills, and its text as positioned spans. The page is drawn as displayed, with its
+// /Rotate value applied, in points. Images, shadings, clipping and the paths of form XObjects
+// are not exported, and text is set in the viewer's fonts since glyph outlines are not extracted.
+func (p *PDFDocument) WritePageSVG(w io.Writer, pageNum int, options *SVGOptions) error {
+	if pageNum < 1 || pageNum > len(p.doc.Pages) {
+		return fmt.Errorf("page number out of range: %d", pageNum)
+	}
+	if options == nil {
+		options = &SVGOptions{}
+	}
+
+	page := p.extractPage(pageNum)
+	width, height := page.Width, page.Height
+	if page.Rotation == 90 || page.Rotation == 270 {
+		width, height = height, width
+	}
+
+	// display maps a user space point to SVG coordinates, which run down from the top left
+	display := func(pt [2]float64) (float64, float64) {
+		x, y := text.UprightPoint(pt[0], pt[1], page.Rotation, page.Width, page.Height)
+		return x, height - y
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%spt\" height=\"%spt\" viewBox=\"0 0 %s\">\n",
+		formatNumbers(width), formatNumbers(height), formatNumbers(width, height))
+
+	for _, path := range text.PagePaths(page) {
+		writeSVGPath(bw, path, display)
+	}
+
+	if !options.OmitText {
+		for _, pos := range page.TextPositions {
+			if strings.TrimSpace(pos.Text) == "" {
+				continue
+			}
+			writeSVGText(bw, pos, page, p.doc.Fonts, display)
+		}
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// writeSVGPath writes a painted path as an SVG path element
+func writeSVGPath(w *bufio.Writer, path text.Path, display func([2]float64) (float64, float64)) {
+	var d strings.Builder
+	for _, seg := range path.Segments {
+		if d.Len() > 0 {
+			d.WriteByte(' ')
+		}
+		d.WriteByte(seg.Op)
+		for _, pt := range seg.Points {
+			x, y := display(pt)
+			d.WriteString(" " + formatNumbers(x, y))
+		}
+	}
+
+	fill := "none"
+	if path.Fill {
+		fill = svgColor(path.FillColor)
+	}
+	fmt.Fprintf(w, "<path d=\"%s\" fill=\"%s\"", d.String(), fill)
+	if path.Fill && path.EvenOdd {
+		w.WriteString(" fill-rule=\"evenodd\"")
+	}
+	if path.Stroke {
+		fmt.Fprintf(w, " stroke=\"%s\" stroke-width=\"%s\"", svgColor(path.StrokeColor), formatNumbers(math.Max(path.LineWidth, 0.1)))
+		if len(path.Dash) > 0 {
+			fmt.Fprintf(w, " stroke-dasharray=\"%s\"", formatNumbers(path.Dash...))
+		}
+	}
+	w.WriteString("/>\n")
+}
+
+// writeSVGText writes a text position as an SVG text element on its baseline
+func writeSVGText(w *bufio.Writer, pos document.TextPosition, page *document.PDFPage, fonts map[string]document.PDFFont,
+	display func([2]float64) (float64, float64)) {
+	ux, uy := text.UserSpacePoint(pos.X, pos.Y, page.TextRotation, page.Width, page.Height)
+	x, y := display([2]float64{ux, uy})
+
+	// Angles run counterclockwise in PDF but clockwise in SVG
+	angle := math.Mod(pos.Angle+float64(page.TextRotation-page.Rotation), 360)
+
+	fmt.Fprintf(w, "<text x=\"%s\" y=\"%s\" font-size=\"%s\"", formatNumbers(x), formatNumbers(y), formatNumbers(pos.FontSize))
+	if family := svgFontFamily(fonts["/"+pos.FontName]); family != "" {
+		fmt.Fprintf(w, " font-family=\"%s\"", html.EscapeString(family))
+	}
+	if angle != 0 {
+		fmt.Fprintf(w, " transform=\"rotate(%s)\"", formatNumbers(-angle, x, y))
+	}
+	if pos.Width > 0 && !pos.Vertical {
+		// Fit the span to its extracted width, since the viewer's font has different metrics
+		fmt.Fprintf(w, " textLength=\"%s\" lengthAdjust=\"spacingAndGlyphs\"", formatNumbers(pos.Width))
+	}
+	fmt.Fprintf(w, ">%s</text>\n", html.EscapeString(pos.Text))
+}
+
+// svgFontFamily returns the font family for a font, without the subset tag of embedded subsets
+func svgFontFamily(font document.PDFFont) string {
+	name := strings.TrimPrefix(font.BaseFont, "/")
+	if i := strings.IndexByte(name, '+'); i == 6 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// svgColor formats an RGB colour as an SVG hex colour
+func svgColor(c text.Color) string {
+	channel := func(v float64) int {
+		return int(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", channel(c.R), channel(c.G), channel(c.B))
+}
@@ -0,0 +1,52 @@
+package pdfex
+
+import (
+	"fmt"
+
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// TableCell is a cell of a table found on a page
+type TableCell struct {
+	Text   string `json:"text"`
+	Bounds Rect   `json:"bounds"`
+}
+
+// Table is a table found on a page, with its cells in row-major order. Coordinates are on the
+// unrotated page, in points like any Rect.
+type Table struct {
+	Page   int           `json:"page"` // 1-based page number
+	Bounds Rect          `json:"bounds"`
+	Rows   [][]TableCell `json:"rows"`
+	Ruled  bool          `json:"ruled"` // Detected from drawn ruling lines rather than text alignment
+}
+
+// ExtractTables detects the tables on a page (1-based), top to bottom. Tables are found from the
+// ruling lines drawn on the page and, for unruled tables, from text lines whose cells align in columns.
+func (p *PDFDocument) ExtractTables(pageNum int) ([]Table, error) {
+	if pageNum < 1 || pageNum > len(p.doc.Pages) {
+		return nil, fmt.Errorf("page number out of range: %d", pageNum)
+	}
+
+	page := p.extractPage(pageNum)
+
+	detected := text.DetectTables(page)
+	tables := make([]Table, 0, len(detected))
+	for _, t := range detected {
+		table := Table{
+			Page:   pageNum,
+			Bounds: userSpaceRect(t.Bounds, page),
+			Ruled:  t.Ruled,
+		}
+		for _, row := range t.Rows {
+			cells := make([]TableCell, 0, len(row))
+			for _, cell := range row {
+				cells = append(cells, TableCell{Text: cell.Text, Bounds: userSpaceRect(cell.Bounds, page)})
+			}
+			table.Rows = append(table.Rows, cells)
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
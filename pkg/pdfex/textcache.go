@@ -0,0 +1,97 @@
+package pdfex
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// textCacheVersion names the layout of the cached text; it changes when extraction does, so
+// that text extracted by an older version is parsed again rather than reused
+const textCacheVersion = "v1"
+
+// TextCache is a persistent cache of the page text that FindTextIn searches, kept in a
+// directory and keyed by the SHA-256 of each file and the parse options that shape its text,
+// so that repeated searches over a corpus only parse the files that changed
+type TextCache struct {
+	store *LocalStore
+}
+
+// DefaultTextCacheDir returns the text cache directory in the user's cache directory, such as
+// ~/.cache/pdfex/text on Linux
+func DefaultTextCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pdfex", "text"), nil
+}
+
+// OpenTextCache returns the text cache kept in dir, creating the directory if needed
+func OpenTextCache(dir string) (*TextCache, error) {
+	store, err := NewLocalStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &TextCache{store: store}, nil
+}
+
+// Text returns the page text of a file, from the cache if the file was parsed before with
+// options that give the same text, or else by parsing it with options and adding its text to the
+// cache. Failing to store the text doesn't fail the call, as the cache only saves time.
+func (c *TextCache) Text(filename string, options *ParseOptions) ([]PageText, error) {
+	if options == nil {
+		options = DefaultParseOptions()
+	}
+	sourceSHA256, _, err := hashFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	name := c.entryName(sourceSHA256, options)
+
+	if pages, ok := c.get(name); ok {
+		return pages, nil
+	}
+
+	doc, err := ParsePDFWithOptions(filename, options)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+
+	pages := doc.SearchText()
+	if data, err := json.Marshal(pages); err == nil {
+		c.store.Put(name, bytes.NewReader(data))
+	}
+	return pages, nil
+}
+
+// get returns the cached page text stored under name, and whether there was any
+func (c *TextCache) get(name string) ([]PageText, bool) {
+	data, err := os.ReadFile(c.store.path(name))
+	if err != nil {
+		return nil, false
+	}
+	var pages []PageText
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return nil, false
+	}
+	return pages, true
+}
+
+// entryName returns the name of the cache entry of a file's text parsed with options. Entries
+// are spread over subdirectories by the first byte of the hash, like git's object store.
+func (c *TextCache) entryName(sourceSHA256 string, options *ParseOptions) string {
+	// Only the options that change the extracted text are part of the key
+	key := fmt.Sprintf("%q %d %d %t %t %t %t %t %+v %t",
+		options.PageRange, options.Normalization, options.Columns, options.StripRunningLines,
+		options.ExcludeHiddenLayers, options.ClipToCropBox, options.IncludeAnnotations,
+		options.RepairMojibake, options.Text, options.OCR != nil)
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("%s/%s/%s-%s.json", textCacheVersion, sourceSHA256[:2], sourceSHA256,
+		hex.EncodeToString(sum[:8]))
+}
@@ -0,0 +1,63 @@
+package pdfex
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestTextCache(t *testing.T) {
+	cache, err := OpenTextCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenTextCache: %v", err)
+	}
+	options := DefaultParseOptions()
+	pages, err := cache.Text(scannedFixture, options)
+	if err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+	doc, err := ParsePDF(scannedFixture)
+	if err != nil {
+		t.Fatalf("ParsePDF: %v", err)
+	}
+	if want := doc.SearchText(); !reflect.DeepEqual(pages, want) {
+		t.Fatalf("got page text %q, want %q", pages, want)
+	}
+
+	// A second search takes the text from the cache entry rather than the file
+	name := cache.entryName(doc.SourceSHA256(), options)
+	if err := os.WriteFile(cache.store.path(name), []byte(`[{"page":1,"text":"cached"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if pages, err := cache.Text(scannedFixture, options); err != nil || len(pages) != 1 || pages[0].Text != "cached" {
+		t.Errorf("Text = %q, %v, want the cached entry", pages, err)
+	}
+
+	// Options that change the text have entries of their own
+	options = DefaultParseOptions()
+	options.PageRange = "2"
+	if cache.entryName(doc.SourceSHA256(), options) == name {
+		t.Error("a page range shares the cache entry of the whole document")
+	}
+}
+
+func TestFindTextIn(t *testing.T) {
+	pages := []PageText{
+		{Page: 1, Text: "Invoice\nTotal: 10"},
+		{Page: 3, Text: "Subtotal: 8\nTotal: 10"},
+	}
+	matches, err := FindTextIn(pages, `\bTotal`)
+	if err != nil {
+		t.Fatalf("FindTextIn: %v", err)
+	}
+	want := []TextMatch{
+		{Page: 1, Offset: 8, Line: 2, Text: "Total", Before: "Invoice\n", After: ": 10"},
+		{Page: 3, Offset: 12, Line: 2, Text: "Total", Before: "Subtotal: 8\n", After: ": 10"},
+	}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("got %+v, want %+v", matches, want)
+	}
+	if _, err := FindTextIn(pages, "("); err == nil {
+		t.Error("FindTextIn accepted an invalid pattern")
+	}
+}
@@ -0,0 +1,161 @@
+package pdfex
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+	"github.com/yourusername/pdfex/internal/utils"
+)
+
+// ErrNoThumbnail is returned by GetPageThumbnail for pages without a /Thumb image
+var ErrNoThumbnail = errors.New("page has no thumbnail")
+
+// Length of the longer side of a synthesized preview in pixels, when none is given
+const defaultPreviewSize = 200
+
+// GetPageThumbnail returns the thumbnail image stored in the /Thumb entry of a page, numbered
+// from 1. Few writers store thumbnails; ErrNoThumbnail is returned for pages without one, and
+// GetPagePreview falls back to a preview built from the page's images.
+func (p *PDFDocument) GetPageThumbnail(pageNum int) (image.Image, error) {
+	if pageNum < 1 || pageNum > len(p.doc.Pages) {
+		return nil, fmt.Errorf("page number out of range: %d", pageNum)
+	}
+	page := &p.doc.Pages[pageNum-1]
+
+	obj, ok := p.doc.Objects[page.ObjectNumber]
+	if !ok {
+		return nil, ErrNoThumbnail
+	}
+	ref, ok := obj.Dict().Ref("Thumb")
+	if !ok {
+		return nil, ErrNoThumbnail
+	}
+
+	resolver := p.doc.Resolver()
+	thumb, err := resolver.ResolveStream(ref)
+	if err != nil {
+		return nil, fmt.Errorf("thumbnail of page %d: %v", pageNum, err)
+	}
+	palette, err := thumbnailPalette(resolver, thumb.Dict().Get("ColorSpace"))
+	if err != nil {
+		return nil, fmt.Errorf("thumbnail of page %d: %v", pageNum, err)
+	}
+	img, ok := decodeImage(thumb, palette)
+	if !ok {
+		return nil, fmt.Errorf("unsupported encoding of the thumbnail of page %d", pageNum)
+	}
+	return img, nil
+}
+
+// thumbnailPalette returns the RGB triplets of an /Indexed color space, as thumbnails may use
+// with a DeviceRGB or DeviceGray base, or nil for any other color space
+func thumbnailPalette(resolver *document.Resolver, colorSpace utils.Value) ([]byte, error) {
+	resolved, err := resolver.ResolveValue(colorSpace)
+	if err != nil {
+		return nil, err
+	}
+	cs, ok := resolved.(utils.Array)
+	if !ok || len(cs) != 4 || cs[0] != utils.Name("Indexed") {
+		return nil, nil
+	}
+
+	base, err := resolver.ResolveValue(cs[1])
+	if err != nil {
+		return nil, err
+	}
+	components := map[utils.Value]int{utils.Name("DeviceGray"): 1, utils.Name("DeviceRGB"): 3}[base]
+	if components == 0 {
+		return nil, fmt.Errorf("unsupported indexed base color space %v", base)
+	}
+
+	var lookup []byte
+	switch value := cs[3].(type) {
+	case utils.String:
+		lookup = []byte(value)
+	case utils.Ref:
+		stream, err := resolver.ResolveStream(value)
+		if err != nil {
+			return nil, err
+		}
+		lookup = stream.Stream
+	default:
+		return nil, fmt.Errorf("invalid indexed color lookup %v", cs[3])
+	}
+	if components == 3 {
+		return lookup, nil
+	}
+
+	palette := make([]byte, 0, len(lookup)*3)
+	for _, grey := range lookup {
+		palette = append(palette, grey, grey, grey)
+	}
+	return palette, nil
+}
+
+// GetPagePreview returns the thumbnail of a page, numbered from 1, or without one a crude
+// preview synthesized from the images the page draws, scaled so that its longer side is size
+// pixels (200 if size is 0 or less). Text and vector graphics are not drawn, so a page without
+// images previews as a blank page of its shape.
+func (p *PDFDocument) GetPagePreview(pageNum int, size int) (image.Image, error) {
+	img, err := p.GetPageThumbnail(pageNum)
+	if !errors.Is(err, ErrNoThumbnail) {
+		return img, err
+	}
+	if size <= 0 {
+		size = defaultPreviewSize
+	}
+
+	page := p.extractPage(pageNum)
+	width, height := page.Width, page.Height
+	if page.Rotation == 90 || page.Rotation == 270 {
+		width, height = height, width
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("page %d has no size", pageNum)
+	}
+	scale := float64(size) / math.Max(width, height)
+
+	preview := image.NewNRGBA(image.Rect(0, 0, max(1, int(width*scale+0.5)), max(1, int(height*scale+0.5))))
+	for i := range preview.Pix {
+		preview.Pix[i] = 0xff
+	}
+
+	for _, drawn := range p.drawnImages(page) {
+		src, ok := decodeImage(drawn.obj, nil)
+		if !ok {
+			continue
+		}
+
+		// Map the image bounds to preview pixels, which run down from the top left
+		r := userSpaceRect(drawn.bounds, page)
+		x1, y1 := text.UprightPoint(r.X1, r.Y1, page.Rotation, page.Width, page.Height)
+		x2, y2 := text.UprightPoint(r.X2, r.Y2, page.Rotation, page.Width, page.Height)
+		dst := image.Rect(
+			int(math.Min(x1, x2)*scale), int((height-math.Max(y1, y2))*scale),
+			int(math.Ceil(math.Max(x1, x2)*scale)), int(math.Ceil((height-math.Min(y1, y2))*scale)),
+		)
+		drawScaled(preview, dst, src)
+	}
+	return preview, nil
+}
+
+// drawScaled draws src stretched over the rectangle dst of img, sampling the nearest pixel
+func drawScaled(img *image.NRGBA, dst image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	if dst.Empty() || sb.Empty() {
+		return
+	}
+	clipped := dst.Intersect(img.Bounds())
+	for y := clipped.Min.Y; y < clipped.Max.Y; y++ {
+		sy := sb.Min.Y + (y-dst.Min.Y)*sb.Dy()/dst.Dy()
+		for x := clipped.Min.X; x < clipped.Max.X; x++ {
+			sx := sb.Min.X + (x-dst.Min.X)*sb.Dx()/dst.Dx()
+			img.Set(x, y, color.NRGBAModel.Convert(src.At(sx, sy)))
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package pdfex
+
+import "github.com/yourusername/pdfex/internal/document"
+
+// Tracer starts spans around the phases of a parse, so that production ingestion systems can
+// attribute latency to a stage. Set ParseOptions.Tracer to an adapter for a tracing library:
+// with OpenTelemetry, StartSpan calls Start on a trace.Tracer with the context of the request,
+// and the returned Span keeps the new context to start the spans nested in it.
+type Tracer = document.Tracer
+
+// Span is a traced phase of a parse; the phases within it are started from the span
+type Span = document.Span
+
+// Span names of the parse and its phases
+const (
+	SpanParse         = document.SpanParse         // The whole parse
+	SpanXRef          = document.SpanXRef          // Reading the xref table and trailer
+	SpanObjects       = document.SpanObjects       // Loading the objects
+	SpanDecompression = document.SpanDecompression // Decoding the filtered streams
+	SpanPages         = document.SpanPages         // Walking the page tree
+	SpanFonts         = document.SpanFonts         // Loading fonts and their encodings
+	SpanText          = document.SpanText          // Extracting the text of the pages
+)
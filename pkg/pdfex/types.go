@@ -0,0 +1,218 @@
+package pdfex
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pdfex/internal/document"
+	"github.com/yourusername/pdfex/internal/text"
+)
+
+// The types in this file are part of the stable public API. They are plain values converted
+// from the internal document model, so callers never depend on internal packages.
+
+// ObjectRef identifies an indirect object in the document
+type ObjectRef struct {
+	Number     int `json:"number"`
+	Generation int `json:"generation"`
+}
+
+// String returns the reference in PDF syntax, e.g. "12 0 R"
+func (r ObjectRef) String() string {
+	return fmt.Sprintf("%d %d R", r.Number, r.Generation)
+}
+
+// Page describes a page of the document
+type Page struct {
+	Number   int       `json:"number"` // 1-based page number
+	Ref      ObjectRef `json:"ref"`
+	Width    float64   `json:"width"`               // In points, including any UserUnit scale
+	Height   float64   `json:"height"`              // In points, including any UserUnit scale
+	Rotation int       `json:"rotation"`            // Clockwise display rotation in degrees
+	UserUnit float64   `json:"user_unit,omitempty"` // Points per user space unit, when not 1
+}
+
+// Word is a word together with its bounding box on the page, in points (PDF user space units
+// scaled by any /UserUnit) with the origin at the bottom-left corner of the upright page
+type Word struct {
+	Text     string  `json:"text"`
+	X        float64 `json:"x"` // Left edge
+	Y        float64 `json:"y"` // Bottom edge, including descenders
+	Width    float64 `json:"width"`
+	Height   float64 `json:"height"`
+	Baseline float64 `json:"baseline"`
+	FontName string  `json:"font_name"`
+	FontSize float64 `json:"font_size"`
+	Angle    float64 `json:"angle,omitempty"` // Baseline direction in degrees, counterclockwise
+}
+
+// TextSpan is a run of text shown by a single text operator, positioned at its baseline origin in
+// points with the origin at the bottom-left corner of the upright page
+type TextSpan struct {
+	Text       string    `json:"text"`
+	X          float64   `json:"x"`
+	Y          float64   `json:"y"` // Baseline, excluding any text rise
+	Width      float64   `json:"width"`
+	FontName   string    `json:"font_name"`
+	FontSize   float64   `json:"font_size"`
+	Rise       float64   `json:"rise,omitempty"`  // Superscript or subscript offset
+	Angle      float64   `json:"angle,omitempty"` // Baseline direction in degrees, counterclockwise
+	Vertical   bool      `json:"vertical,omitempty"`
+	CharWidths []float64 `json:"char_widths,omitempty"` // Advance of each character
+}
+
+// Font describes a font resource used by the document
+type Font struct {
+	Name         string `json:"name"`
+	Subtype      string `json:"subtype"`
+	Encoding     string `json:"encoding"`
+	HasToUnicode bool   `json:"has_to_unicode"`
+}
+
+// newPage converts an internal page into its public representation
+func newPage(page document.PDFPage, generation int) Page {
+	return Page{
+		Number:   page.PageNumber,
+		Ref:      ObjectRef{Number: page.ObjectNumber, Generation: generation},
+		Width:    page.Width,
+		Height:   page.Height,
+		Rotation: page.Rotation,
+		UserUnit: userUnit(page),
+	}
+}
+
+// userUnit returns the /UserUnit of a page, or 0 if it is the default of 1
+func userUnit(page document.PDFPage) float64 {
+	if unit := page.Unit(); unit != 1 {
+		return unit
+	}
+	return 0
+}
+
+// newFont converts an internal font into its public representation
+func newFont(font document.PDFFont) Font {
+	return Font{
+		Name:         font.Name,
+		Subtype:      strings.TrimPrefix(font.Subtype, "/"),
+		Encoding:     strings.TrimPrefix(font.Encoding, "/"),
+		HasToUnicode: len(font.ToUnicode) > 0,
+	}
+}
+
+// newTextSpan converts an internal text position into its public representation
+func newTextSpan(pos document.TextPosition) TextSpan {
+	return TextSpan{
+		Text:       pos.Text,
+		X:          pos.X,
+		Y:          pos.Y,
+		Width:      pos.Width,
+		FontName:   pos.FontName,
+		FontSize:   pos.FontSize,
+		Rise:       pos.Rise,
+		Angle:      pos.Angle,
+		Vertical:   pos.Vertical,
+		CharWidths: pos.CharWidths,
+	}
+}
+
+// newWord converts an indexed word into its public representation
+func newWord(word text.IndexedWord) Word {
+	return Word{
+		Text:     word.Text,
+		X:        word.Box.MinX,
+		Y:        word.Box.MinY,
+		Width:    word.Box.MaxX - word.Box.MinX,
+		Height:   word.Box.MaxY - word.Box.MinY,
+		Baseline: word.Position.Y,
+		FontName: word.Position.FontName,
+		FontSize: word.Position.FontSize,
+		Angle:    word.Position.Angle,
+	}
+}
+
+// Pages returns all pages of the document in order
+func (p *PDFDocument) Pages() []Page {
+	pages := make([]Page, 0, len(p.doc.Pages))
+	for _, page := range p.doc.Pages {
+		pages = append(pages, newPage(page, p.generationOf(page.ObjectNumber)))
+	}
+	return pages
+}
+
+// Page returns a specific page (1-based)
+func (p *PDFDocument) Page(pageNum int) (Page, error) {
+	page, ok := p.doc.GetPage(pageNum)
+	if !ok {
+		return Page{}, fmt.Errorf("page number out of range: %d", pageNum)
+	}
+	return newPage(page, p.generationOf(page.ObjectNumber)), nil
+}
+
+// GetPageTextPositions returns the text spans of a page (1-based) in reading order
+func (p *PDFDocument) GetPageTextPositions(pageNum int) ([]TextSpan, error) {
+	if pageNum < 1 || pageNum > len(p.doc.Pages) {
+		return nil, fmt.Errorf("page number out of range: %d", pageNum)
+	}
+
+	page := p.extractPage(pageNum)
+
+	spans := make([]TextSpan, 0, len(page.TextPositions))
+	for _, pos := range page.TextPositions {
+		spans = append(spans, newTextSpan(pos))
+	}
+	return spans, nil
+}
+
+// GetWords returns the words of a page (1-based) in reading order, with bounding boxes computed
+// from the fonts' glyph widths
+func (p *PDFDocument) GetWords(pageNum int) ([]Word, error) {
+	if pageNum < 1 || pageNum > len(p.doc.Pages) {
+		return nil, fmt.Errorf("page number out of range: %d", pageNum)
+	}
+
+	page := p.extractPage(pageNum)
+	indexed := text.NewPageIndex(page.TextPositions).Words()
+
+	words := make([]Word, 0, len(indexed))
+	for _, word := range indexed {
+		words = append(words, newWord(word))
+	}
+	return words, nil
+}
+
+// Fonts returns the fonts of the document, sorted by name
+func (p *PDFDocument) Fonts() []Font {
+	fonts := make([]Font, 0, len(p.doc.Fonts))
+	for _, font := range p.doc.Fonts {
+		fonts = append(fonts, newFont(font))
+	}
+
+	sort.Slice(fonts, func(i, j int) bool {
+		return fonts[i].Name < fonts[j].Name
+	})
+
+	return fonts
+}
+
+// ObjectRefs returns references to all loaded objects, sorted by object number
+func (p *PDFDocument) ObjectRefs() []ObjectRef {
+	refs := make([]ObjectRef, 0, len(p.doc.Objects))
+	for _, obj := range p.doc.Objects {
+		refs = append(refs, ObjectRef{Number: obj.ObjectNumber, Generation: obj.Generation})
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Number < refs[j].Number
+	})
+
+	return refs
+}
+
+// generationOf returns the generation number of a loaded object
+func (p *PDFDocument) generationOf(objNum int) int {
+	if obj, ok := p.doc.GetObject(objNum); ok {
+		return obj.Generation
+	}
+	return 0
+}
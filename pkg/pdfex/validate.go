@@ -0,0 +1,109 @@
+package pdfex
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Severity grades a validation finding
+type Severity string
+
+// Finding severities
+const (
+	SeverityError   Severity = "error"   // The file breaks the specification; readers must guess or recover
+	SeverityWarning Severity = "warning" // The file deviates from the specification but reads unambiguously
+)
+
+// Finding is a structural problem found by Validate, or a PDF/A requirement CheckPDFA found unmet
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`             // Stable identifier of the check, e.g. "broken-reference" or "missing-length"
+	Object   int      `json:"object,omitempty"` // Object the finding is about, 0 if it concerns the file as a whole
+	Message  string   `json:"message"`
+}
+
+// String formats the finding as "severity: code: object N: message"
+func (f Finding) String() string {
+	if f.Object == 0 {
+		return fmt.Sprintf("%s: %s: %s", f.Severity, f.Code, f.Message)
+	}
+	return fmt.Sprintf("%s: %s: object %d: %s", f.Severity, f.Code, f.Object, f.Message)
+}
+
+// Validate checks the structural conformance of the document: the header, the consistency of
+// the cross-reference table with the file, broken references, missing or wrong stream
+// lengths, stream/endstream mismatches and the page tree. The data the document was parsed
+// from is read again; ErrNoSource is returned if it is no longer available. An empty result
+// means no problems were found.
+func (p *PDFDocument) Validate() ([]Finding, error) {
+	var items []document.Finding
+	err := p.withSource(func(src io.ReaderAt, size int64) error {
+		var err error
+		items, err = p.doc.Validate(src, size)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(items))
+	for _, item := range items {
+		findings = append(findings, newFinding(item))
+	}
+	return findings, nil
+}
+
+// AuditXRef compares the cross-reference sections of the file, followed back through the
+// revisions of incremental updates, with the objects found by scanning it. Its findings are
+// warnings coded "orphaned-object" for definitions no section lists, "unreachable-object" for
+// objects not connected to the trailer's /Root, /Info or /Encrypt and "shadowed-object" for
+// object numbers defined more than once, and "free-list" errors or warnings for a broken list
+// of free entries. Like Validate, it reads the data the document was parsed from again.
+func (p *PDFDocument) AuditXRef() ([]Finding, error) {
+	var items []document.Finding
+	err := p.withSource(func(src io.ReaderAt, size int64) error {
+		var err error
+		items, err = p.doc.AuditXRef(src, size)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to audit the xref: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(items))
+	for _, item := range items {
+		findings = append(findings, newFinding(item))
+	}
+	return findings, nil
+}
+
+// CheckPDFA screens the document against key PDF/A requirements and lists the unmet ones as
+// findings with codes starting with "pdfa-": identification in the XMP metadata (pdfaid:part and
+// pdfaid:conformance), a GTS_PDFA1 output intent with an ICC profile, no encryption, no
+// JavaScript and embedded fonts. It is a screening for archiving workflows rather than a full
+// validation, so an empty result doesn't prove conformance.
+func (p *PDFDocument) CheckPDFA() []Finding {
+	items := p.doc.CheckPDFA()
+	findings := make([]Finding, 0, len(items))
+	for _, item := range items {
+		findings = append(findings, newFinding(item))
+	}
+	return findings
+}
+
+// HasErrors reports whether any of the findings is an error
+func HasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// newFinding converts a finding into its public representation
+func newFinding(item document.Finding) Finding {
+	return Finding{Severity: Severity(item.Severity), Code: item.Code, Object: item.Object, Message: item.Message}
+}
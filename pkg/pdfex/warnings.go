@@ -0,0 +1,49 @@
+package pdfex
+
+import (
+	"fmt"
+
+	"github.com/yourusername/pdfex/internal/document"
+)
+
+// Warning is a problem found while parsing that the parse recovered from
+type Warning struct {
+	Stage    string   `json:"stage"`            // Phase of the parse: xref, objects, decompression or pages
+	Severity Severity `json:"severity"`         // SeverityError when the document had to be recovered
+	Object   int      `json:"object,omitempty"` // Object the warning is about, 0 if it concerns the file as a whole
+	Message  string   `json:"message"`
+}
+
+// String formats the warning as "stage: object N: message"
+func (w Warning) String() string {
+	if w.Object == 0 {
+		return fmt.Sprintf("%s: %s", w.Stage, w.Message)
+	}
+	return fmt.Sprintf("%s: object %d: %s", w.Stage, w.Object, w.Message)
+}
+
+// Warnings returns the problems found while parsing the document, which are also logged: the
+// recovery steps taken first, then the warnings of each stage in the order they were found.
+// Set ParseOptions.TreatWarningsAsErrors to fail the parse instead.
+func (p *PDFDocument) Warnings() []Warning {
+	items := p.doc.Warnings()
+	warnings := make([]Warning, 0, len(items))
+	for _, item := range items {
+		warnings = append(warnings, newWarning(item))
+	}
+	return warnings
+}
+
+// newWarning converts an internal warning into its public representation
+func newWarning(item document.Warning) Warning {
+	return Warning{Stage: string(item.Stage), Severity: Severity(item.Severity), Object: item.Object, Message: item.Message}
+}
+
+// problemsError wraps err with the first of a non-empty list of problems and how many more
+// there are
+func problemsError(err error, problems []fmt.Stringer) error {
+	if len(problems) == 1 {
+		return fmt.Errorf("%w: %s", err, problems[0])
+	}
+	return fmt.Errorf("%w: %s (and %d more)", err, problems[0], len(problems)-1)
+}